@@ -3,66 +3,157 @@ package main
 import (
 	"context"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 
 	"pvz-service/internal/api"
+	"pvz-service/internal/api/handlers"
 	"pvz-service/internal/api/middleware"
+	"pvz-service/internal/api/respond"
+	"pvz-service/internal/auth"
 	"pvz-service/internal/config"
+	"pvz-service/internal/domain/interfaces"
 	"pvz-service/internal/grpc"
 	"pvz-service/internal/logger"
 	"pvz-service/internal/metrics"
+	"pvz-service/internal/notifier"
 	"pvz-service/internal/repository/postgres"
 	"pvz-service/internal/services"
+	"pvz-service/internal/worker"
 )
 
 func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	cfg := config.LoadConfig()
+
+	// Неизвестный LOG_LEVEL не мешает запуску: остается info, предупреждение пишется ниже
+	logLevel := logger.LevelInfo
+	logLevelErr := logLevel.UnmarshalText([]byte(cfg.LogLevel))
+	if logLevelErr != nil {
+		logLevel = logger.LevelInfo
+	}
+
 	log := logger.New(logger.Config{
-		Level:       logger.LevelInfo,
+		Level:       logLevel,
 		Format:      "json",
 		Output:      os.Stdout,
 		ServiceName: "pvz-service",
 		Version:     "1.0.0",
 		Environment: os.Getenv("ENVIRONMENT"),
+		// Без активного спана trace_id/span_id просто не добавляются
+		TraceContext:    true,
+		DebugSampleRate: cfg.LogDebugSampleRate,
 	})
 
 	slog.SetDefault(log)
 
 	log.Info("приложение запускается", "pid", os.Getpid())
 
-	cfg := config.LoadConfig()
+	if logLevelErr != nil {
+		log.Warn("некорректный LOG_LEVEL, используется info", "log_level", cfg.LogLevel, "error", logLevelErr)
+	}
 	log.Debug("конфигурация загружена", "server_port", cfg.ServerPort)
 
+	if err := cfg.Validate(); err != nil {
+		log.Error("некорректная конфигурация", "environment", cfg.Environment, "error", err)
+		os.Exit(1)
+	}
+
 	db, err := postgres.NewDatabase(&cfg.Database)
 	if err != nil {
 		log.Error("ошибка подключения к базе данных", "error", err)
 		os.Exit(1)
 	}
 
+	replicaDB, err := postgres.NewReplicaDatabase(&cfg.Database)
+	if err != nil {
+		log.Error("ошибка подключения к реплике базы данных", "error", err)
+		os.Exit(1)
+	}
+	if replicaDB != nil {
+		log.Info("списочные запросы направляются на реплику")
+	}
+
 	ctx = logger.WithLogger(ctx, log)
 
 	log.Debug("инициализация репозиториев")
 	userRepo := postgres.NewUserRepository(db)
-	pvzRepo := postgres.NewPVZRepository(db)
-	receptionRepo := postgres.NewReceptionRepository(db)
-	productRepo := postgres.NewProductRepository(db)
+	pvzRepo := postgres.NewPVZRepository(db).WithReplica(replicaDB)
+	receptionRepo := postgres.NewReceptionRepository(db).WithReplica(replicaDB)
+	productRepo := postgres.NewProductRepository(db).WithReplica(replicaDB)
+	userPVZRepo := postgres.NewUserPVZRepository(db)
 
 	log.Debug("инициализация сервисов")
-	authService := services.NewAuthService(userRepo, cfg.JWTSecret)
+	apiKeys, err := auth.ParseAPIKeys(cfg.APIKeys)
+	if err != nil {
+		log.Error("некорректный список API_KEYS", "error", err)
+		os.Exit(1)
+	}
+	if len(apiKeys) > 0 {
+		log.Info("включена аутентификация по API-ключам", "keys", len(apiKeys))
+	}
+
+	authService := services.NewAuthService(userRepo, cfg.JWTSecret).
+		WithAPIKeys(apiKeys).
+		WithBlockedEmailDomains(strings.Split(cfg.BlockedEmailDomains, ",")).
+		WithPreviousJWTSecret(cfg.JWTPreviousSecret).
+		WithTokenClaims(cfg.JWTIssuer, cfg.JWTAudience).
+		WithMaxDummyTokenTTL(time.Duration(cfg.DummyTokenMaxTTLSeconds) * time.Second)
 	pvzService := services.NewPVZService(pvzRepo)
-	receptionService := services.NewReceptionService(receptionRepo, pvzRepo, productRepo)
-	productService := services.NewProductService(productRepo, receptionRepo, pvzRepo)
+	// Шина событий для потоков GET /pvz/{pvzId}/events
+	eventBus := notifier.NewEventBus()
+
+	receptionService := services.NewReceptionService(receptionRepo, pvzRepo, productRepo).
+		WithEventPublisher(eventBus)
+	if cfg.Webhook.URL != "" {
+		log.Info("включены вебхуки о закрытии приемок", "url", cfg.Webhook.URL)
+		receptionService.WithCloseNotifier(notifier.NewWebhookNotifier(
+			cfg.Webhook.URL,
+			time.Duration(cfg.Webhook.TimeoutSeconds)*time.Second,
+			cfg.Webhook.MaxRetries,
+		))
+	}
+	productService := services.NewProductService(productRepo, receptionRepo, pvzRepo).
+		WithMaxProductsPerReception(cfg.MaxProductsPerReception).
+		WithEventPublisher(eventBus)
+
+	assignmentService := services.NewAssignmentService(userPVZRepo, userRepo, pvzRepo, receptionRepo, productRepo)
+
+	var pvzAccess interfaces.PVZAccessChecker
+	if cfg.EnforcePVZAssignment {
+		log.Info("включена проверка назначения сотрудников на ПВЗ")
+		pvzAccess = assignmentService
+	}
 
 	metrics.InitMetrics()
 
+	var autoCloser *worker.ReceptionAutoCloser
+	if cfg.AutoClose.Enabled {
+		autoCloser = worker.NewReceptionAutoCloser(
+			receptionRepo,
+			time.Duration(cfg.AutoClose.MaxAgeHours)*time.Hour,
+			time.Duration(cfg.AutoClose.IntervalMinutes)*time.Minute,
+			log,
+		)
+		autoCloser.Start(ctx)
+	}
+
 	metricsServeMux := http.NewServeMux()
 	metricsServeMux.Handle("/metrics", promhttp.Handler())
 	metricsServer := &http.Server{
@@ -70,25 +161,94 @@ func main() {
 		Handler: metricsServeMux,
 	}
 
-	router := api.NewRouter(authService, pvzService, receptionService, productService)
+	trustedProxies, err := middleware.ParseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		log.Error("некорректный список TRUSTED_PROXIES", "error", err)
+		os.Exit(1)
+	}
+
+	respond.SetPretty(cfg.PrettyJSON)
 
+	var tracerProvider *sdktrace.TracerProvider
+	var outerMiddleware []mux.MiddlewareFunc
+	if cfg.TracingEnabled {
+		tracerOpts := []sdktrace.TracerProviderOption{
+			sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", "pvz-service"))),
+		}
+		if cfg.TracingOTLPEndpoint != "" {
+			exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(cfg.TracingOTLPEndpoint))
+			if err != nil {
+				log.Error("ошибка создания OTLP-экспортера трейсов", "endpoint", cfg.TracingOTLPEndpoint, "error", err)
+				os.Exit(1)
+			}
+			tracerOpts = append(tracerOpts, sdktrace.WithBatcher(exporter))
+			log.Info("спаны отправляются по OTLP", "endpoint", cfg.TracingOTLPEndpoint)
+		} else {
+			// Без экспортера спаны никуда не уходят: остаются проброс traceparent и trace_id в логах
+			log.Warn("TRACING_OTLP_ENDPOINT не задан, спаны не экспортируются")
+		}
+		tracerProvider = sdktrace.NewTracerProvider(tracerOpts...)
+		otel.SetTracerProvider(tracerProvider)
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+		// Трейсинг - самый внешний слой: спан покрывает и общие middleware роутера,
+		// а trace_id попадает в логи запроса
+		outerMiddleware = append(outerMiddleware, middleware.TracingMiddleware(tracerProvider, propagation.TraceContext{}))
+		log.Info("трейсинг HTTP запросов включен")
+	}
+	router := api.NewRouter(authService, pvzService, receptionService, productService, assignmentService, pvzAccess, eventBus, cfg.MaxListPage, outerMiddleware...)
+
+	// Сжатие подключается снаружи метрик и логирования,
+	// чтобы они учитывали размер ответа до сжатия
+	if cfg.HTTPSRedirectEnabled {
+		// Перенаправление отвечает раньше сжатия и метрик обработчиков
+		router.Use(middleware.HTTPSRedirectMiddleware)
+		log.Info("перенаправление http на https включено")
+	}
+	router.Use(middleware.GzipMiddleware)
+	router.Use(metrics.InFlightMiddleware)
 	router.Use(metrics.PrometheusMiddleware)
-	router.Use(middleware.LoggingMiddleware(log))
+	router.Use(middleware.ClientIPMiddleware(trustedProxies))
+	router.Use(middleware.LoggingMiddleware(log, time.Duration(cfg.SlowRequestThresholdMs)*time.Millisecond))
+	// Лимит внутри логирования и метрик, чтобы отказы 503 были видны в них
+	router.Use(middleware.ConcurrencyLimitMiddleware(cfg.MaxConcurrentRequests))
+	router.Use(middleware.SlowClientMiddleware(time.Duration(cfg.ServerWriteTimeoutMs) * time.Millisecond))
+	router.Use(middleware.CSRFMiddleware(cfg.CSRFAuthCookie))
+
+	// Флаги выставляются серверами gRPC и метрик и читаются /readyz
+	grpcStatus := &handlers.ServingStatus{}
+	metricsStatus := &handlers.ServingStatus{}
+
+	// GET /readyz - готовность базы, gRPC и сервера метрик
+	readinessHandler := handlers.NewReadinessHandler(db, grpcStatus, metricsStatus)
+	router.HandleFunc("/readyz", readinessHandler.Ready).Methods("GET")
+
+	api.LogRoutes(router, log)
 
 	var grpcServer *grpc.Server
 
 	go func() {
 		log.Info("gRPC сервер запускается", "port", 3000)
 		grpcServer = grpc.StartGRPCServer(pvzService, 3000)
-		log.Info("gRPC сервер запущен")
+		if grpcServer != nil {
+			grpcStatus.SetServing(true)
+			log.Info("gRPC сервер запущен")
+		}
 	}()
 
 	go func() {
 		log.Info("Prometheus метрики запускаются", "port", 9000)
-		if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		lis, err := net.Listen("tcp", metricsServer.Addr)
+		if err != nil {
 			log.Error("ошибка запуска сервера метрик", "error", err)
 			cancel()
+			return
 		}
+		metricsStatus.SetServing(true)
+		if err := metricsServer.Serve(lis); err != nil && err != http.ErrServerClosed {
+			log.Error("ошибка запуска сервера метрик", "error", err)
+			cancel()
+		}
+		metricsStatus.SetServing(false)
 	}()
 
 	server := api.NewServer(cfg, router)
@@ -110,6 +270,17 @@ func main() {
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer shutdownCancel()
 
+	// Во время остановки /readyz сразу отвечает 503
+	grpcStatus.SetServing(false)
+	metricsStatus.SetServing(false)
+
+	// Потоки событий иначе держали бы соединения до таймаута остановки
+	eventBus.Close()
+
+	if autoCloser != nil {
+		autoCloser.Stop()
+	}
+
 	log.Info("завершение работы сервера метрик...")
 	if err := metricsServer.Shutdown(shutdownCtx); err != nil {
 		log.Error("ошибка завершения сервера метрик", "error", err)
@@ -143,6 +314,12 @@ func main() {
 		log.Info("HTTP сервер корректно остановлен")
 	}
 
+	if tracerProvider != nil {
+		if err := tracerProvider.Shutdown(shutdownCtx); err != nil {
+			log.Error("ошибка остановки трейсинга", "error", err)
+		}
+	}
+
 	log.Info("закрытие соединения с базой данных...")
 	if err := db.Close(); err != nil {
 		log.Error("ошибка закрытия соединения с базой данных", "error", err)
@@ -150,5 +327,11 @@ func main() {
 		log.Info("соединение с базой данных закрыто")
 	}
 
+	if replicaDB != nil {
+		if err := replicaDB.Close(); err != nil {
+			log.Error("ошибка закрытия соединения с репликой", "error", err)
+		}
+	}
+
 	log.Info("приложение корректно завершило работу")
 }
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"pvz-service/internal/api/respond"
+	"pvz-service/internal/api/validator"
+	"pvz-service/internal/domain/interfaces"
+	"pvz-service/internal/domain/models"
+	"pvz-service/internal/logger"
+
+	"github.com/gorilla/mux"
+)
+
+type AssignmentHandler struct {
+	assignmentService interfaces.AssignmentService
+}
+
+func NewAssignmentHandler(assignmentService interfaces.AssignmentService) *AssignmentHandler {
+	return &AssignmentHandler{
+		assignmentService: assignmentService,
+	}
+}
+
+func (h *AssignmentHandler) AssignEmployee(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	pvzIDStr := mux.Vars(r)["pvzId"]
+	log.Info("запрос на назначение сотрудника на ПВЗ", "pvz_id", pvzIDStr)
+
+	pvzID, ok := pathUUID(w, r, "pvzId")
+	if !ok {
+		return
+	}
+
+	var req models.EmployeeAssignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("ошибка декодирования JSON", "error", err)
+		respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeInvalidRequest, "Invalid request format", err)
+		return
+	}
+
+	if err := validator.ValidateStruct(req); err != nil {
+		log.Warn("ошибка валидации запроса назначения",
+			"validation_errors", validator.FormatValidationErrors(err),
+		)
+		respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeValidationFailed, "Validation failed: "+validator.FormatValidationErrors(err), nil)
+		return
+	}
+
+	if err := h.assignmentService.AssignEmployee(r.Context(), pvzID, req.UserID); err != nil {
+		log.Error("ошибка назначения сотрудника", "pvz_id", pvzID, "user_id", req.UserID, "error", err)
+		sendAssignmentError(w, r, err, "Unable to assign employee")
+		return
+	}
+
+	log.Info("сотрудник назначен на ПВЗ", "pvz_id", pvzID, "user_id", req.UserID)
+
+	respond.JSON(w, r, http.StatusCreated, SuccessResponse{Message: "Employee successfully assigned"})
+}
+
+func (h *AssignmentHandler) UnassignEmployee(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	vars := mux.Vars(r)
+	pvzIDStr := vars["pvzId"]
+	userIDStr := vars["userId"]
+	log.Info("запрос на снятие сотрудника с ПВЗ", "pvz_id", pvzIDStr, "user_id", userIDStr)
+
+	pvzID, ok := pathUUID(w, r, "pvzId")
+	if !ok {
+		return
+	}
+
+	userID, ok := pathUUID(w, r, "userId")
+	if !ok {
+		return
+	}
+
+	if err := h.assignmentService.UnassignEmployee(r.Context(), pvzID, userID); err != nil {
+		log.Error("ошибка снятия сотрудника с ПВЗ", "pvz_id", pvzID, "user_id", userID, "error", err)
+		sendAssignmentError(w, r, err, "Unable to unassign employee")
+		return
+	}
+
+	log.Info("сотрудник снят с ПВЗ", "pvz_id", pvzID, "user_id", userID)
+
+	respond.JSON(w, r, http.StatusOK, SuccessResponse{Message: "Employee successfully unassigned"})
+}
+
+func (h *AssignmentHandler) ListEmployees(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	pvzIDStr := mux.Vars(r)["pvzId"]
+	log.Info("запрос списка сотрудников ПВЗ", "pvz_id", pvzIDStr)
+
+	pvzID, ok := pathUUID(w, r, "pvzId")
+	if !ok {
+		return
+	}
+
+	users, err := h.assignmentService.ListEmployees(r.Context(), pvzID)
+	if err != nil {
+		log.Error("ошибка получения сотрудников ПВЗ", "pvz_id", pvzID, "error", err)
+		sendAssignmentError(w, r, err, "Error retrieving employees")
+		return
+	}
+
+	log.Info("список сотрудников ПВЗ получен", "pvz_id", pvzID, "count", len(users))
+
+	respond.JSON(w, r, http.StatusOK, users)
+}
+
+// sendAssignmentError сопоставляет доменные ошибки назначений с HTTP-статусами
+func sendAssignmentError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	switch {
+	case errors.Is(err, models.ErrPVZNotFound):
+		respond.ErrorWithCode(w, r, http.StatusNotFound, respond.CodePVZNotFound, "PVZ not found", err)
+	case errors.Is(err, models.ErrUserNotFound):
+		respond.ErrorWithCode(w, r, http.StatusNotFound, respond.CodeUserNotFound, "User not found", err)
+	case errors.Is(err, models.ErrAssignmentNotFound):
+		respond.ErrorWithCode(w, r, http.StatusNotFound, respond.CodeAssignmentNotFound, "Assignment not found", err)
+	case errors.Is(err, models.ErrUserNotEmployee):
+		respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeUserNotEmployee, "User is not an employee", err)
+	default:
+		respond.Error(w, r, http.StatusInternalServerError, fallback, err)
+	}
+}
@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/api/respond"
+	"pvz-service/internal/domain/models"
+	"pvz-service/internal/logger"
+)
+
+type MockAssignmentService struct {
+	mock.Mock
+}
+
+func (m *MockAssignmentService) IsEmployeeAssigned(ctx context.Context, userID, pvzID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID, pvzID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAssignmentService) ReceptionPVZID(ctx context.Context, receptionID uuid.UUID) (uuid.UUID, error) {
+	args := m.Called(ctx, receptionID)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockAssignmentService) ProductPVZID(ctx context.Context, productID uuid.UUID) (uuid.UUID, error) {
+	args := m.Called(ctx, productID)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockAssignmentService) AssignEmployee(ctx context.Context, pvzID, userID uuid.UUID) error {
+	args := m.Called(ctx, pvzID, userID)
+	return args.Error(0)
+}
+
+func (m *MockAssignmentService) UnassignEmployee(ctx context.Context, pvzID, userID uuid.UUID) error {
+	args := m.Called(ctx, pvzID, userID)
+	return args.Error(0)
+}
+
+func (m *MockAssignmentService) ListEmployees(ctx context.Context, pvzID uuid.UUID) ([]*models.User, error) {
+	args := m.Called(ctx, pvzID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.User), args.Error(1)
+}
+
+func setupAssignmentTest() (*AssignmentHandler, *MockAssignmentService) {
+	mockService := new(MockAssignmentService)
+	handler := NewAssignmentHandler(mockService)
+	return handler, mockService
+}
+
+func newAssignmentRequest(method, target string, body []byte, vars map[string]string) *http.Request {
+	req := httptest.NewRequest(method, target, bytes.NewBuffer(body))
+	req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
+	return mux.SetURLVars(req, vars)
+}
+
+func TestAssignEmployee_Success(t *testing.T) {
+	handler, mockService := setupAssignmentTest()
+
+	pvzID := uuid.New()
+	userID := uuid.New()
+	jsonBody, _ := json.Marshal(models.EmployeeAssignRequest{UserID: userID})
+
+	req := newAssignmentRequest("POST", "/pvz/"+pvzID.String()+"/employees", jsonBody, map[string]string{"pvzId": pvzID.String()})
+	w := httptest.NewRecorder()
+
+	mockService.On("AssignEmployee", mock.Anything, pvzID, userID).Return(nil)
+
+	handler.AssignEmployee(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAssignEmployee_NotFound(t *testing.T) {
+	testCases := []struct {
+		name          string
+		serviceErr    error
+		expectedError string
+	}{
+		{name: "PVZ Not Found", serviceErr: models.ErrPVZNotFound, expectedError: "PVZ not found"},
+		{name: "User Not Found", serviceErr: models.ErrUserNotFound, expectedError: "User not found"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, mockService := setupAssignmentTest()
+
+			pvzID := uuid.New()
+			userID := uuid.New()
+			jsonBody, _ := json.Marshal(models.EmployeeAssignRequest{UserID: userID})
+
+			req := newAssignmentRequest("POST", "/pvz/"+pvzID.String()+"/employees", jsonBody, map[string]string{"pvzId": pvzID.String()})
+			w := httptest.NewRecorder()
+
+			mockService.On("AssignEmployee", mock.Anything, pvzID, userID).Return(tc.serviceErr)
+
+			handler.AssignEmployee(w, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code)
+
+			var response respond.ErrorResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedError, response.Error)
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUnassignEmployee_NotFound(t *testing.T) {
+	testCases := []struct {
+		name          string
+		serviceErr    error
+		expectedError string
+	}{
+		{name: "PVZ Not Found", serviceErr: models.ErrPVZNotFound, expectedError: "PVZ not found"},
+		{name: "User Not Found", serviceErr: models.ErrUserNotFound, expectedError: "User not found"},
+		{name: "Assignment Not Found", serviceErr: models.ErrAssignmentNotFound, expectedError: "Assignment not found"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, mockService := setupAssignmentTest()
+
+			pvzID := uuid.New()
+			userID := uuid.New()
+
+			req := newAssignmentRequest("DELETE", "/pvz/"+pvzID.String()+"/employees/"+userID.String(), nil,
+				map[string]string{"pvzId": pvzID.String(), "userId": userID.String()})
+			w := httptest.NewRecorder()
+
+			mockService.On("UnassignEmployee", mock.Anything, pvzID, userID).Return(tc.serviceErr)
+
+			handler.UnassignEmployee(w, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code)
+
+			var response respond.ErrorResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedError, response.Error)
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListEmployees_Success(t *testing.T) {
+	handler, mockService := setupAssignmentTest()
+
+	pvzID := uuid.New()
+	users := []*models.User{{ID: uuid.New(), Email: "employee@example.com", Password: "hash", Role: models.RoleEmployee}}
+
+	req := newAssignmentRequest("GET", "/pvz/"+pvzID.String()+"/employees", nil, map[string]string{"pvzId": pvzID.String()})
+	w := httptest.NewRecorder()
+
+	mockService.On("ListEmployees", mock.Anything, pvzID).Return(users, nil)
+
+	handler.ListEmployees(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "hash")
+
+	var response []*models.User
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+	require.Len(t, response, 1)
+	assert.Equal(t, "employee@example.com", response[0].Email)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestListEmployees_PVZNotFound(t *testing.T) {
+	handler, mockService := setupAssignmentTest()
+
+	pvzID := uuid.New()
+
+	req := newAssignmentRequest("GET", "/pvz/"+pvzID.String()+"/employees", nil, map[string]string{"pvzId": pvzID.String()})
+	w := httptest.NewRecorder()
+
+	mockService.On("ListEmployees", mock.Anything, pvzID).Return(nil, models.ErrPVZNotFound)
+
+	handler.ListEmployees(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	mockService.AssertExpectations(t)
+}
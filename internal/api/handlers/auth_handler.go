@@ -2,47 +2,22 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strings"
+	"time"
 
+	"pvz-service/internal/api/respond"
 	"pvz-service/internal/api/validator"
 	"pvz-service/internal/domain/interfaces"
 	"pvz-service/internal/domain/models"
 	"pvz-service/internal/logger"
-
-	"golang.org/x/exp/slog"
 )
 
 type AuthHandler struct {
 	authService interfaces.AuthService
 }
 
-// Структура для стандартизированных ответов об ошибках
-type ErrorResponse struct {
-	Error string `json:"error"`
-}
-
-func sendErrorResponse(w http.ResponseWriter, message string, status int, err error) {
-	// Используем глобальный логгер, так как у нас нет доступа к контексту запроса
-	log := slog.Default()
-
-	if err != nil {
-		log.Error("ошибка обработки запроса",
-			"error", err,
-			"status", status,
-			"message", message,
-		)
-	} else {
-		log.Warn("запрос завершен с ошибкой",
-			"status", status,
-			"message", message,
-		)
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(ErrorResponse{Error: message})
-}
-
 func NewAuthHandler(authService interfaces.AuthService) *AuthHandler {
 	return &AuthHandler{
 		authService: authService,
@@ -56,7 +31,7 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	var req models.AuthRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Warn("ошибка декодирования JSON", "error", err)
-		sendErrorResponse(w, "Invalid request format", http.StatusBadRequest, err)
+		respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeInvalidRequest, "Invalid request format", err)
 		return
 	}
 
@@ -67,18 +42,23 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 			"email", req.Email,
 			"validation_errors", validator.FormatValidationErrors(err),
 		)
-		sendErrorResponse(w, "Validation failed: "+validator.FormatValidationErrors(err), http.StatusBadRequest, nil)
+		respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeValidationFailed, "Validation failed: "+validator.FormatValidationErrors(err), nil)
 		return
 	}
 
 	user, err := h.authService.Register(r.Context(), req.Email, req.Password, req.Role)
+	if errors.Is(err, models.ErrEmailDomainBlocked) {
+		log.Warn("регистрация с запрещенного домена", "email", req.Email)
+		respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeEmailDomainBlocked, "Email domain is not allowed", nil)
+		return
+	}
 	if err != nil {
 		log.Error("ошибка регистрации пользователя",
 			"email", req.Email,
 			"role", req.Role,
 			"error", err,
 		)
-		sendErrorResponse(w, "Registration failed", http.StatusBadRequest, err)
+		respond.Error(w, r, http.StatusBadRequest, "Registration failed", err)
 		return
 	}
 
@@ -88,9 +68,7 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		"role", user.Role,
 	)
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(user)
+	respond.JSON(w, r, http.StatusCreated, user)
 }
 
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
@@ -100,7 +78,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	var req models.AuthRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Warn("ошибка декодирования JSON", "error", err)
-		sendErrorResponse(w, "Invalid request format", http.StatusBadRequest, err)
+		respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeInvalidRequest, "Invalid request format", err)
 		return
 	}
 
@@ -112,7 +90,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 			"email", req.Email,
 			"validation_errors", validator.FormatValidationErrors(err),
 		)
-		sendErrorResponse(w, "Validation failed: "+validator.FormatValidationErrors(err), http.StatusBadRequest, nil)
+		respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeValidationFailed, "Validation failed: "+validator.FormatValidationErrors(err), nil)
 		return
 	}
 
@@ -120,15 +98,41 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		// Для защиты от атак перечисления пользователей не логируем причину ошибки
 		log.Warn("неудачная попытка входа", "email", req.Email)
-		sendErrorResponse(w, "Invalid credentials", http.StatusUnauthorized, err)
+		respond.ErrorWithCode(w, r, http.StatusUnauthorized, respond.CodeInvalidCredentials, "Invalid credentials", err)
 		return
 	}
 
 	log.Info("пользователь успешно аутентифицирован", "email", req.Email)
 
 	tokenResponse := models.TokenResponse{Token: token}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(tokenResponse)
+	respond.JSON(w, r, http.StatusOK, tokenResponse)
+}
+
+// Introspect проверяет bearer-токен без побочных эффектов и возвращает
+// данные о его владельце. Причина недействительности токена не раскрывается
+func (h *AuthHandler) Introspect(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+	log.Info("запрос на проверку токена")
+
+	response := &models.TokenIntrospection{Active: false}
+
+	authHeader := r.Header.Get("Authorization")
+	token := strings.TrimPrefix(authHeader, "Bearer ")
+	if strings.HasPrefix(authHeader, "Bearer ") && token != "" {
+		result, err := h.authService.IntrospectToken(token)
+		if err != nil {
+			log.Info("токен недействителен", "error", err)
+		} else {
+			response = result
+		}
+	} else {
+		log.Debug("bearer-токен не передан")
+	}
+
+	log.Info("проверка токена завершена", "active", response.Active)
+
+	w.Header().Set("Cache-Control", "no-store")
+	respond.JSON(w, r, http.StatusOK, response)
 }
 
 func (h *AuthHandler) DummyLogin(w http.ResponseWriter, r *http.Request) {
@@ -136,16 +140,23 @@ func (h *AuthHandler) DummyLogin(w http.ResponseWriter, r *http.Request) {
 	log.Info("запрос на тестовую аутентификацию")
 
 	var req struct {
-		Role string `json:"role"`
+		Role       string `json:"role"`
+		TTLSeconds int    `json:"ttlSeconds,omitempty"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Warn("ошибка декодирования JSON", "error", err)
-		sendErrorResponse(w, "Invalid request format", http.StatusBadRequest, err)
+		respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeInvalidRequest, "Invalid request format", err)
 		return
 	}
 
-	log.Debug("запрос тестового токена", "requested_role", req.Role)
+	log.Debug("запрос тестового токена", "requested_role", req.Role, "ttl_seconds", req.TTLSeconds)
+
+	if req.TTLSeconds < 0 {
+		log.Warn("запрошен отрицательный TTL", "ttl_seconds", req.TTLSeconds)
+		respond.Error(w, r, http.StatusBadRequest, "Invalid ttlSeconds: must be positive", nil)
+		return
+	}
 
 	var role models.UserRole
 	if req.Role == string(models.RoleModerator) {
@@ -154,20 +165,20 @@ func (h *AuthHandler) DummyLogin(w http.ResponseWriter, r *http.Request) {
 		role = models.RoleEmployee
 	} else {
 		log.Warn("запрошена недопустимая роль", "role", req.Role)
-		sendErrorResponse(w, "Invalid role: must be 'employee' or 'moderator'", http.StatusBadRequest, nil)
+		respond.Error(w, r, http.StatusBadRequest, "Invalid role: must be 'employee' or 'moderator'", nil)
 		return
 	}
 
-	token, err := h.authService.GenerateDummyToken(role)
+	// Нулевой TTL означает время жизни по умолчанию
+	token, err := h.authService.GenerateDummyToken(role, time.Duration(req.TTLSeconds)*time.Second)
 	if err != nil {
-		log.Error("ошибка генерации тестового токена", "role", role, "error", err)
-		sendErrorResponse(w, "Failed to generate token", http.StatusInternalServerError, err)
+		logRequestError(log, "ошибка генерации тестового токена", err, "role", role)
+		respond.Error(w, r, http.StatusInternalServerError, "Failed to generate token", err)
 		return
 	}
 
 	log.Info("тестовый токен успешно сгенерирован", "role", role)
 
 	tokenResponse := models.TokenResponse{Token: token}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(tokenResponse)
+	respond.JSON(w, r, http.StatusOK, tokenResponse)
 }
@@ -8,12 +8,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"pvz-service/internal/api/respond"
 	"pvz-service/internal/domain/models"
 	"pvz-service/internal/logger"
 )
@@ -35,8 +37,8 @@ func (m *MockAuthService) Login(ctx context.Context, email, password string) (st
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockAuthService) GenerateDummyToken(role models.UserRole) (string, error) {
-	args := m.Called(role)
+func (m *MockAuthService) GenerateDummyToken(role models.UserRole, ttl time.Duration) (string, error) {
+	args := m.Called(role, ttl)
 	return args.String(0), args.Error(1)
 }
 
@@ -48,6 +50,22 @@ func (m *MockAuthService) ValidateToken(token string) (*models.User, error) {
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
+func (m *MockAuthService) ValidateAPIKey(key string) (*models.User, error) {
+	args := m.Called(key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockAuthService) IntrospectToken(token string) (*models.TokenIntrospection, error) {
+	args := m.Called(token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TokenIntrospection), args.Error(1)
+}
+
 func setupTest() (*AuthHandler, *MockAuthService) {
 	mockService := new(MockAuthService)
 	handler := NewAuthHandler(mockService)
@@ -116,7 +134,7 @@ func TestRegister_InvalidJSON(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var response ErrorResponse
+	var response respond.ErrorResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
 	assert.Contains(t, response.Error, "Invalid request format")
@@ -140,7 +158,7 @@ func TestRegister_ValidationError(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var response ErrorResponse
+	var response respond.ErrorResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
 	assert.Contains(t, response.Error, "Validation failed")
@@ -171,7 +189,7 @@ func TestRegister_ServiceError(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var response ErrorResponse
+	var response respond.ErrorResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
 	assert.Equal(t, "Registration failed", response.Error)
@@ -179,6 +197,35 @@ func TestRegister_ServiceError(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestRegister_BlockedEmailDomain(t *testing.T) {
+	setupTestContext()
+	handler, mockService := setupTest()
+
+	reqBody := models.AuthRequest{
+		Email:    "user@mailinator.com",
+		Password: "password123",
+		Role:     models.RoleEmployee,
+	}
+
+	jsonBody, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("POST", "/auth/register", bytes.NewBuffer(jsonBody))
+	w := httptest.NewRecorder()
+
+	mockService.On("Register", mock.Anything, reqBody.Email, reqBody.Password, reqBody.Role).
+		Return(nil, models.ErrEmailDomainBlocked)
+
+	handler.Register(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response respond.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Email domain is not allowed", response.Error)
+	assert.Equal(t, "email_domain_blocked", response.Code)
+
+	mockService.AssertExpectations(t)
+}
+
 func TestLogin_Success(t *testing.T) {
 	setupTestContext()
 	handler, mockService := setupTest()
@@ -222,7 +269,7 @@ func TestLogin_InvalidJSON(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var response ErrorResponse
+	var response respond.ErrorResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
 	assert.Contains(t, response.Error, "Invalid request format")
@@ -245,7 +292,7 @@ func TestLogin_ValidationError(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var response ErrorResponse
+	var response respond.ErrorResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
 	assert.Contains(t, response.Error, "Validation failed")
@@ -274,7 +321,7 @@ func TestLogin_ServiceError(t *testing.T) {
 
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 
-	var response ErrorResponse
+	var response respond.ErrorResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
 	assert.Equal(t, "Invalid credentials", response.Error)
@@ -299,7 +346,7 @@ func TestDummyLogin_Success(t *testing.T) {
 	req := httptest.NewRequest("POST", "/auth/dummy-login", bytes.NewBuffer(jsonBody))
 	w := httptest.NewRecorder()
 
-	mockService.On("GenerateDummyToken", role).Return(token, nil)
+	mockService.On("GenerateDummyToken", role, time.Duration(0)).Return(token, nil)
 
 	handler.DummyLogin(w, req)
 
@@ -313,6 +360,34 @@ func TestDummyLogin_Success(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestDummyLogin_WithTTL(t *testing.T) {
+	setupTestContext()
+	handler, mockService := setupTest()
+
+	req := httptest.NewRequest("POST", "/auth/dummy-login", bytes.NewBufferString(`{"role":"moderator","ttlSeconds":120}`))
+	w := httptest.NewRecorder()
+
+	mockService.On("GenerateDummyToken", models.RoleModerator, 120*time.Second).Return("jwt.short.token", nil)
+
+	handler.DummyLogin(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestDummyLogin_NegativeTTL(t *testing.T) {
+	setupTestContext()
+	handler, mockService := setupTest()
+
+	req := httptest.NewRequest("POST", "/auth/dummy-login", bytes.NewBufferString(`{"role":"employee","ttlSeconds":-5}`))
+	w := httptest.NewRecorder()
+
+	handler.DummyLogin(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "GenerateDummyToken", mock.Anything, mock.Anything)
+}
+
 func TestDummyLogin_InvalidJSON(t *testing.T) {
 	setupTestContext()
 	handler, _ := setupTest()
@@ -325,7 +400,7 @@ func TestDummyLogin_InvalidJSON(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var response ErrorResponse
+	var response respond.ErrorResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
 	assert.Contains(t, response.Error, "Invalid request format")
@@ -349,7 +424,7 @@ func TestDummyLogin_InvalidRole(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var response ErrorResponse
+	var response respond.ErrorResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
 	assert.Contains(t, response.Error, "Invalid role")
@@ -371,17 +446,100 @@ func TestDummyLogin_ServiceError(t *testing.T) {
 	req := httptest.NewRequest("POST", "/auth/dummy-login", bytes.NewBuffer(jsonBody))
 	w := httptest.NewRecorder()
 
-	mockService.On("GenerateDummyToken", role).
+	mockService.On("GenerateDummyToken", role, time.Duration(0)).
 		Return("", errors.New("token generation failed"))
 
 	handler.DummyLogin(w, req)
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 
-	var response ErrorResponse
+	var response respond.ErrorResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
 	assert.Equal(t, "Failed to generate token", response.Error)
 
 	mockService.AssertExpectations(t)
 }
+
+func TestIntrospect_ValidToken(t *testing.T) {
+	setupTestContext()
+	handler, mockService := setupTest()
+
+	userID := uuid.New()
+	exp := time.Now().Add(time.Hour).Unix()
+
+	req := httptest.NewRequest("GET", "/auth/introspect", nil)
+	req.Header.Set("Authorization", "Bearer valid.jwt.token")
+	w := httptest.NewRecorder()
+
+	mockService.On("IntrospectToken", "valid.jwt.token").Return(&models.TokenIntrospection{
+		Active: true,
+		UserID: &userID,
+		Role:   models.RoleEmployee,
+		Exp:    exp,
+	}, nil)
+
+	handler.Introspect(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+	assert.Equal(t, true, response["active"])
+	assert.Equal(t, userID.String(), response["userId"])
+	assert.Equal(t, string(models.RoleEmployee), response["role"])
+	assert.Equal(t, float64(exp), response["exp"])
+
+	mockService.AssertExpectations(t)
+}
+
+func TestIntrospect_InactiveTokens(t *testing.T) {
+	testCases := []struct {
+		name       string
+		authHeader string
+		serviceErr error
+	}{
+		{
+			name:       "Expired token",
+			authHeader: "Bearer expired.jwt.token",
+			serviceErr: errors.New("token is expired by 1h0m0s"),
+		},
+		{
+			name:       "Malformed token",
+			authHeader: "Bearer malformed",
+			serviceErr: errors.New("token contains an invalid number of segments"),
+		},
+		{
+			name:       "Missing bearer prefix",
+			authHeader: "Basic dXNlcjpwYXNz",
+		},
+		{
+			name: "Missing header",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			setupTestContext()
+			handler, mockService := setupTest()
+
+			req := httptest.NewRequest("GET", "/auth/introspect", nil)
+			if tc.authHeader != "" {
+				req.Header.Set("Authorization", tc.authHeader)
+			}
+			w := httptest.NewRecorder()
+
+			if tc.serviceErr != nil {
+				mockService.On("IntrospectToken", mock.Anything).Return(nil, tc.serviceErr)
+			}
+
+			handler.Introspect(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.JSONEq(t, `{"active":false}`, w.Body.String())
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
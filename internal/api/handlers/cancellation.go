@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"log/slog"
+
+	"pvz-service/internal/api/respond"
+)
+
+// logRequestError пишет ошибку обработчика уровнем ERROR. Отмена контекста сбоем
+// сервиса не является, поэтому такие ошибки пишутся уровнем INFO
+func logRequestError(log *slog.Logger, msg string, err error, args ...any) {
+	args = append(args, "error", err)
+	if _, _, ok := respond.ContextErrorStatus(err); ok {
+		log.Info(msg, args...)
+		return
+	}
+	log.Error(msg, args...)
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"pvz-service/internal/domain/interfaces"
+	"pvz-service/internal/logger"
+)
+
+type EventsHandler struct {
+	events interfaces.EventSubscriber
+}
+
+func NewEventsHandler(events interfaces.EventSubscriber) *EventsHandler {
+	return &EventsHandler{
+		events: events,
+	}
+}
+
+// StreamPVZEvents отдает события ПВЗ потоком Server-Sent Events, пока клиент не отключится
+func (h *EventsHandler) StreamPVZEvents(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	pvzID, ok := pathUUID(w, r, "pvzId")
+	if !ok {
+		return
+	}
+
+	// Поток живет дольше WriteTimeout сервера, поэтому дедлайн записи снимается
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		log.Warn("не удалось снять дедлайн записи для потока событий", "error", err)
+	}
+
+	events, unsubscribe := h.events.Subscribe(pvzID)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		log.Error("поток событий не поддерживается", "error", err)
+		return
+	}
+
+	log.Info("клиент подписался на события ПВЗ", "pvz_id", pvzID)
+
+	for {
+		select {
+		case <-r.Context().Done():
+			log.Info("клиент отписался от событий ПВЗ", "pvz_id", pvzID)
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+
+			data, err := json.Marshal(event)
+			if err != nil {
+				log.Error("ошибка сериализации события", "error", err, "type", event.Type)
+				continue
+			}
+
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				log.Warn("ошибка отправки события клиенту", "error", err, "pvz_id", pvzID)
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				log.Warn("ошибка отправки события клиенту", "error", err, "pvz_id", pvzID)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/domain/models"
+	"pvz-service/internal/notifier"
+)
+
+func TestStreamPVZEvents(t *testing.T) {
+	bus := notifier.NewEventBus()
+	handler := NewEventsHandler(bus)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/pvz/{pvzId}/events", handler.StreamPVZEvents).Methods("GET")
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	pvzID := uuid.New()
+	productID := uuid.New()
+
+	resp, err := http.Get(server.URL + "/pvz/" + pvzID.String() + "/events")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	// Заголовки отправляются после подписки, поэтому событие не потеряется
+	bus.Publish(models.Event{
+		Type:  models.EventProductAdded,
+		PVZID: pvzID,
+		Data:  models.Product{ID: productID, Type: models.TypeElectronics},
+	})
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	var frame []string
+	timeout := time.After(time.Second)
+	for len(frame) < 2 {
+		select {
+		case line, ok := <-lines:
+			require.True(t, ok, "поток закрыт до получения события")
+			if line != "" {
+				frame = append(frame, line)
+			}
+		case <-timeout:
+			t.Fatal("событие не получено")
+		}
+	}
+
+	assert.Equal(t, "event: product_added", frame[0])
+	assert.True(t, strings.HasPrefix(frame[1], "data: "))
+	assert.Contains(t, frame[1], productID.String())
+}
+
+func TestStreamPVZEvents_InvalidPVZID(t *testing.T) {
+	handler := NewEventsHandler(notifier.NewEventBus())
+
+	req := httptest.NewRequest("GET", "/pvz/not-a-uuid/events", nil)
+	req = mux.SetURLVars(req, map[string]string{"pvzId": "not-a-uuid"})
+	w := httptest.NewRecorder()
+
+	handler.StreamPVZEvents(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
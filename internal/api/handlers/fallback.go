@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"net/http"
+
+	"pvz-service/internal/api/respond"
+)
+
+// NotFound отвечает JSON-ошибкой 404 для неизвестных путей
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	respond.ErrorWithCode(w, r, http.StatusNotFound, respond.CodeNotFound, "Resource not found", nil)
+}
+
+// MethodNotAllowed отвечает JSON-ошибкой 405, когда путь существует, но метод не поддерживается
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	respond.ErrorWithCode(w, r, http.StatusMethodNotAllowed, respond.CodeMethodNotAllowed, "Method not allowed", nil)
+}
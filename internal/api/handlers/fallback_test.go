@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/api/respond"
+)
+
+func decodeErrorResponse(t *testing.T, w *httptest.ResponseRecorder) respond.ErrorResponse {
+	var response respond.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response
+}
+
+func TestErrorResponse_RussianAcceptLanguage(t *testing.T) {
+	t.Run("NotFound", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/unknown", nil)
+		req.Header.Set("Accept-Language", "ru-RU,ru;q=0.9,en;q=0.8")
+		w := httptest.NewRecorder()
+
+		NotFound(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "ru", w.Header().Get("Content-Language"))
+		response := decodeErrorResponse(t, w)
+		assert.Equal(t, "Ресурс не найден", response.Error)
+		assert.Equal(t, "not_found", response.Code)
+	})
+
+	t.Run("Invalid PVZ ID", func(t *testing.T) {
+		handler, _ := setupReceptionTest()
+
+		req := httptest.NewRequest("POST", "/pvz/bad/close_last_reception", nil)
+		req.Header.Set("Accept-Language", "ru")
+		req = mux.SetURLVars(req, map[string]string{"pvzId": "bad"})
+		w := httptest.NewRecorder()
+
+		handler.CloseLastReception(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		response := decodeErrorResponse(t, w)
+		assert.Equal(t, "Некорректный формат идентификатора ПВЗ", response.Error)
+		assert.Equal(t, "invalid_pvz_id", response.Code)
+	})
+}
+
+func TestErrorResponse_DefaultsToEnglish(t *testing.T) {
+	req := httptest.NewRequest("GET", "/unknown", nil)
+	req.Header.Set("Accept-Language", "de-DE")
+	w := httptest.NewRecorder()
+
+	MethodNotAllowed(w, req)
+
+	assert.Equal(t, "en", w.Header().Get("Content-Language"))
+	response := decodeErrorResponse(t, w)
+	assert.Equal(t, "Method not allowed", response.Error)
+	assert.Equal(t, "method_not_allowed", response.Code)
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"pvz-service/internal/api/respond"
+	"pvz-service/internal/domain/models"
+	"pvz-service/internal/logger"
+)
+
+// defaultMaxPage - наибольший номер страницы в списках по умолчанию. Дальние страницы
+// требуют OFFSET-сканирования всей выборки, поэтому клиенту предлагается сузить ее фильтрами
+const defaultMaxPage = 1000
+
+// maxPageBound - абсолютный предел page независимо от MAX_LIST_PAGE: вместе с limit не
+// более maxHTTPListLimit он исключает переполнение (page-1)*limit при расчете OFFSET
+const maxPageBound = 1_000_000
+
+// maxHTTPListLimit - наибольший limit в списках; большие значения заменяются значением по умолчанию
+const maxHTTPListLimit = 30
+
+// defaultListLimit - limit по умолчанию в списках
+const defaultListLimit = 10
+
+// parsePagination разбирает page и limit списка. Нечисловые и неположительные значения
+// заменяются значениями по умолчанию, а числа за пределами maxPageBound или int
+// отклоняются ответом 400, как и страница за пределом maxPage. При ok=false ответ уже отправлен
+func parsePagination(w http.ResponseWriter, r *http.Request, pageStr, limitStr string, maxPage int) (page, limit int, ok bool) {
+	log := logger.FromContext(r.Context())
+
+	page = 1
+	limit = defaultListLimit
+
+	if pageStr != "" {
+		p, err := strconv.Atoi(pageStr)
+		switch {
+		case errors.Is(err, strconv.ErrRange) || p > maxPageBound:
+			log.Warn("page вне допустимого диапазона", "page", pageStr, "max_page_bound", maxPageBound)
+			respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodePageOutOfRange, respond.PageOutOfRangeMessage, nil)
+			return 0, 0, false
+		case err != nil:
+			log.Warn("некорректное значение page", "page", pageStr, "error", err)
+		case p > 0:
+			page = p
+		}
+	}
+
+	if limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		switch {
+		case errors.Is(err, strconv.ErrRange):
+			log.Warn("limit вне допустимого диапазона", "limit", limitStr)
+			respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodePageOutOfRange, respond.PageOutOfRangeMessage, nil)
+			return 0, 0, false
+		case err != nil:
+			log.Warn("некорректное значение limit", "limit", limitStr, "error", err)
+		case l > 0 && l <= maxHTTPListLimit:
+			limit = l
+		}
+	}
+
+	if pageExceedsMax(page, maxPage) {
+		log.Warn("запрошена слишком дальняя страница", "page", page, "max_page", maxPage)
+		respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodePageTooLarge, respond.PageTooLargeMessage, nil)
+		return 0, 0, false
+	}
+
+	return page, limit, true
+}
+
+// pageExceedsMax сообщает, превышает ли page ограничение. Ноль и меньше - без ограничения
+func pageExceedsMax(page, maxPage int) bool {
+	return maxPage > 0 && page > maxPage
+}
+
+// withTotalParam - query-параметр, которым клиент отказывается от точного total
+const withTotalParam = "withTotal"
+
+// parseWithTotal сообщает, нужен ли клиенту точный total. Только withTotal=false
+// отключает COUNT; отсутствующее или некорректное значение оставляет подсчет
+func parseWithTotal(r *http.Request) bool {
+	value := r.URL.Query().Get(withTotalParam)
+	if value == "" {
+		return true
+	}
+
+	withTotal, err := strconv.ParseBool(value)
+	if err != nil {
+		logger.FromContext(r.Context()).Warn("некорректное значение withTotal", "withTotal", value, "error", err)
+		return true
+	}
+	return withTotal
+}
+
+// paginationEnvelope строит блок pagination ответа. С точным total формат прежний
+// (page, limit, total, pageCount); без него - page, limit и hasNext
+func paginationEnvelope(page, limit, total int, withTotal bool) interface{} {
+	if !withTotal {
+		return map[string]interface{}{
+			"page":    page,
+			"limit":   limit,
+			"hasNext": models.HasNextPage(page, limit, total),
+		}
+	}
+
+	return map[string]int{
+		"page":      page,
+		"limit":     limit,
+		"total":     total,
+		"pageCount": (total + limit - 1) / limit,
+	}
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/api/respond"
+)
+
+func TestParsePagination(t *testing.T) {
+	testCases := []struct {
+		name          string
+		page          string
+		limit         string
+		maxPage       int
+		expectedOK    bool
+		expectedPage  int
+		expectedLimit int
+		expectedError string
+	}{
+		{name: "Defaults", expectedOK: true, expectedPage: 1, expectedLimit: 10},
+		{name: "Explicit Values", page: "3", limit: "25", expectedOK: true, expectedPage: 3, expectedLimit: 25},
+		{name: "Non Numeric Falls Back", page: "abc", limit: "xyz", expectedOK: true, expectedPage: 1, expectedLimit: 10},
+		{name: "Negative Falls Back", page: "-5", limit: "-1", expectedOK: true, expectedPage: 1, expectedLimit: 10},
+		{name: "Limit Above Max Falls Back", limit: "100", expectedOK: true, expectedPage: 1, expectedLimit: 10},
+		{name: "Page At Bound", page: "1000000", expectedOK: true, expectedPage: 1000000, expectedLimit: 10},
+		{name: "Page Above Bound", page: "1000001", expectedError: respond.PageOutOfRangeMessage},
+		{name: "Page Overflows Int", page: "99999999999999999999", expectedError: respond.PageOutOfRangeMessage},
+		{name: "Limit Overflows Int", limit: "99999999999999999999", expectedError: respond.PageOutOfRangeMessage},
+		{name: "Page Above Max Page", page: "1001", maxPage: 1000, expectedError: respond.PageTooLargeMessage},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/pvz", nil)
+			w := httptest.NewRecorder()
+
+			page, limit, ok := parsePagination(w, req, tc.page, tc.limit, tc.maxPage)
+
+			assert.Equal(t, tc.expectedOK, ok)
+			if tc.expectedOK {
+				assert.Equal(t, tc.expectedPage, page)
+				assert.Equal(t, tc.expectedLimit, limit)
+				return
+			}
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var response respond.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tc.expectedError, response.Error)
+		})
+	}
+}
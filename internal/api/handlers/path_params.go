@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"net/http"
+
+	"pvz-service/internal/api/middleware"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// pathUUID возвращает переменную пути name, уже проверенную middleware.ValidateUUIDVars.
+// Если маршрут зарегистрирован без проверки, ошибка разбора дает тот же ответ 400.
+// При ok=false ответ уже отправлен
+func pathUUID(w http.ResponseWriter, r *http.Request, name string) (uuid.UUID, bool) {
+	id, err := middleware.UUIDVar(r, name)
+	if err != nil {
+		middleware.WriteInvalidUUID(w, r, name, mux.Vars(r)[name])
+		return uuid.Nil, false
+	}
+	return id, true
+}
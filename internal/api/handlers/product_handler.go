@@ -2,19 +2,22 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 
+	"pvz-service/internal/api/respond"
 	"pvz-service/internal/api/validator"
 	"pvz-service/internal/domain/interfaces"
 	"pvz-service/internal/domain/models"
 	"pvz-service/internal/logger"
 
-	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 )
 
 type ProductHandler struct {
 	productService interfaces.ProductService
+	maxPage        int
 }
 
 // SuccessResponse для стандартизации успешных ответов
@@ -25,9 +28,16 @@ type SuccessResponse struct {
 func NewProductHandler(productService interfaces.ProductService) *ProductHandler {
 	return &ProductHandler{
 		productService: productService,
+		maxPage:        defaultMaxPage,
 	}
 }
 
+// WithMaxPage ограничивает номер страницы в списке товаров ПВЗ. Ноль - без ограничения
+func (h *ProductHandler) WithMaxPage(maxPage int) *ProductHandler {
+	h.maxPage = maxPage
+	return h
+}
+
 func (h *ProductHandler) AddProduct(w http.ResponseWriter, r *http.Request) {
 	log := logger.FromContext(r.Context())
 	log.Info("запрос на добавление товара")
@@ -35,7 +45,7 @@ func (h *ProductHandler) AddProduct(w http.ResponseWriter, r *http.Request) {
 	var req models.ProductCreateRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Warn("ошибка декодирования JSON", "error", err)
-		sendErrorResponse(w, "Invalid request format", http.StatusBadRequest, err)
+		respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeInvalidRequest, "Invalid request format", err)
 		return
 	}
 
@@ -50,7 +60,7 @@ func (h *ProductHandler) AddProduct(w http.ResponseWriter, r *http.Request) {
 			"product_type", req.Type,
 			"validation_errors", validator.FormatValidationErrors(err),
 		)
-		sendErrorResponse(w, "Validation failed: "+validator.FormatValidationErrors(err), http.StatusBadRequest, nil)
+		respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeValidationFailed, "Validation failed: "+validator.FormatValidationErrors(err), nil)
 		return
 	}
 
@@ -61,7 +71,11 @@ func (h *ProductHandler) AddProduct(w http.ResponseWriter, r *http.Request) {
 			"product_type", req.Type,
 			"error", err,
 		)
-		sendErrorResponse(w, "Unable to add product", http.StatusBadRequest, err)
+		if errors.Is(err, models.ErrReceptionFull) {
+			respond.ErrorWithCode(w, r, http.StatusConflict, respond.CodeReceptionFull, "Reception is full", err)
+			return
+		}
+		respond.Error(w, r, http.StatusBadRequest, "Unable to add product", err)
 		return
 	}
 
@@ -71,9 +85,8 @@ func (h *ProductHandler) AddProduct(w http.ResponseWriter, r *http.Request) {
 		"product_type", product.Type,
 	)
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(product)
+	w.Header().Set("Location", "/products/"+product.ID.String())
+	respond.JSON(w, r, http.StatusCreated, product)
 }
 
 func (h *ProductHandler) DeleteLastProduct(w http.ResponseWriter, r *http.Request) {
@@ -84,23 +97,257 @@ func (h *ProductHandler) DeleteLastProduct(w http.ResponseWriter, r *http.Reques
 
 	log.Info("запрос на удаление последнего товара", "pvz_id", pvzIDStr)
 
-	pvzID, err := uuid.Parse(pvzIDStr)
-	if err != nil {
-		log.Warn("некорректный формат UUID для ПВЗ", "pvz_id", pvzIDStr, "error", err)
-		sendErrorResponse(w, "Invalid PVZ ID format", http.StatusBadRequest, err)
+	pvzID, ok := pathUUID(w, r, "pvzId")
+	if !ok {
 		return
 	}
 
-	err = h.productService.DeleteLastProduct(r.Context(), pvzID)
+	err := h.productService.DeleteLastProduct(r.Context(), pvzID)
 	if err != nil {
 		log.Error("ошибка удаления последнего товара", "pvz_id", pvzID, "error", err)
-		sendErrorResponse(w, "Unable to delete product", http.StatusBadRequest, err)
+		respond.Error(w, r, http.StatusBadRequest, "Unable to delete product", err)
 		return
 	}
 
 	log.Info("последний товар успешно удален", "pvz_id", pvzID)
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(SuccessResponse{Message: "Product successfully deleted"})
+	respond.JSON(w, r, http.StatusOK, SuccessResponse{Message: "Product successfully deleted"})
+}
+
+func (h *ProductHandler) ClearReception(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	vars := mux.Vars(r)
+	pvzIDStr := vars["pvzId"]
+
+	log.Info("запрос на очистку открытой приемки", "pvz_id", pvzIDStr)
+
+	pvzID, ok := pathUUID(w, r, "pvzId")
+	if !ok {
+		return
+	}
+
+	deleted, err := h.productService.ClearReception(r.Context(), pvzID)
+	if err != nil {
+		log.Error("ошибка очистки приемки", "pvz_id", pvzID, "error", err)
+		respond.Error(w, r, http.StatusBadRequest, "Unable to clear reception", err)
+		return
+	}
+
+	log.Info("приемка успешно очищена", "pvz_id", pvzID, "deleted", deleted)
+
+	respond.JSON(w, r, http.StatusOK, models.ClearReceptionResponse{Deleted: deleted})
+}
+
+func (h *ProductHandler) GetProductByID(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	idStr := mux.Vars(r)["id"]
+	log.Info("запрос товара", "product_id", idStr)
+
+	productID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	product, err := h.productService.GetProductByID(r.Context(), productID)
+	if err != nil {
+		if errors.Is(err, models.ErrProductNotFound) {
+			log.Warn("товар не найден", "product_id", productID)
+			respond.ErrorWithCode(w, r, http.StatusNotFound, respond.CodeProductNotFound, "Product not found", err)
+			return
+		}
+		logRequestError(log, "ошибка получения товара", err, "product_id", productID)
+		respond.Error(w, r, http.StatusInternalServerError, "Unable to get product", err)
+		return
+	}
+
+	log.Info("товар успешно получен", "product_id", productID)
+
+	respond.JSON(w, r, http.StatusOK, product)
+}
+
+func (h *ProductHandler) UpdateProductType(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	idStr := mux.Vars(r)["id"]
+	log.Info("запрос на изменение типа товара", "product_id", idStr)
+
+	productID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req models.ProductUpdateTypeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("ошибка декодирования JSON", "error", err)
+		respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeInvalidRequest, "Invalid request format", err)
+		return
+	}
+
+	if err := validator.ValidateStruct(req); err != nil {
+		log.Warn("ошибка валидации типа товара",
+			"product_type", req.Type,
+			"validation_errors", validator.FormatValidationErrors(err),
+		)
+		respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeValidationFailed, "Validation failed: "+validator.FormatValidationErrors(err), nil)
+		return
+	}
+
+	err := h.productService.UpdateProductType(r.Context(), productID, req.Type)
+	if err != nil {
+		log.Error("ошибка изменения типа товара", "product_id", productID, "error", err)
+		switch {
+		case errors.Is(err, models.ErrProductNotFound):
+			respond.ErrorWithCode(w, r, http.StatusNotFound, respond.CodeProductNotFound, "Product not found", err)
+		case errors.Is(err, models.ErrReceptionClosed):
+			respond.ErrorWithCode(w, r, http.StatusConflict, respond.CodeReceptionClosed, "Reception is closed", err)
+		default:
+			respond.Error(w, r, http.StatusBadRequest, "Unable to update product", err)
+		}
+		return
+	}
+
+	log.Info("тип товара успешно изменен", "product_id", productID, "product_type", req.Type)
+
+	respond.JSON(w, r, http.StatusOK, SuccessResponse{Message: "Product type successfully updated"})
+}
+
+// MoveProduct переносит товар в открытую приемку другого ПВЗ (moderator)
+func (h *ProductHandler) MoveProduct(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	idStr := mux.Vars(r)["id"]
+	log.Info("запрос на перенос товара", "product_id", idStr)
+
+	productID, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req models.ProductMoveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("ошибка декодирования JSON", "error", err)
+		respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeInvalidRequest, "Invalid request format", err)
+		return
+	}
+
+	if err := validator.ValidateStruct(req); err != nil {
+		log.Warn("ошибка валидации запроса переноса товара",
+			"validation_errors", validator.FormatValidationErrors(err),
+		)
+		respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeValidationFailed, "Validation failed: "+validator.FormatValidationErrors(err), nil)
+		return
+	}
+
+	err := h.productService.MoveProduct(r.Context(), productID, req.PVZID)
+	if err != nil {
+		log.Error("ошибка переноса товара", "product_id", productID, "pvz_id", req.PVZID, "error", err)
+		switch {
+		case errors.Is(err, models.ErrProductNotFound):
+			respond.ErrorWithCode(w, r, http.StatusNotFound, respond.CodeProductNotFound, "Product not found", err)
+		case errors.Is(err, models.ErrPVZNotFound):
+			respond.ErrorWithCode(w, r, http.StatusNotFound, respond.CodePVZNotFound, "PVZ not found", err)
+		case errors.Is(err, models.ErrReceptionClosed), errors.Is(err, models.ErrReceptionNotFound):
+			respond.ErrorWithCode(w, r, http.StatusConflict, respond.CodeReceptionClosed, "Reception is closed", err)
+		default:
+			respond.Error(w, r, http.StatusBadRequest, "Unable to move product", err)
+		}
+		return
+	}
+
+	log.Info("товар успешно перенесен", "product_id", productID, "pvz_id", req.PVZID)
+
+	respond.JSON(w, r, http.StatusOK, SuccessResponse{Message: "Product successfully moved"})
+}
+
+func (h *ProductHandler) GetRecentProducts(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	pvzIDStr := mux.Vars(r)["pvzId"]
+	log.Info("запрос последних товаров ПВЗ", "pvz_id", pvzIDStr)
+
+	pvzID, ok := pathUUID(w, r, "pvzId")
+	if !ok {
+		return
+	}
+
+	n := 0
+	if nStr := r.URL.Query().Get("n"); nStr != "" {
+		var err error
+		n, err = strconv.Atoi(nStr)
+		if err != nil {
+			log.Warn("некорректное значение n", "n", nStr, "error", err)
+			respond.Error(w, r, http.StatusBadRequest, "Invalid n parameter", err)
+			return
+		}
+	}
+
+	products, err := h.productService.GetRecentProducts(r.Context(), pvzID, n)
+	if err != nil {
+		logRequestError(log, "ошибка получения последних товаров", err, "pvz_id", pvzID)
+		respond.Error(w, r, http.StatusInternalServerError, "Error retrieving recent products", err)
+		return
+	}
+
+	log.Info("последние товары ПВЗ получены", "pvz_id", pvzID, "count", len(products))
+
+	respond.JSON(w, r, http.StatusOK, products)
+}
+
+// ListPVZProducts возвращает товары всех приемок ПВЗ постранично, с фильтром по типу
+func (h *ProductHandler) ListPVZProducts(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	pvzIDStr := mux.Vars(r)["pvzId"]
+	query := r.URL.Query()
+	pageStr := query.Get("page")
+	limitStr := query.Get("limit")
+	productType := models.ProductType(query.Get("type"))
+
+	log.Info("запрос товаров всех приемок ПВЗ",
+		"pvz_id", pvzIDStr,
+		"page", pageStr,
+		"limit", limitStr,
+		"type", productType,
+	)
+
+	pvzID, ok := pathUUID(w, r, "pvzId")
+	if !ok {
+		return
+	}
+
+	if productType != "" && !productType.Valid() {
+		log.Warn("недопустимое значение type", "type", productType)
+		respond.Error(w, r, http.StatusBadRequest, "Invalid type value. Use электроника, одежда or обувь", nil)
+		return
+	}
+
+	page, limit, ok := parsePagination(w, r, pageStr, limitStr, h.maxPage)
+	if !ok {
+		return
+	}
+
+	products, total, err := h.productService.ListPVZProducts(r.Context(), pvzID, productType, page, limit)
+	if err != nil {
+		if errors.Is(err, models.ErrPVZNotFound) {
+			log.Warn("ПВЗ не найден", "pvz_id", pvzID)
+			respond.ErrorWithCode(w, r, http.StatusNotFound, respond.CodePVZNotFound, "PVZ not found", err)
+			return
+		}
+		logRequestError(log, "ошибка получения товаров ПВЗ", err, "pvz_id", pvzID)
+		respond.Error(w, r, http.StatusInternalServerError, "Failed to retrieve products", err)
+		return
+	}
+
+	if products == nil {
+		products = []*models.Product{}
+	}
+
+	log.Info("товары всех приемок ПВЗ получены", "pvz_id", pvzID, "count", len(products), "total", total)
+
+	respond.JSON(w, r, http.StatusOK, map[string]interface{}{
+		"data":       products,
+		"pagination": paginationEnvelope(page, limit, total, true),
+	})
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"pvz-service/internal/api/respond"
 	"pvz-service/internal/domain/models"
 	"pvz-service/internal/logger"
 )
@@ -37,8 +38,47 @@ func (m *MockProductService) DeleteLastProduct(ctx context.Context, pvzID uuid.U
 	return args.Error(0)
 }
 
-func (m *MockProductService) GetProductsByReceptionID(ctx context.Context, receptionID uuid.UUID, page, limit int) ([]*models.Product, int, error) {
-	args := m.Called(ctx, receptionID, page, limit)
+func (m *MockProductService) GetProductByID(ctx context.Context, productID uuid.UUID) (*models.Product, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *MockProductService) UpdateProductType(ctx context.Context, productID uuid.UUID, productType models.ProductType) error {
+	args := m.Called(ctx, productID, productType)
+	return args.Error(0)
+}
+
+func (m *MockProductService) MoveProduct(ctx context.Context, productID, targetPVZID uuid.UUID) error {
+	args := m.Called(ctx, productID, targetPVZID)
+	return args.Error(0)
+}
+
+func (m *MockProductService) GetRecentProducts(ctx context.Context, pvzID uuid.UUID, n int) ([]*models.Product, error) {
+	args := m.Called(ctx, pvzID, n)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Product), args.Error(1)
+}
+
+func (m *MockProductService) ClearReception(ctx context.Context, pvzID uuid.UUID) (int, error) {
+	args := m.Called(ctx, pvzID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockProductService) GetProductsByReceptionID(ctx context.Context, receptionID uuid.UUID, page, limit int, sort string) ([]*models.Product, int, error) {
+	args := m.Called(ctx, receptionID, page, limit, sort)
+	return args.Get(0).([]*models.Product), args.Int(1), args.Error(2)
+}
+
+func (m *MockProductService) ListPVZProducts(ctx context.Context, pvzID uuid.UUID, productType models.ProductType, page, limit int) ([]*models.Product, int, error) {
+	args := m.Called(ctx, pvzID, productType, page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
 	return args.Get(0).([]*models.Product), args.Int(1), args.Error(2)
 }
 
@@ -79,6 +119,7 @@ func TestAddProduct_Success(t *testing.T) {
 	handler.AddProduct(w, req)
 
 	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "/products/"+productID.String(), w.Header().Get("Location"))
 
 	var response models.Product
 	err := json.Unmarshal(w.Body.Bytes(), &response)
@@ -102,7 +143,7 @@ func TestAddProduct_InvalidJSON(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var response ErrorResponse
+	var response respond.ErrorResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
 	assert.Contains(t, response.Error, "Invalid request format")
@@ -126,7 +167,7 @@ func TestAddProduct_ValidationError(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var response ErrorResponse
+	var response respond.ErrorResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
 	assert.Contains(t, response.Error, "Validation failed")
@@ -154,7 +195,7 @@ func TestAddProduct_ServiceError(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var response ErrorResponse
+	var response respond.ErrorResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
 	assert.Equal(t, "Unable to add product", response.Error)
@@ -162,6 +203,34 @@ func TestAddProduct_ServiceError(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestAddProduct_ReceptionFull(t *testing.T) {
+	handler, mockService := setupProductTest()
+
+	pvzID := uuid.New()
+	reqBody := models.ProductCreateRequest{
+		PVZID: pvzID,
+		Type:  models.TypeFootwear,
+	}
+
+	jsonBody, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("POST", "/products", bytes.NewBuffer(jsonBody))
+	req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
+	w := httptest.NewRecorder()
+
+	mockService.On("AddProduct", mock.Anything, pvzID, models.TypeFootwear).Return(nil, models.ErrReceptionFull)
+
+	handler.AddProduct(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var response respond.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+	assert.Equal(t, "Reception is full", response.Error)
+
+	mockService.AssertExpectations(t)
+}
+
 func TestDeleteLastProduct_Success(t *testing.T) {
 	handler, mockService := setupProductTest()
 
@@ -208,10 +277,10 @@ func TestDeleteLastProduct_InvalidUUID(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var response ErrorResponse
+	var response respond.ErrorResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
-	assert.Contains(t, response.Error, "Invalid PVZ ID format")
+	assert.Contains(t, response.Error, "Invalid pvzId format")
 }
 
 func TestDeleteLastProduct_ServiceError(t *testing.T) {
@@ -235,10 +304,350 @@ func TestDeleteLastProduct_ServiceError(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var response ErrorResponse
+	var response respond.ErrorResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
 	assert.Equal(t, "Unable to delete product", response.Error)
 
 	mockService.AssertExpectations(t)
 }
+
+func TestClearReception_Success(t *testing.T) {
+	handler, mockService := setupProductTest()
+
+	pvzID := uuid.New()
+
+	req := httptest.NewRequest("POST", "/pvz/"+pvzID.String()+"/clear_reception", nil)
+	req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
+	req = mux.SetURLVars(req, map[string]string{"pvzId": pvzID.String()})
+
+	w := httptest.NewRecorder()
+
+	mockService.On("ClearReception", mock.Anything, pvzID).Return(3, nil)
+
+	handler.ClearReception(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.ClearReceptionResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+	assert.Equal(t, 3, response.Deleted)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestClearReception_ServiceError(t *testing.T) {
+	handler, mockService := setupProductTest()
+
+	pvzID := uuid.New()
+
+	req := httptest.NewRequest("POST", "/pvz/"+pvzID.String()+"/clear_reception", nil)
+	req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
+	req = mux.SetURLVars(req, map[string]string{"pvzId": pvzID.String()})
+
+	w := httptest.NewRecorder()
+
+	mockService.On("ClearReception", mock.Anything, pvzID).Return(0, errors.New("no open reception found for this pvz"))
+
+	handler.ClearReception(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response respond.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+	assert.Equal(t, "Unable to clear reception", response.Error)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetProductByID_Success(t *testing.T) {
+	handler, mockService := setupProductTest()
+
+	productID := uuid.New()
+	product := &models.Product{
+		ID:          productID,
+		DateTime:    time.Now(),
+		Type:        models.TypeElectronics,
+		ReceptionID: uuid.New(),
+		SequenceNum: 3,
+	}
+
+	req := httptest.NewRequest("GET", "/products/"+productID.String(), nil)
+	req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
+	req = mux.SetURLVars(req, map[string]string{"id": productID.String()})
+
+	w := httptest.NewRecorder()
+
+	mockService.On("GetProductByID", mock.Anything, productID).Return(product, nil)
+
+	handler.GetProductByID(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.Product
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, productID, response.ID)
+	assert.Equal(t, models.TypeElectronics, response.Type)
+	assert.Equal(t, 3, response.SequenceNum)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetProductByID_NotFound(t *testing.T) {
+	handler, mockService := setupProductTest()
+
+	productID := uuid.New()
+
+	req := httptest.NewRequest("GET", "/products/"+productID.String(), nil)
+	req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
+	req = mux.SetURLVars(req, map[string]string{"id": productID.String()})
+
+	w := httptest.NewRecorder()
+
+	mockService.On("GetProductByID", mock.Anything, productID).Return(nil, models.ErrProductNotFound)
+
+	handler.GetProductByID(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdateProductType_Success(t *testing.T) {
+	handler, mockService := setupProductTest()
+
+	productID := uuid.New()
+	jsonBody, _ := json.Marshal(models.ProductUpdateTypeRequest{Type: models.TypeClothes})
+
+	req := httptest.NewRequest("PATCH", "/products/"+productID.String(), bytes.NewBuffer(jsonBody))
+	req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
+	req = mux.SetURLVars(req, map[string]string{"id": productID.String()})
+
+	w := httptest.NewRecorder()
+
+	mockService.On("UpdateProductType", mock.Anything, productID, models.TypeClothes).Return(nil)
+
+	handler.UpdateProductType(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdateProductType_InvalidType(t *testing.T) {
+	handler, mockService := setupProductTest()
+
+	productID := uuid.New()
+	jsonBody := []byte(`{"type":"мебель"}`)
+
+	req := httptest.NewRequest("PATCH", "/products/"+productID.String(), bytes.NewBuffer(jsonBody))
+	req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
+	req = mux.SetURLVars(req, map[string]string{"id": productID.String()})
+
+	w := httptest.NewRecorder()
+
+	handler.UpdateProductType(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mockService.AssertNotCalled(t, "UpdateProductType", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateProductType_ReceptionClosed(t *testing.T) {
+	handler, mockService := setupProductTest()
+
+	productID := uuid.New()
+	jsonBody, _ := json.Marshal(models.ProductUpdateTypeRequest{Type: models.TypeFootwear})
+
+	req := httptest.NewRequest("PATCH", "/products/"+productID.String(), bytes.NewBuffer(jsonBody))
+	req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
+	req = mux.SetURLVars(req, map[string]string{"id": productID.String()})
+
+	w := httptest.NewRecorder()
+
+	mockService.On("UpdateProductType", mock.Anything, productID, models.TypeFootwear).Return(models.ErrReceptionClosed)
+
+	handler.UpdateProductType(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var response respond.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+	assert.Equal(t, "Reception is closed", response.Error)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetRecentProducts_Success(t *testing.T) {
+	handler, mockService := setupProductTest()
+
+	pvzID := uuid.New()
+	products := []*models.Product{
+		{ID: uuid.New(), DateTime: time.Now(), Type: models.TypeFootwear, SequenceNum: 2},
+		{ID: uuid.New(), DateTime: time.Now(), Type: models.TypeClothes, SequenceNum: 1},
+	}
+
+	req := httptest.NewRequest("GET", "/pvz/"+pvzID.String()+"/recent_products?n=2", nil)
+	req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
+	req = mux.SetURLVars(req, map[string]string{"pvzId": pvzID.String()})
+
+	w := httptest.NewRecorder()
+
+	mockService.On("GetRecentProducts", mock.Anything, pvzID, 2).Return(products, nil)
+
+	handler.GetRecentProducts(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []*models.Product
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+	require.Len(t, response, 2)
+	assert.Equal(t, 2, response[0].SequenceNum)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetRecentProducts_NoOpenReception(t *testing.T) {
+	handler, mockService := setupProductTest()
+
+	pvzID := uuid.New()
+
+	req := httptest.NewRequest("GET", "/pvz/"+pvzID.String()+"/recent_products", nil)
+	req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
+	req = mux.SetURLVars(req, map[string]string{"pvzId": pvzID.String()})
+
+	w := httptest.NewRecorder()
+
+	mockService.On("GetRecentProducts", mock.Anything, pvzID, 0).Return([]*models.Product{}, nil)
+
+	handler.GetRecentProducts(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[]`, w.Body.String())
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetRecentProducts_InvalidN(t *testing.T) {
+	handler, mockService := setupProductTest()
+
+	pvzID := uuid.New()
+
+	req := httptest.NewRequest("GET", "/pvz/"+pvzID.String()+"/recent_products?n=abc", nil)
+	req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
+	req = mux.SetURLVars(req, map[string]string{"pvzId": pvzID.String()})
+
+	w := httptest.NewRecorder()
+
+	handler.GetRecentProducts(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mockService.AssertNotCalled(t, "GetRecentProducts", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestListPVZProducts_Success(t *testing.T) {
+	handler, mockService := setupProductTest()
+
+	pvzID := uuid.New()
+	products := []*models.Product{
+		{ID: uuid.New(), DateTime: time.Now(), Type: models.TypeFootwear, ReceptionID: uuid.New(), SequenceNum: 4},
+	}
+
+	req := httptest.NewRequest("GET", "/pvz/"+pvzID.String()+"/products?page=2&limit=5&type=обувь", nil)
+	req = mux.SetURLVars(req, map[string]string{"pvzId": pvzID.String()})
+	w := httptest.NewRecorder()
+
+	mockService.On("ListPVZProducts", mock.Anything, pvzID, models.TypeFootwear, 2, 5).Return(products, 6, nil)
+
+	handler.ListPVZProducts(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data       []*models.Product `json:"data"`
+		Pagination map[string]int    `json:"pagination"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Data, 1)
+	assert.Equal(t, 4, response.Data[0].SequenceNum)
+	assert.Equal(t, map[string]int{"page": 2, "limit": 5, "total": 6, "pageCount": 2}, response.Pagination)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestListPVZProducts_MaxPage(t *testing.T) {
+	testCases := []struct {
+		name               string
+		page               string
+		expectedStatusCode int
+	}{
+		{name: "At Limit", page: "10", expectedStatusCode: http.StatusOK},
+		{name: "Over Limit", page: "11", expectedStatusCode: http.StatusBadRequest},
+		{name: "Deep Page", page: "999999", expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockService := new(MockProductService)
+			handler := NewProductHandler(mockService).WithMaxPage(10)
+
+			mockService.On("ListPVZProducts", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Return([]*models.Product{}, 0, nil).Maybe()
+
+			pvzID := uuid.New()
+			req := httptest.NewRequest("GET", "/pvz/"+pvzID.String()+"/products?page="+tc.page, nil)
+			req = mux.SetURLVars(req, map[string]string{"pvzId": pvzID.String()})
+			w := httptest.NewRecorder()
+
+			handler.ListPVZProducts(w, req)
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			if tc.expectedStatusCode == http.StatusBadRequest {
+				var response respond.ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, respond.PageTooLargeMessage, response.Error)
+				mockService.AssertNotCalled(t, "ListPVZProducts", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestListPVZProducts_PVZNotFound(t *testing.T) {
+	handler, mockService := setupProductTest()
+
+	pvzID := uuid.New()
+
+	req := httptest.NewRequest("GET", "/pvz/"+pvzID.String()+"/products", nil)
+	req = mux.SetURLVars(req, map[string]string{"pvzId": pvzID.String()})
+	w := httptest.NewRecorder()
+
+	mockService.On("ListPVZProducts", mock.Anything, pvzID, models.ProductType(""), 1, 10).Return(nil, 0, models.ErrPVZNotFound)
+
+	handler.ListPVZProducts(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"error":"PVZ not found","code":"pvz_not_found"}`, w.Body.String())
+
+	mockService.AssertExpectations(t)
+}
+
+func TestListPVZProducts_InvalidType(t *testing.T) {
+	handler, mockService := setupProductTest()
+
+	pvzID := uuid.New()
+
+	req := httptest.NewRequest("GET", "/pvz/"+pvzID.String()+"/products?type=мебель", nil)
+	req = mux.SetURLVars(req, map[string]string{"pvzId": pvzID.String()})
+	w := httptest.NewRecorder()
+
+	handler.ListPVZProducts(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "ListPVZProducts", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"pvz-service/internal/api/respond"
+	"pvz-service/internal/domain/models"
+	"pvz-service/internal/logger"
+)
+
+const (
+	pvzExportFormatJSON = "json"
+	pvzExportFormatCSV  = "csv"
+)
+
+// pvzExportCSVHeader - заголовок CSV-выгрузки ПВЗ
+var pvzExportCSVHeader = []string{"id", "city", "registration_date"}
+
+// pvzExportWriter пишет выгрузку ПВЗ в ответ по одной записи
+type pvzExportWriter interface {
+	contentType() string
+	begin() error
+	write(pvz *models.PVZ) error
+	end() error
+}
+
+func newPVZExportWriter(format string, w io.Writer) pvzExportWriter {
+	switch format {
+	case pvzExportFormatJSON:
+		return &pvzJSONExportWriter{w: w}
+	case pvzExportFormatCSV:
+		return &pvzCSVExportWriter{w: csv.NewWriter(w)}
+	}
+	return nil
+}
+
+// pvzExportRecord - запись JSON-выгрузки: только поля, нужные для резервной копии
+type pvzExportRecord struct {
+	ID               string    `json:"id"`
+	City             string    `json:"city"`
+	RegistrationDate time.Time `json:"registrationDate"`
+}
+
+// pvzJSONExportWriter пишет JSON-массив поэлементно, не собирая его в памяти
+type pvzJSONExportWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (e *pvzJSONExportWriter) contentType() string { return "application/json" }
+
+func (e *pvzJSONExportWriter) begin() error {
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+func (e *pvzJSONExportWriter) write(pvz *models.PVZ) error {
+	data, err := json.Marshal(pvzExportRecord{
+		ID:               pvz.ID.String(),
+		City:             pvz.City,
+		RegistrationDate: pvz.RegistrationDate,
+	})
+	if err != nil {
+		return err
+	}
+	if e.count > 0 {
+		if _, err := io.WriteString(e.w, ","); err != nil {
+			return err
+		}
+	}
+	e.count++
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *pvzJSONExportWriter) end() error {
+	_, err := io.WriteString(e.w, "]\n")
+	return err
+}
+
+// pvzCSVExportWriter пишет CSV с заголовком, даты - в RFC3339
+type pvzCSVExportWriter struct {
+	w *csv.Writer
+}
+
+func (e *pvzCSVExportWriter) contentType() string { return "text/csv; charset=utf-8" }
+
+func (e *pvzCSVExportWriter) begin() error {
+	return e.w.Write(pvzExportCSVHeader)
+}
+
+func (e *pvzCSVExportWriter) write(pvz *models.PVZ) error {
+	return e.w.Write([]string{pvz.ID.String(), pvz.City, pvz.RegistrationDate.UTC().Format(time.RFC3339Nano)})
+}
+
+func (e *pvzCSVExportWriter) end() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// ExportPVZ отдает все ПВЗ одним файлом без пагинации: format=json (по умолчанию) или csv.
+// Записи пишутся в ответ по мере чтения из базы. Ошибку до первой записи клиент получает
+// обычным ответом 500; после начала выгрузки статус уже отправлен, и ответ просто обрывается
+func (h *PVZHandler) ExportPVZ(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+	log.Info("запрос на выгрузку всех ПВЗ")
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = pvzExportFormatJSON
+	}
+
+	export := newPVZExportWriter(format, w)
+	if export == nil {
+		log.Warn("некорректный формат выгрузки ПВЗ", "format", format)
+		respond.Error(w, r, http.StatusBadRequest, "Invalid format. Use json or csv", nil)
+		return
+	}
+
+	// Полная выгрузка может писаться дольше WriteTimeout сервера
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		log.Debug("не удалось снять дедлайн записи для выгрузки ПВЗ", "error", err)
+	}
+
+	started := false
+	start := func() error {
+		started = true
+		w.Header().Set("Content-Type", export.contentType())
+		w.Header().Set("Content-Disposition", `attachment; filename="pvz.`+format+`"`)
+		w.WriteHeader(http.StatusOK)
+		return export.begin()
+	}
+
+	count := 0
+	err := h.pvzService.ExportPVZ(r.Context(), func(pvz *models.PVZ) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		count++
+		return export.write(pvz)
+	})
+	if err != nil {
+		if !started {
+			logRequestError(log, "ошибка выгрузки ПВЗ", err, "format", format)
+			respond.Error(w, r, http.StatusInternalServerError, "Failed to export PVZ", err)
+			return
+		}
+		logRequestError(log, "выгрузка ПВЗ прервана", err, "format", format, "exported", count)
+		return
+	}
+
+	if !started {
+		if err := start(); err != nil {
+			logRequestError(log, "ошибка записи выгрузки ПВЗ", err, "format", format)
+			return
+		}
+	}
+	if err := export.end(); err != nil {
+		logRequestError(log, "ошибка записи выгрузки ПВЗ", err, "format", format, "exported", count)
+		return
+	}
+
+	log.Info("ПВЗ выгружены", "format", format, "count", count)
+}
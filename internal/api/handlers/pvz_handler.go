@@ -1,30 +1,43 @@
 package handlers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
+	"pvz-service/internal/api/respond"
 	"pvz-service/internal/api/validator"
 	"pvz-service/internal/domain/interfaces"
 	"pvz-service/internal/domain/models"
 	"pvz-service/internal/logger"
 
-	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 )
 
 type PVZHandler struct {
 	pvzService interfaces.PVZService
+	maxPage    int
 }
 
 func NewPVZHandler(pvzService interfaces.PVZService) *PVZHandler {
 	return &PVZHandler{
 		pvzService: pvzService,
+		maxPage:    defaultMaxPage,
 	}
 }
 
+// WithMaxPage ограничивает номер страницы в списке ПВЗ. Ноль - без ограничения
+func (h *PVZHandler) WithMaxPage(maxPage int) *PVZHandler {
+	h.maxPage = maxPage
+	return h
+}
+
 func (h *PVZHandler) CreatePVZ(w http.ResponseWriter, r *http.Request) {
 	log := logger.FromContext(r.Context())
 	log.Info("запрос на создание ПВЗ")
@@ -32,33 +45,183 @@ func (h *PVZHandler) CreatePVZ(w http.ResponseWriter, r *http.Request) {
 	var req models.PVZCreateRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Warn("ошибка декодирования JSON", "error", err)
-		sendErrorResponse(w, "Invalid request format", http.StatusBadRequest, err)
+		respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeInvalidRequest, "Invalid request format", err)
 		return
 	}
 
-	log.Debug("запрос на создание ПВЗ", "city", req.City)
+	log.Debug("запрос на создание ПВЗ", "city", req.City, "external_id", req.ExternalID)
 
 	if err := validator.ValidateStruct(req); err != nil {
 		log.Warn("ошибка валидации ПВЗ",
 			"city", req.City,
 			"validation_errors", validator.FormatValidationErrors(err),
 		)
-		sendErrorResponse(w, "Validation failed: "+validator.FormatValidationErrors(err), http.StatusBadRequest, nil)
+		respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeValidationFailed, "Validation failed: "+validator.FormatValidationErrors(err), nil)
 		return
 	}
 
-	pvz, err := h.pvzService.CreatePVZ(r.Context(), req.City)
+	pvz, created, err := h.pvzService.CreatePVZ(r.Context(), req.City, req.ExternalID)
 	if err != nil {
 		log.Error("ошибка создания ПВЗ", "city", req.City, "error", err)
-		sendErrorResponse(w, "Unable to create PVZ", http.StatusBadRequest, err)
+		respond.Error(w, r, http.StatusBadRequest, "Unable to create PVZ", err)
 		return
 	}
 
-	log.Info("ПВЗ успешно создан", "pvz_id", pvz.ID, "city", pvz.City)
+	// Повторный запрос с существующим externalId возвращает уже созданный ПВЗ со статусом 200
+	status := http.StatusCreated
+	if created {
+		log.Info("ПВЗ успешно создан", "pvz_id", pvz.ID, "city", pvz.City)
+		w.Header().Set("Location", "/pvz/"+pvz.ID.String())
+	} else {
+		status = http.StatusOK
+		log.Info("возвращен существующий ПВЗ по внешнему ID", "pvz_id", pvz.ID, "external_id", req.ExternalID)
+	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(pvz)
+	respond.JSON(w, r, status, pvz)
+}
+
+func (h *PVZHandler) CreatePVZBatch(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+	log.Info("запрос на пакетное создание ПВЗ")
+
+	var req models.PVZBatchCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("ошибка декодирования JSON", "error", err)
+		respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeInvalidRequest, "Invalid request format", err)
+		return
+	}
+
+	if err := validator.ValidateStruct(req); err != nil {
+		log.Warn("ошибка валидации пакета ПВЗ",
+			"count", len(req.Cities),
+			"validation_errors", validator.FormatValidationErrors(err),
+		)
+		respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeValidationFailed, "Validation failed: "+validator.FormatValidationErrors(err), nil)
+		return
+	}
+
+	result, err := h.pvzService.CreatePVZBatch(r.Context(), req.Cities)
+	if err != nil {
+		log.Error("ошибка пакетного создания ПВЗ", "count", len(req.Cities), "error", err)
+		respond.Error(w, r, http.StatusBadRequest, "Unable to create PVZ batch", err)
+		return
+	}
+
+	log.Info("пакетное создание ПВЗ завершено", "created", len(result.Created), "failed", len(result.Errors))
+
+	// Если не создан ни один ПВЗ, весь пакет отклонен - отвечаем 422 с теми же поэлементными ошибками
+	status := http.StatusCreated
+	if len(result.Created) == 0 {
+		status = http.StatusUnprocessableEntity
+	}
+
+	respond.JSON(w, r, status, result)
+}
+
+// ValidateCities проверяет список городов по перечню разрешенных, ничего не сохраняя.
+// Результаты возвращаются в порядке запроса
+func (h *PVZHandler) ValidateCities(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+	log.Info("запрос на проверку списка городов")
+
+	var req models.PVZValidateCitiesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("ошибка декодирования JSON", "error", err)
+		respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeInvalidRequest, "Invalid request format", err)
+		return
+	}
+
+	if err := validator.ValidateStruct(req); err != nil {
+		log.Warn("ошибка валидации списка городов",
+			"count", len(req.Cities),
+			"validation_errors", validator.FormatValidationErrors(err),
+		)
+		respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeValidationFailed, "Validation failed: "+validator.FormatValidationErrors(err), nil)
+		return
+	}
+
+	results := make([]models.CityValidationResult, 0, len(req.Cities))
+	invalid := 0
+	for _, city := range req.Cities {
+		valid := models.AllowedCities[city]
+		if !valid {
+			invalid++
+		}
+		results = append(results, models.CityValidationResult{City: city, Valid: valid})
+	}
+
+	log.Info("список городов проверен", "count", len(results), "invalid", invalid)
+
+	respond.JSON(w, r, http.StatusOK, results)
+}
+
+// ListAllowedCities возвращает перечень городов, в которых разрешено создавать ПВЗ,
+// в алфавитном порядке. Клиенты берут список отсюда, а не хранят его у себя
+func (h *PVZHandler) ListAllowedCities(w http.ResponseWriter, r *http.Request) {
+	cities := make([]string, 0, len(models.AllowedCities))
+	for city, allowed := range models.AllowedCities {
+		if allowed {
+			cities = append(cities, city)
+		}
+	}
+	sort.Strings(cities)
+
+	respond.JSON(w, r, http.StatusOK, cities)
+}
+
+// ListCities возвращает города, в которых есть ПВЗ, с числом ПВЗ в каждом
+func (h *PVZHandler) ListCities(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+	log.Info("запрос на получение списка городов с ПВЗ")
+
+	cities, err := h.pvzService.CountPVZByCity(r.Context())
+	if err != nil {
+		logRequestError(log, "ошибка подсчета ПВЗ по городам", err)
+		respond.Error(w, r, http.StatusInternalServerError, "Failed to retrieve cities", err)
+		return
+	}
+
+	if cities == nil {
+		cities = []models.CityPVZCount{}
+	}
+
+	log.Info("список городов с ПВЗ получен", "count", len(cities))
+
+	respond.JSON(w, r, http.StatusOK, cities)
+}
+
+// GetPVZBatch возвращает ПВЗ по списку идентификаторов одним запросом.
+// Ненайденные идентификаторы в ответ не попадают
+func (h *PVZHandler) GetPVZBatch(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+	log.Info("запрос на получение ПВЗ по списку ID")
+
+	var req models.PVZBatchGetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Warn("ошибка декодирования JSON", "error", err)
+		respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeInvalidRequest, "Invalid request format", err)
+		return
+	}
+
+	if err := validator.ValidateStruct(req); err != nil {
+		log.Warn("ошибка валидации списка ID ПВЗ",
+			"count", len(req.IDs),
+			"validation_errors", validator.FormatValidationErrors(err),
+		)
+		respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeValidationFailed, "Validation failed: "+validator.FormatValidationErrors(err), nil)
+		return
+	}
+
+	pvzs, err := h.pvzService.GetPVZsByIDs(r.Context(), req.IDs)
+	if err != nil {
+		logRequestError(log, "ошибка получения ПВЗ по списку ID", err, "count", len(req.IDs))
+		respond.Error(w, r, http.StatusInternalServerError, "Failed to retrieve PVZ list", err)
+		return
+	}
+
+	log.Info("ПВЗ по списку ID успешно получены", "requested", len(req.IDs), "found", len(pvzs))
+
+	respond.JSON(w, r, http.StatusOK, pvzs)
 }
 
 func (h *PVZHandler) ListPVZ(w http.ResponseWriter, r *http.Request) {
@@ -68,31 +231,26 @@ func (h *PVZHandler) ListPVZ(w http.ResponseWriter, r *http.Request) {
 	limitStr := r.URL.Query().Get("limit")
 	startDateStr := r.URL.Query().Get("startDate")
 	endDateStr := r.URL.Query().Get("endDate")
+	registeredFromStr := r.URL.Query().Get("registeredFrom")
+	registeredToStr := r.URL.Query().Get("registeredTo")
+	sortStr := r.URL.Query().Get("sort")
+	onlyOpenStr := r.URL.Query().Get("onlyOpen")
+	withTotal := parseWithTotal(r)
 
 	log.Info("запрос на получение списка ПВЗ",
 		"page", pageStr,
 		"limit", limitStr,
 		"startDate", startDateStr,
 		"endDate", endDateStr,
+		"registeredFrom", registeredFromStr,
+		"registeredTo", registeredToStr,
+		"sort", sortStr,
+		"onlyOpen", onlyOpenStr,
 	)
 
-	page := 1
-	limit := 10
-
-	if pageStr != "" {
-		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
-			page = p
-		} else if err != nil {
-			log.Warn("некорректное значение page", "page", pageStr, "error", err)
-		}
-	}
-
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 30 {
-			limit = l
-		} else if err != nil {
-			log.Warn("некорректное значение limit", "limit", limitStr, "error", err)
-		}
+	page, limit, ok := parsePagination(w, r, pageStr, limitStr, h.maxPage)
+	if !ok {
+		return
 	}
 
 	var startDate, endDate time.Time
@@ -102,7 +260,7 @@ func (h *PVZHandler) ListPVZ(w http.ResponseWriter, r *http.Request) {
 		startDate, err = time.Parse(time.RFC3339, startDateStr)
 		if err != nil {
 			log.Warn("некорректный формат startDate", "startDate", startDateStr, "error", err)
-			sendErrorResponse(w, "Invalid startDate format. Use RFC3339 format", http.StatusBadRequest, err)
+			respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeInvalidDate, "Invalid startDate format. Use RFC3339 format", err)
 			return
 		}
 	}
@@ -111,16 +269,60 @@ func (h *PVZHandler) ListPVZ(w http.ResponseWriter, r *http.Request) {
 		endDate, err = time.Parse(time.RFC3339, endDateStr)
 		if err != nil {
 			log.Warn("некорректный формат endDate", "endDate", endDateStr, "error", err)
-			sendErrorResponse(w, "Invalid endDate format. Use RFC3339 format", http.StatusBadRequest, err)
+			respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeInvalidDate, "Invalid endDate format. Use RFC3339 format", err)
+			return
+		}
+	}
+
+	var registeredFrom, registeredTo time.Time
+
+	if registeredFromStr != "" {
+		registeredFrom, err = time.Parse(time.RFC3339, registeredFromStr)
+		if err != nil {
+			log.Warn("некорректный формат registeredFrom", "registeredFrom", registeredFromStr, "error", err)
+			respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeInvalidDate, "Invalid registeredFrom format. Use RFC3339 format", err)
+			return
+		}
+	}
+
+	if registeredToStr != "" {
+		registeredTo, err = time.Parse(time.RFC3339, registeredToStr)
+		if err != nil {
+			log.Warn("некорректный формат registeredTo", "registeredTo", registeredToStr, "error", err)
+			respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeInvalidDate, "Invalid registeredTo format. Use RFC3339 format", err)
+			return
+		}
+	}
+
+	sortBy := strings.TrimPrefix(sortStr, "-")
+	sortDesc := strings.HasPrefix(sortStr, "-")
+	if sortStr != "" && !models.AllowedPVZSortFields[sortBy] {
+		log.Warn("недопустимое значение sort", "sort", sortStr)
+		respond.Error(w, r, http.StatusBadRequest, "Invalid sort value. Use registrationDate, -registrationDate, city or -city", nil)
+		return
+	}
+
+	onlyOpen := false
+	if onlyOpenStr != "" {
+		onlyOpen, err = strconv.ParseBool(onlyOpenStr)
+		if err != nil {
+			log.Warn("некорректное значение onlyOpen", "onlyOpen", onlyOpenStr, "error", err)
+			respond.Error(w, r, http.StatusBadRequest, "Invalid onlyOpen value. Use true or false", err)
 			return
 		}
 	}
 
 	options := models.PVZListOptions{
-		Page:      page,
-		Limit:     limit,
-		StartDate: startDate,
-		EndDate:   endDate,
+		Page:           page,
+		Limit:          limit,
+		StartDate:      startDate,
+		EndDate:        endDate,
+		RegisteredFrom: registeredFrom,
+		RegisteredTo:   registeredTo,
+		SortBy:         sortBy,
+		SortDesc:       sortDesc,
+		OnlyOpen:       onlyOpen,
+		SkipTotal:      !withTotal,
 	}
 
 	log.Debug("получение списка ПВЗ с параметрами",
@@ -132,8 +334,8 @@ func (h *PVZHandler) ListPVZ(w http.ResponseWriter, r *http.Request) {
 
 	pvzs, total, err := h.pvzService.ListPVZ(r.Context(), options)
 	if err != nil {
-		log.Error("ошибка получения списка ПВЗ", "error", err)
-		sendErrorResponse(w, "Failed to retrieve PVZ list", http.StatusInternalServerError, err)
+		logRequestError(log, "ошибка получения списка ПВЗ", err)
+		respond.Error(w, r, http.StatusInternalServerError, "Failed to retrieve PVZ list", err)
 		return
 	}
 
@@ -143,17 +345,11 @@ func (h *PVZHandler) ListPVZ(w http.ResponseWriter, r *http.Request) {
 	)
 
 	response := map[string]interface{}{
-		"data": pvzs,
-		"pagination": map[string]int{
-			"page":      page,
-			"limit":     limit,
-			"total":     total,
-			"pageCount": (total + limit - 1) / limit,
-		},
+		"data":       pvzs,
+		"pagination": paginationEnvelope(page, limit, total, withTotal),
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	respond.JSON(w, r, http.StatusOK, response)
 }
 
 func (h *PVZHandler) GetPVZByID(w http.ResponseWriter, r *http.Request) {
@@ -164,28 +360,60 @@ func (h *PVZHandler) GetPVZByID(w http.ResponseWriter, r *http.Request) {
 
 	log.Info("запрос на получение ПВЗ по ID", "pvz_id", idStr)
 
-	id, err := uuid.Parse(idStr)
-	if err != nil {
-		log.Warn("некорректный формат UUID", "pvz_id", idStr, "error", err)
-		sendErrorResponse(w, "Invalid PVZ ID format", http.StatusBadRequest, err)
+	id, ok := pathUUID(w, r, "pvzId")
+	if !ok {
 		return
 	}
 
 	pvz, err := h.pvzService.GetPVZByID(r.Context(), id)
+	if errors.Is(err, models.ErrPVZNotFound) {
+		log.Warn("ПВЗ не найден", "pvz_id", id)
+		respond.ErrorWithCode(w, r, http.StatusNotFound, respond.CodePVZNotFound, "PVZ not found", nil)
+		return
+	}
 	if err != nil {
-		log.Error("ошибка получения ПВЗ", "pvz_id", id, "error", err)
-		sendErrorResponse(w, "Error retrieving PVZ", http.StatusInternalServerError, err)
+		logRequestError(log, "ошибка получения ПВЗ", err, "pvz_id", id)
+		respond.Error(w, r, http.StatusInternalServerError, "Error retrieving PVZ", err)
 		return
 	}
 
 	if pvz == nil {
 		log.Warn("ПВЗ не найден", "pvz_id", id)
-		sendErrorResponse(w, "PVZ not found", http.StatusNotFound, nil)
+		respond.ErrorWithCode(w, r, http.StatusNotFound, respond.CodePVZNotFound, "PVZ not found", nil)
+		return
+	}
+
+	etag := pvzETag(pvz)
+	w.Header().Set("ETag", etag)
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		log.Debug("ПВЗ не изменился, возвращаем 304", "pvz_id", id)
+		w.WriteHeader(http.StatusNotModified)
 		return
 	}
 
 	log.Info("ПВЗ успешно получен", "pvz_id", id, "city", pvz.City)
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(pvz)
+	respond.JSON(w, r, http.StatusOK, pvz)
+}
+
+// pvzETag строит слабый ETag по неизменяемым полям ПВЗ
+func pvzETag(pvz *models.PVZ) string {
+	sum := sha256.Sum256([]byte(pvz.ID.String() + "|" + pvz.City + "|" + pvz.RegistrationDate.UTC().Format(time.RFC3339Nano)))
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches сравнивает ETag со значением If-None-Match (слабое сравнение)
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
 }
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"pvz-service/internal/api/respond"
 	"pvz-service/internal/domain/models"
 	"pvz-service/internal/logger"
 )
@@ -24,12 +26,12 @@ type MockPVZService struct {
 	mock.Mock
 }
 
-func (m *MockPVZService) CreatePVZ(ctx context.Context, city string) (*models.PVZ, error) {
-	args := m.Called(ctx, city)
+func (m *MockPVZService) CreatePVZ(ctx context.Context, city, externalID string) (*models.PVZ, bool, error) {
+	args := m.Called(ctx, city, externalID)
 	if args.Get(0) == nil {
-		return nil, args.Error(1)
+		return nil, args.Bool(1), args.Error(2)
 	}
-	return args.Get(0).(*models.PVZ), args.Error(1)
+	return args.Get(0).(*models.PVZ), args.Bool(1), args.Error(2)
 }
 
 func (m *MockPVZService) GetPVZByID(ctx context.Context, id uuid.UUID) (*models.PVZ, error) {
@@ -40,6 +42,22 @@ func (m *MockPVZService) GetPVZByID(ctx context.Context, id uuid.UUID) (*models.
 	return args.Get(0).(*models.PVZ), args.Error(1)
 }
 
+func (m *MockPVZService) GetPVZsByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.PVZ, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.PVZ), args.Error(1)
+}
+
+func (m *MockPVZService) CreatePVZBatch(ctx context.Context, cities []string) (*models.PVZBatchCreateResponse, error) {
+	args := m.Called(ctx, cities)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PVZBatchCreateResponse), args.Error(1)
+}
+
 func (m *MockPVZService) ListPVZ(ctx context.Context, options models.PVZListOptions) ([]*models.PVZWithReceptionsResponse, int, error) {
 	args := m.Called(ctx, options)
 	if args.Get(0) == nil {
@@ -48,6 +66,26 @@ func (m *MockPVZService) ListPVZ(ctx context.Context, options models.PVZListOpti
 	return args.Get(0).([]*models.PVZWithReceptionsResponse), args.Int(1), args.Error(2)
 }
 
+func (m *MockPVZService) CountPVZByCity(ctx context.Context) ([]models.CityPVZCount, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.CityPVZCount), args.Error(1)
+}
+
+func (m *MockPVZService) ExportPVZ(ctx context.Context, fn func(*models.PVZ) error) error {
+	args := m.Called(ctx)
+	if pvzs, ok := args.Get(0).([]*models.PVZ); ok {
+		for _, pvz := range pvzs {
+			if err := fn(pvz); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
 func setupPVZTest() (*PVZHandler, *MockPVZService) {
 	mockService := new(MockPVZService)
 	handler := NewPVZHandler(mockService)
@@ -76,11 +114,12 @@ func TestCreatePVZ_Success(t *testing.T) {
 	req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
 	w := httptest.NewRecorder()
 
-	mockService.On("CreatePVZ", mock.Anything, city).Return(pvz, nil)
+	mockService.On("CreatePVZ", mock.Anything, city, "").Return(pvz, true, nil)
 
 	handler.CreatePVZ(w, req)
 
 	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "/pvz/"+pvzID.String(), w.Header().Get("Location"))
 
 	var response models.PVZ
 	err := json.Unmarshal(w.Body.Bytes(), &response)
@@ -91,6 +130,139 @@ func TestCreatePVZ_Success(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestCreatePVZ_ExistingExternalIDReturnsOK(t *testing.T) {
+	handler, mockService := setupPVZTest()
+
+	externalID := "site-42"
+	pvz := &models.PVZ{
+		ID:               uuid.New(),
+		RegistrationDate: time.Now().Add(-time.Hour),
+		City:             "Казань",
+		ExternalID:       &externalID,
+	}
+
+	jsonBody, _ := json.Marshal(models.PVZCreateRequest{City: "Казань", ExternalID: externalID})
+	req := httptest.NewRequest("POST", "/pvz", bytes.NewBuffer(jsonBody))
+	req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
+	w := httptest.NewRecorder()
+
+	mockService.On("CreatePVZ", mock.Anything, "Казань", externalID).Return(pvz, false, nil)
+
+	handler.CreatePVZ(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Location"))
+
+	var response models.PVZ
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+	assert.Equal(t, pvz.ID, response.ID)
+	require.NotNil(t, response.ExternalID)
+	assert.Equal(t, externalID, *response.ExternalID)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestCreatePVZBatch_Success(t *testing.T) {
+	handler, mockService := setupPVZTest()
+
+	cities := []string{"Москва", "Казань"}
+	pvzs := []*models.PVZ{
+		{ID: uuid.New(), RegistrationDate: time.Now(), City: "Москва"},
+		{ID: uuid.New(), RegistrationDate: time.Now(), City: "Казань"},
+	}
+
+	jsonBody, _ := json.Marshal(models.PVZBatchCreateRequest{Cities: cities})
+	req := httptest.NewRequest("POST", "/pvz/batch", bytes.NewBuffer(jsonBody))
+	req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
+	w := httptest.NewRecorder()
+
+	mockService.On("CreatePVZBatch", mock.Anything, cities).Return(&models.PVZBatchCreateResponse{
+		Created: pvzs,
+		Errors:  []models.PVZBatchItemError{},
+	}, nil)
+
+	handler.CreatePVZBatch(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response models.PVZBatchCreateResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+	require.Len(t, response.Created, 2)
+	assert.Equal(t, pvzs[0].ID, response.Created[0].ID)
+	assert.Equal(t, "Казань", response.Created[1].City)
+	assert.Empty(t, response.Errors)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestCreatePVZBatch_EmptyCities(t *testing.T) {
+	handler, mockService := setupPVZTest()
+
+	req := httptest.NewRequest("POST", "/pvz/batch", bytes.NewBufferString(`{"cities":[]}`))
+	req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
+	w := httptest.NewRecorder()
+
+	handler.CreatePVZBatch(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "CreatePVZBatch", mock.Anything, mock.Anything)
+}
+
+func TestCreatePVZBatch_AllInvalid(t *testing.T) {
+	handler, mockService := setupPVZTest()
+
+	cities := []string{"Тверь"}
+
+	jsonBody, _ := json.Marshal(models.PVZBatchCreateRequest{Cities: cities})
+	req := httptest.NewRequest("POST", "/pvz/batch", bytes.NewBuffer(jsonBody))
+	req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
+	w := httptest.NewRecorder()
+
+	mockService.On("CreatePVZBatch", mock.Anything, cities).Return(&models.PVZBatchCreateResponse{
+		Created: []*models.PVZ{},
+		Errors:  []models.PVZBatchItemError{{Index: 0, City: "Тверь", Error: "city must be one of: Москва, Санкт-Петербург, Казань"}},
+	}, nil)
+
+	handler.CreatePVZBatch(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var response models.PVZBatchCreateResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+	assert.Empty(t, response.Created)
+	require.Len(t, response.Errors, 1)
+	assert.Equal(t, "Тверь", response.Errors[0].City)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestCreatePVZBatch_ServiceError(t *testing.T) {
+	handler, mockService := setupPVZTest()
+
+	cities := []string{"Москва", "Новосибирск"}
+
+	jsonBody, _ := json.Marshal(models.PVZBatchCreateRequest{Cities: cities})
+	req := httptest.NewRequest("POST", "/pvz/batch", bytes.NewBuffer(jsonBody))
+	req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
+	w := httptest.NewRecorder()
+
+	mockService.On("CreatePVZBatch", mock.Anything, cities).Return(nil, errors.New("invalid cities"))
+
+	handler.CreatePVZBatch(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response respond.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+	assert.Equal(t, "Unable to create PVZ batch", response.Error)
+
+	mockService.AssertExpectations(t)
+}
+
 func TestCreatePVZ_InvalidJSON(t *testing.T) {
 	handler, _ := setupPVZTest()
 
@@ -103,7 +275,7 @@ func TestCreatePVZ_InvalidJSON(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var response ErrorResponse
+	var response respond.ErrorResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
 	assert.Contains(t, response.Error, "Invalid request format")
@@ -125,7 +297,7 @@ func TestCreatePVZ_ValidationError(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var response ErrorResponse
+	var response respond.ErrorResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
 	assert.Contains(t, response.Error, "Validation failed")
@@ -145,13 +317,13 @@ func TestCreatePVZ_ServiceError(t *testing.T) {
 	req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
 	w := httptest.NewRecorder()
 
-	mockService.On("CreatePVZ", mock.Anything, city).Return(nil, errors.New("service error"))
+	mockService.On("CreatePVZ", mock.Anything, city, "").Return(nil, false, errors.New("service error"))
 
 	handler.CreatePVZ(w, req)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var response ErrorResponse
+	var response respond.ErrorResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
 	assert.Equal(t, "Unable to create PVZ", response.Error)
@@ -159,6 +331,81 @@ func TestCreatePVZ_ServiceError(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestGetPVZBatch_PartialMiss(t *testing.T) {
+	handler, mockService := setupPVZTest()
+
+	foundID := uuid.New()
+	missingID := uuid.New()
+	ids := []uuid.UUID{foundID, missingID}
+
+	jsonBody, _ := json.Marshal(models.PVZBatchGetRequest{IDs: ids})
+	req := httptest.NewRequest("POST", "/pvz/batch_get", bytes.NewBuffer(jsonBody))
+	w := httptest.NewRecorder()
+
+	mockService.On("GetPVZsByIDs", mock.Anything, ids).Return([]*models.PVZ{
+		{ID: foundID, RegistrationDate: time.Now(), City: "Москва"},
+	}, nil)
+
+	handler.GetPVZBatch(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []models.PVZ
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response, 1)
+	assert.Equal(t, foundID, response[0].ID)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetPVZBatch_InvalidRequest(t *testing.T) {
+	tooMany := make([]string, models.MaxPVZBatchGetIDs+1)
+	for i := range tooMany {
+		tooMany[i] = `"` + uuid.NewString() + `"`
+	}
+
+	testCases := []struct {
+		name string
+		body string
+	}{
+		{name: "Malformed JSON", body: `{"ids":`},
+		{name: "Malformed ID", body: `{"ids":["not-a-uuid"]}`},
+		{name: "Empty IDs", body: `{"ids":[]}`},
+		{name: "Too Many IDs", body: `{"ids":[` + strings.Join(tooMany, ",") + `]}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, mockService := setupPVZTest()
+
+			req := httptest.NewRequest("POST", "/pvz/batch_get", bytes.NewBufferString(tc.body))
+			w := httptest.NewRecorder()
+
+			handler.GetPVZBatch(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockService.AssertNotCalled(t, "GetPVZsByIDs", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestGetPVZBatch_ServiceError(t *testing.T) {
+	handler, mockService := setupPVZTest()
+
+	ids := []uuid.UUID{uuid.New()}
+
+	jsonBody, _ := json.Marshal(models.PVZBatchGetRequest{IDs: ids})
+	req := httptest.NewRequest("POST", "/pvz/batch_get", bytes.NewBuffer(jsonBody))
+	w := httptest.NewRecorder()
+
+	mockService.On("GetPVZsByIDs", mock.Anything, ids).Return(nil, errors.New("db error"))
+
+	handler.GetPVZBatch(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	mockService.AssertExpectations(t)
+}
+
 func TestListPVZ_Success(t *testing.T) {
 	handler, mockService := setupPVZTest()
 
@@ -224,12 +471,87 @@ func TestListPVZ_InvalidDateFormat(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var response ErrorResponse
+	var response respond.ErrorResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
 	assert.Contains(t, response.Error, "Invalid startDate format")
 }
 
+func TestListPVZ_Sort(t *testing.T) {
+	handler, mockService := setupPVZTest()
+
+	options := models.PVZListOptions{
+		Page:     1,
+		Limit:    10,
+		SortBy:   models.PVZSortRegistrationDate,
+		SortDesc: true,
+	}
+
+	req := httptest.NewRequest("GET", "/pvz?sort=-registrationDate", nil)
+	req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
+	w := httptest.NewRecorder()
+
+	mockService.On("ListPVZ", mock.Anything, options).Return([]*models.PVZWithReceptionsResponse{}, 0, nil)
+
+	handler.ListPVZ(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestListPVZ_InvalidSort(t *testing.T) {
+	handler, mockService := setupPVZTest()
+
+	req := httptest.NewRequest("GET", "/pvz?sort=id%3B+DROP+TABLE+pvz", nil)
+	req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
+	w := httptest.NewRecorder()
+
+	handler.ListPVZ(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response respond.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+	assert.Contains(t, response.Error, "Invalid sort value")
+
+	mockService.AssertNotCalled(t, "ListPVZ", mock.Anything, mock.Anything)
+}
+
+func TestListPVZ_MaxPage(t *testing.T) {
+	testCases := []struct {
+		name               string
+		query              string
+		expectedStatusCode int
+	}{
+		{name: "Within Limit", query: "/pvz?page=5", expectedStatusCode: http.StatusOK},
+		{name: "At Limit", query: "/pvz?page=10", expectedStatusCode: http.StatusOK},
+		{name: "Over Limit", query: "/pvz?page=11", expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockService := new(MockPVZService)
+			handler := NewPVZHandler(mockService).WithMaxPage(10)
+
+			mockService.On("ListPVZ", mock.Anything, mock.Anything).Return([]*models.PVZWithReceptionsResponse{}, 0, nil).Maybe()
+
+			req := httptest.NewRequest("GET", tc.query, nil)
+			w := httptest.NewRecorder()
+
+			handler.ListPVZ(w, req)
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			if tc.expectedStatusCode == http.StatusBadRequest {
+				var response respond.ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, respond.PageTooLargeMessage, response.Error)
+				mockService.AssertNotCalled(t, "ListPVZ", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
 func TestListPVZ_ServiceError(t *testing.T) {
 	handler, mockService := setupPVZTest()
 
@@ -249,7 +571,7 @@ func TestListPVZ_ServiceError(t *testing.T) {
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 
-	var response ErrorResponse
+	var response respond.ErrorResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
 	assert.Equal(t, "Failed to retrieve PVZ list", response.Error)
@@ -295,6 +617,51 @@ func TestGetPVZByID_Success(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestGetPVZByID_ETagConditionalGet(t *testing.T) {
+	handler, mockService := setupPVZTest()
+
+	pvzID := uuid.New()
+	pvz := &models.PVZ{
+		ID:               pvzID,
+		RegistrationDate: time.Now(),
+		City:             "Казань",
+	}
+
+	newRequest := func(ifNoneMatch string) *http.Request {
+		req := httptest.NewRequest("GET", "/pvz/"+pvzID.String(), nil)
+		req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
+		req = mux.SetURLVars(req, map[string]string{"pvzId": pvzID.String()})
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		return req
+	}
+
+	mockService.On("GetPVZByID", mock.Anything, pvzID).Return(pvz, nil)
+
+	w := httptest.NewRecorder()
+	handler.GetPVZByID(w, newRequest(""))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.True(t, strings.HasPrefix(etag, `W/"`))
+
+	w = httptest.NewRecorder()
+	handler.GetPVZByID(w, newRequest(etag))
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, etag, w.Header().Get("ETag"))
+	assert.Empty(t, w.Body.Bytes())
+
+	w = httptest.NewRecorder()
+	handler.GetPVZByID(w, newRequest(`W/"stale"`))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	mockService.AssertExpectations(t)
+}
+
 func TestGetPVZByID_InvalidUUID(t *testing.T) {
 	handler, _ := setupPVZTest()
 
@@ -312,10 +679,10 @@ func TestGetPVZByID_InvalidUUID(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var response ErrorResponse
+	var response respond.ErrorResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
-	assert.Contains(t, response.Error, "Invalid PVZ ID format")
+	assert.Contains(t, response.Error, "Invalid pvzId format")
 }
 
 func TestGetPVZByID_NotFound(t *testing.T) {
@@ -339,7 +706,7 @@ func TestGetPVZByID_NotFound(t *testing.T) {
 
 	assert.Equal(t, http.StatusNotFound, w.Code)
 
-	var response ErrorResponse
+	var response respond.ErrorResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
 	assert.Equal(t, "PVZ not found", response.Error)
@@ -347,6 +714,23 @@ func TestGetPVZByID_NotFound(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestGetPVZByID_NotFoundError(t *testing.T) {
+	handler, mockService := setupPVZTest()
+
+	pvzID := uuid.New()
+
+	req := httptest.NewRequest("GET", "/pvz/"+pvzID.String(), nil)
+	req = mux.SetURLVars(req, map[string]string{"pvzId": pvzID.String()})
+	w := httptest.NewRecorder()
+
+	mockService.On("GetPVZByID", mock.Anything, pvzID).Return(nil, models.ErrPVZNotFound)
+
+	handler.GetPVZByID(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertExpectations(t)
+}
+
 func TestGetPVZByID_ServiceError(t *testing.T) {
 	handler, mockService := setupPVZTest()
 
@@ -368,10 +752,218 @@ func TestGetPVZByID_ServiceError(t *testing.T) {
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 
-	var response ErrorResponse
+	var response respond.ErrorResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
 	assert.Equal(t, "Error retrieving PVZ", response.Error)
 
 	mockService.AssertExpectations(t)
 }
+
+func TestValidateCities_MixedCities(t *testing.T) {
+	handler, mockService := setupPVZTest()
+
+	jsonBody, _ := json.Marshal(models.PVZValidateCitiesRequest{
+		Cities: []string{"Москва", "Новосибирск", "Казань", ""},
+	})
+	req := httptest.NewRequest("POST", "/pvz/validate_cities", bytes.NewBuffer(jsonBody))
+	w := httptest.NewRecorder()
+
+	handler.ValidateCities(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []models.CityValidationResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []models.CityValidationResult{
+		{City: "Москва", Valid: true},
+		{City: "Новосибирск", Valid: false},
+		{City: "Казань", Valid: true},
+		{City: "", Valid: false},
+	}, response)
+
+	mockService.AssertNotCalled(t, "CreatePVZ", mock.Anything, mock.Anything, mock.Anything)
+	mockService.AssertNotCalled(t, "CreatePVZBatch", mock.Anything, mock.Anything)
+}
+
+func TestValidateCities_EmptyList(t *testing.T) {
+	handler, _ := setupPVZTest()
+
+	req := httptest.NewRequest("POST", "/pvz/validate_cities", bytes.NewBufferString(`{"cities":[]}`))
+	w := httptest.NewRecorder()
+
+	handler.ValidateCities(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestListAllowedCities(t *testing.T) {
+	handler, mockService := setupPVZTest()
+
+	req := httptest.NewRequest("GET", "/cities", nil)
+	w := httptest.NewRecorder()
+
+	handler.ListAllowedCities(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var response []string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []string{"Казань", "Москва", "Санкт-Петербург"}, response)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestListCities(t *testing.T) {
+	testCases := []struct {
+		name           string
+		cities         []models.CityPVZCount
+		serviceErr     error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Success",
+			cities:         []models.CityPVZCount{{City: "Казань", Count: 2}, {City: "Москва", Count: 5}},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"city":"Казань","count":2},{"city":"Москва","count":5}]`,
+		},
+		{
+			name:           "No PVZ",
+			cities:         []models.CityPVZCount{},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[]`,
+		},
+		{
+			name:           "Service Error",
+			serviceErr:     errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error":"Failed to retrieve cities"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, mockService := setupPVZTest()
+
+			if tc.serviceErr != nil {
+				mockService.On("CountPVZByCity", mock.Anything).Return(nil, tc.serviceErr)
+			} else {
+				mockService.On("CountPVZByCity", mock.Anything).Return(tc.cities, nil)
+			}
+
+			req := httptest.NewRequest("GET", "/pvz/cities", nil)
+			w := httptest.NewRecorder()
+
+			handler.ListCities(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.JSONEq(t, tc.expectedBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestExportPVZ(t *testing.T) {
+	first := &models.PVZ{
+		ID:               uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		City:             "Москва",
+		RegistrationDate: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	externalID := "ext-2"
+	second := &models.PVZ{
+		ID:               uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+		City:             "Казань",
+		RegistrationDate: time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC),
+		ExternalID:       &externalID,
+	}
+
+	testCases := []struct {
+		name                string
+		query               string
+		pvzs                []*models.PVZ
+		serviceErr          error
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:                "JSON By Default",
+			pvzs:                []*models.PVZ{first, second},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/json",
+			expectedBody: `[{"id":"11111111-1111-1111-1111-111111111111","city":"Москва","registrationDate":"2024-01-02T03:04:05Z"},` +
+				`{"id":"22222222-2222-2222-2222-222222222222","city":"Казань","registrationDate":"2024-02-03T04:05:06Z"}]` + "\n",
+		},
+		{
+			name:                "CSV",
+			query:               "?format=csv",
+			pvzs:                []*models.PVZ{first, second},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody: "id,city,registration_date\n" +
+				"11111111-1111-1111-1111-111111111111,Москва,2024-01-02T03:04:05Z\n" +
+				"22222222-2222-2222-2222-222222222222,Казань,2024-02-03T04:05:06Z\n",
+		},
+		{
+			name:                "Empty JSON",
+			query:               "?format=json",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/json",
+			expectedBody:        "[]\n",
+		},
+		{
+			name:                "Empty CSV",
+			query:               "?format=csv",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody:        "id,city,registration_date\n",
+		},
+		{
+			name:                "Service Error Before First Row",
+			query:               "?format=csv",
+			serviceErr:          errors.New("database error"),
+			expectedStatus:      http.StatusInternalServerError,
+			expectedContentType: "application/json",
+			expectedBody:        `{"error":"Failed to export PVZ"}` + "\n",
+		},
+		{
+			name:                "Service Error Mid Stream Truncates",
+			pvzs:                []*models.PVZ{first},
+			serviceErr:          errors.New("database error"),
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/json",
+			expectedBody:        `[{"id":"11111111-1111-1111-1111-111111111111","city":"Москва","registrationDate":"2024-01-02T03:04:05Z"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, mockService := setupPVZTest()
+			mockService.On("ExportPVZ", mock.Anything).Return(tc.pvzs, tc.serviceErr)
+
+			req := httptest.NewRequest("GET", "/pvz/export"+tc.query, nil)
+			w := httptest.NewRecorder()
+
+			handler.ExportPVZ(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Equal(t, tc.expectedContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tc.expectedBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestExportPVZ_InvalidFormat(t *testing.T) {
+	handler, mockService := setupPVZTest()
+
+	req := httptest.NewRequest("GET", "/pvz/export?format=xml", nil)
+	w := httptest.NewRecorder()
+
+	handler.ExportPVZ(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "ExportPVZ", mock.Anything)
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"pvz-service/internal/api/respond"
+	"pvz-service/internal/logger"
+)
+
+const (
+	componentUp   = "up"
+	componentDown = "down"
+
+	readinessReady    = "ready"
+	readinessNotReady = "not_ready"
+
+	// readinessPingTimeout ограничивает проверку базы, чтобы /readyz не зависал
+	readinessPingTimeout = 2 * time.Second
+)
+
+// ServingStatus - флаг, который сервер выставляет, пока принимает соединения
+type ServingStatus struct {
+	serving atomic.Bool
+}
+
+// SetServing обновляет флаг
+func (s *ServingStatus) SetServing(serving bool) {
+	s.serving.Store(serving)
+}
+
+// Serving сообщает, принимает ли сервер соединения
+func (s *ServingStatus) Serving() bool {
+	return s.serving.Load()
+}
+
+// Pinger проверяет доступность базы данных
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// ReadinessResponse - общий статус и состояние каждого компонента
+type ReadinessResponse struct {
+	Status     string            `json:"status"`
+	Components map[string]string `json:"components"`
+}
+
+type ReadinessHandler struct {
+	db      Pinger
+	grpc    *ServingStatus
+	metrics *ServingStatus
+}
+
+func NewReadinessHandler(db Pinger, grpc, metrics *ServingStatus) *ReadinessHandler {
+	return &ReadinessHandler{
+		db:      db,
+		grpc:    grpc,
+		metrics: metrics,
+	}
+}
+
+// Ready отвечает 200, если база, gRPC и сервер метрик доступны, иначе 503
+func (h *ReadinessHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	ctx, cancel := context.WithTimeout(r.Context(), readinessPingTimeout)
+	defer cancel()
+
+	dbStatus := componentUp
+	if err := h.db.PingContext(ctx); err != nil {
+		log.Warn("база данных не готова", "error", err)
+		dbStatus = componentDown
+	}
+
+	response := ReadinessResponse{
+		Status: readinessReady,
+		Components: map[string]string{
+			"db":      dbStatus,
+			"grpc":    servingComponent(h.grpc),
+			"metrics": servingComponent(h.metrics),
+		},
+	}
+
+	status := http.StatusOK
+	for name, state := range response.Components {
+		if state != componentUp {
+			log.Warn("компонент не готов", "component", name)
+			response.Status = readinessNotReady
+			status = http.StatusServiceUnavailable
+		}
+	}
+
+	respond.JSON(w, r, status, response)
+}
+
+func servingComponent(status *ServingStatus) string {
+	if status != nil && status.Serving() {
+		return componentUp
+	}
+	return componentDown
+}
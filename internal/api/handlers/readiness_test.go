@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubPinger struct {
+	err error
+}
+
+func (p stubPinger) PingContext(ctx context.Context) error {
+	return p.err
+}
+
+func servingStatus(serving bool) *ServingStatus {
+	status := &ServingStatus{}
+	status.SetServing(serving)
+	return status
+}
+
+func TestReadinessHandler_Ready(t *testing.T) {
+	testCases := []struct {
+		name               string
+		dbErr              error
+		grpcServing        bool
+		metricsServing     bool
+		expectedStatusCode int
+		expected           ReadinessResponse
+	}{
+		{
+			name:               "All Components Up",
+			grpcServing:        true,
+			metricsServing:     true,
+			expectedStatusCode: http.StatusOK,
+			expected: ReadinessResponse{
+				Status:     "ready",
+				Components: map[string]string{"db": "up", "grpc": "up", "metrics": "up"},
+			},
+		},
+		{
+			name:               "gRPC Down",
+			grpcServing:        false,
+			metricsServing:     true,
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expected: ReadinessResponse{
+				Status:     "not_ready",
+				Components: map[string]string{"db": "up", "grpc": "down", "metrics": "up"},
+			},
+		},
+		{
+			name:               "DB And Metrics Down",
+			dbErr:              errors.New("connection refused"),
+			grpcServing:        true,
+			metricsServing:     false,
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expected: ReadinessResponse{
+				Status:     "not_ready",
+				Components: map[string]string{"db": "down", "grpc": "up", "metrics": "down"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewReadinessHandler(stubPinger{err: tc.dbErr}, servingStatus(tc.grpcServing), servingStatus(tc.metricsServing))
+
+			req := httptest.NewRequest("GET", "/readyz", nil)
+			w := httptest.NewRecorder()
+
+			handler.Ready(w, req)
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+
+			var response ReadinessResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tc.expected, response)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"time"
+
+	"pvz-service/internal/api/respond"
+	"pvz-service/internal/domain/models"
+	"pvz-service/internal/logger"
+)
+
+// receptionExportCSVHeader - заголовок CSV одного ПВЗ: строка на товар, приемка без товаров
+// занимает одну строку с пустыми полями товара
+var receptionExportCSVHeader = []string{
+	"reception_id", "reception_date_time", "reception_status",
+	"product_id", "product_date_time", "product_type", "sequence_num",
+}
+
+// writeReceptionExportCSV пишет приемки ПВЗ в CSV, даты - в RFC3339
+func writeReceptionExportCSV(w *csv.Writer, receptions []*models.Reception) error {
+	if err := w.Write(receptionExportCSVHeader); err != nil {
+		return err
+	}
+
+	for _, reception := range receptions {
+		prefix := []string{
+			reception.ID.String(),
+			reception.DateTime.UTC().Format(time.RFC3339Nano),
+			string(reception.Status),
+		}
+		if len(reception.Products) == 0 {
+			if err := w.Write(append(prefix, "", "", "", "")); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, product := range reception.Products {
+			record := append(append([]string{}, prefix...),
+				product.ID.String(),
+				product.DateTime.UTC().Format(time.RFC3339Nano),
+				string(product.Type),
+				strconv.Itoa(product.SequenceNum),
+			)
+			if err := w.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// ExportReceptions отдает ZIP-архив с CSV-файлом <pvzId>.csv на каждый ПВЗ, у которого есть
+// приемки с датой в [from, to) (оба параметра обязательны, RFC3339). Приемки в файле идут
+// от новых к старым, товары приемки - по sequence_num. Архив пишется в ответ
+// по мере загрузки ПВЗ; пустой период дает корректный пустой архив. Ошибку до первого
+// файла клиент получает обычным ответом 500, после - ответ обрывается
+func (h *ReceptionHandler) ExportReceptions(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	query := r.URL.Query()
+	fromStr, toStr := query.Get("from"), query.Get("to")
+	log.Info("запрос на выгрузку приемок", "from", fromStr, "to", toStr)
+
+	from, err := time.Parse(time.RFC3339, fromStr)
+	if err != nil {
+		log.Warn("некорректный формат from", "from", fromStr, "error", err)
+		respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeInvalidDate, "Invalid from format. Use RFC3339 format", err)
+		return
+	}
+	to, err := time.Parse(time.RFC3339, toStr)
+	if err != nil {
+		log.Warn("некорректный формат to", "to", toStr, "error", err)
+		respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeInvalidDate, "Invalid to format. Use RFC3339 format", err)
+		return
+	}
+	if !from.Before(to) {
+		log.Warn("пустой или обратный период выгрузки", "from", from, "to", to)
+		respond.Error(w, r, http.StatusBadRequest, "from must be before to", nil)
+		return
+	}
+
+	// Выгрузка за месяц может писаться дольше WriteTimeout сервера
+	controller := http.NewResponseController(w)
+	if err := controller.SetWriteDeadline(time.Time{}); err != nil {
+		log.Debug("не удалось снять дедлайн записи для выгрузки приемок", "error", err)
+	}
+
+	var archive *zip.Writer
+	start := func() {
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", `attachment; filename="receptions.zip"`)
+		w.WriteHeader(http.StatusOK)
+		archive = zip.NewWriter(w)
+	}
+
+	files := 0
+	err = h.receptionService.ExportReceptions(r.Context(), from, to, func(pvz *models.PVZ, receptions []*models.Reception) error {
+		if archive == nil {
+			start()
+		}
+
+		file, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     pvz.ID.String() + ".csv",
+			Method:   zip.Deflate,
+			Modified: time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		if err := writeReceptionExportCSV(csv.NewWriter(file), receptions); err != nil {
+			return err
+		}
+		if err := archive.Flush(); err != nil {
+			return err
+		}
+		files++
+		// Отдаем клиенту готовый файл, не дожидаясь следующего ПВЗ
+		if err := controller.Flush(); err != nil {
+			log.Debug("не удалось сбросить буфер выгрузки приемок", "error", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if archive == nil {
+			logRequestError(log, "ошибка выгрузки приемок", err, "from", from, "to", to)
+			respond.Error(w, r, http.StatusInternalServerError, "Failed to export receptions", err)
+			return
+		}
+		logRequestError(log, "выгрузка приемок прервана", err, "from", from, "to", to, "files", files)
+		return
+	}
+
+	if archive == nil {
+		start()
+	}
+	if err := archive.Close(); err != nil {
+		logRequestError(log, "ошибка записи выгрузки приемок", err, "from", from, "to", to, "files", files)
+		return
+	}
+
+	log.Info("приемки выгружены", "from", from, "to", to, "files", files)
+}
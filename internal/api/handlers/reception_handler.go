@@ -2,8 +2,13 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
+	"pvz-service/internal/api/middleware"
+	"pvz-service/internal/api/respond"
 	"pvz-service/internal/api/validator"
 	"pvz-service/internal/domain/interfaces"
 	"pvz-service/internal/domain/models"
@@ -15,14 +20,22 @@ import (
 
 type ReceptionHandler struct {
 	receptionService interfaces.ReceptionService
+	maxPage          int
 }
 
 func NewReceptionHandler(receptionService interfaces.ReceptionService) *ReceptionHandler {
 	return &ReceptionHandler{
 		receptionService: receptionService,
+		maxPage:          defaultMaxPage,
 	}
 }
 
+// WithMaxPage ограничивает номер страницы в списке приемок. Ноль - без ограничения
+func (h *ReceptionHandler) WithMaxPage(maxPage int) *ReceptionHandler {
+	h.maxPage = maxPage
+	return h
+}
+
 func (h *ReceptionHandler) CreateReception(w http.ResponseWriter, r *http.Request) {
 	log := logger.FromContext(r.Context())
 	log.Info("запрос на создание приемки")
@@ -30,7 +43,7 @@ func (h *ReceptionHandler) CreateReception(w http.ResponseWriter, r *http.Reques
 	var req models.ReceptionCreateRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		log.Warn("ошибка декодирования JSON", "error", err)
-		sendErrorResponse(w, "Invalid request format", http.StatusBadRequest, err)
+		respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeInvalidRequest, "Invalid request format", err)
 		return
 	}
 
@@ -41,14 +54,38 @@ func (h *ReceptionHandler) CreateReception(w http.ResponseWriter, r *http.Reques
 			"pvz_id", req.PVZID,
 			"validation_errors", validator.FormatValidationErrors(err),
 		)
-		sendErrorResponse(w, "Validation failed: "+validator.FormatValidationErrors(err), http.StatusBadRequest, nil)
+		respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeValidationFailed, "Validation failed: "+validator.FormatValidationErrors(err), nil)
 		return
 	}
 
-	reception, err := h.receptionService.CreateReception(r.Context(), req.PVZID)
+	reuseOpen := false
+	if value := r.URL.Query().Get("reuseOpen"); value != "" {
+		var err error
+		if reuseOpen, err = strconv.ParseBool(value); err != nil {
+			log.Warn("некорректное значение reuseOpen", "reuseOpen", value, "error", err)
+			respond.Error(w, r, http.StatusBadRequest, "Invalid reuseOpen value. Use true or false", nil)
+			return
+		}
+	}
+
+	var reception *models.Reception
+	var reused bool
+	var err error
+	if reuseOpen {
+		reception, reused, err = h.receptionService.CreateOrReuseReception(r.Context(), req.PVZID)
+	} else {
+		reception, err = h.receptionService.CreateReception(r.Context(), req.PVZID)
+	}
 	if err != nil {
 		log.Error("ошибка создания приемки", "pvz_id", req.PVZID, "error", err)
-		sendErrorResponse(w, "Unable to create reception", http.StatusBadRequest, err)
+		respond.Error(w, r, http.StatusBadRequest, "Unable to create reception", err)
+		return
+	}
+
+	// Уже открытая приемка возвращается как есть, без Location: ничего не создано
+	if reused {
+		log.Info("возвращена уже открытая приемка", "reception_id", reception.ID, "pvz_id", reception.PVZID)
+		respond.JSON(w, r, http.StatusOK, reception)
 		return
 	}
 
@@ -58,9 +95,8 @@ func (h *ReceptionHandler) CreateReception(w http.ResponseWriter, r *http.Reques
 		"status", reception.Status,
 	)
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(reception)
+	w.Header().Set("Location", "/receptions/"+reception.ID.String())
+	respond.JSON(w, r, http.StatusCreated, reception)
 }
 
 func (h *ReceptionHandler) CloseLastReception(w http.ResponseWriter, r *http.Request) {
@@ -71,17 +107,15 @@ func (h *ReceptionHandler) CloseLastReception(w http.ResponseWriter, r *http.Req
 
 	log.Info("запрос на закрытие последней приемки", "pvz_id", pvzIDStr)
 
-	pvzID, err := uuid.Parse(pvzIDStr)
-	if err != nil {
-		log.Warn("некорректный формат UUID для ПВЗ", "pvz_id", pvzIDStr, "error", err)
-		sendErrorResponse(w, "Invalid PVZ ID format", http.StatusBadRequest, err)
+	pvzID, ok := pathUUID(w, r, "pvzId")
+	if !ok {
 		return
 	}
 
 	reception, err := h.receptionService.CloseLastReception(r.Context(), pvzID)
 	if err != nil {
 		log.Error("ошибка закрытия последней приемки", "pvz_id", pvzID, "error", err)
-		sendErrorResponse(w, "Unable to close reception", http.StatusBadRequest, err)
+		respond.Error(w, r, http.StatusBadRequest, "Unable to close reception", err)
 		return
 	}
 
@@ -90,8 +124,71 @@ func (h *ReceptionHandler) CloseLastReception(w http.ResponseWriter, r *http.Req
 		"pvz_id", reception.PVZID,
 	)
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(reception)
+	respond.JSON(w, r, http.StatusOK, reception)
+}
+
+// TouchCurrentReception отмечает активность в открытой приемке ПВЗ, откладывая ее автозакрытие
+func (h *ReceptionHandler) TouchCurrentReception(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	pvzIDStr := mux.Vars(r)["pvzId"]
+	log.Info("запрос на продление открытой приемки", "pvz_id", pvzIDStr)
+
+	pvzID, ok := pathUUID(w, r, "pvzId")
+	if !ok {
+		return
+	}
+
+	reception, err := h.receptionService.TouchReception(r.Context(), pvzID)
+	if err != nil {
+		if errors.Is(err, models.ErrReceptionNotFound) {
+			log.Warn("открытая приемка не найдена", "pvz_id", pvzID)
+			respond.ErrorWithCode(w, r, http.StatusNotFound, respond.CodeReceptionNotFound, "Reception not found", err)
+			return
+		}
+		logRequestError(log, "ошибка продления открытой приемки", err, "pvz_id", pvzID)
+		respond.Error(w, r, http.StatusInternalServerError, "Unable to touch reception", err)
+		return
+	}
+
+	log.Info("открытая приемка продлена", "reception_id", reception.ID, "pvz_id", pvzID)
+
+	respond.JSON(w, r, http.StatusOK, reception)
+}
+
+func (h *ReceptionHandler) CloseReception(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	idStr := mux.Vars(r)["id"]
+	log.Info("запрос на закрытие приемки", "reception_id", idStr)
+
+	id, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	reception, err := h.receptionService.CloseReceptionByID(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrReceptionNotFound):
+			log.Warn("приемка не найдена", "reception_id", id)
+			respond.ErrorWithCode(w, r, http.StatusNotFound, respond.CodeReceptionNotFound, "Reception not found", err)
+		case errors.Is(err, models.ErrReceptionClosed):
+			log.Warn("приемка уже закрыта", "reception_id", id)
+			respond.ErrorWithCode(w, r, http.StatusConflict, respond.CodeReceptionClosed, "Reception is closed", err)
+		default:
+			logRequestError(log, "ошибка закрытия приемки", err, "reception_id", id)
+			respond.Error(w, r, http.StatusInternalServerError, "Unable to close reception", err)
+		}
+		return
+	}
+
+	log.Info("приемка успешно закрыта",
+		"reception_id", reception.ID,
+		"pvz_id", reception.PVZID,
+	)
+
+	respond.JSON(w, r, http.StatusOK, reception)
 }
 
 func (h *ReceptionHandler) GetReception(w http.ResponseWriter, r *http.Request) {
@@ -102,23 +199,26 @@ func (h *ReceptionHandler) GetReception(w http.ResponseWriter, r *http.Request)
 
 	log.Info("запрос на получение приемки", "reception_id", idStr)
 
-	id, err := uuid.Parse(idStr)
-	if err != nil {
-		log.Warn("некорректный формат UUID для приемки", "reception_id", idStr, "error", err)
-		sendErrorResponse(w, "Invalid reception ID format", http.StatusBadRequest, err)
+	id, ok := pathUUID(w, r, "id")
+	if !ok {
 		return
 	}
 
 	reception, err := h.receptionService.GetReceptionByID(r.Context(), id)
+	if errors.Is(err, models.ErrReceptionNotFound) {
+		log.Warn("приемка не найдена", "reception_id", id)
+		respond.ErrorWithCode(w, r, http.StatusNotFound, respond.CodeReceptionNotFound, "Reception not found", err)
+		return
+	}
 	if err != nil {
-		log.Error("ошибка получения приемки", "reception_id", id, "error", err)
-		sendErrorResponse(w, "Error retrieving reception", http.StatusInternalServerError, err)
+		logRequestError(log, "ошибка получения приемки", err, "reception_id", id)
+		respond.Error(w, r, http.StatusInternalServerError, "Error retrieving reception", err)
 		return
 	}
 
 	if reception == nil {
 		log.Warn("приемка не найдена", "reception_id", id)
-		sendErrorResponse(w, "Reception not found", http.StatusNotFound, nil)
+		respond.ErrorWithCode(w, r, http.StatusNotFound, respond.CodeReceptionNotFound, "Reception not found", nil)
 		return
 	}
 
@@ -128,6 +228,345 @@ func (h *ReceptionHandler) GetReception(w http.ResponseWriter, r *http.Request)
 		"status", reception.Status,
 	)
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(reception)
+	respond.JSON(w, r, http.StatusOK, reception)
+}
+
+func (h *ReceptionHandler) GetReceptionSummary(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	idStr := mux.Vars(r)["id"]
+	log.Info("запрос на получение сводки по приемке", "reception_id", idStr)
+
+	id, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	summary, err := h.receptionService.GetReceptionSummary(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, models.ErrReceptionNotFound) {
+			log.Warn("приемка не найдена", "reception_id", id)
+			respond.ErrorWithCode(w, r, http.StatusNotFound, respond.CodeReceptionNotFound, "Reception not found", err)
+			return
+		}
+		logRequestError(log, "ошибка получения сводки по приемке", err, "reception_id", id)
+		respond.Error(w, r, http.StatusInternalServerError, "Error retrieving reception summary", err)
+		return
+	}
+
+	log.Info("сводка по приемке успешно получена", "reception_id", id, "items_count", summary.ItemsCount)
+
+	respond.JSON(w, r, http.StatusOK, summary)
+}
+
+func (h *ReceptionHandler) GetReceptionTypeCounts(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	idStr := mux.Vars(r)["id"]
+	log.Info("запрос количества товаров приемки по типам", "reception_id", idStr)
+
+	id, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	counts, err := h.receptionService.GetReceptionTypeCounts(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, models.ErrReceptionNotFound) {
+			log.Warn("приемка не найдена", "reception_id", id)
+			respond.ErrorWithCode(w, r, http.StatusNotFound, respond.CodeReceptionNotFound, "Reception not found", err)
+			return
+		}
+		logRequestError(log, "ошибка подсчета товаров приемки по типам", err, "reception_id", id)
+		respond.Error(w, r, http.StatusInternalServerError, "Error retrieving reception type counts", err)
+		return
+	}
+
+	log.Info("количество товаров по типам успешно получено", "reception_id", id)
+
+	respond.JSON(w, r, http.StatusOK, counts)
+}
+
+// GetReceptionSequenceGaps возвращает номера товаров, пропущенные в нумерации приемки после удалений
+func (h *ReceptionHandler) GetReceptionSequenceGaps(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	idStr := mux.Vars(r)["id"]
+	log.Info("запрос пропусков в нумерации товаров приемки", "reception_id", idStr)
+
+	id, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	gaps, err := h.receptionService.GetReceptionSequenceGaps(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, models.ErrReceptionNotFound) {
+			log.Warn("приемка не найдена", "reception_id", id)
+			respond.ErrorWithCode(w, r, http.StatusNotFound, respond.CodeReceptionNotFound, "Reception not found", err)
+			return
+		}
+		logRequestError(log, "ошибка поиска пропусков в нумерации товаров", err, "reception_id", id)
+		respond.Error(w, r, http.StatusInternalServerError, "Error retrieving sequence gaps", err)
+		return
+	}
+
+	log.Info("пропуски в нумерации товаров получены", "reception_id", id, "missing", len(gaps.Missing))
+
+	respond.JSON(w, r, http.StatusOK, gaps)
+}
+
+// ResequenceReception перенумеровывает товары открытой приемки подряд от 1, закрывая пропуски после удалений
+func (h *ReceptionHandler) ResequenceReception(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	idStr := mux.Vars(r)["id"]
+	log.Info("запрос перенумерации товаров приемки", "reception_id", idStr)
+
+	id, ok := pathUUID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	result, err := h.receptionService.ResequenceReception(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrReceptionNotFound):
+			log.Warn("приемка не найдена", "reception_id", id)
+			respond.ErrorWithCode(w, r, http.StatusNotFound, respond.CodeReceptionNotFound, "Reception not found", err)
+		case errors.Is(err, models.ErrReceptionClosed):
+			log.Warn("приемка закрыта", "reception_id", id)
+			respond.ErrorWithCode(w, r, http.StatusConflict, respond.CodeReceptionClosed, "Reception is closed", err)
+		default:
+			logRequestError(log, "ошибка перенумерации товаров приемки", err, "reception_id", id)
+			respond.Error(w, r, http.StatusInternalServerError, "Error resequencing products", err)
+		}
+		return
+	}
+
+	log.Info("товары приемки перенумерованы", "reception_id", id, "products", result.Products)
+
+	respond.JSON(w, r, http.StatusOK, result)
+}
+
+func (h *ReceptionHandler) ListReceptions(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	query := r.URL.Query()
+	pageStr := query.Get("page")
+	limitStr := query.Get("limit")
+	pvzIDStr := query.Get("pvzId")
+	status := query.Get("status")
+	startDateStr := query.Get("startDate")
+	endDateStr := query.Get("endDate")
+	containsType := models.ProductType(query.Get("containsType"))
+	sortBy := query.Get("sortBy")
+	sortOrder := query.Get("sortOrder")
+	withTotal := parseWithTotal(r)
+
+	log.Info("запрос на получение списка приемок",
+		"page", pageStr,
+		"limit", limitStr,
+		"pvzId", pvzIDStr,
+		"status", status,
+		"startDate", startDateStr,
+		"endDate", endDateStr,
+		"containsType", containsType,
+		"sortBy", sortBy,
+		"sortOrder", sortOrder,
+	)
+
+	page, limit, ok := parsePagination(w, r, pageStr, limitStr, h.maxPage)
+	if !ok {
+		return
+	}
+
+	options := models.ReceptionListOptions{
+		Page:         page,
+		Limit:        limit,
+		Status:       status,
+		ContainsType: containsType,
+		SortBy:       sortBy,
+		SortOrder:    sortOrder,
+		SkipTotal:    !withTotal,
+	}
+
+	var err error
+
+	if pvzIDStr != "" {
+		options.PVZID, err = uuid.Parse(pvzIDStr)
+		if err != nil {
+			middleware.WriteInvalidUUID(w, r, "pvzId", pvzIDStr)
+			return
+		}
+	}
+
+	if startDateStr != "" {
+		options.FromDate, err = time.Parse(time.RFC3339, startDateStr)
+		if err != nil {
+			log.Warn("некорректный формат startDate", "startDate", startDateStr, "error", err)
+			respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeInvalidDate, "Invalid startDate format. Use RFC3339 format", err)
+			return
+		}
+	}
+
+	if endDateStr != "" {
+		options.ToDate, err = time.Parse(time.RFC3339, endDateStr)
+		if err != nil {
+			log.Warn("некорректный формат endDate", "endDate", endDateStr, "error", err)
+			respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeInvalidDate, "Invalid endDate format. Use RFC3339 format", err)
+			return
+		}
+	}
+
+	if containsType != "" && !containsType.Valid() {
+		log.Warn("недопустимое значение containsType", "containsType", containsType)
+		respond.Error(w, r, http.StatusBadRequest, "Invalid containsType value. Use электроника, одежда or обувь", nil)
+		return
+	}
+
+	if sortBy != "" && !models.AllowedReceptionSortFields[sortBy] {
+		log.Warn("недопустимое значение sortBy", "sortBy", sortBy)
+		respond.Error(w, r, http.StatusBadRequest, "Invalid sortBy value. Use date_time or status", nil)
+		return
+	}
+
+	if sortOrder != "" && sortOrder != models.SortOrderAsc && sortOrder != models.SortOrderDesc {
+		log.Warn("недопустимое значение sortOrder", "sortOrder", sortOrder)
+		respond.Error(w, r, http.StatusBadRequest, "Invalid sortOrder value. Use asc or desc", nil)
+		return
+	}
+
+	receptions, total, err := h.receptionService.ListReceptions(r.Context(), options)
+	if err != nil {
+		logRequestError(log, "ошибка получения списка приемок", err)
+		respond.Error(w, r, http.StatusInternalServerError, "Failed to retrieve receptions", err)
+		return
+	}
+
+	log.Info("список приемок успешно получен", "count", len(receptions), "total", total)
+	writeReceptionList(w, r, receptions, page, limit, total, withTotal)
+}
+
+// GetReceptionStats отдает количество приемок по статусам; pvzId в query ограничивает подсчет одним ПВЗ
+func (h *ReceptionHandler) GetReceptionStats(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	pvzIDStr := r.URL.Query().Get("pvzId")
+	log.Info("запрос статистики приемок", "pvzId", pvzIDStr)
+
+	var pvzID *uuid.UUID
+	if pvzIDStr != "" {
+		id, err := uuid.Parse(pvzIDStr)
+		if err != nil {
+			middleware.WriteInvalidUUID(w, r, "pvzId", pvzIDStr)
+			return
+		}
+		pvzID = &id
+	}
+
+	stats, err := h.receptionService.GetReceptionStats(r.Context(), pvzID)
+	if err != nil {
+		if errors.Is(err, models.ErrPVZNotFound) {
+			log.Warn("ПВЗ не найден", "pvzId", pvzIDStr)
+			respond.ErrorWithCode(w, r, http.StatusNotFound, respond.CodePVZNotFound, "PVZ not found", err)
+			return
+		}
+		logRequestError(log, "ошибка получения статистики приемок", err)
+		respond.Error(w, r, http.StatusInternalServerError, "Failed to retrieve reception stats", err)
+		return
+	}
+
+	log.Info("статистика приемок успешно получена", "total", stats.Total)
+
+	respond.JSON(w, r, http.StatusOK, stats)
+}
+
+// PurgeReceptions удаляет закрытые приемки старше обязательного параметра before (RFC3339)
+func (h *ReceptionHandler) PurgeReceptions(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	beforeStr := r.URL.Query().Get("before")
+	log.Info("запрос на удаление старых приемок", "before", beforeStr)
+
+	before, err := time.Parse(time.RFC3339, beforeStr)
+	if err != nil {
+		log.Warn("некорректный формат before", "before", beforeStr, "error", err)
+		respond.ErrorWithCode(w, r, http.StatusBadRequest, respond.CodeInvalidDate, "Invalid before format. Use RFC3339 format", err)
+		return
+	}
+
+	deleted, err := h.receptionService.PurgeReceptions(r.Context(), before)
+	if err != nil {
+		logRequestError(log, "ошибка удаления старых приемок", err, "before", before)
+		respond.Error(w, r, http.StatusInternalServerError, "Failed to purge receptions", err)
+		return
+	}
+
+	log.Info("старые приемки удалены", "before", before, "deleted", deleted)
+
+	respond.JSON(w, r, http.StatusOK, models.PurgeReceptionsResponse{Deleted: deleted})
+}
+
+// ListPVZReceptions отдает приемки одного ПВЗ с пагинацией и фильтром по статусу
+func (h *ReceptionHandler) ListPVZReceptions(w http.ResponseWriter, r *http.Request) {
+	log := logger.FromContext(r.Context())
+
+	pvzIDStr := mux.Vars(r)["pvzId"]
+	query := r.URL.Query()
+	pageStr := query.Get("page")
+	limitStr := query.Get("limit")
+	status := query.Get("status")
+	withTotal := parseWithTotal(r)
+
+	log.Info("запрос на получение приемок ПВЗ",
+		"pvz_id", pvzIDStr,
+		"page", pageStr,
+		"limit", limitStr,
+		"status", status,
+	)
+
+	pvzID, ok := pathUUID(w, r, "pvzId")
+	if !ok {
+		return
+	}
+
+	page, limit, ok := parsePagination(w, r, pageStr, limitStr, h.maxPage)
+	if !ok {
+		return
+	}
+
+	receptions, total, err := h.receptionService.ListReceptionsByPVZ(r.Context(), pvzID, models.ReceptionListOptions{
+		Page:      page,
+		Limit:     limit,
+		Status:    status,
+		SkipTotal: !withTotal,
+	})
+	if err != nil {
+		if errors.Is(err, models.ErrPVZNotFound) {
+			log.Warn("ПВЗ не найден", "pvz_id", pvzID)
+			respond.ErrorWithCode(w, r, http.StatusNotFound, respond.CodePVZNotFound, "PVZ not found", err)
+			return
+		}
+		logRequestError(log, "ошибка получения приемок ПВЗ", err, "pvz_id", pvzID)
+		respond.Error(w, r, http.StatusInternalServerError, "Failed to retrieve receptions", err)
+		return
+	}
+
+	log.Info("приемки ПВЗ успешно получены", "pvz_id", pvzID, "count", len(receptions), "total", total)
+	writeReceptionList(w, r, receptions, page, limit, total, withTotal)
+}
+
+// writeReceptionList отправляет страницу приемок вместе с данными пагинации
+func writeReceptionList(w http.ResponseWriter, r *http.Request, receptions []*models.Reception, page, limit, total int, withTotal bool) {
+	if receptions == nil {
+		receptions = []*models.Reception{}
+	}
+
+	response := map[string]interface{}{
+		"data":       receptions,
+		"pagination": paginationEnvelope(page, limit, total, withTotal),
+	}
+
+	respond.JSON(w, r, http.StatusOK, response)
 }
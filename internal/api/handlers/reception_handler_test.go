@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"archive/zip"
 	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -16,6 +19,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"pvz-service/internal/api/respond"
 	"pvz-service/internal/domain/models"
 	"pvz-service/internal/logger"
 )
@@ -32,6 +36,14 @@ func (m *MockReceptionService) CreateReception(ctx context.Context, pvzID uuid.U
 	return args.Get(0).(*models.Reception), args.Error(1)
 }
 
+func (m *MockReceptionService) CreateOrReuseReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, bool, error) {
+	args := m.Called(ctx, pvzID)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).(*models.Reception), args.Bool(1), args.Error(2)
+}
+
 func (m *MockReceptionService) CloseLastReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error) {
 	args := m.Called(ctx, pvzID)
 	if args.Get(0) == nil {
@@ -40,6 +52,14 @@ func (m *MockReceptionService) CloseLastReception(ctx context.Context, pvzID uui
 	return args.Get(0).(*models.Reception), args.Error(1)
 }
 
+func (m *MockReceptionService) CloseReceptionByID(ctx context.Context, id uuid.UUID) (*models.Reception, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Reception), args.Error(1)
+}
+
 func (m *MockReceptionService) GetReceptionByID(ctx context.Context, id uuid.UUID) (*models.Reception, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -48,6 +68,93 @@ func (m *MockReceptionService) GetReceptionByID(ctx context.Context, id uuid.UUI
 	return args.Get(0).(*models.Reception), args.Error(1)
 }
 
+func (m *MockReceptionService) GetReceptionSummary(ctx context.Context, id uuid.UUID) (*models.ReceptionSummary, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReceptionSummary), args.Error(1)
+}
+
+func (m *MockReceptionService) GetReceptionTypeCounts(ctx context.Context, id uuid.UUID) (*models.ReceptionTypeCounts, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReceptionTypeCounts), args.Error(1)
+}
+
+func (m *MockReceptionService) GetReceptionSequenceGaps(ctx context.Context, id uuid.UUID) (*models.ReceptionSequenceGaps, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReceptionSequenceGaps), args.Error(1)
+}
+
+func (m *MockReceptionService) ResequenceReception(ctx context.Context, id uuid.UUID) (*models.ReceptionResequence, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReceptionResequence), args.Error(1)
+}
+
+func (m *MockReceptionService) ListReceptions(ctx context.Context, options models.ReceptionListOptions) ([]*models.Reception, int, error) {
+	args := m.Called(ctx, options)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.Reception), args.Int(1), args.Error(2)
+}
+
+func (m *MockReceptionService) ListReceptionsByPVZ(ctx context.Context, pvzID uuid.UUID, options models.ReceptionListOptions) ([]*models.Reception, int, error) {
+	args := m.Called(ctx, pvzID, options)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.Reception), args.Int(1), args.Error(2)
+}
+
+func (m *MockReceptionService) GetReceptionStats(ctx context.Context, pvzID *uuid.UUID) (*models.ReceptionStats, error) {
+	args := m.Called(ctx, pvzID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReceptionStats), args.Error(1)
+}
+
+// receptionExportGroup - приемки одного ПВЗ, которые мок ExportReceptions передает в fn
+type receptionExportGroup struct {
+	pvz        *models.PVZ
+	receptions []*models.Reception
+}
+
+func (m *MockReceptionService) ExportReceptions(ctx context.Context, from, to time.Time, fn func(pvz *models.PVZ, receptions []*models.Reception) error) error {
+	args := m.Called(ctx, from, to)
+	if groups, ok := args.Get(0).([]receptionExportGroup); ok {
+		for _, group := range groups {
+			if err := fn(group.pvz, group.receptions); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockReceptionService) PurgeReceptions(ctx context.Context, before time.Time) (int, error) {
+	args := m.Called(ctx, before)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockReceptionService) TouchReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error) {
+	args := m.Called(ctx, pvzID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Reception), args.Error(1)
+}
+
 func setupReceptionTest() (*ReceptionHandler, *MockReceptionService) {
 	mockService := new(MockReceptionService)
 	handler := NewReceptionHandler(mockService)
@@ -82,6 +189,7 @@ func TestCreateReception_Success(t *testing.T) {
 	handler.CreateReception(w, req)
 
 	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "/receptions/"+receptionID.String(), w.Header().Get("Location"))
 
 	var response models.Reception
 	err := json.Unmarshal(w.Body.Bytes(), &response)
@@ -93,6 +201,80 @@ func TestCreateReception_Success(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestCreateReception_ReuseOpen(t *testing.T) {
+	pvzID := uuid.New()
+	reception := &models.Reception{
+		ID:       uuid.New(),
+		DateTime: time.Now(),
+		PVZID:    pvzID,
+		Status:   models.StatusInProgress,
+	}
+
+	testCases := []struct {
+		name             string
+		reused           bool
+		expectedStatus   int
+		expectedLocation string
+	}{
+		{name: "Existing Open Reception", reused: true, expectedStatus: http.StatusOK},
+		{name: "No Open Reception", expectedStatus: http.StatusCreated, expectedLocation: "/receptions/" + reception.ID.String()},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, mockService := setupReceptionTest()
+
+			jsonBody, _ := json.Marshal(models.ReceptionCreateRequest{PVZID: pvzID})
+			req := httptest.NewRequest("POST", "/receptions?reuseOpen=true", bytes.NewBuffer(jsonBody))
+			w := httptest.NewRecorder()
+
+			mockService.On("CreateOrReuseReception", mock.Anything, pvzID).Return(reception, tc.reused, nil)
+
+			handler.CreateReception(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Equal(t, tc.expectedLocation, w.Header().Get("Location"))
+
+			var response models.Reception
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, reception.ID, response.ID)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateReception_StrictByDefault(t *testing.T) {
+	handler, mockService := setupReceptionTest()
+
+	pvzID := uuid.New()
+	jsonBody, _ := json.Marshal(models.ReceptionCreateRequest{PVZID: pvzID})
+	req := httptest.NewRequest("POST", "/receptions?reuseOpen=false", bytes.NewBuffer(jsonBody))
+	w := httptest.NewRecorder()
+
+	mockService.On("CreateReception", mock.Anything, pvzID).
+		Return(nil, errors.New("there is already an open reception for this pvz"))
+
+	handler.CreateReception(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+	mockService.AssertNotCalled(t, "CreateOrReuseReception", mock.Anything, mock.Anything)
+}
+
+func TestCreateReception_InvalidReuseOpen(t *testing.T) {
+	handler, mockService := setupReceptionTest()
+
+	jsonBody, _ := json.Marshal(models.ReceptionCreateRequest{PVZID: uuid.New()})
+	req := httptest.NewRequest("POST", "/receptions?reuseOpen=maybe", bytes.NewBuffer(jsonBody))
+	w := httptest.NewRecorder()
+
+	handler.CreateReception(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "CreateReception", mock.Anything, mock.Anything)
+	mockService.AssertNotCalled(t, "CreateOrReuseReception", mock.Anything, mock.Anything)
+}
+
 func TestCreateReception_InvalidJSON(t *testing.T) {
 	handler, _ := setupReceptionTest()
 
@@ -105,7 +287,7 @@ func TestCreateReception_InvalidJSON(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var response ErrorResponse
+	var response respond.ErrorResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
 	assert.Contains(t, response.Error, "Invalid request format")
@@ -127,7 +309,7 @@ func TestCreateReception_ValidationError(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var response ErrorResponse
+	var response respond.ErrorResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
 	assert.Contains(t, response.Error, "Validation failed")
@@ -153,7 +335,7 @@ func TestCreateReception_ServiceError(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var response ErrorResponse
+	var response respond.ErrorResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
 	assert.Equal(t, "Unable to create reception", response.Error)
@@ -218,10 +400,10 @@ func TestCloseLastReception_InvalidUUID(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var response ErrorResponse
+	var response respond.ErrorResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
-	assert.Contains(t, response.Error, "Invalid PVZ ID format")
+	assert.Contains(t, response.Error, "Invalid pvzId format")
 }
 
 func TestCloseLastReception_ServiceError(t *testing.T) {
@@ -245,7 +427,7 @@ func TestCloseLastReception_ServiceError(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var response ErrorResponse
+	var response respond.ErrorResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
 	assert.Equal(t, "Unable to close reception", response.Error)
@@ -253,6 +435,83 @@ func TestCloseLastReception_ServiceError(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestCloseReception_Success(t *testing.T) {
+	handler, mockService := setupReceptionTest()
+
+	receptionID := uuid.New()
+	pvzID := uuid.New()
+
+	req := httptest.NewRequest("POST", "/receptions/"+receptionID.String()+"/close", nil)
+	req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
+	req = mux.SetURLVars(req, map[string]string{"id": receptionID.String()})
+
+	w := httptest.NewRecorder()
+
+	mockService.On("CloseReceptionByID", mock.Anything, receptionID).Return(&models.Reception{
+		ID:       receptionID,
+		DateTime: time.Now(),
+		PVZID:    pvzID,
+		Status:   models.StatusClosed,
+	}, nil)
+
+	handler.CloseReception(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.Reception
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+	assert.Equal(t, receptionID, response.ID)
+	assert.Equal(t, models.StatusClosed, response.Status)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestCloseReception_AlreadyClosed(t *testing.T) {
+	handler, mockService := setupReceptionTest()
+
+	receptionID := uuid.New()
+
+	req := httptest.NewRequest("POST", "/receptions/"+receptionID.String()+"/close", nil)
+	req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
+	req = mux.SetURLVars(req, map[string]string{"id": receptionID.String()})
+
+	w := httptest.NewRecorder()
+
+	mockService.On("CloseReceptionByID", mock.Anything, receptionID).Return(nil, models.ErrReceptionClosed)
+
+	handler.CloseReception(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var response respond.ErrorResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+	assert.Equal(t, "Reception is closed", response.Error)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestCloseReception_NotFound(t *testing.T) {
+	handler, mockService := setupReceptionTest()
+
+	receptionID := uuid.New()
+
+	req := httptest.NewRequest("POST", "/receptions/"+receptionID.String()+"/close", nil)
+	req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
+	req = mux.SetURLVars(req, map[string]string{"id": receptionID.String()})
+
+	w := httptest.NewRecorder()
+
+	mockService.On("CloseReceptionByID", mock.Anything, receptionID).Return(nil, models.ErrReceptionNotFound)
+
+	handler.CloseReception(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	mockService.AssertExpectations(t)
+}
+
 func TestGetReception_Success(t *testing.T) {
 	handler, mockService := setupReceptionTest()
 
@@ -310,10 +569,10 @@ func TestGetReception_InvalidUUID(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	var response ErrorResponse
+	var response respond.ErrorResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
-	assert.Contains(t, response.Error, "Invalid reception ID format")
+	assert.Contains(t, response.Error, "Invalid id format")
 }
 
 func TestGetReception_NotFound(t *testing.T) {
@@ -337,7 +596,7 @@ func TestGetReception_NotFound(t *testing.T) {
 
 	assert.Equal(t, http.StatusNotFound, w.Code)
 
-	var response ErrorResponse
+	var response respond.ErrorResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
 	assert.Equal(t, "Reception not found", response.Error)
@@ -345,6 +604,28 @@ func TestGetReception_NotFound(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestGetReception_NotFoundError(t *testing.T) {
+	handler, mockService := setupReceptionTest()
+
+	receptionID := uuid.New()
+
+	req := httptest.NewRequest("GET", "/receptions/"+receptionID.String(), nil)
+	req = mux.SetURLVars(req, map[string]string{"id": receptionID.String()})
+	w := httptest.NewRecorder()
+
+	mockService.On("GetReceptionByID", mock.Anything, receptionID).Return(nil, models.ErrReceptionNotFound)
+
+	handler.GetReception(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var response respond.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Reception not found", response.Error)
+
+	mockService.AssertExpectations(t)
+}
+
 func TestGetReception_ServiceError(t *testing.T) {
 	handler, mockService := setupReceptionTest()
 
@@ -366,10 +647,851 @@ func TestGetReception_ServiceError(t *testing.T) {
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 
-	var response ErrorResponse
+	var response respond.ErrorResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	require.NoError(t, err)
 	assert.Equal(t, "Error retrieving reception", response.Error)
 
 	mockService.AssertExpectations(t)
 }
+
+func TestGetReception_ContextErrors(t *testing.T) {
+	testCases := []struct {
+		name            string
+		err             error
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{
+			name:            "Client Canceled",
+			err:             fmt.Errorf("error querying reception: %w", context.Canceled),
+			expectedStatus:  respond.StatusClientClosedRequest,
+			expectedMessage: "Request canceled",
+		},
+		{
+			name:            "Deadline Exceeded",
+			err:             fmt.Errorf("error querying reception: %w", context.DeadlineExceeded),
+			expectedStatus:  http.StatusRequestTimeout,
+			expectedMessage: "Request timeout",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, mockService := setupReceptionTest()
+
+			receptionID := uuid.New()
+
+			var logs bytes.Buffer
+			ctx, cancel := context.WithCancel(logger.WithLogger(context.Background(), logger.New(logger.Config{
+				Level:  logger.LevelDebug,
+				Format: "json",
+				Output: &logs,
+			})))
+			cancel()
+
+			req := httptest.NewRequest("GET", "/receptions/"+receptionID.String(), nil).WithContext(ctx)
+			req = mux.SetURLVars(req, map[string]string{"id": receptionID.String()})
+			w := httptest.NewRecorder()
+
+			mockService.On("GetReceptionByID", mock.Anything, receptionID).Return(nil, tc.err)
+
+			handler.GetReception(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			var response respond.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tc.expectedMessage, response.Error)
+			assert.Contains(t, logs.String(), "запрос прерван до завершения")
+			assert.NotContains(t, logs.String(), `"level":"ERROR"`)
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetReceptionSummary_Success(t *testing.T) {
+	handler, mockService := setupReceptionTest()
+
+	receptionID := uuid.New()
+	summary := &models.ReceptionSummary{
+		ItemsCount: 4,
+		ByType: map[models.ProductType]int{
+			models.TypeElectronics: 3,
+			models.TypeClothes:     0,
+			models.TypeFootwear:    1,
+		},
+	}
+
+	req := httptest.NewRequest("GET", "/receptions/"+receptionID.String()+"/summary", nil)
+	req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
+	req = mux.SetURLVars(req, map[string]string{"id": receptionID.String()})
+
+	w := httptest.NewRecorder()
+
+	mockService.On("GetReceptionSummary", mock.Anything, receptionID).Return(summary, nil)
+
+	handler.GetReceptionSummary(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"itemsCount":4,"byType":{"электроника":3,"одежда":0,"обувь":1}}`, w.Body.String())
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetReceptionSummary_NotFound(t *testing.T) {
+	handler, mockService := setupReceptionTest()
+
+	receptionID := uuid.New()
+
+	req := httptest.NewRequest("GET", "/receptions/"+receptionID.String()+"/summary", nil)
+	req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
+	req = mux.SetURLVars(req, map[string]string{"id": receptionID.String()})
+
+	w := httptest.NewRecorder()
+
+	mockService.On("GetReceptionSummary", mock.Anything, receptionID).Return(nil, models.ErrReceptionNotFound)
+
+	handler.GetReceptionSummary(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetReceptionTypeCounts_PartialTypes(t *testing.T) {
+	handler, mockService := setupReceptionTest()
+
+	receptionID := uuid.New()
+
+	req := httptest.NewRequest("GET", "/receptions/"+receptionID.String()+"/type_counts", nil)
+	req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
+	req = mux.SetURLVars(req, map[string]string{"id": receptionID.String()})
+
+	w := httptest.NewRecorder()
+
+	// В приемке только электроника и обувь: одежда должна вернуться нулем
+	mockService.On("GetReceptionTypeCounts", mock.Anything, receptionID).
+		Return(&models.ReceptionTypeCounts{Electronics: 2, Footwear: 5}, nil)
+
+	handler.GetReceptionTypeCounts(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"electronics":2,"clothes":0,"footwear":5}`, w.Body.String())
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetReceptionTypeCounts_NotFound(t *testing.T) {
+	handler, mockService := setupReceptionTest()
+
+	receptionID := uuid.New()
+
+	req := httptest.NewRequest("GET", "/receptions/"+receptionID.String()+"/type_counts", nil)
+	req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
+	req = mux.SetURLVars(req, map[string]string{"id": receptionID.String()})
+
+	w := httptest.NewRecorder()
+
+	mockService.On("GetReceptionTypeCounts", mock.Anything, receptionID).Return(nil, models.ErrReceptionNotFound)
+
+	handler.GetReceptionTypeCounts(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetReceptionTypeCounts_InvalidID(t *testing.T) {
+	handler, mockService := setupReceptionTest()
+
+	req := httptest.NewRequest("GET", "/receptions/not-a-uuid/type_counts", nil)
+	req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
+	req = mux.SetURLVars(req, map[string]string{"id": "not-a-uuid"})
+
+	w := httptest.NewRecorder()
+
+	handler.GetReceptionTypeCounts(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mockService.AssertNotCalled(t, "GetReceptionTypeCounts", mock.Anything, mock.Anything)
+}
+
+func TestListReceptions_ContainsType(t *testing.T) {
+	handler, mockService := setupReceptionTest()
+
+	receptions := []*models.Reception{
+		{ID: uuid.New(), DateTime: time.Now(), PVZID: uuid.New(), Status: models.StatusClosed},
+	}
+
+	req := httptest.NewRequest("GET", "/receptions?containsType=%D0%BE%D0%B1%D1%83%D0%B2%D1%8C&startDate=2025-01-01T00:00:00Z", nil)
+	req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
+	w := httptest.NewRecorder()
+
+	mockService.On("ListReceptions", mock.Anything, mock.MatchedBy(func(options models.ReceptionListOptions) bool {
+		return options.ContainsType == models.TypeFootwear &&
+			options.FromDate.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) &&
+			options.Page == 1 && options.Limit == 10
+	})).Return(receptions, 1, nil)
+
+	handler.ListReceptions(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data       []models.Reception `json:"data"`
+		Pagination map[string]int     `json:"pagination"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+	require.Len(t, response.Data, 1)
+	assert.Equal(t, receptions[0].ID, response.Data[0].ID)
+	assert.Equal(t, 1, response.Pagination["total"])
+
+	mockService.AssertExpectations(t)
+}
+
+func TestListReceptions_InvalidContainsType(t *testing.T) {
+	handler, mockService := setupReceptionTest()
+
+	req := httptest.NewRequest("GET", "/receptions?containsType=furniture", nil)
+	req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
+	w := httptest.NewRecorder()
+
+	handler.ListReceptions(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "ListReceptions", mock.Anything, mock.Anything)
+}
+
+func TestListReceptions_Sort(t *testing.T) {
+	testCases := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedSortBy string
+		expectedOrder  string
+	}{
+		{name: "Status Ascending", query: "sortBy=status&sortOrder=asc", expectedStatus: http.StatusOK, expectedSortBy: models.ReceptionSortStatus, expectedOrder: models.SortOrderAsc},
+		{name: "Default", expectedStatus: http.StatusOK},
+		{name: "Invalid Field", query: "sortBy=id%3BDROP%20TABLE%20receptions", expectedStatus: http.StatusBadRequest},
+		{name: "Invalid Order", query: "sortBy=status&sortOrder=sideways", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, mockService := setupReceptionTest()
+
+			req := httptest.NewRequest("GET", "/receptions?"+tc.query, nil)
+			req = req.WithContext(logger.WithLogger(req.Context(), logger.New(logger.Config{Level: logger.LevelDebug, Format: "text"})))
+			w := httptest.NewRecorder()
+
+			if tc.expectedStatus == http.StatusOK {
+				mockService.On("ListReceptions", mock.Anything, mock.MatchedBy(func(options models.ReceptionListOptions) bool {
+					return options.SortBy == tc.expectedSortBy && options.SortOrder == tc.expectedOrder
+				})).Return([]*models.Reception{}, 0, nil)
+			}
+
+			handler.ListReceptions(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedStatus == http.StatusOK {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "ListReceptions", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestListReceptions_MaxPage(t *testing.T) {
+	testCases := []struct {
+		name               string
+		query              string
+		expectedStatusCode int
+	}{
+		{name: "Within Limit", query: "/receptions?page=3", expectedStatusCode: http.StatusOK},
+		{name: "Over Limit", query: "/receptions?page=1000000", expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockService := new(MockReceptionService)
+			handler := NewReceptionHandler(mockService)
+
+			mockService.On("ListReceptions", mock.Anything, mock.Anything).Return([]*models.Reception{}, 0, nil).Maybe()
+
+			req := httptest.NewRequest("GET", tc.query, nil)
+			w := httptest.NewRecorder()
+
+			handler.ListReceptions(w, req)
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			if tc.expectedStatusCode == http.StatusBadRequest {
+				var response respond.ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, "page_too_large", response.Code)
+				mockService.AssertNotCalled(t, "ListReceptions", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestListReceptions_MaxPageDisabled(t *testing.T) {
+	mockService := new(MockReceptionService)
+	handler := NewReceptionHandler(mockService).WithMaxPage(0)
+
+	mockService.On("ListReceptions", mock.Anything, mock.MatchedBy(func(options models.ReceptionListOptions) bool {
+		return options.Page == 1000000
+	})).Return([]*models.Reception{}, 0, nil)
+
+	req := httptest.NewRequest("GET", "/receptions?page=1000000", nil)
+	w := httptest.NewRecorder()
+
+	handler.ListReceptions(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestListPVZReceptions(t *testing.T) {
+	pvzID := uuid.New()
+	receptions := []*models.Reception{
+		{ID: uuid.New(), DateTime: time.Now(), PVZID: pvzID, Status: models.StatusInProgress},
+		{ID: uuid.New(), DateTime: time.Now(), PVZID: pvzID, Status: models.StatusClosed},
+	}
+
+	testCases := []struct {
+		name               string
+		query              string
+		setupMock          func(*MockReceptionService)
+		expectedStatusCode int
+		expectedCount      int
+	}{
+		{
+			name:  "Success",
+			query: "?page=2&limit=5",
+			setupMock: func(m *MockReceptionService) {
+				m.On("ListReceptionsByPVZ", mock.Anything, pvzID, models.ReceptionListOptions{Page: 2, Limit: 5}).
+					Return(receptions, 7, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCount:      2,
+		},
+		{
+			name:  "Status Filter",
+			query: "?status=close",
+			setupMock: func(m *MockReceptionService) {
+				m.On("ListReceptionsByPVZ", mock.Anything, pvzID, models.ReceptionListOptions{Page: 1, Limit: 10, Status: string(models.StatusClosed)}).
+					Return(receptions[1:], 1, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCount:      1,
+		},
+		{
+			name:  "PVZ Not Found",
+			query: "",
+			setupMock: func(m *MockReceptionService) {
+				m.On("ListReceptionsByPVZ", mock.Anything, pvzID, mock.Anything).
+					Return(nil, 0, models.ErrPVZNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, mockService := setupReceptionTest()
+			tc.setupMock(mockService)
+
+			req := httptest.NewRequest("GET", "/pvz/"+pvzID.String()+"/receptions"+tc.query, nil)
+			req = mux.SetURLVars(req, map[string]string{"pvzId": pvzID.String()})
+			w := httptest.NewRecorder()
+
+			handler.ListPVZReceptions(w, req)
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			if tc.expectedStatusCode == http.StatusOK {
+				var response struct {
+					Data       []models.Reception `json:"data"`
+					Pagination map[string]int     `json:"pagination"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Len(t, response.Data, tc.expectedCount)
+			} else {
+				var response respond.ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, "pvz_not_found", response.Code)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetReceptionStats(t *testing.T) {
+	pvzID := uuid.New()
+	stats := &models.ReceptionStats{
+		ByStatus: map[string]int{string(models.StatusInProgress): 1, string(models.StatusClosed): 3},
+		Total:    4,
+	}
+
+	testCases := []struct {
+		name               string
+		query              string
+		setupMock          func(*MockReceptionService)
+		expectedStatusCode int
+	}{
+		{
+			name: "All PVZ",
+			setupMock: func(m *MockReceptionService) {
+				m.On("GetReceptionStats", mock.Anything, (*uuid.UUID)(nil)).Return(stats, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:  "Single PVZ",
+			query: "?pvzId=" + pvzID.String(),
+			setupMock: func(m *MockReceptionService) {
+				m.On("GetReceptionStats", mock.Anything, &pvzID).Return(stats, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:  "PVZ Not Found",
+			query: "?pvzId=" + pvzID.String(),
+			setupMock: func(m *MockReceptionService) {
+				m.On("GetReceptionStats", mock.Anything, &pvzID).Return(nil, models.ErrPVZNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "Invalid PVZ ID",
+			query:              "?pvzId=bad",
+			setupMock:          func(m *MockReceptionService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, mockService := setupReceptionTest()
+			tc.setupMock(mockService)
+
+			req := httptest.NewRequest("GET", "/receptions/stats"+tc.query, nil)
+			w := httptest.NewRecorder()
+
+			handler.GetReceptionStats(w, req)
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			if tc.expectedStatusCode == http.StatusOK {
+				var response models.ReceptionStats
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, *stats, response)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListReceptions_WithoutTotal(t *testing.T) {
+	handler, mockService := setupReceptionTest()
+
+	receptions := []*models.Reception{
+		{ID: uuid.New(), DateTime: time.Now(), PVZID: uuid.New(), Status: models.StatusClosed},
+		{ID: uuid.New(), DateTime: time.Now(), PVZID: uuid.New(), Status: models.StatusClosed},
+	}
+
+	mockService.On("ListReceptions", mock.Anything, mock.MatchedBy(func(options models.ReceptionListOptions) bool {
+		return options.SkipTotal && options.Page == 1 && options.Limit == 2
+	})).Return(receptions, 3, nil)
+
+	req := httptest.NewRequest("GET", "/receptions?limit=2&withTotal=false", nil)
+	w := httptest.NewRecorder()
+
+	handler.ListReceptions(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data       []models.Reception     `json:"data"`
+		Pagination map[string]interface{} `json:"pagination"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Data, 2)
+	assert.Equal(t, true, response.Pagination["hasNext"])
+	assert.NotContains(t, response.Pagination, "total")
+	assert.NotContains(t, response.Pagination, "pageCount")
+
+	mockService.AssertExpectations(t)
+}
+
+func TestPurgeReceptions(t *testing.T) {
+	before := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name               string
+		query              string
+		setupMock          func(*MockReceptionService)
+		expectedStatusCode int
+		expectedDeleted    int
+	}{
+		{
+			name:  "Success",
+			query: "?before=2024-01-01T00:00:00Z",
+			setupMock: func(m *MockReceptionService) {
+				m.On("PurgeReceptions", mock.Anything, before).Return(4, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedDeleted:    4,
+		},
+		{
+			name:               "Missing Before",
+			setupMock:          func(m *MockReceptionService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "Invalid Before",
+			query:              "?before=yesterday",
+			setupMock:          func(m *MockReceptionService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, mockService := setupReceptionTest()
+			tc.setupMock(mockService)
+
+			req := httptest.NewRequest("DELETE", "/receptions"+tc.query, nil)
+			w := httptest.NewRecorder()
+
+			handler.PurgeReceptions(w, req)
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			if tc.expectedStatusCode == http.StatusOK {
+				var response models.PurgeReceptionsResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tc.expectedDeleted, response.Deleted)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTouchCurrentReception(t *testing.T) {
+	pvzID := uuid.New()
+	touchedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		reception      *models.Reception
+		serviceErr     error
+		expectedStatus int
+	}{
+		{
+			name: "Touched",
+			reception: &models.Reception{
+				ID:             uuid.New(),
+				DateTime:       touchedAt.Add(-80 * time.Hour),
+				PVZID:          pvzID,
+				Status:         models.StatusInProgress,
+				LastActivityAt: &touchedAt,
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "No Open Reception",
+			serviceErr:     models.ErrReceptionNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Service Error",
+			serviceErr:     errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, mockService := setupReceptionTest()
+
+			if tc.serviceErr != nil {
+				mockService.On("TouchReception", mock.Anything, pvzID).Return(nil, tc.serviceErr)
+			} else {
+				mockService.On("TouchReception", mock.Anything, pvzID).Return(tc.reception, nil)
+			}
+
+			req := httptest.NewRequest("POST", "/pvz/"+pvzID.String()+"/receptions/current/touch", nil)
+			req = mux.SetURLVars(req, map[string]string{"pvzId": pvzID.String()})
+			w := httptest.NewRecorder()
+
+			handler.TouchCurrentReception(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedStatus == http.StatusOK {
+				var response models.Reception
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				require.NotNil(t, response.LastActivityAt)
+				assert.True(t, touchedAt.Equal(*response.LastActivityAt))
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetReceptionSequenceGaps(t *testing.T) {
+	receptionID := uuid.New()
+
+	testCases := []struct {
+		name           string
+		gaps           *models.ReceptionSequenceGaps
+		serviceErr     error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Gaps",
+			gaps:           &models.ReceptionSequenceGaps{ReceptionID: receptionID, Missing: []int{2, 4}},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"receptionId":"` + receptionID.String() + `","missing":[2,4]}`,
+		},
+		{
+			name:           "No Gaps",
+			gaps:           &models.ReceptionSequenceGaps{ReceptionID: receptionID, Missing: []int{}},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"receptionId":"` + receptionID.String() + `","missing":[]}`,
+		},
+		{
+			name:           "Reception Not Found",
+			serviceErr:     models.ErrReceptionNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"Reception not found","code":"reception_not_found"}`,
+		},
+		{
+			name:           "Service Error",
+			serviceErr:     errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error":"Error retrieving sequence gaps"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, mockService := setupReceptionTest()
+
+			if tc.serviceErr != nil {
+				mockService.On("GetReceptionSequenceGaps", mock.Anything, receptionID).Return(nil, tc.serviceErr)
+			} else {
+				mockService.On("GetReceptionSequenceGaps", mock.Anything, receptionID).Return(tc.gaps, nil)
+			}
+
+			req := httptest.NewRequest("GET", "/receptions/"+receptionID.String()+"/sequence_gaps", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": receptionID.String()})
+			w := httptest.NewRecorder()
+
+			handler.GetReceptionSequenceGaps(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.JSONEq(t, tc.expectedBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestResequenceReception(t *testing.T) {
+	receptionID := uuid.New()
+
+	testCases := []struct {
+		name           string
+		result         *models.ReceptionResequence
+		serviceErr     error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Success",
+			result:         &models.ReceptionResequence{ReceptionID: receptionID, Products: 4},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"receptionId":"` + receptionID.String() + `","products":4}`,
+		},
+		{
+			name:           "Reception Not Found",
+			serviceErr:     models.ErrReceptionNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":"Reception not found","code":"reception_not_found"}`,
+		},
+		{
+			name:           "Reception Closed",
+			serviceErr:     models.ErrReceptionClosed,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":"Reception is closed","code":"reception_closed"}`,
+		},
+		{
+			name:           "Service Error",
+			serviceErr:     errors.New("database error"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error":"Error resequencing products"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, mockService := setupReceptionTest()
+
+			if tc.serviceErr != nil {
+				mockService.On("ResequenceReception", mock.Anything, receptionID).Return(nil, tc.serviceErr)
+			} else {
+				mockService.On("ResequenceReception", mock.Anything, receptionID).Return(tc.result, nil)
+			}
+
+			req := httptest.NewRequest("POST", "/receptions/"+receptionID.String()+"/resequence", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": receptionID.String()})
+			w := httptest.NewRecorder()
+
+			handler.ResequenceReception(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.JSONEq(t, tc.expectedBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+// unzipExport возвращает содержимое файлов ZIP-выгрузки по именам
+func unzipExport(t *testing.T, body []byte) map[string]string {
+	t.Helper()
+
+	archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	require.NoError(t, err)
+
+	files := make(map[string]string, len(archive.File))
+	for _, file := range archive.File {
+		rc, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		require.NoError(t, err)
+		files[file.Name] = string(content)
+	}
+	return files
+}
+
+func TestExportReceptions(t *testing.T) {
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	firstPVZ := &models.PVZ{ID: uuid.MustParse("11111111-1111-1111-1111-111111111111"), City: "Москва"}
+	secondPVZ := &models.PVZ{ID: uuid.MustParse("22222222-2222-2222-2222-222222222222"), City: "Казань"}
+	withProducts := &models.Reception{
+		ID:       uuid.MustParse("aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa"),
+		DateTime: time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC),
+		PVZID:    firstPVZ.ID,
+		Status:   models.StatusClosed,
+		Products: []*models.Product{
+			{
+				ID:          uuid.MustParse("cccccccc-cccc-cccc-cccc-cccccccccccc"),
+				DateTime:    time.Date(2024, 5, 2, 10, 1, 0, 0, time.UTC),
+				Type:        models.TypeClothes,
+				SequenceNum: 1,
+			},
+			{
+				ID:          uuid.MustParse("dddddddd-dddd-dddd-dddd-dddddddddddd"),
+				DateTime:    time.Date(2024, 5, 2, 10, 2, 0, 0, time.UTC),
+				Type:        models.TypeFootwear,
+				SequenceNum: 2,
+			},
+		},
+	}
+	empty := &models.Reception{
+		ID:       uuid.MustParse("bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb"),
+		DateTime: time.Date(2024, 5, 20, 9, 0, 0, 0, time.UTC),
+		PVZID:    secondPVZ.ID,
+		Status:   models.StatusInProgress,
+	}
+
+	t.Run("Archive Per PVZ", func(t *testing.T) {
+		handler, mockService := setupReceptionTest()
+		mockService.On("ExportReceptions", mock.Anything, from, to).Return([]receptionExportGroup{
+			{pvz: firstPVZ, receptions: []*models.Reception{withProducts}},
+			{pvz: secondPVZ, receptions: []*models.Reception{empty}},
+		}, nil)
+
+		req := httptest.NewRequest("GET", "/admin/export?from=2024-05-01T00:00:00Z&to=2024-06-01T00:00:00Z", nil)
+		w := httptest.NewRecorder()
+
+		handler.ExportReceptions(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+
+		header := "reception_id,reception_date_time,reception_status,product_id,product_date_time,product_type,sequence_num\n"
+		assert.Equal(t, map[string]string{
+			firstPVZ.ID.String() + ".csv": header +
+				"aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa,2024-05-02T10:00:00Z,close,cccccccc-cccc-cccc-cccc-cccccccccccc,2024-05-02T10:01:00Z,одежда,1\n" +
+				"aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa,2024-05-02T10:00:00Z,close,dddddddd-dddd-dddd-dddd-dddddddddddd,2024-05-02T10:02:00Z,обувь,2\n",
+			secondPVZ.ID.String() + ".csv": header +
+				"bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb,2024-05-20T09:00:00Z,in_progress,,,,\n",
+		}, unzipExport(t, w.Body.Bytes()))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Empty Range", func(t *testing.T) {
+		handler, mockService := setupReceptionTest()
+		mockService.On("ExportReceptions", mock.Anything, from, to).Return(nil, nil)
+
+		req := httptest.NewRequest("GET", "/admin/export?from=2024-05-01T00:00:00Z&to=2024-06-01T00:00:00Z", nil)
+		w := httptest.NewRecorder()
+
+		handler.ExportReceptions(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, unzipExport(t, w.Body.Bytes()))
+	})
+
+	t.Run("Service Error Before First File", func(t *testing.T) {
+		handler, mockService := setupReceptionTest()
+		mockService.On("ExportReceptions", mock.Anything, from, to).Return(nil, errors.New("database error"))
+
+		req := httptest.NewRequest("GET", "/admin/export?from=2024-05-01T00:00:00Z&to=2024-06-01T00:00:00Z", nil)
+		w := httptest.NewRecorder()
+
+		handler.ExportReceptions(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `{"error":"Failed to export receptions"}`, w.Body.String())
+	})
+}
+
+func TestExportReceptions_InvalidRange(t *testing.T) {
+	testCases := []struct {
+		name          string
+		query         string
+		expectedError string
+	}{
+		{name: "Missing From", query: "?to=2024-06-01T00:00:00Z", expectedError: "Invalid from format. Use RFC3339 format"},
+		{name: "Invalid To", query: "?from=2024-05-01T00:00:00Z&to=june", expectedError: "Invalid to format. Use RFC3339 format"},
+		{name: "Reversed", query: "?from=2024-06-01T00:00:00Z&to=2024-05-01T00:00:00Z", expectedError: "from must be before to"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, mockService := setupReceptionTest()
+
+			req := httptest.NewRequest("GET", "/admin/export"+tc.query, nil)
+			w := httptest.NewRecorder()
+
+			handler.ExportReceptions(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var response respond.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tc.expectedError, response.Error)
+			mockService.AssertNotCalled(t, "ExportReceptions", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
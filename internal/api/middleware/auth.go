@@ -16,10 +16,26 @@ const (
 	UserContextKey = contextKey("user")
 )
 
-// AuthMiddleware проверяет валидность JWT токена и добавляет информацию о пользователе в контекст
+// APIKeyHeader - заголовок с API-ключом внутренних клиентов
+const APIKeyHeader = "X-API-Key"
+
+// AuthMiddleware проверяет валидность JWT токена и добавляет информацию о пользователе в контекст.
+// Если передан заголовок X-API-Key, вместо JWT проверяется ключ
 func AuthMiddleware(authService interfaces.AuthService) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if apiKey := r.Header.Get(APIKeyHeader); apiKey != "" {
+				user, err := authService.ValidateAPIKey(apiKey)
+				if err != nil {
+					http.Error(w, "Invalid API key", http.StatusUnauthorized)
+					return
+				}
+
+				ctx := context.WithValue(r.Context(), UserContextKey, user)
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
 			authHeader := r.Header.Get("Authorization")
 			if authHeader == "" {
 				http.Error(w, "Authorization header is required", http.StatusUnauthorized)
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/auth"
+	"pvz-service/internal/domain/models"
+	"pvz-service/internal/services"
+)
+
+func newAuthTestHandler(t *testing.T) (http.Handler, *services.AuthService) {
+	keys, err := auth.ParseAPIKeys("reporting-key:moderator")
+	require.NoError(t, err)
+
+	authService := services.NewAuthService(nil, "test_jwt_secret").WithAPIKeys(keys)
+
+	handler := AuthMiddleware(authService)(RequireRole(models.RoleModerator)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, err := GetUserFromContext(r.Context())
+			require.NoError(t, err)
+			w.Write([]byte(user.Role))
+		}),
+	))
+	return handler, authService
+}
+
+func TestAuthMiddleware_APIKey(t *testing.T) {
+	handler, _ := newAuthTestHandler(t)
+
+	t.Run("Valid Key Grants Mapped Role", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/pvz", nil)
+		req.Header.Set(APIKeyHeader, "reporting-key")
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, string(models.RoleModerator), w.Body.String())
+	})
+
+	t.Run("Invalid Key Rejected", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/pvz", nil)
+		req.Header.Set(APIKeyHeader, "wrong-key")
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Body.String(), "Invalid API key")
+	})
+}
+
+func TestAuthMiddleware_JWTStillAccepted(t *testing.T) {
+	handler, authService := newAuthTestHandler(t)
+
+	token, err := authService.GenerateDummyToken(models.RoleModerator, time.Hour)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("GET", "/pvz", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// clientIPKey для хранения IP клиента в контексте
+type clientIPKey struct{}
+
+// TrustedProxies описывает прокси, которым разрешено передавать IP клиента
+// в X-Forwarded-For/X-Real-IP. Нулевое значение не доверяет никому
+type TrustedProxies struct {
+	nets []*net.IPNet
+}
+
+// ParseTrustedProxies разбирает список IP-адресов и CIDR через запятую
+func ParseTrustedProxies(spec string) (*TrustedProxies, error) {
+	tp := &TrustedProxies{}
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		if !strings.Contains(item, "/") {
+			ip := net.ParseIP(item)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", item)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 8 * net.IPv4len
+			}
+			tp.nets = append(tp.nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, ipNet, err := net.ParseCIDR(item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", item, err)
+		}
+		tp.nets = append(tp.nets, ipNet)
+	}
+	return tp, nil
+}
+
+func (tp *TrustedProxies) trusts(ip net.IP) bool {
+	if tp == nil || ip == nil {
+		return false
+	}
+	for _, n := range tp.nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP определяет IP клиента. Заголовки прокси учитываются, только если запрос
+// пришел от доверенного прокси: X-Forwarded-For просматривается справа налево
+// и первым берется адрес, не принадлежащий доверенным прокси
+func (tp *TrustedProxies) ClientIP(r *http.Request) string {
+	remote := remoteHost(r.RemoteAddr)
+	if !tp.trusts(net.ParseIP(remote)) {
+		return remote
+	}
+
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		hops := strings.Split(xff, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			ip := net.ParseIP(hop)
+			if ip == nil {
+				// Мусор в цепочке - дальше доверять ей нельзя
+				return remote
+			}
+			if !tp.trusts(ip) || i == 0 {
+				return ip.String()
+			}
+		}
+	}
+
+	if realIP := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); realIP != nil {
+		return realIP.String()
+	}
+
+	return remote
+}
+
+// ClientIPMiddleware сохраняет IP клиента в контексте запроса.
+// Должен подключаться раньше логирования
+func ClientIPMiddleware(tp *TrustedProxies) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), clientIPKey{}, tp.ClientIP(r))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// ClientIP возвращает IP клиента, определенный ClientIPMiddleware,
+// а без него - хост из r.RemoteAddr
+func ClientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(clientIPKey{}).(string); ok {
+		return ip
+	}
+	return remoteHost(r.RemoteAddr)
+}
+
+func remoteHost(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrustedProxies_ClientIP(t *testing.T) {
+	tp, err := ParseTrustedProxies("10.0.0.0/8, 192.168.1.10")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		remoteAddr string
+		xff        string
+		xRealIP    string
+		expected   string
+	}{
+		{
+			name:       "Untrusted Source Ignores Headers",
+			remoteAddr: "203.0.113.5:4321",
+			xff:        "1.2.3.4",
+			xRealIP:    "5.6.7.8",
+			expected:   "203.0.113.5",
+		},
+		{
+			name:       "Trusted Proxy Uses Forwarded For",
+			remoteAddr: "10.1.2.3:4321",
+			xff:        "198.51.100.7",
+			expected:   "198.51.100.7",
+		},
+		{
+			name:       "Trusted Chain Takes Rightmost Untrusted Hop",
+			remoteAddr: "10.1.2.3:4321",
+			xff:        "6.6.6.6, 198.51.100.7, 192.168.1.10",
+			expected:   "198.51.100.7",
+		},
+		{
+			name:       "All Hops Trusted Takes Leftmost",
+			remoteAddr: "10.1.2.3:4321",
+			xff:        "10.9.9.9, 192.168.1.10",
+			expected:   "10.9.9.9",
+		},
+		{
+			name:       "Trusted Proxy Uses Real IP",
+			remoteAddr: "192.168.1.10:4321",
+			xRealIP:    "198.51.100.8",
+			expected:   "198.51.100.8",
+		},
+		{
+			name:       "Malformed Forwarded For Falls Back To Remote",
+			remoteAddr: "10.1.2.3:4321",
+			xff:        "not-an-ip",
+			expected:   "10.1.2.3",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/pvz", nil)
+			req.RemoteAddr = tc.remoteAddr
+			if tc.xff != "" {
+				req.Header.Set("X-Forwarded-For", tc.xff)
+			}
+			if tc.xRealIP != "" {
+				req.Header.Set("X-Real-IP", tc.xRealIP)
+			}
+
+			assert.Equal(t, tc.expected, tp.ClientIP(req))
+		})
+	}
+}
+
+func TestTrustedProxies_NotConfigured(t *testing.T) {
+	tp, err := ParseTrustedProxies("")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/pvz", nil)
+	req.RemoteAddr = "10.1.2.3:4321"
+	req.Header.Set("X-Forwarded-For", "1.2.3.4")
+
+	assert.Equal(t, "10.1.2.3", tp.ClientIP(req))
+}
+
+func TestParseTrustedProxies_Invalid(t *testing.T) {
+	_, err := ParseTrustedProxies("10.0.0.0/8, proxy.local")
+	assert.Error(t, err)
+}
+
+func TestClientIPMiddleware(t *testing.T) {
+	tp, err := ParseTrustedProxies("10.0.0.1")
+	require.NoError(t, err)
+
+	var got string
+	handler := ClientIPMiddleware(tp)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = ClientIP(r)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/pvz", nil)
+	req.RemoteAddr = "10.0.0.1:80"
+	req.Header.Set("X-Forwarded-For", "198.51.100.7")
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "198.51.100.7", got)
+}
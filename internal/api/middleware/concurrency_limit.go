@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"pvz-service/internal/logger"
+)
+
+// concurrencyLimitExempt - проверки состояния не ограничиваются, чтобы перегрузка
+// не выглядела для балансировщика как отказ экземпляра
+var concurrencyLimitExempt = map[string]bool{
+	"/readyz": true,
+}
+
+// concurrencyLimitExemptRoutes - шаблоны маршрутов потоков событий: они живут долго
+// и не нагружают базу. Исключение по маршруту, а не по заголовку Accept, чтобы клиент
+// не мог обойти лимит на любом запросе
+var concurrencyLimitExemptRoutes = map[string]bool{
+	"/pvz/{pvzId}/events": true,
+}
+
+// concurrencyRetryAfterSeconds - значение Retry-After при отказе из-за перегрузки
+const concurrencyRetryAfterSeconds = 1
+
+// ConcurrencyLimitMiddleware ограничивает число одновременно обрабатываемых запросов
+// семафором емкостью capacity. Запрос сверх лимита не ждет, а сразу получает 503
+// с Retry-After. Проверки состояния и потоки событий место в семафоре не занимают.
+// Ноль и меньше отключают ограничение
+func ConcurrencyLimitMiddleware(capacity int) func(http.Handler) http.Handler {
+	if capacity <= 0 {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	sem := make(chan struct{}, capacity)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if concurrencyLimitExempt[r.URL.Path] || concurrencyLimitExemptRoutes[routeTemplate(r)] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+				next.ServeHTTP(w, r)
+			default:
+				logger.FromContext(r.Context()).Warn("превышен лимит одновременных запросов", "capacity", capacity)
+				w.Header().Set("Retry-After", strconv.Itoa(concurrencyRetryAfterSeconds))
+				http.Error(w, "Service Unavailable: too many concurrent requests", http.StatusServiceUnavailable)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// saturate запускает capacity запросов к /pvz, которые держат семафор, пока их не отпустит обработчик
+func saturate(t *testing.T, handler http.Handler, capacity int) *sync.WaitGroup {
+	t.Helper()
+
+	var wg sync.WaitGroup
+	for i := 0; i < capacity; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/pvz", nil))
+		}()
+	}
+	return &wg
+}
+
+func TestConcurrencyLimitMiddleware(t *testing.T) {
+	const capacity = 2
+
+	entered := make(chan struct{}, capacity)
+	release := make(chan struct{})
+	// Исключение потоков событий определяется по шаблону маршрута, поэтому нужен роутер mux
+	handler := mux.NewRouter()
+	handler.Use(ConcurrencyLimitMiddleware(capacity))
+	ok := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}
+	handler.HandleFunc("/pvz/{pvzId}/events", ok)
+	handler.HandleFunc("/pvz", func(w http.ResponseWriter, r *http.Request) {
+		entered <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+	handler.PathPrefix("/").HandlerFunc(ok)
+
+	wg := saturate(t, handler, capacity)
+	for i := 0; i < capacity; i++ {
+		<-entered
+	}
+
+	tests := []struct {
+		name       string
+		target     string
+		accept     string
+		status     int
+		retryAfter string
+	}{
+		{"Overflow Request", "/receptions", "", http.StatusServiceUnavailable, "1"},
+		{"Readiness Probe", "/readyz", "", http.StatusOK, ""},
+		{"Event Stream", "/pvz/11111111-1111-1111-1111-111111111111/events", "text/event-stream", http.StatusOK, ""},
+		{"Event-Stream Accept On Other Route", "/receptions", "text/event-stream", http.StatusServiceUnavailable, "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.target, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.retryAfter, w.Header().Get("Retry-After"))
+		})
+	}
+
+	close(release)
+	wg.Wait()
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/receptions", nil))
+	assert.Equal(t, http.StatusOK, w.Code, "после освобождения семафора запросы снова проходят")
+}
+
+func TestConcurrencyLimitMiddleware_Disabled(t *testing.T) {
+	handler := ConcurrencyLimitMiddleware(0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/pvz", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
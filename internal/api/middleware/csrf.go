@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"pvz-service/internal/logger"
+)
+
+const (
+	// CSRFCookieName - cookie с CSRF-токеном, который клиент дублирует в заголовке
+	CSRFCookieName = "csrf_token"
+
+	// CSRFHeader - заголовок, значение которого должно совпасть с CSRFCookieName
+	CSRFHeader = "X-CSRF-Token"
+)
+
+// csrfSafeMethods не меняют состояние и не проверяются
+var csrfSafeMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+}
+
+// CSRFMiddleware защищает изменяющие запросы с авторизацией по cookie authCookie методом
+// double-submit cookie: значение заголовка X-CSRF-Token должно совпасть с cookie csrf_token,
+// иначе 403. Запросы с заголовком Authorization или X-API-Key браузер сам не подставляет,
+// поэтому они, как и запросы без cookie authCookie, не проверяются. Пустой authCookie
+// отключает проверку
+func CSRFMiddleware(authCookie string) func(http.Handler) http.Handler {
+	if authCookie == "" {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if csrfSafeMethods[r.Method] || r.Header.Get("Authorization") != "" || r.Header.Get(APIKeyHeader) != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if _, err := r.Cookie(authCookie); err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token := r.Header.Get(CSRFHeader)
+			cookie, err := r.Cookie(CSRFCookieName)
+			if token == "" || err != nil || subtle.ConstantTimeCompare([]byte(token), []byte(cookie.Value)) != 1 {
+				logger.FromContext(r.Context()).Warn("отклонен запрос без корректного CSRF-токена",
+					"method", r.Method,
+					"path", r.URL.Path,
+					"header_present", token != "",
+				)
+				http.Error(w, "Forbidden: invalid CSRF token", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSRFMiddleware(t *testing.T) {
+	const authCookie = "session"
+
+	handler := CSRFMiddleware(authCookie)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name          string
+		method        string
+		session       bool
+		csrfCookie    string
+		csrfHeader    string
+		authorization string
+		apiKey        string
+		status        int
+	}{
+		{name: "Valid Token", method: "POST", session: true, csrfCookie: "abc", csrfHeader: "abc", status: http.StatusOK},
+		{name: "Missing Header", method: "POST", session: true, csrfCookie: "abc", status: http.StatusForbidden},
+		{name: "Missing Cookie", method: "POST", session: true, csrfHeader: "abc", status: http.StatusForbidden},
+		{name: "Mismatched Token", method: "DELETE", session: true, csrfCookie: "abc", csrfHeader: "xyz", status: http.StatusForbidden},
+		{name: "Safe Method", method: "GET", session: true, status: http.StatusOK},
+		{name: "Bearer Auth", method: "POST", session: true, authorization: "Bearer token", status: http.StatusOK},
+		{name: "API Key", method: "POST", session: true, apiKey: "key", status: http.StatusOK},
+		{name: "No Session Cookie", method: "POST", status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/receptions", nil)
+			if tt.session {
+				req.AddCookie(&http.Cookie{Name: authCookie, Value: "session-id"})
+			}
+			if tt.csrfCookie != "" {
+				req.AddCookie(&http.Cookie{Name: CSRFCookieName, Value: tt.csrfCookie})
+			}
+			if tt.csrfHeader != "" {
+				req.Header.Set(CSRFHeader, tt.csrfHeader)
+			}
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			if tt.apiKey != "" {
+				req.Header.Set(APIKeyHeader, tt.apiKey)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestCSRFMiddleware_Disabled(t *testing.T) {
+	handler := CSRFMiddleware("")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("POST", "/receptions", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "session-id"})
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
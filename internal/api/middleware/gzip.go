@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strings"
+)
+
+// gzipMinSize - ответы меньше этого размера отправляются без сжатия
+const gzipMinSize = 1024
+
+// GzipMiddleware сжимает ответы, если клиент передал Accept-Encoding: gzip.
+// Небольшие и уже сжатые ответы отправляются как есть
+func GzipMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !acceptsGzip(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		gzw := &gzipResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		completed := false
+		defer func() {
+			// При панике до начала ответа ничего не отправляем,
+			// иначе RecoveryMiddleware не сможет ответить 500
+			if !completed && !gzw.decided {
+				return
+			}
+			gzw.Close()
+		}()
+
+		next.ServeHTTP(gzw, r)
+		completed = true
+	})
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		encoding := strings.TrimSpace(strings.SplitN(part, ";", 2)[0])
+		if encoding == "gzip" || encoding == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// gzipResponseWriter накапливает начало ответа, пока не станет ясно, нужно ли сжатие
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	gz      *gzip.Writer
+	buf     []byte
+	status  int
+	decided bool
+}
+
+func (g *gzipResponseWriter) WriteHeader(code int) {
+	if g.decided {
+		return
+	}
+	g.status = code
+}
+
+func (g *gzipResponseWriter) Write(b []byte) (int, error) {
+	if g.decided {
+		if g.gz != nil {
+			return g.gz.Write(b)
+		}
+		return g.ResponseWriter.Write(b)
+	}
+
+	g.buf = append(g.buf, b...)
+	if len(g.buf) >= gzipMinSize {
+		if err := g.start(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// Flush начинает отправку ответа (со сжатием, если это допустимо) и пробрасывает Flush дальше
+func (g *gzipResponseWriter) Flush() {
+	if !g.decided {
+		g.start(len(g.buf) > 0)
+	}
+	if g.gz != nil {
+		g.gz.Flush()
+	}
+	if f, ok := g.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap дает http.ResponseController доступ к исходному writer
+func (g *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return g.ResponseWriter
+}
+
+// Close завершает ответ: короткие ответы отправляются без сжатия
+func (g *gzipResponseWriter) Close() error {
+	if !g.decided {
+		return g.start(false)
+	}
+	if g.gz != nil {
+		return g.gz.Close()
+	}
+	return nil
+}
+
+func (g *gzipResponseWriter) start(compress bool) error {
+	g.decided = true
+
+	h := g.ResponseWriter.Header()
+	if compress && g.compressible() {
+		h.Set("Content-Encoding", "gzip")
+		h.Del("Content-Length")
+		g.gz = gzip.NewWriter(g.ResponseWriter)
+	}
+
+	g.ResponseWriter.WriteHeader(g.status)
+
+	buf := g.buf
+	g.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	if g.gz != nil {
+		_, err := g.gz.Write(buf)
+		return err
+	}
+	_, err := g.ResponseWriter.Write(buf)
+	return err
+}
+
+func (g *gzipResponseWriter) compressible() bool {
+	if g.status < http.StatusOK || g.status == http.StatusNoContent || g.status == http.StatusNotModified {
+		return false
+	}
+
+	h := g.ResponseWriter.Header()
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+
+	contentType := h.Get("Content-Type")
+	for _, compressed := range []string{"application/gzip", "application/zip", "image/", "video/"} {
+		if strings.HasPrefix(contentType, compressed) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+)
+
+// httpsRedirectExempt - пути проверок состояния, которые балансировщик опрашивает по http
+var httpsRedirectExempt = map[string]bool{
+	"/readyz": true,
+}
+
+// HTTPSRedirectMiddleware перенаправляет (308) запросы, пришедшие на TLS-терминирующий
+// прокси по http (X-Forwarded-Proto: http), на тот же адрес по https.
+// 308 сохраняет метод и тело запроса. Проверки состояния не перенаправляются
+func HTTPSRedirectMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proto := strings.TrimSpace(r.Header.Get("X-Forwarded-Proto"))
+		if !strings.EqualFold(proto, "http") || httpsRedirectExempt[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		http.Redirect(w, r, "https://"+r.Host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPSRedirectMiddleware(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := HTTPSRedirectMiddleware(ok)
+
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		proto    string
+		status   int
+		location string
+	}{
+		{"http через прокси", http.MethodGet, "/pvz?page=2", "http", http.StatusPermanentRedirect, "https://pvz.example.com/pvz?page=2"},
+		{"POST сохраняет метод", http.MethodPost, "/receptions", "HTTP", http.StatusPermanentRedirect, "https://pvz.example.com/receptions"},
+		{"https через прокси", http.MethodGet, "/pvz", "https", http.StatusOK, ""},
+		{"без заголовка", http.MethodGet, "/pvz", "", http.StatusOK, ""},
+		{"проверка готовности", http.MethodGet, "/readyz", "http", http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			req.Host = "pvz.example.com"
+			if tt.proto != "" {
+				req.Header.Set("X-Forwarded-Proto", tt.proto)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.location, w.Header().Get("Location"))
+		})
+	}
+}
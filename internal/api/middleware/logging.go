@@ -1,7 +1,6 @@
 package middleware
 
 import (
-	"context"
 	"log/slog"
 	"net/http"
 	"time"
@@ -11,11 +10,9 @@ import (
 	"github.com/google/uuid"
 )
 
-// RequestIDKey для хранения ID запроса в контексте
-type RequestIDKey struct{}
-
-// LoggingMiddleware логирует информацию о HTTP запросах с использованием структурированного логгера
-func LoggingMiddleware(log *slog.Logger) func(http.Handler) http.Handler {
+// LoggingMiddleware логирует информацию о HTTP запросах с использованием структурированного логгера.
+// Запросы дольше slowThreshold дополнительно логируются на уровне WARN; ноль отключает проверку
+func LoggingMiddleware(log *slog.Logger, slowThreshold time.Duration) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
@@ -23,18 +20,25 @@ func LoggingMiddleware(log *slog.Logger) func(http.Handler) http.Handler {
 			// Генерируем уникальный ID для запроса
 			requestID := uuid.New().String()
 
-			// Создаем логгер с контекстом запроса
-			requestLog := log.With(
+			// Создаем логгер с контекстом запроса. Заголовки целиком не логируются:
+			// Authorization содержит токен. Если понадобятся заголовки, их нужно
+			// пропускать через RedactHeaders. route - шаблон маршрута mux (/pvz/{pvzId}),
+			// по нему записи удобно группировать, в отличие от path с конкретными ID
+			// trace_id и span_id привязываются к логгеру, чтобы их несли и записи
+			// обработчиков, сервисов и репозиториев, сделанные без контекста
+			requestLog := logger.WithTraceContext(r.Context(), log).With(
 				"request_id", requestID,
 				"method", r.Method,
 				"path", r.URL.Path,
+				"route", routeTemplate(r),
 				"remote_addr", r.RemoteAddr,
+				"client_ip", ClientIP(r),
 				"user_agent", r.UserAgent(),
 			)
 
 			// Добавляем логгер и ID запроса в контекст
 			ctx := logger.WithLogger(r.Context(), requestLog)
-			ctx = context.WithValue(ctx, RequestIDKey{}, requestID)
+			ctx = logger.WithRequestID(ctx, requestID)
 
 			// Логируем начало запроса
 			requestLog.Info("входящий запрос")
@@ -50,11 +54,20 @@ func LoggingMiddleware(log *slog.Logger) func(http.Handler) http.Handler {
 
 			// Логируем результат запроса
 			duration := time.Since(start)
-			requestLog.Info("запрос обработан",
+			requestLog.InfoContext(r.Context(), "запрос обработан",
 				"status", lrw.statusCode,
 				"duration", duration.String(),
 				"duration_ms", float64(duration.Microseconds())/1000.0,
 			)
+
+			if slowThreshold > 0 && duration > slowThreshold {
+				requestLog.WarnContext(r.Context(), "медленный запрос",
+					"status", lrw.statusCode,
+					"duration", duration.String(),
+					"duration_ms", float64(duration.Microseconds())/1000.0,
+					"threshold", slowThreshold.String(),
+				)
+			}
 		})
 	}
 }
@@ -81,6 +94,11 @@ func (lrw *loggingResponseWriter) Write(b []byte) (int, error) {
 	return n, err
 }
 
+// Unwrap дает http.ResponseController доступ к исходному writer (Flush, дедлайны)
+func (lrw *loggingResponseWriter) Unwrap() http.ResponseWriter {
+	return lrw.ResponseWriter
+}
+
 // Добавляем методы для получения метрик
 func (lrw *loggingResponseWriter) Status() int {
 	return lrw.statusCode
@@ -0,0 +1,145 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"pvz-service/internal/logger"
+)
+
+// warnRecords возвращает WARN-записи из JSON-лога
+func warnRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var records []map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(line, &record))
+		if record["level"] == slog.LevelWarn.String() {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+func TestLoggingMiddleware_SlowRequestWarning(t *testing.T) {
+	testCases := []struct {
+		name         string
+		handlerDelay time.Duration
+		expectWarn   bool
+	}{
+		{name: "Slow Handler", handlerDelay: 30 * time.Millisecond, expectWarn: true},
+		{name: "Fast Handler", expectWarn: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+			router := mux.NewRouter()
+			router.Use(LoggingMiddleware(log, 10*time.Millisecond))
+			router.HandleFunc("/pvz/{pvzId}", func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(tc.handlerDelay)
+				w.WriteHeader(http.StatusOK)
+			}).Methods("GET")
+
+			req := httptest.NewRequest("GET", "/pvz/"+uuid.New().String(), nil)
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			warnings := warnRecords(t, &buf)
+			if !tc.expectWarn {
+				assert.Empty(t, warnings)
+				return
+			}
+
+			require.Len(t, warnings, 1)
+			assert.Equal(t, "медленный запрос", warnings[0]["msg"])
+			assert.Equal(t, "/pvz/{pvzId}", warnings[0]["route"])
+			assert.GreaterOrEqual(t, warnings[0]["duration_ms"], float64(30))
+		})
+	}
+}
+
+func TestLoggingMiddleware_RouteTemplate(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	router := mux.NewRouter()
+	router.Use(LoggingMiddleware(log, 0))
+	router.HandleFunc("/pvz/{pvzId}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("GET")
+
+	path := "/pvz/" + uuid.New().String()
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	for _, line := range lines {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(line, &record))
+		assert.Equal(t, path, record["path"])
+		assert.Equal(t, "/pvz/{pvzId}", record["route"])
+	}
+}
+
+func TestLoggingMiddleware_TraceIDInHandlerLogs(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Level: logger.LevelInfo, Format: "json", Output: &buf, TraceContext: true})
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(tracetest.NewInMemoryExporter()))
+
+	router := mux.NewRouter()
+	router.Use(TracingMiddleware(tp, propagation.TraceContext{}))
+	router.Use(LoggingMiddleware(log, 0))
+	router.HandleFunc("/pvz", func(w http.ResponseWriter, r *http.Request) {
+		// Обработчики, сервисы и репозитории пишут без контекста
+		logger.FromContext(r.Context()).Info("запись обработчика")
+		w.WriteHeader(http.StatusOK)
+	}).Methods("GET")
+
+	req := httptest.NewRequest("GET", "/pvz", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 3)
+	for _, line := range lines {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(line, &record))
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", record["trace_id"], record["msg"])
+		assert.NotEmpty(t, record["span_id"])
+	}
+}
+
+func TestLoggingMiddleware_NeverLogsAuthorization(t *testing.T) {
+	const token = "Bearer eyJhbGciOiJIUzI1NiJ9.secret-payload.signature"
+
+	var buf bytes.Buffer
+	log := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	handler := LoggingMiddleware(log, time.Nanosecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(time.Millisecond)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+
+	req := httptest.NewRequest("GET", "/pvz", nil)
+	req.Header.Set("Authorization", token)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.NotEmpty(t, buf.String())
+	assert.NotContains(t, buf.String(), "secret-payload")
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"pvz-service/internal/api/respond"
+	"pvz-service/internal/logger"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type uuidVarsKey struct{}
+
+// ValidateUUIDVars возвращает middleware, которое проверяет, что перечисленные переменные пути
+// являются UUID, и отвечает единообразной JSON-ошибкой 400 до вызова обработчика.
+// Разобранные значения сохраняются в контексте и читаются обработчиками через UUIDVar.
+// Переменные, отсутствующие в маршруте, пропускаются
+func ValidateUUIDVars(names ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			vars := mux.Vars(r)
+			parsed, _ := r.Context().Value(uuidVarsKey{}).(map[string]uuid.UUID)
+			validated := make(map[string]uuid.UUID, len(parsed)+len(names))
+			for name, id := range parsed {
+				validated[name] = id
+			}
+
+			for _, name := range names {
+				value, ok := vars[name]
+				if !ok {
+					continue
+				}
+				id, err := uuid.Parse(value)
+				if err != nil {
+					WriteInvalidUUID(w, r, name, value)
+					return
+				}
+				validated[name] = id
+			}
+
+			ctx := context.WithValue(r.Context(), uuidVarsKey{}, validated)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// UUIDVar возвращает переменную пути name как UUID. Значение, проверенное ValidateUUIDVars,
+// берется из контекста; если маршрут зарегистрирован без проверки, переменная разбирается здесь
+func UUIDVar(r *http.Request, name string) (uuid.UUID, error) {
+	if validated, ok := r.Context().Value(uuidVarsKey{}).(map[string]uuid.UUID); ok {
+		if id, ok := validated[name]; ok {
+			return id, nil
+		}
+	}
+	return uuid.Parse(mux.Vars(r)[name])
+}
+
+// WriteInvalidUUID отвечает 400 "Invalid <name> format" - единый формат ошибки для
+// некорректного UUID в переменной пути или query-параметре name
+func WriteInvalidUUID(w http.ResponseWriter, r *http.Request, name, value string) {
+	logger.FromContext(r.Context()).Warn("некорректный UUID в запросе",
+		"param", name,
+		"value", value,
+	)
+	respond.ErrorWithCode(w, r, http.StatusBadRequest, invalidUUIDCode(name), "Invalid "+name+" format", nil)
+}
+
+// invalidUUIDCode возвращает код ошибки для некорректного UUID в параметре name.
+// У параметров без отдельного кода ответ уходит без кода
+func invalidUUIDCode(name string) respond.Code {
+	switch name {
+	case "pvzId":
+		return respond.CodeInvalidPVZID
+	case "userId":
+		return respond.CodeInvalidUserID
+	case "id":
+		return respond.CodeInvalidID
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/api/respond"
+)
+
+func newUUIDVarsRouter() *mux.Router {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	router := mux.NewRouter()
+	router.Handle("/pvz/{pvzId}/employees/{userId}", ValidateUUIDVars("pvzId", "userId")(ok)).Methods("DELETE")
+	router.Handle("/receptions/{id}/summary", ValidateUUIDVars("id")(ok)).Methods("GET")
+	return router
+}
+
+func TestValidateUUIDVars(t *testing.T) {
+	router := newUUIDVarsRouter()
+	validID := uuid.New().String()
+
+	testCases := []struct {
+		name            string
+		method          string
+		target          string
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{
+			name:           "Valid UUIDs",
+			method:         "DELETE",
+			target:         "/pvz/" + validID + "/employees/" + uuid.New().String(),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:            "Invalid pvzId",
+			method:          "DELETE",
+			target:          "/pvz/not-a-uuid/employees/" + validID,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "Invalid pvzId format",
+		},
+		{
+			name:            "Invalid userId",
+			method:          "DELETE",
+			target:          "/pvz/" + validID + "/employees/42",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "Invalid userId format",
+		},
+		{
+			name:           "Valid reception id",
+			method:         "GET",
+			target:         "/receptions/" + validID + "/summary",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:            "Invalid reception id",
+			method:          "GET",
+			target:          "/receptions/abc/summary",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "Invalid id format",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tc.method, tc.target, nil))
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedMessage != "" {
+				assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+				var response respond.ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tc.expectedMessage, response.Error)
+			}
+		})
+	}
+}
+
+func TestUUIDVar(t *testing.T) {
+	pvzID := uuid.New()
+
+	var got uuid.UUID
+	var gotErr error
+	router := mux.NewRouter()
+	router.Handle("/pvz/{pvzId}", ValidateUUIDVars("pvzId")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, gotErr = UUIDVar(r, "pvzId")
+	})))
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/pvz/"+pvzID.String(), nil))
+
+	assert.NoError(t, gotErr)
+	assert.Equal(t, pvzID, got)
+
+	t.Run("Without Middleware", func(t *testing.T) {
+		req := mux.SetURLVars(httptest.NewRequest("GET", "/", nil), map[string]string{"pvzId": "bad"})
+		_, err := UUIDVar(req, "pvzId")
+		assert.Error(t, err)
+
+		req = mux.SetURLVars(httptest.NewRequest("GET", "/", nil), map[string]string{"pvzId": pvzID.String()})
+		id, err := UUIDVar(req, "pvzId")
+		assert.NoError(t, err)
+		assert.Equal(t, pvzID, id)
+	})
+}
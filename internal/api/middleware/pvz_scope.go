@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"pvz-service/internal/domain/interfaces"
+	"pvz-service/internal/domain/models"
+	"pvz-service/internal/logger"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// maxScopeBodyBytes ограничивает тело, которое читается ради pvzId до проверки прав.
+// Тела изменяющих запросов сотрудника - несколько полей JSON
+const maxScopeBodyBytes = 64 << 10
+
+// pvzResolver определяет ПВЗ запроса. ok=false - ПВЗ определить нельзя, запрос уходит
+// обработчику, который сам ответит 400 или 404
+type pvzResolver func(w http.ResponseWriter, r *http.Request) (pvzID uuid.UUID, ok bool, err error)
+
+// RequirePVZAssignment пропускает запрос сотрудника, только если он назначен на ПВЗ,
+// указанный в пути ({pvzId}) или в поле pvzId JSON-тела. Модераторы проверку не проходят.
+// Запросы без корректного pvzId пропускаются дальше - их отклонит обработчик
+func RequirePVZAssignment(checker interfaces.PVZAccessChecker) func(http.Handler) http.Handler {
+	return requireAssignment(checker, pvzIDFromRequest)
+}
+
+// RequireReceptionPVZAssignment - то же для маршрутов /receptions/{id}: ПВЗ берется из приемки
+func RequireReceptionPVZAssignment(checker interfaces.PVZAccessChecker) func(http.Handler) http.Handler {
+	return requireAssignment(checker, pvzIDFromPathEntity(checker.ReceptionPVZID, models.ErrReceptionNotFound))
+}
+
+// RequireProductPVZAssignment - то же для маршрутов /products/{id}: ПВЗ берется из приемки товара
+func RequireProductPVZAssignment(checker interfaces.PVZAccessChecker) func(http.Handler) http.Handler {
+	return requireAssignment(checker, pvzIDFromPathEntity(checker.ProductPVZID, models.ErrProductNotFound))
+}
+
+func requireAssignment(checker interfaces.PVZAccessChecker, resolve pvzResolver) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := r.Context().Value(UserContextKey).(*models.User)
+			if !ok {
+				http.Error(w, "Unauthorized: user not found in context", http.StatusUnauthorized)
+				return
+			}
+
+			if user.Role == models.RoleModerator {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			pvzID, ok, err := resolve(w, r)
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			if err != nil {
+				logger.FromContext(r.Context()).Error("ошибка определения ПВЗ запроса",
+					"error", err,
+					"user_id", user.ID,
+				)
+				http.Error(w, "Unable to verify PVZ assignment", http.StatusInternalServerError)
+				return
+			}
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			assigned, err := checker.IsEmployeeAssigned(r.Context(), user.ID, pvzID)
+			if err != nil {
+				logger.FromContext(r.Context()).Error("ошибка проверки назначения на ПВЗ",
+					"error", err,
+					"user_id", user.ID,
+					"pvz_id", pvzID,
+				)
+				http.Error(w, "Unable to verify PVZ assignment", http.StatusInternalServerError)
+				return
+			}
+
+			if !assigned {
+				http.Error(w, "Forbidden: employee is not assigned to this PVZ", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// pvzIDFromRequest достает pvzId из пути, а если его там нет - из JSON-тела.
+// Тело читается не больше maxScopeBodyBytes и после чтения восстанавливается для обработчика
+func pvzIDFromRequest(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool, error) {
+	if idStr, ok := mux.Vars(r)["pvzId"]; ok {
+		id, err := uuid.Parse(idStr)
+		return id, err == nil, nil
+	}
+
+	if r.Body == nil {
+		return uuid.Nil, false, nil
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxScopeBodyBytes))
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return uuid.Nil, false, err
+		}
+		return uuid.Nil, false, nil
+	}
+
+	var payload struct {
+		PVZID uuid.UUID `json:"pvzId"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil || payload.PVZID == uuid.Nil {
+		return uuid.Nil, false, nil
+	}
+	return payload.PVZID, true, nil
+}
+
+// pvzIDFromPathEntity определяет ПВЗ по сущности из {id} в пути. Если сущность не найдена
+// (notFound), запрос пропускается - обработчик ответит 404
+func pvzIDFromPathEntity(lookup func(ctx context.Context, id uuid.UUID) (uuid.UUID, error), notFound error) pvzResolver {
+	return func(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool, error) {
+		id, err := uuid.Parse(mux.Vars(r)["id"])
+		if err != nil {
+			return uuid.Nil, false, nil
+		}
+
+		pvzID, err := lookup(r.Context(), id)
+		if errors.Is(err, notFound) {
+			return uuid.Nil, false, nil
+		}
+		if err != nil {
+			return uuid.Nil, false, err
+		}
+		return pvzID, true, nil
+	}
+}
@@ -0,0 +1,230 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/domain/models"
+)
+
+type fakePVZAccessChecker struct {
+	assignments map[uuid.UUID]uuid.UUID
+	err         error
+	calls       int
+
+	// receptions и products сопоставляют идентификаторы из пути с ПВЗ
+	receptions map[uuid.UUID]uuid.UUID
+	products   map[uuid.UUID]uuid.UUID
+	lookupErr  error
+}
+
+func (f *fakePVZAccessChecker) IsEmployeeAssigned(ctx context.Context, userID, pvzID uuid.UUID) (bool, error) {
+	f.calls++
+	if f.err != nil {
+		return false, f.err
+	}
+	return f.assignments[userID] == pvzID, nil
+}
+
+func (f *fakePVZAccessChecker) ReceptionPVZID(ctx context.Context, receptionID uuid.UUID) (uuid.UUID, error) {
+	if f.lookupErr != nil {
+		return uuid.Nil, f.lookupErr
+	}
+	pvzID, ok := f.receptions[receptionID]
+	if !ok {
+		return uuid.Nil, models.ErrReceptionNotFound
+	}
+	return pvzID, nil
+}
+
+func (f *fakePVZAccessChecker) ProductPVZID(ctx context.Context, productID uuid.UUID) (uuid.UUID, error) {
+	if f.lookupErr != nil {
+		return uuid.Nil, f.lookupErr
+	}
+	pvzID, ok := f.products[productID]
+	if !ok {
+		return uuid.Nil, models.ErrProductNotFound
+	}
+	return pvzID, nil
+}
+
+func serveWithScope(checker *fakePVZAccessChecker, user *models.User, req *http.Request) (*httptest.ResponseRecorder, string) {
+	return serveWithScopeMiddleware(RequirePVZAssignment(checker), user, req)
+}
+
+func serveWithScopeMiddleware(scope func(http.Handler) http.Handler, user *models.User, req *http.Request) (*httptest.ResponseRecorder, string) {
+	var body string
+	handler := scope(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req = req.WithContext(context.WithValue(req.Context(), UserContextKey, user))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w, body
+}
+
+func TestRequirePVZAssignment(t *testing.T) {
+	employee := &models.User{ID: uuid.New(), Role: models.RoleEmployee}
+	moderator := &models.User{ID: uuid.New(), Role: models.RoleModerator}
+	assignedPVZ := uuid.New()
+	otherPVZ := uuid.New()
+
+	testCases := []struct {
+		name           string
+		user           *models.User
+		pvzID          uuid.UUID
+		checkerErr     error
+		expectedStatus int
+	}{
+		{name: "Allowed - Assigned Employee", user: employee, pvzID: assignedPVZ, expectedStatus: http.StatusOK},
+		{name: "Denied - Unassigned Employee", user: employee, pvzID: otherPVZ, expectedStatus: http.StatusForbidden},
+		{name: "Allowed - Moderator Bypass", user: moderator, pvzID: otherPVZ, expectedStatus: http.StatusOK},
+		{name: "Failure - Checker Error", user: employee, pvzID: assignedPVZ, checkerErr: errors.New("db down"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			checker := &fakePVZAccessChecker{
+				assignments: map[uuid.UUID]uuid.UUID{employee.ID: assignedPVZ},
+				err:         tc.checkerErr,
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/pvz/"+tc.pvzID.String()+"/close_last_reception", nil)
+			req = mux.SetURLVars(req, map[string]string{"pvzId": tc.pvzID.String()})
+
+			w, _ := serveWithScope(checker, tc.user, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestRequirePVZAssignment_BodyPVZID(t *testing.T) {
+	employee := &models.User{ID: uuid.New(), Role: models.RoleEmployee}
+	assignedPVZ := uuid.New()
+	checker := &fakePVZAccessChecker{assignments: map[uuid.UUID]uuid.UUID{employee.ID: assignedPVZ}}
+
+	payload := `{"pvzId":"` + assignedPVZ.String() + `","type":"обувь"}`
+	req := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(payload))
+
+	w, body := serveWithScope(checker, employee, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, payload, body, "тело запроса должно дойти до обработчика без изменений")
+
+	req = httptest.NewRequest(http.MethodPost, "/receptions", strings.NewReader(`{"pvzId":"`+uuid.New().String()+`"}`))
+
+	w, _ = serveWithScope(checker, employee, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestRequirePVZAssignment_NoPVZIDPassesThrough(t *testing.T) {
+	employee := &models.User{ID: uuid.New(), Role: models.RoleEmployee}
+	checker := &fakePVZAccessChecker{}
+
+	req := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(`{invalid`))
+
+	w, body := serveWithScope(checker, employee, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{invalid`, body)
+	assert.Zero(t, checker.calls)
+}
+
+func TestRequirePVZAssignment_BodyTooLarge(t *testing.T) {
+	employee := &models.User{ID: uuid.New(), Role: models.RoleEmployee}
+	checker := &fakePVZAccessChecker{}
+
+	payload := `{"pvzId":"` + uuid.New().String() + `","type":"` + strings.Repeat("x", maxScopeBodyBytes) + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(payload))
+
+	w, _ := serveWithScope(checker, employee, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Zero(t, checker.calls)
+}
+
+func TestRequireProductPVZAssignment(t *testing.T) {
+	employee := &models.User{ID: uuid.New(), Role: models.RoleEmployee}
+	moderator := &models.User{ID: uuid.New(), Role: models.RoleModerator}
+	assignedPVZ := uuid.New()
+	ownProduct := uuid.New()
+	foreignProduct := uuid.New()
+
+	testCases := []struct {
+		name           string
+		user           *models.User
+		productID      uuid.UUID
+		lookupErr      error
+		expectedStatus int
+	}{
+		{name: "Allowed - Product Of Assigned PVZ", user: employee, productID: ownProduct, expectedStatus: http.StatusOK},
+		{name: "Denied - Product Of Other PVZ", user: employee, productID: foreignProduct, expectedStatus: http.StatusForbidden},
+		{name: "Allowed - Moderator Bypass", user: moderator, productID: foreignProduct, expectedStatus: http.StatusOK},
+		{name: "Passes Through - Product Not Found", user: employee, productID: uuid.New(), expectedStatus: http.StatusOK},
+		{name: "Failure - Lookup Error", user: employee, productID: ownProduct, lookupErr: errors.New("db down"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			checker := &fakePVZAccessChecker{
+				assignments: map[uuid.UUID]uuid.UUID{employee.ID: assignedPVZ},
+				products:    map[uuid.UUID]uuid.UUID{ownProduct: assignedPVZ, foreignProduct: uuid.New()},
+				lookupErr:   tc.lookupErr,
+			}
+
+			req := httptest.NewRequest(http.MethodPatch, "/products/"+tc.productID.String(), strings.NewReader(`{"type":"обувь"}`))
+			req = mux.SetURLVars(req, map[string]string{"id": tc.productID.String()})
+
+			w, _ := serveWithScopeMiddleware(RequireProductPVZAssignment(checker), tc.user, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestRequireReceptionPVZAssignment(t *testing.T) {
+	employee := &models.User{ID: uuid.New(), Role: models.RoleEmployee}
+	assignedPVZ := uuid.New()
+	ownReception := uuid.New()
+	foreignReception := uuid.New()
+
+	testCases := []struct {
+		name           string
+		receptionID    uuid.UUID
+		expectedStatus int
+	}{
+		{name: "Allowed - Reception Of Assigned PVZ", receptionID: ownReception, expectedStatus: http.StatusOK},
+		{name: "Denied - Reception Of Other PVZ", receptionID: foreignReception, expectedStatus: http.StatusForbidden},
+		{name: "Passes Through - Reception Not Found", receptionID: uuid.New(), expectedStatus: http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			checker := &fakePVZAccessChecker{
+				assignments: map[uuid.UUID]uuid.UUID{employee.ID: assignedPVZ},
+				receptions:  map[uuid.UUID]uuid.UUID{ownReception: assignedPVZ, foreignReception: uuid.New()},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/receptions/"+tc.receptionID.String()+"/close", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": tc.receptionID.String()})
+
+			w, _ := serveWithScopeMiddleware(RequireReceptionPVZAssignment(checker), employee, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+		})
+	}
+}
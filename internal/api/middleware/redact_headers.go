@@ -0,0 +1,30 @@
+package middleware
+
+import "net/http"
+
+// redactedHeaderValue подставляется в логи вместо значений чувствительных заголовков
+const redactedHeaderValue = "***"
+
+// DefaultSensitiveHeaders - заголовки, которые маскируются при логировании по умолчанию
+var DefaultSensitiveHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// RedactHeaders возвращает копию заголовков, пригодную для лога: значения заголовков
+// из sensitive заменяются на ***. Authorization маскируется всегда, даже если его нет
+// в sensitive, чтобы токены не попали в лог из-за неполной настройки
+func RedactHeaders(header http.Header, sensitive []string) http.Header {
+	redact := make(map[string]bool, len(sensitive)+1)
+	redact["Authorization"] = true
+	for _, name := range sensitive {
+		redact[http.CanonicalHeaderKey(name)] = true
+	}
+
+	redacted := make(http.Header, len(header))
+	for name, values := range header {
+		if redact[http.CanonicalHeaderKey(name)] {
+			redacted[name] = []string{redactedHeaderValue}
+			continue
+		}
+		redacted[name] = append([]string(nil), values...)
+	}
+	return redacted
+}
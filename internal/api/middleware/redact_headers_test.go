@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("Authorization", "Bearer secret-token")
+	header.Set("Cookie", "session=abc")
+	header.Set("X-Api-Key", "key-123")
+	header.Set("User-Agent", "curl/8.0")
+
+	testCases := []struct {
+		name      string
+		sensitive []string
+		expected  http.Header
+	}{
+		{
+			name:      "Default Set",
+			sensitive: DefaultSensitiveHeaders,
+			expected: http.Header{
+				"Authorization": {"***"},
+				"Cookie":        {"***"},
+				"X-Api-Key":     {"***"},
+				"User-Agent":    {"curl/8.0"},
+			},
+		},
+		{
+			name:      "Authorization Always Redacted",
+			sensitive: []string{"x-api-key"},
+			expected: http.Header{
+				"Authorization": {"***"},
+				"Cookie":        {"session=abc"},
+				"X-Api-Key":     {"***"},
+				"User-Agent":    {"curl/8.0"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			redacted := RedactHeaders(header, tc.sensitive)
+
+			assert.Equal(t, tc.expected, redacted)
+			assert.Equal(t, "Bearer secret-token", header.Get("Authorization"), "исходные заголовки не должны меняться")
+		})
+	}
+}
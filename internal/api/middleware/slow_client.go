@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"time"
+
+	"pvz-service/internal/logger"
+)
+
+// ResponseErrorTrailer - трейлер, которым помечается ответ, оборванный ошибкой записи
+const ResponseErrorTrailer = "X-Response-Error"
+
+// SlowClientMiddleware замечает ответы, которые не удалось дописать клиенту: медленный
+// клиент не успевает прочитать большой ответ до WriteTimeout сервера, и запись обрывается.
+// Вместо молчаливо обрезанного JSON пишется WARN с ID запроса, а в ответ по возможности
+// добавляется трейлер ResponseErrorTrailer. writeTimeout - WriteTimeout сервера, по нему
+// в логе видно, был ли достигнут дедлайн. Должен стоять внутри LoggingMiddleware
+func SlowClientMiddleware(writeTimeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			dw := &deadlineResponseWriter{ResponseWriter: w}
+			next.ServeHTTP(dw, r)
+
+			if dw.err == nil {
+				return
+			}
+
+			elapsed := time.Since(start)
+			deadlineExceeded := errors.Is(dw.err, os.ErrDeadlineExceeded) ||
+				(writeTimeout > 0 && elapsed >= writeTimeout)
+
+			logger.FromContext(r.Context()).WarnContext(r.Context(), "ответ не дописан клиенту",
+				"route", routeTemplate(r),
+				"bytes_written", dw.written,
+				"elapsed", elapsed.String(),
+				"write_timeout", writeTimeout.String(),
+				"deadline_exceeded", deadlineExceeded,
+				"error", dw.err,
+			)
+		})
+	}
+}
+
+// deadlineResponseWriter запоминает первую ошибку записи. После нее остаток ответа
+// не отправляется: соединение уже не дописать, а обработчику возвращается та же ошибка
+type deadlineResponseWriter struct {
+	http.ResponseWriter
+	written int
+	err     error
+}
+
+func (w *deadlineResponseWriter) Write(b []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	n, err := w.ResponseWriter.Write(b)
+	w.written += n
+	if err != nil {
+		w.err = err
+		// Трейлер дойдет, только если соединение еще живо, например при ошибке gzip
+		w.ResponseWriter.Header().Set(http.TrailerPrefix+ResponseErrorTrailer, "incomplete response")
+	}
+	return n, err
+}
+
+// Unwrap дает http.ResponseController доступ к исходному writer (Flush, дедлайны)
+func (w *deadlineResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowClientWriter имитирует соединение медленного клиента: после limit байт запись
+// упирается в дедлайн, как net/http при истекшем WriteTimeout
+type slowClientWriter struct {
+	*httptest.ResponseRecorder
+	limit int
+	calls int
+}
+
+func (w *slowClientWriter) Write(b []byte) (int, error) {
+	w.calls++
+	room := w.limit - w.Body.Len()
+	if room >= len(b) {
+		return w.ResponseRecorder.Write(b)
+	}
+	n, _ := w.ResponseRecorder.Write(b[:room])
+	return n, fmt.Errorf("write tcp 127.0.0.1:8080->127.0.0.1:50000: %w", os.ErrDeadlineExceeded)
+}
+
+func TestSlowClientMiddleware_SlowWriter(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	chunk := []byte(strings.Repeat("x", 100))
+	var writeErrs []error
+
+	handler := LoggingMiddleware(log, 0)(SlowClientMiddleware(2 * time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		for i := 0; i < 5; i++ {
+			_, err := w.Write(chunk)
+			writeErrs = append(writeErrs, err)
+		}
+	})))
+
+	w := &slowClientWriter{ResponseRecorder: httptest.NewRecorder(), limit: 250}
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/pvz", nil))
+
+	// После ошибки остаток ответа в соединение не пишется
+	assert.Equal(t, 3, w.calls)
+	assert.Equal(t, 250, w.Body.Len())
+	assert.NoError(t, writeErrs[1])
+	for _, err := range writeErrs[2:] {
+		assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+	}
+	assert.Equal(t, "incomplete response", w.Header().Get(http.TrailerPrefix+ResponseErrorTrailer))
+
+	warnings := warnRecords(t, &buf)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "ответ не дописан клиенту", warnings[0]["msg"])
+	assert.Equal(t, w.Header().Get("X-Request-ID"), warnings[0]["request_id"])
+	assert.Equal(t, float64(250), warnings[0]["bytes_written"])
+	assert.Equal(t, true, warnings[0]["deadline_exceeded"])
+}
+
+func TestSlowClientMiddleware_CompleteResponse(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	handler := LoggingMiddleware(log, 0)(SlowClientMiddleware(2 * time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok":true}`))
+	})))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/pvz", nil))
+
+	assert.Equal(t, `{"ok":true}`, w.Body.String())
+	assert.Empty(t, w.Header().Get(http.TrailerPrefix+ResponseErrorTrailer))
+	assert.Empty(t, warnRecords(t, &buf))
+}
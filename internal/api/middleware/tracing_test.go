@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTracedRouter(exporter *tracetest.InMemoryExporter) *mux.Router {
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	router := mux.NewRouter()
+	router.Use(TracingMiddleware(tp, propagation.TraceContext{}))
+	router.HandleFunc("/pvz/{pvzId}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("GET")
+	router.HandleFunc("/fail", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}).Methods("GET")
+	return router
+}
+
+func TestTracingMiddleware_SpanPerRequest(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	router := newTracedRouter(exporter)
+
+	for _, id := range []string{"11111111-1111-1111-1111-111111111111", "22222222-2222-2222-2222-222222222222"} {
+		req := httptest.NewRequest(http.MethodGet, "/pvz/"+id, nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	for _, span := range spans {
+		assert.Equal(t, "GET /pvz/{pvzId}", span.Name)
+		assert.Contains(t, span.Attributes, attribute.Int("http.response.status_code", http.StatusOK))
+		assert.Equal(t, codes.Unset, span.Status.Code)
+	}
+}
+
+func TestTracingMiddleware_PropagatesTraceparent(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	router := newTracedRouter(exporter)
+
+	req := httptest.NewRequest(http.MethodGet, "/pvz/11111111-1111-1111-1111-111111111111", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent.SpanID().String())
+}
+
+func TestTracingMiddleware_ServerErrorStatus(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	router := newTracedRouter(exporter)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "GET /fail", spans[0].Name)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+)
+
+const (
+	// maxURLLength ограничивает длину пути вместе со строкой запроса
+	maxURLLength = 2048
+	// maxQueryValueLength ограничивает длину одного значения query-параметра
+	maxQueryValueLength = 256
+)
+
+// URLLimitMiddleware отклоняет запросы со слишком длинным URL (414)
+// и с чрезмерно длинными или некорректно закодированными query-параметрами (400)
+func URLLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.URL.RequestURI()) > maxURLLength {
+			http.Error(w, "Request-URI Too Long", http.StatusRequestURITooLong)
+			return
+		}
+
+		query, err := url.ParseQuery(r.URL.RawQuery)
+		if err != nil {
+			http.Error(w, "Bad Request: malformed query string", http.StatusBadRequest)
+			return
+		}
+
+		for _, values := range query {
+			for _, value := range values {
+				if len(value) > maxQueryValueLength {
+					http.Error(w, "Bad Request: query parameter value too long", http.StatusBadRequest)
+					return
+				}
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURLLimitMiddleware(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := URLLimitMiddleware(ok)
+
+	tests := []struct {
+		name   string
+		target string
+		status int
+	}{
+		{"обычный запрос", "/pvz?page=1&limit=10&startDate=2025-04-01T00:00:00Z", http.StatusOK},
+		{"слишком длинный URL", "/pvz?" + strings.Repeat("a=1&", maxURLLength/4+1), http.StatusRequestURITooLong},
+		{"слишком длинное значение", "/pvz?startDate=" + strings.Repeat("9", maxQueryValueLength+1), http.StatusBadRequest},
+		{"некорректная кодировка", "/pvz?startDate=%zz", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
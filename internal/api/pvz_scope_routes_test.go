@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/domain/interfaces"
+	"pvz-service/internal/domain/models"
+)
+
+// scopeTestAuth принимает любой токен как сотрудника user
+type scopeTestAuth struct {
+	interfaces.AuthService
+	user *models.User
+}
+
+func (a scopeTestAuth) ValidateToken(token string) (*models.User, error) {
+	return a.user, nil
+}
+
+// scopeTestAccess: сотрудник ни на что не назначен, все приемки и товары принадлежат pvzID
+type scopeTestAccess struct {
+	pvzID uuid.UUID
+}
+
+func (a scopeTestAccess) IsEmployeeAssigned(ctx context.Context, userID, pvzID uuid.UUID) (bool, error) {
+	return false, nil
+}
+
+func (a scopeTestAccess) ReceptionPVZID(ctx context.Context, receptionID uuid.UUID) (uuid.UUID, error) {
+	return a.pvzID, nil
+}
+
+func (a scopeTestAccess) ProductPVZID(ctx context.Context, productID uuid.UUID) (uuid.UUID, error) {
+	return a.pvzID, nil
+}
+
+// Изменяющие маршруты сотрудника по идентификатору приемки или товара проверяют назначение
+// на ПВЗ этой сущности; до обработчиков (здесь nil-сервисы) запрос не доходит
+func TestRouter_EmployeeMutationsByIDRequirePVZAssignment(t *testing.T) {
+	employee := &models.User{ID: uuid.New(), Role: models.RoleEmployee, CreatedAt: time.Now()}
+	router := NewRouter(scopeTestAuth{user: employee}, nil, nil, nil, nil, scopeTestAccess{pvzID: uuid.New()}, nil, 0)
+
+	testCases := []struct {
+		method string
+		path   string
+	}{
+		{method: http.MethodPost, path: "/receptions/" + uuid.New().String() + "/close"},
+		{method: http.MethodPost, path: "/receptions/" + uuid.New().String() + "/resequence"},
+		{method: http.MethodPatch, path: "/products/" + uuid.New().String()},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			req.Header.Set("Authorization", "Bearer token")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusForbidden, w.Code)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package respond
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Code - стабильный машиночитаемый код ошибки, по которому выбирается перевод сообщения.
+// Обработчики передают его явно в ErrorWithCode
+type Code string
+
+const (
+	CodeInvalidRequest     Code = "invalid_request"
+	CodeValidationFailed   Code = "validation_failed"
+	CodeInvalidPVZID       Code = "invalid_pvz_id"
+	CodeInvalidUserID      Code = "invalid_user_id"
+	CodeInvalidID          Code = "invalid_id"
+	CodeInvalidDate        Code = "invalid_date"
+	CodeInvalidCredentials Code = "invalid_credentials"
+	CodePVZNotFound        Code = "pvz_not_found"
+	CodeReceptionNotFound  Code = "reception_not_found"
+	CodeProductNotFound    Code = "product_not_found"
+	CodeUserNotFound       Code = "user_not_found"
+	CodeAssignmentNotFound Code = "assignment_not_found"
+	CodeUserNotEmployee    Code = "user_not_employee"
+	CodeReceptionClosed    Code = "reception_closed"
+	CodeReceptionFull      Code = "reception_full"
+	CodeNotFound           Code = "not_found"
+	CodeMethodNotAllowed   Code = "method_not_allowed"
+	CodePageTooLarge       Code = "page_too_large"
+	CodePageOutOfRange     Code = "page_out_of_range"
+	CodeRequestCanceled    Code = "request_canceled"
+	CodeRequestTimeout     Code = "request_timeout"
+	CodeEmailDomainBlocked Code = "email_domain_blocked"
+)
+
+const (
+	langEN = "en"
+	langRU = "ru"
+
+	// defaultLang используется, если Accept-Language не указан или не поддерживается
+	defaultLang = langEN
+)
+
+const (
+	// PageTooLargeMessage возвращается при запросе страницы списка за пределом MAX_LIST_PAGE
+	PageTooLargeMessage = "Page is too large. Narrow the list with startDate and endDate filters"
+
+	// PageOutOfRangeMessage возвращается, если page или limit не помещаются в допустимые границы
+	PageOutOfRangeMessage = "Page or limit is out of range"
+)
+
+// validationFailedPrefix - начало сообщения об ошибке валидации, за которым следуют детали
+const validationFailedPrefix = "Validation failed: "
+
+// errorMessages - переводы сообщений по кодам ошибок
+var errorMessages = map[Code]map[string]string{
+	CodeInvalidRequest:     {langEN: "Invalid request format", langRU: "Некорректный формат запроса"},
+	CodeValidationFailed:   {langEN: "Validation failed", langRU: "Ошибка валидации"},
+	CodeInvalidPVZID:       {langEN: "Invalid pvzId format", langRU: "Некорректный формат идентификатора ПВЗ"},
+	CodeInvalidUserID:      {langEN: "Invalid userId format", langRU: "Некорректный формат идентификатора пользователя"},
+	CodeInvalidID:          {langEN: "Invalid id format", langRU: "Некорректный формат идентификатора"},
+	CodeInvalidDate:        {langEN: "Invalid date format. Use RFC3339 format", langRU: "Некорректный формат даты. Используйте RFC3339"},
+	CodeInvalidCredentials: {langEN: "Invalid credentials", langRU: "Неверный email или пароль"},
+	CodeEmailDomainBlocked: {langEN: "Email domain is not allowed", langRU: "Регистрация с этого почтового домена запрещена"},
+	CodePVZNotFound:        {langEN: "PVZ not found", langRU: "ПВЗ не найден"},
+	CodeReceptionNotFound:  {langEN: "Reception not found", langRU: "Приемка не найдена"},
+	CodeProductNotFound:    {langEN: "Product not found", langRU: "Товар не найден"},
+	CodeUserNotFound:       {langEN: "User not found", langRU: "Пользователь не найден"},
+	CodeAssignmentNotFound: {langEN: "Assignment not found", langRU: "Назначение не найдено"},
+	CodeUserNotEmployee:    {langEN: "User is not an employee", langRU: "Пользователь не является сотрудником"},
+	CodeReceptionClosed:    {langEN: "Reception is closed", langRU: "Приемка закрыта"},
+	CodeReceptionFull:      {langEN: "Reception is full", langRU: "Приемка заполнена"},
+	CodeNotFound:           {langEN: "Resource not found", langRU: "Ресурс не найден"},
+	CodeMethodNotAllowed:   {langEN: "Method not allowed", langRU: "Метод не поддерживается"},
+	CodePageTooLarge:       {langEN: PageTooLargeMessage, langRU: "Слишком дальняя страница. Сузьте список фильтрами startDate и endDate"},
+	CodePageOutOfRange:     {langEN: PageOutOfRangeMessage, langRU: "Значение page или limit вне допустимого диапазона"},
+	CodeRequestCanceled:    {langEN: requestCanceledMessage, langRU: "Запрос отменен клиентом"},
+	CodeRequestTimeout:     {langEN: requestTimeoutMessage, langRU: "Превышено время обработки запроса"},
+}
+
+// localizeError возвращает сообщение с кодом code на языке lang. Сообщения без кода
+// и английский текст возвращаются как есть: исходное сообщение может быть точнее общего перевода
+func localizeError(code Code, message, lang string) string {
+	if code == "" || lang == langEN {
+		return message
+	}
+
+	translated, ok := errorMessages[code][lang]
+	if !ok {
+		return message
+	}
+	if code == CodeValidationFailed {
+		if details, ok := strings.CutPrefix(message, validationFailedPrefix); ok {
+			return translated + ": " + details
+		}
+	}
+	return translated
+}
+
+// preferredLanguage выбирает поддерживаемый язык из Accept-Language с учетом q-весов
+func preferredLanguage(r *http.Request) string {
+	header := r.Header.Get("Accept-Language")
+	if header == "" {
+		return defaultLang
+	}
+
+	best, bestQ := defaultLang, 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if primary != langEN && primary != langRU {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		if q > bestQ {
+			best, bestQ = primary, q
+		}
+	}
+	return best
+}
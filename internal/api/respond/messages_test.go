@@ -0,0 +1,45 @@
+package respond
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalizeError(t *testing.T) {
+	message := localizeError(CodeValidationFailed, "Validation failed: city is required", langRU)
+	assert.Equal(t, "Ошибка валидации: city is required", message)
+
+	message = localizeError(CodeInvalidDate, "Invalid from format. Use RFC3339 format", langRU)
+	assert.Equal(t, "Некорректный формат даты. Используйте RFC3339", message)
+
+	message = localizeError(CodeInvalidDate, "Invalid from format. Use RFC3339 format", langEN)
+	assert.Equal(t, "Invalid from format. Use RFC3339 format", message)
+
+	// Без кода сообщение не переводится, даже если совпадает с текстом из каталога
+	message = localizeError("", "PVZ not found", langRU)
+	assert.Equal(t, "PVZ not found", message)
+}
+
+func TestPreferredLanguage(t *testing.T) {
+	testCases := []struct {
+		header   string
+		expected string
+	}{
+		{header: "", expected: "en"},
+		{header: "ru", expected: "ru"},
+		{header: "en-US,en;q=0.9,ru;q=0.8", expected: "en"},
+		{header: "fr;q=1.0, ru;q=0.5", expected: "ru"},
+		{header: "en;q=0.3, ru-RU;q=0.7", expected: "ru"},
+		{header: "de", expected: "en"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.header, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("Accept-Language", tc.header)
+			assert.Equal(t, tc.expected, preferredLanguage(req))
+		})
+	}
+}
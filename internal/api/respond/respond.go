@@ -0,0 +1,129 @@
+// Package respond формирует JSON-ответы HTTP-обработчиков: единый Content-Type,
+// X-Request-ID и формат ошибок с переводом сообщений по Accept-Language
+package respond
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	"pvz-service/internal/logger"
+)
+
+// StatusClientClosedRequest - нестандартный код nginx: клиент закрыл соединение, не дождавшись ответа
+const StatusClientClosedRequest = 499
+
+const (
+	requestCanceledMessage = "Request canceled"
+	requestTimeoutMessage  = "Request timeout"
+)
+
+// ErrorResponse - тело ответа об ошибке. Code заполняется для ошибок, отправленных
+// через ErrorWithCode, RequestID - если запрос прошел через LoggingMiddleware
+type ErrorResponse struct {
+	Error     string `json:"error"`
+	Code      string `json:"code,omitempty"`
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// pretty включает отступы в JSON-ответах; задается при старте через SetPretty
+var pretty atomic.Bool
+
+// SetPretty включает форматирование JSON-ответов с отступами для отладки.
+// По умолчанию ответы компактные
+func SetPretty(enabled bool) {
+	pretty.Store(enabled)
+}
+
+func marshal(v interface{}) ([]byte, error) {
+	if pretty.Load() {
+		return json.MarshalIndent(v, "", "  ")
+	}
+	return json.Marshal(v)
+}
+
+// requestID возвращает ID запроса, выданный LoggingMiddleware, или пустую строку
+func requestID(r *http.Request) string {
+	return logger.RequestIDFromContext(r.Context())
+}
+
+// JSON отправляет v как JSON со статусом status. Тело сначала кодируется в память,
+// поэтому значение, которое не сериализуется, дает 500 вместо обрезанного ответа.
+// Ошибка записи уже начатого ответа (например, клиент отключился) статус не меняет
+// и только попадает в лог
+func JSON(w http.ResponseWriter, r *http.Request, status int, v interface{}) {
+	body, err := marshal(v)
+	if err != nil {
+		Error(w, r, http.StatusInternalServerError, "Error encoding response", fmt.Errorf("error encoding %T: %w", v, err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if id := requestID(r); id != "" && w.Header().Get("X-Request-ID") == "" {
+		w.Header().Set("X-Request-ID", id)
+	}
+	w.WriteHeader(status)
+	if _, err := w.Write(append(body, '\n')); err != nil {
+		logger.FromContext(r.Context()).Warn("ошибка записи JSON-ответа", "error", err, "status", status)
+	}
+}
+
+// Error отправляет JSON-ошибку без кода: message уходит клиенту как есть, без перевода.
+// Для сообщений из каталога переводов используется ErrorWithCode
+func Error(w http.ResponseWriter, r *http.Request, status int, message string, err error) {
+	ErrorWithCode(w, r, status, "", message, err)
+}
+
+// ErrorWithCode отправляет JSON-ошибку с кодом code. Сообщение переводится по коду на язык
+// из Accept-Language; в лог пишется исходный английский текст. Ошибки отмены контекста
+// заменяют status, code и message на 499/408
+func ErrorWithCode(w http.ResponseWriter, r *http.Request, status int, code Code, message string, err error) {
+	log := logger.FromContext(r.Context())
+
+	if ctxStatus, ctxCode, ctxMessage, ok := contextError(err); ok {
+		log.Info("запрос прерван до завершения",
+			"error", err,
+			"status", ctxStatus,
+			"message", message,
+		)
+		status, code, message = ctxStatus, ctxCode, ctxMessage
+	} else if err != nil {
+		log.Error("ошибка обработки запроса",
+			"error", err,
+			"status", status,
+			"message", message,
+		)
+	} else {
+		log.Warn("запрос завершен с ошибкой",
+			"status", status,
+			"message", message,
+		)
+	}
+
+	lang := preferredLanguage(r)
+
+	w.Header().Set("Content-Language", lang)
+	JSON(w, r, status, ErrorResponse{Error: localizeError(code, message, lang), Code: string(code), RequestID: requestID(r)})
+}
+
+// ContextErrorStatus сопоставляет ошибки отмены контекста с кодом и сообщением ответа:
+// 499 при отключении клиента, 408 при истечении дедлайна. ok=false для прочих ошибок
+func ContextErrorStatus(err error) (status int, message string, ok bool) {
+	status, _, message, ok = contextError(err)
+	return status, message, ok
+}
+
+// contextError - ContextErrorStatus вместе с кодом ошибки ответа
+func contextError(err error) (status int, code Code, message string, ok bool) {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return StatusClientClosedRequest, CodeRequestCanceled, requestCanceledMessage, true
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusRequestTimeout, CodeRequestTimeout, requestTimeoutMessage, true
+	default:
+		return 0, "", "", false
+	}
+}
@@ -0,0 +1,191 @@
+package respond
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/logger"
+)
+
+// failingWriter имитирует клиента, отключившегося до записи тела
+type failingWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (w failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+func newTestRequest(buf *bytes.Buffer) *http.Request {
+	req := httptest.NewRequest("GET", "/pvz", nil)
+	log := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	return req.WithContext(logger.WithLogger(req.Context(), log))
+}
+
+func decodeError(t *testing.T, w *httptest.ResponseRecorder) ErrorResponse {
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response
+}
+
+func TestJSON(t *testing.T) {
+	var buf bytes.Buffer
+	req := newTestRequest(&buf)
+	w := httptest.NewRecorder()
+
+	JSON(w, req, http.StatusCreated, map[string]string{"message": "ok"})
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Empty(t, w.Header().Get("X-Request-ID"))
+	assert.Equal(t, "{\"message\":\"ok\"}\n", w.Body.String())
+	assert.Empty(t, buf.String())
+}
+
+func TestJSON_RequestID(t *testing.T) {
+	var buf bytes.Buffer
+	req := newTestRequest(&buf)
+	req = req.WithContext(logger.WithRequestID(req.Context(), "req-1"))
+	w := httptest.NewRecorder()
+
+	JSON(w, req, http.StatusOK, map[string]string{"message": "ok"})
+
+	assert.Equal(t, "req-1", w.Header().Get("X-Request-ID"))
+}
+
+func TestJSON_MarshalError(t *testing.T) {
+	var buf bytes.Buffer
+	req := newTestRequest(&buf)
+	w := httptest.NewRecorder()
+
+	JSON(w, req, http.StatusOK, map[string]float64{"value": math.Inf(1)})
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"error":"Error encoding response"}`, w.Body.String())
+	assert.Contains(t, buf.String(), `"level":"ERROR"`)
+	assert.Contains(t, buf.String(), "error encoding map[string]float64")
+}
+
+func TestJSON_WriteError(t *testing.T) {
+	var buf bytes.Buffer
+	req := newTestRequest(&buf)
+	w := failingWriter{httptest.NewRecorder()}
+
+	JSON(w, req, http.StatusOK, map[string]string{"message": "ok"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, buf.String(), "ошибка записи JSON-ответа")
+	assert.Contains(t, buf.String(), "broken pipe")
+}
+
+func TestJSON_Pretty(t *testing.T) {
+	SetPretty(true)
+	t.Cleanup(func() { SetPretty(false) })
+
+	var buf bytes.Buffer
+	req := newTestRequest(&buf)
+	w := httptest.NewRecorder()
+
+	JSON(w, req, http.StatusOK, map[string]string{"message": "ok"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "{\n  \"message\": \"ok\"\n}\n", w.Body.String())
+}
+
+func TestError(t *testing.T) {
+	testCases := []struct {
+		name           string
+		status         int
+		code           Code
+		message        string
+		err            error
+		expectedStatus int
+		expectedBody   ErrorResponse
+		expectedLevel  string
+	}{
+		{
+			name:           "Catalog Message",
+			status:         http.StatusNotFound,
+			code:           CodePVZNotFound,
+			message:        "PVZ not found",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   ErrorResponse{Error: "PVZ not found", Code: string(CodePVZNotFound), RequestID: "req-1"},
+			expectedLevel:  "WARN",
+		},
+		{
+			name:           "Catalog Text Without Code",
+			status:         http.StatusNotFound,
+			message:        "PVZ not found",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   ErrorResponse{Error: "PVZ not found", RequestID: "req-1"},
+			expectedLevel:  "WARN",
+		},
+		{
+			name:           "Internal Error",
+			status:         http.StatusInternalServerError,
+			message:        "Unable to create PVZ",
+			err:            errors.New("db is down"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   ErrorResponse{Error: "Unable to create PVZ", RequestID: "req-1"},
+			expectedLevel:  "ERROR",
+		},
+		{
+			name:           "Client Canceled",
+			status:         http.StatusInternalServerError,
+			message:        "Unable to create PVZ",
+			err:            fmt.Errorf("error creating pvz: %w", context.Canceled),
+			expectedStatus: StatusClientClosedRequest,
+			expectedBody:   ErrorResponse{Error: requestCanceledMessage, Code: string(CodeRequestCanceled), RequestID: "req-1"},
+			expectedLevel:  "INFO",
+		},
+		{
+			name:           "Deadline Exceeded",
+			status:         http.StatusInternalServerError,
+			message:        "Unable to create PVZ",
+			err:            context.DeadlineExceeded,
+			expectedStatus: http.StatusRequestTimeout,
+			expectedBody:   ErrorResponse{Error: requestTimeoutMessage, Code: string(CodeRequestTimeout), RequestID: "req-1"},
+			expectedLevel:  "INFO",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			req := newTestRequest(&buf)
+			req = req.WithContext(logger.WithRequestID(req.Context(), "req-1"))
+			w := httptest.NewRecorder()
+
+			ErrorWithCode(w, req, tc.status, tc.code, tc.message, tc.err)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			assert.Equal(t, "en", w.Header().Get("Content-Language"))
+			assert.Equal(t, "req-1", w.Header().Get("X-Request-ID"))
+			assert.Equal(t, tc.expectedBody, decodeError(t, w))
+			assert.Contains(t, buf.String(), `"level":"`+tc.expectedLevel+`"`)
+		})
+	}
+}
+
+func TestError_WithoutRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	req := newTestRequest(&buf)
+	w := httptest.NewRecorder()
+
+	Error(w, req, http.StatusBadRequest, "Invalid request format", nil)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.NotContains(t, w.Body.String(), "requestId")
+}
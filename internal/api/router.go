@@ -16,54 +16,187 @@ func NewRouter(
 	pvzService interfaces.PVZService,
 	receptionService interfaces.ReceptionService,
 	productService interfaces.ProductService,
+	assignmentService interfaces.AssignmentService,
+	pvzAccess interfaces.PVZAccessChecker,
+	events interfaces.EventSubscriber,
+	maxPage int,
+	outer ...mux.MiddlewareFunc,
 ) *mux.Router {
 	router := mux.NewRouter()
 
+	// Ответы на неизвестные пути и методы в том же JSON-формате, что и остальные ошибки
+	router.NotFoundHandler = http.HandlerFunc(handlers.NotFound)
+	router.MethodNotAllowedHandler = http.HandlerFunc(handlers.MethodNotAllowed)
+
+	// outer (например, трейсинг) оборачивают все остальные middleware, чтобы видеть
+	// и их ответы: 414 от URLLimit и 500 после паники, перехваченной Recovery
+	router.Use(outer...)
+
 	// Добавляем общий middleware для мониторинга производительности
 	router.Use(middleware.ResponseTimeMiddleware)
 	router.Use(middleware.RecoveryMiddleware)
+	router.Use(middleware.URLLimitMiddleware)
 
 	// Инициализируем обработчики
 	authHandler := handlers.NewAuthHandler(authService)
-	pvzHandler := handlers.NewPVZHandler(pvzService)
-	receptionHandler := handlers.NewReceptionHandler(receptionService)
-	productHandler := handlers.NewProductHandler(productService)
+	pvzHandler := handlers.NewPVZHandler(pvzService).WithMaxPage(maxPage)
+	receptionHandler := handlers.NewReceptionHandler(receptionService).WithMaxPage(maxPage)
+	productHandler := handlers.NewProductHandler(productService).WithMaxPage(maxPage)
+	assignmentHandler := handlers.NewAssignmentHandler(assignmentService)
+	eventsHandler := handlers.NewEventsHandler(events)
 
 	// Создаем middleware для авторизации
 	authMiddleware := middleware.AuthMiddleware(authService)
 	employeeRoleMiddleware := middleware.RequireRole(models.RoleEmployee)
 	moderatorRoleMiddleware := middleware.RequireRole(models.RoleModerator)
 
+	// Идентификаторы в пути проверяются до обработчиков, чтобы ошибка формата была единообразной
+	pvzIDVar := middleware.ValidateUUIDVars("pvzId")
+	idVar := middleware.ValidateUUIDVars("id")
+
+	// Сотрудник может менять данные только назначенных ему ПВЗ; без pvzAccess проверка отключена
+	pvzScopeMiddleware := func(next http.Handler) http.Handler { return next }
+	receptionScopeMiddleware := pvzScopeMiddleware
+	productScopeMiddleware := pvzScopeMiddleware
+	if pvzAccess != nil {
+		pvzScopeMiddleware = middleware.RequirePVZAssignment(pvzAccess)
+		receptionScopeMiddleware = middleware.RequireReceptionPVZAssignment(pvzAccess)
+		productScopeMiddleware = middleware.RequireProductPVZAssignment(pvzAccess)
+	}
+
 	// Авторизация - согласно спецификации
 	router.HandleFunc("/dummyLogin", authHandler.DummyLogin).Methods("POST")
 	router.HandleFunc("/register", authHandler.Register).Methods("POST")
 	router.HandleFunc("/login", authHandler.Login).Methods("POST")
 
+	// GET /auth/introspect - проверка токена без побочных эффектов
+	router.HandleFunc("/auth/introspect", authHandler.Introspect).Methods("GET")
+
+	// GET /cities - перечень городов, допустимых для создания ПВЗ (без авторизации)
+	router.HandleFunc("/cities", pvzHandler.ListAllowedCities).Methods("GET")
+
 	// ПВЗ - согласно спецификации
 	pvzRouter := router.PathPrefix("/pvz").Subrouter()
 	pvzRouter.Use(authMiddleware)
+	pvzRouter.Use(middleware.ValidateUUIDVars("pvzId", "userId"))
 
 	// POST /pvz - создание ПВЗ (только модератор)
 	pvzRouter.Handle("", moderatorRoleMiddleware(http.HandlerFunc(pvzHandler.CreatePVZ))).Methods("POST")
 
+	// POST /pvz/batch - пакетное создание ПВЗ в одной транзакции (только модератор)
+	pvzRouter.Handle("/batch", moderatorRoleMiddleware(http.HandlerFunc(pvzHandler.CreatePVZBatch))).Methods("POST")
+
+	// POST /pvz/batch_get - получение нескольких ПВЗ по списку идентификаторов
+	pvzRouter.HandleFunc("/batch_get", pvzHandler.GetPVZBatch).Methods("POST")
+
+	// POST /pvz/validate_cities - проверка списка городов без создания ПВЗ
+	pvzRouter.HandleFunc("/validate_cities", pvzHandler.ValidateCities).Methods("POST")
+
+	// GET /pvz/cities - города с числом ПВЗ; регистрируется до /{pvzId}
+	pvzRouter.HandleFunc("/cities", pvzHandler.ListCities).Methods("GET")
+
+	// GET /pvz/export - выгрузка всех ПВЗ в JSON или CSV (только модератор); регистрируется до /{pvzId}
+	pvzRouter.Handle("/export", moderatorRoleMiddleware(http.HandlerFunc(pvzHandler.ExportPVZ))).Methods("GET")
+
 	// GET /pvz - получение списка ПВЗ
 	pvzRouter.HandleFunc("", pvzHandler.ListPVZ).Methods("GET")
 
+	// GET /pvz/{pvzId} - получение ПВЗ по ID с поддержкой ETag/If-None-Match
+	pvzRouter.HandleFunc("/{pvzId}", pvzHandler.GetPVZByID).Methods("GET")
+
+	// Назначения сотрудников на ПВЗ (только модератор)
+	pvzRouter.Handle("/{pvzId}/employees", moderatorRoleMiddleware(http.HandlerFunc(assignmentHandler.ListEmployees))).Methods("GET")
+	pvzRouter.Handle("/{pvzId}/employees", moderatorRoleMiddleware(http.HandlerFunc(assignmentHandler.AssignEmployee))).Methods("POST")
+	pvzRouter.Handle("/{pvzId}/employees/{userId}", moderatorRoleMiddleware(http.HandlerFunc(assignmentHandler.UnassignEmployee))).Methods("DELETE")
+
+	// GET /pvz/{pvzId}/recent_products?n= - последние товары открытой приемки
+	pvzRouter.HandleFunc("/{pvzId}/recent_products", productHandler.GetRecentProducts).Methods("GET")
+
+	// GET /pvz/{pvzId}/products - товары всех приемок ПВЗ постранично
+	pvzRouter.HandleFunc("/{pvzId}/products", productHandler.ListPVZProducts).Methods("GET")
+
+	// GET /pvz/{pvzId}/receptions?page=&limit=&status= - приемки ПВЗ
+	pvzRouter.HandleFunc("/{pvzId}/receptions", receptionHandler.ListPVZReceptions).Methods("GET")
+
+	// GET /pvz/{pvzId}/events - поток событий ПВЗ (Server-Sent Events)
+	pvzRouter.HandleFunc("/{pvzId}/events", eventsHandler.StreamPVZEvents).Methods("GET")
+
 	// POST /pvz/{pvzId}/close_last_reception - закрытие последней приемки (employee)
 	router.Handle("/pvz/{pvzId}/close_last_reception",
-		authMiddleware(employeeRoleMiddleware(http.HandlerFunc(receptionHandler.CloseLastReception)))).Methods("POST")
+		authMiddleware(pvzIDVar(employeeRoleMiddleware(pvzScopeMiddleware(http.HandlerFunc(receptionHandler.CloseLastReception)))))).Methods("POST")
+
+	// POST /pvz/{pvzId}/receptions/current/touch - продление открытой приемки (employee)
+	router.Handle("/pvz/{pvzId}/receptions/current/touch",
+		authMiddleware(pvzIDVar(employeeRoleMiddleware(pvzScopeMiddleware(http.HandlerFunc(receptionHandler.TouchCurrentReception)))))).Methods("POST")
 
 	// POST /pvz/{pvzId}/delete_last_product - удаление последнего товара (employee)
 	router.Handle("/pvz/{pvzId}/delete_last_product",
-		authMiddleware(employeeRoleMiddleware(http.HandlerFunc(productHandler.DeleteLastProduct)))).Methods("POST")
+		authMiddleware(pvzIDVar(employeeRoleMiddleware(pvzScopeMiddleware(http.HandlerFunc(productHandler.DeleteLastProduct)))))).Methods("POST")
+
+	// POST /pvz/{pvzId}/clear_reception - удаление всех товаров открытой приемки (employee)
+	router.Handle("/pvz/{pvzId}/clear_reception",
+		authMiddleware(pvzIDVar(employeeRoleMiddleware(pvzScopeMiddleware(http.HandlerFunc(productHandler.ClearReception)))))).Methods("POST")
 
 	// POST /receptions - создание новой приемки (employee)
 	router.Handle("/receptions",
-		authMiddleware(employeeRoleMiddleware(http.HandlerFunc(receptionHandler.CreateReception)))).Methods("POST")
+		authMiddleware(employeeRoleMiddleware(pvzScopeMiddleware(http.HandlerFunc(receptionHandler.CreateReception))))).Methods("POST")
+
+	// GET /receptions - список приемок с фильтрами, в т.ч. containsType
+	router.Handle("/receptions",
+		authMiddleware(http.HandlerFunc(receptionHandler.ListReceptions))).Methods("GET")
+
+	// DELETE /receptions?before= - удаление старых закрытых приемок с товарами (moderator)
+	router.Handle("/receptions",
+		authMiddleware(moderatorRoleMiddleware(http.HandlerFunc(receptionHandler.PurgeReceptions)))).Methods("DELETE")
+
+	// GET /receptions/stats?pvzId= - количество открытых и закрытых приемок
+	router.Handle("/receptions/stats",
+		authMiddleware(http.HandlerFunc(receptionHandler.GetReceptionStats))).Methods("GET")
+
+	// GET /receptions/{id} - приемка с товарами; на нее указывает Location ответа POST /receptions.
+	// Регистрируется после /receptions/stats, чтобы "stats" не принимался за идентификатор
+	router.Handle("/receptions/{id}",
+		authMiddleware(idVar(http.HandlerFunc(receptionHandler.GetReception)))).Methods("GET")
+
+	// GET /receptions/{id}/summary - количество товаров приемки по типам
+	router.Handle("/receptions/{id}/summary",
+		authMiddleware(idVar(http.HandlerFunc(receptionHandler.GetReceptionSummary)))).Methods("GET")
+
+	// GET /receptions/{id}/type_counts - количество товаров приемки по каждому типу
+	router.Handle("/receptions/{id}/type_counts",
+		authMiddleware(idVar(http.HandlerFunc(receptionHandler.GetReceptionTypeCounts)))).Methods("GET")
+
+	// GET /receptions/{id}/sequence_gaps - пропущенные номера товаров после удалений
+	router.Handle("/receptions/{id}/sequence_gaps",
+		authMiddleware(idVar(http.HandlerFunc(receptionHandler.GetReceptionSequenceGaps)))).Methods("GET")
+
+	// POST /receptions/{id}/resequence - перенумерация товаров открытой приемки подряд от 1 (employee)
+	router.Handle("/receptions/{id}/resequence",
+		authMiddleware(idVar(employeeRoleMiddleware(receptionScopeMiddleware(http.HandlerFunc(receptionHandler.ResequenceReception)))))).Methods("POST")
+
+	// POST /receptions/{id}/close - закрытие приемки по идентификатору (employee)
+	router.Handle("/receptions/{id}/close",
+		authMiddleware(idVar(employeeRoleMiddleware(receptionScopeMiddleware(http.HandlerFunc(receptionHandler.CloseReception)))))).Methods("POST")
 
 	// POST /products - добавление товара (employee)
 	router.Handle("/products",
-		authMiddleware(employeeRoleMiddleware(http.HandlerFunc(productHandler.AddProduct)))).Methods("POST")
+		authMiddleware(employeeRoleMiddleware(pvzScopeMiddleware(http.HandlerFunc(productHandler.AddProduct))))).Methods("POST")
+
+	// GET /products/{id} - товар по идентификатору
+	router.Handle("/products/{id}",
+		authMiddleware(idVar(http.HandlerFunc(productHandler.GetProductByID)))).Methods("GET")
+
+	// PATCH /products/{id} - исправление типа товара в открытой приемке (employee)
+	router.Handle("/products/{id}",
+		authMiddleware(idVar(employeeRoleMiddleware(productScopeMiddleware(http.HandlerFunc(productHandler.UpdateProductType)))))).Methods("PATCH")
+
+	// POST /products/{id}/move - перенос товара в открытую приемку другого ПВЗ (moderator)
+	router.Handle("/products/{id}/move",
+		authMiddleware(idVar(moderatorRoleMiddleware(http.HandlerFunc(productHandler.MoveProduct))))).Methods("POST")
+
+	// GET /admin/export?from=&to= - ZIP с CSV приемок и товаров за период, файл на ПВЗ (moderator)
+	router.Handle("/admin/export",
+		authMiddleware(moderatorRoleMiddleware(http.HandlerFunc(receptionHandler.ExportReceptions)))).Methods("GET")
 
 	return router
 }
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"pvz-service/internal/api/middleware"
+	"pvz-service/internal/api/respond"
+)
+
+func TestRouter_MethodNotAllowed(t *testing.T) {
+	router := NewRouter(nil, nil, nil, nil, nil, nil, nil, 0)
+
+	req := httptest.NewRequest(http.MethodGet, "/login", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var response respond.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Method not allowed", response.Error)
+}
+
+func TestRouter_NotFound(t *testing.T) {
+	router := NewRouter(nil, nil, nil, nil, nil, nil, nil, 0)
+
+	req := httptest.NewRequest(http.MethodGet, "/no/such/path", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var response respond.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Resource not found", response.Error)
+}
+
+func TestRouter_OuterMiddlewareWrapsRecoveryAndURLLimit(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	router := NewRouter(nil, nil, nil, nil, nil, nil, nil, 0,
+		middleware.TracingMiddleware(tp, propagation.TraceContext{}))
+	router.HandleFunc("/panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}).Methods(http.MethodGet)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/cities?q="+strings.Repeat("a", 3000), nil))
+	assert.Equal(t, http.StatusRequestURITooLong, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "GET /cities", spans[0].Name)
+	assert.Contains(t, spans[0].Attributes, attribute.Int("http.response.status_code", http.StatusRequestURITooLong))
+	assert.Equal(t, "GET /panic", spans[1].Name)
+	assert.Contains(t, spans[1].Attributes, attribute.Int("http.response.status_code", http.StatusInternalServerError))
+	assert.Equal(t, codes.Error, spans[1].Status.Code)
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// RouteInfo описывает зарегистрированный маршрут
+type RouteInfo struct {
+	Methods []string
+	Path    string
+}
+
+// RegisteredRoutes обходит роутер и возвращает все маршруты с шаблонами путей
+func RegisteredRoutes(router *mux.Router) ([]RouteInfo, error) {
+	var routes []RouteInfo
+
+	err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			// Маршруты без шаблона пути (например, только с матчером хоста) пропускаем
+			return nil
+		}
+
+		// Для маршрутов без ограничения по методу (суброутеры) список будет пустым
+		methods, _ := route.GetMethods()
+		routes = append(routes, RouteInfo{Methods: methods, Path: path})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return routes, nil
+}
+
+// LogRoutes логирует все зарегистрированные маршруты при старте,
+// чтобы сразу было видно незарегистрированные обработчики
+func LogRoutes(router *mux.Router, log *slog.Logger) {
+	routes, err := RegisteredRoutes(router)
+	if err != nil {
+		log.Error("ошибка обхода маршрутов", "error", err)
+		return
+	}
+
+	for _, route := range routes {
+		if len(route.Methods) == 0 {
+			continue
+		}
+		log.Info("зарегистрирован маршрут",
+			"method", strings.Join(route.Methods, ","),
+			"path", route.Path,
+		)
+	}
+
+	log.Info("маршруты зарегистрированы", "count", len(routes))
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/logger"
+)
+
+func TestRegisteredRoutes(t *testing.T) {
+	router := NewRouter(nil, nil, nil, nil, nil, nil, nil, 0)
+
+	routes, err := RegisteredRoutes(router)
+	require.NoError(t, err)
+
+	registered := make(map[string]bool)
+	for _, route := range routes {
+		for _, method := range route.Methods {
+			registered[method+" "+route.Path] = true
+		}
+	}
+
+	expected := []string{
+		"POST /dummyLogin",
+		"POST /register",
+		"POST /login",
+		"GET /auth/introspect",
+		"GET /cities",
+		"POST /pvz",
+		"GET /pvz",
+		"POST /pvz/batch",
+		"POST /pvz/batch_get",
+		"POST /pvz/validate_cities",
+		"GET /pvz/cities",
+		"GET /pvz/export",
+		"GET /pvz/{pvzId}",
+		"GET /pvz/{pvzId}/employees",
+		"POST /pvz/{pvzId}/employees",
+		"DELETE /pvz/{pvzId}/employees/{userId}",
+		"GET /pvz/{pvzId}/recent_products",
+		"GET /pvz/{pvzId}/products",
+		"GET /pvz/{pvzId}/receptions",
+		"GET /pvz/{pvzId}/events",
+		"POST /pvz/{pvzId}/close_last_reception",
+		"POST /pvz/{pvzId}/receptions/current/touch",
+		"POST /pvz/{pvzId}/delete_last_product",
+		"POST /pvz/{pvzId}/clear_reception",
+		"POST /receptions",
+		"GET /receptions",
+		"DELETE /receptions",
+		"GET /receptions/stats",
+		"GET /receptions/{id}",
+		"GET /receptions/{id}/summary",
+		"GET /receptions/{id}/type_counts",
+		"GET /receptions/{id}/sequence_gaps",
+		"POST /receptions/{id}/resequence",
+		"POST /receptions/{id}/close",
+		"POST /products",
+		"GET /products/{id}",
+		"PATCH /products/{id}",
+		"POST /products/{id}/move",
+		"GET /admin/export",
+	}
+
+	for _, route := range expected {
+		assert.True(t, registered[route], "маршрут %s не зарегистрирован", route)
+	}
+}
+
+func TestLogRoutes(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.New(logger.Config{Level: logger.LevelInfo, Format: "json", Output: &buf})
+
+	LogRoutes(NewRouter(nil, nil, nil, nil, nil, nil, nil, 0), log)
+
+	output := buf.String()
+	assert.Contains(t, output, `"path":"/pvz/{pvzId}/close_last_reception"`)
+	assert.Contains(t, output, `"method":"POST"`)
+	assert.Contains(t, output, "маршруты зарегистрированы")
+}
@@ -27,7 +27,7 @@ func NewServer(cfg *config.Config, handler http.Handler) *Server {
 			Addr:         fmt.Sprintf(":%d", cfg.ServerPort),
 			Handler:      handler,
 			ReadTimeout:  2 * time.Second,
-			WriteTimeout: 2 * time.Second,
+			WriteTimeout: time.Duration(cfg.ServerWriteTimeoutMs) * time.Millisecond,
 			IdleTimeout:  60 * time.Second,
 		},
 		log: log,
@@ -45,10 +45,7 @@ func FormatValidationErrors(err error) string {
 
 // validateItemType проверяет, что тип товара допустимый
 func validateItemType(fl validator.FieldLevel) bool {
-	value := fl.Field().String()
-	return value == string(models.TypeElectronics) ||
-		value == string(models.TypeClothes) ||
-		value == string(models.TypeFootwear)
+	return models.ProductType(fl.Field().String()).Valid()
 }
 
 // validateAllowedCity проверяет, что город разрешен для создания ПВЗ
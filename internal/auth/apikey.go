@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+
+	"pvz-service/internal/domain/models"
+)
+
+// ErrInvalidAPIKey возвращается, если ключ не входит в настроенный набор
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+// APIKeys сопоставляет API-ключи внутренних клиентов с ролями
+type APIKeys map[string]models.UserRole
+
+// ParseAPIKeys разбирает список пар "ключ:роль" через запятую
+func ParseAPIKeys(spec string) (APIKeys, error) {
+	keys := APIKeys{}
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		key, role, ok := strings.Cut(item, ":")
+		key, role = strings.TrimSpace(key), strings.TrimSpace(role)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid api key entry %q: expected key:role", item)
+		}
+
+		userRole := models.UserRole(role)
+		if userRole != models.RoleEmployee && userRole != models.RoleModerator {
+			return nil, fmt.Errorf("invalid role %q for api key %s", role, KeyID(key))
+		}
+		keys[key] = userRole
+	}
+	return keys, nil
+}
+
+// Lookup возвращает роль ключа. Сравниваются все ключи за постоянное время,
+// чтобы время ответа не выдавало совпадающий префикс
+func (k APIKeys) Lookup(key string) (models.UserRole, error) {
+	var role models.UserRole
+	found := false
+	for candidate, candidateRole := range k {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(key)) == 1 {
+			role = candidateRole
+			found = true
+		}
+	}
+	if !found {
+		return "", ErrInvalidAPIKey
+	}
+	return role, nil
+}
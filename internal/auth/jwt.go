@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"time"
 
@@ -17,7 +19,52 @@ type Claims struct {
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(user *models.User, secret string, expiresIn time.Duration) (string, error) {
+// TokenClaimsConfig задает значения iss/aud для токенов.
+// Пустое значение отключает установку и проверку соответствующего claim.
+type TokenClaimsConfig struct {
+	Issuer   string
+	Audience string
+}
+
+var (
+	ErrInvalidIssuer   = errors.New("invalid token issuer")
+	ErrInvalidAudience = errors.New("invalid token audience")
+	ErrUnknownKeyID    = errors.New("unknown token key id")
+)
+
+// Keyset - ключи для проверки подписи: текущий и, на время ротации, предыдущий.
+// Пустой Previous означает, что предыдущего ключа нет
+type Keyset struct {
+	Current  string
+	Previous string
+}
+
+// KeyID возвращает идентификатор ключа для заголовка kid.
+// Он выводится из секрета, поэтому отдельно его настраивать не нужно
+func KeyID(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:8])
+}
+
+// keysFor возвращает ключи-кандидаты для токена: ключ с совпадающим kid,
+// а для токенов без kid, выпущенных до ротации, - все ключи набора
+func (k Keyset) keysFor(kid string) [][]byte {
+	secrets := []string{k.Current}
+	if k.Previous != "" {
+		secrets = append(secrets, k.Previous)
+	}
+
+	var keys [][]byte
+	for _, secret := range secrets {
+		if kid == "" || KeyID(secret) == kid {
+			keys = append(keys, []byte(secret))
+		}
+	}
+	return keys
+}
+
+// GenerateToken подписывает токен секретом secret и указывает его идентификатор в заголовке kid
+func GenerateToken(user *models.User, secret string, expiresIn time.Duration, claimsCfg TokenClaimsConfig) (string, error) {
 	claims := &Claims{
 		UserID: user.ID,
 		Email:  user.Email,
@@ -25,19 +72,48 @@ func GenerateToken(user *models.User, secret string, expiresIn time.Duration) (s
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    claimsCfg.Issuer,
 		},
 	}
+	if claimsCfg.Audience != "" {
+		claims.Audience = jwt.ClaimStrings{claimsCfg.Audience}
+	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = KeyID(secret)
 	return token.SignedString([]byte(secret))
 }
 
-func ValidateToken(tokenString, secret string) (*Claims, error) {
-	claims := &Claims{}
+// ValidateToken проверяет подпись ключом из keys, выбранным по заголовку kid
+func ValidateToken(tokenString string, keys Keyset, claimsCfg TokenClaimsConfig) (*Claims, error) {
+	var (
+		claims *Claims
+		token  *jwt.Token
+		err    error
+	)
 
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(secret), nil
-	})
+	kid, err := tokenKeyID(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := keys.keysFor(kid)
+	if len(candidates) == 0 {
+		return nil, ErrUnknownKeyID
+	}
+
+	for _, key := range candidates {
+		claims = &Claims{}
+		token, err = jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, errors.New("unexpected signing method")
+			}
+			return key, nil
+		})
+		if err == nil || !errors.Is(err, jwt.ErrSignatureInvalid) {
+			break
+		}
+	}
 
 	if err != nil {
 		return nil, err
@@ -47,5 +123,23 @@ func ValidateToken(tokenString, secret string) (*Claims, error) {
 		return nil, errors.New("invalid token")
 	}
 
+	if claimsCfg.Issuer != "" && !claims.VerifyIssuer(claimsCfg.Issuer, true) {
+		return nil, ErrInvalidIssuer
+	}
+
+	if claimsCfg.Audience != "" && !claims.VerifyAudience(claimsCfg.Audience, true) {
+		return nil, ErrInvalidAudience
+	}
+
 	return claims, nil
 }
+
+// tokenKeyID читает kid из заголовка токена без проверки подписи
+func tokenKeyID(tokenString string) (string, error) {
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, &Claims{})
+	if err != nil {
+		return "", err
+	}
+	kid, _ := token.Header["kid"].(string)
+	return kid, nil
+}
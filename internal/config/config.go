@@ -4,14 +4,104 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
 
+// EnvironmentProduction - значение ENVIRONMENT, при котором небезопасные значения по умолчанию запрещены
+const EnvironmentProduction = "production"
+
+const (
+	defaultJWTSecret  = "your_jwt_secret_key"
+	defaultDBPassword = "postgres"
+)
+
 type Config struct {
-	ServerPort int
-	JWTSecret  string
-	Database   DBConfig
+	// Environment - окружение запуска (ENVIRONMENT), например development или production
+	Environment string
+
+	ServerPort  int
+	JWTSecret   string
+	JWTIssuer   string
+	JWTAudience string
+
+	// JWTPreviousSecret - прежний секрет, принимаемый при проверке токенов во время ротации
+	JWTPreviousSecret string
+
+	// APIKeys - пары "ключ:роль" через запятую для внутренних клиентов без JWT
+	APIKeys string
+
+	// BlockedEmailDomains - почтовые домены через запятую, с которых запрещена регистрация
+	BlockedEmailDomains string
+
+	// DummyTokenMaxTTLSeconds ограничивает TTL токенов /dummyLogin, 0 - без ограничения
+	DummyTokenMaxTTLSeconds int
+
+	Database DBConfig
+
+	// MaxProductsPerReception ограничивает число товаров в приемке, 0 — без ограничения
+	MaxProductsPerReception int
+
+	Webhook WebhookConfig
+
+	AutoClose AutoCloseConfig
+
+	// MaxListPage - наибольший номер страницы в списках ПВЗ и приемок, 0 — без ограничения
+	MaxListPage int
+
+	// EnforcePVZAssignment ограничивает действия сотрудников назначенными им ПВЗ
+	EnforcePVZAssignment bool
+
+	// TrustedProxies - IP и CIDR через запятую, чьим X-Forwarded-For/X-Real-IP можно верить
+	TrustedProxies string
+
+	// TracingEnabled включает OpenTelemetry-спаны на каждый HTTP запрос
+	TracingEnabled bool
+
+	// TracingOTLPEndpoint - URL OTLP/HTTP-коллектора (например, http://otel-collector:4318).
+	// Пусто - спаны не экспортируются, работают только traceparent и trace_id в логах
+	TracingOTLPEndpoint string
+
+	// MaxConcurrentRequests - наибольшее число одновременно обрабатываемых HTTP-запросов,
+	// сверх него отвечается 503. 0 — без ограничения
+	MaxConcurrentRequests int
+
+	// CSRFAuthCookie - имя cookie с сессией; изменяющие запросы с ней требуют
+	// X-CSRF-Token, совпадающий с cookie csrf_token. Пусто — проверка отключена
+	CSRFAuthCookie string
+
+	// PrettyJSON включает отступы в JSON-ответах API, только для отладки
+	PrettyJSON bool
+
+	// HTTPSRedirectEnabled включает 308-перенаправление на https для запросов с X-Forwarded-Proto: http
+	HTTPSRedirectEnabled bool
+
+	// LogLevel - минимальный уровень логов: debug, info, warn или error
+	LogLevel string
+
+	// LogDebugSampleRate оставляет одну из N debug-записей, 0 и 1 — все
+	LogDebugSampleRate int
+
+	// ServerWriteTimeoutMs - WriteTimeout HTTP-сервера: время на чтение запроса и запись ответа
+	ServerWriteTimeoutMs int
+
+	// SlowRequestThresholdMs - порог длительности запроса для WARN-лога, 0 — без предупреждений
+	SlowRequestThresholdMs int
+}
+
+// AutoCloseConfig описывает фоновое закрытие приемок, оставшихся открытыми слишком долго
+type AutoCloseConfig struct {
+	Enabled         bool
+	MaxAgeHours     int
+	IntervalMinutes int
+}
+
+// WebhookConfig описывает вебхук о закрытии приемок. Пустой URL отключает отправку
+type WebhookConfig struct {
+	URL            string
+	TimeoutSeconds int
+	MaxRetries     int
 }
 
 type DBConfig struct {
@@ -21,32 +111,138 @@ type DBConfig struct {
 	Password string
 	DBName   string
 	SSLMode  string
+
+	// ConnectAttempts - число попыток подключения при старте, ConnectRetryIntervalMs -
+	// пауза перед второй попыткой; дальше она удваивается
+	ConnectAttempts        int
+	ConnectRetryIntervalMs int
+
+	// ReplicaDSN - строка подключения к реплике для списочных запросов.
+	// Пустое значение оставляет все запросы на основной базе
+	ReplicaDSN string
+
+	// SlowQueryThresholdMs - порог длительности SQL-вызова для WARN-лога, 0 — без предупреждений
+	SlowQueryThresholdMs int
 }
 
+// ConnectionString собирает DSN в формате key=value. Значения экранируются,
+// поэтому пароли с пробелами, кавычками и обратными слешами не ломают строку
 func (db *DBConfig) ConnectionString() string {
 	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		db.Host, db.Port, db.User, db.Password, db.DBName, db.SSLMode)
+		quoteDSNValue(db.Host), db.Port, quoteDSNValue(db.User), quoteDSNValue(db.Password),
+		quoteDSNValue(db.DBName), quoteDSNValue(db.SSLMode))
+}
+
+// quoteDSNValue берет значение в одинарные кавычки, если оно пустое или содержит
+// пробелы, '=', кавычки или обратные слеши; ' и \ при этом экранируются
+func quoteDSNValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\n\r\v\f='\\") {
+		return value
+	}
+
+	var b strings.Builder
+	b.WriteByte('\'')
+	for _, r := range value {
+		if r == '\'' || r == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('\'')
+	return b.String()
 }
 
 func LoadConfig() *Config {
 	_ = godotenv.Load()
 
 	cfg := &Config{
-		ServerPort: getEnvAsInt("SERVER_PORT", 8080),
-		JWTSecret:  getEnv("JWT_SECRET", "your_jwt_secret_key"),
+		Environment:       getEnv("ENVIRONMENT", "development"),
+		ServerPort:        getEnvAsInt("SERVER_PORT", 8080),
+		JWTSecret:         getEnv("JWT_SECRET", defaultJWTSecret),
+		JWTPreviousSecret: getEnv("JWT_PREVIOUS_SECRET", ""),
+		JWTIssuer:         getEnv("JWT_ISSUER", ""),
+		JWTAudience:       getEnv("JWT_AUDIENCE", ""),
+		APIKeys:           getEnv("API_KEYS", ""),
+
+		BlockedEmailDomains: getEnv("BLOCKED_EMAIL_DOMAINS", ""),
+
+		DummyTokenMaxTTLSeconds: getEnvAsInt("DUMMY_TOKEN_MAX_TTL_SECONDS", 7*24*3600),
 		Database: DBConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
 			Port:     getEnvAsInt("DB_PORT", 5432),
 			User:     getEnv("DB_USER", "postgres"),
-			Password: getEnv("DB_PASSWORD", "postgres"),
+			Password: getEnv("DB_PASSWORD", defaultDBPassword),
 			DBName:   getEnv("DB_NAME", "pvz_service"),
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+
+			ConnectAttempts:        getEnvAsInt("DB_CONNECT_ATTEMPTS", 5),
+			ConnectRetryIntervalMs: getEnvAsInt("DB_CONNECT_RETRY_INTERVAL_MS", 500),
+
+			ReplicaDSN: getEnv("DB_REPLICA_DSN", ""),
+
+			SlowQueryThresholdMs: getEnvAsInt("DB_SLOW_QUERY_THRESHOLD_MS", 200),
+		},
+		MaxProductsPerReception: getEnvAsInt("MAX_PRODUCTS_PER_RECEPTION", 0),
+		Webhook: WebhookConfig{
+			URL:            getEnv("RECEPTION_WEBHOOK_URL", ""),
+			TimeoutSeconds: getEnvAsInt("RECEPTION_WEBHOOK_TIMEOUT_SECONDS", 5),
+			MaxRetries:     getEnvAsInt("RECEPTION_WEBHOOK_MAX_RETRIES", 3),
+		},
+		AutoClose: AutoCloseConfig{
+			Enabled:         getEnvAsBool("RECEPTION_AUTOCLOSE_ENABLED", false),
+			MaxAgeHours:     getEnvAsInt("RECEPTION_AUTOCLOSE_MAX_AGE_HOURS", 72),
+			IntervalMinutes: getEnvAsInt("RECEPTION_AUTOCLOSE_INTERVAL_MINUTES", 10),
 		},
+		MaxListPage:          getEnvAsInt("MAX_LIST_PAGE", 1000),
+		EnforcePVZAssignment: getEnvAsBool("ENFORCE_PVZ_ASSIGNMENT", false),
+		TrustedProxies:       getEnv("TRUSTED_PROXIES", ""),
+		TracingEnabled:       getEnvAsBool("TRACING_ENABLED", false),
+		TracingOTLPEndpoint:  getEnv("TRACING_OTLP_ENDPOINT", ""),
+
+		SlowRequestThresholdMs: getEnvAsInt("SLOW_REQUEST_THRESHOLD_MS", 1000),
+		ServerWriteTimeoutMs:   getEnvAsInt("SERVER_WRITE_TIMEOUT_MS", 2000),
+		HTTPSRedirectEnabled:   getEnvAsBool("HTTPS_REDIRECT_ENABLED", false),
+		MaxConcurrentRequests:  getEnvAsInt("MAX_CONCURRENT_REQUESTS", 200),
+		CSRFAuthCookie:         getEnv("CSRF_AUTH_COOKIE", ""),
+		PrettyJSON:             getEnvAsBool("PRETTY_JSON", false),
+
+		LogLevel:           getEnv("LOG_LEVEL", "info"),
+		LogDebugSampleRate: getEnvAsInt("LOG_DEBUG_SAMPLE_RATE", 1),
 	}
 
 	return cfg
 }
 
+// Validate проверяет параметры автозакрытия приемок (в любом окружении) и то, что в production
+// не используются заведомо небезопасные значения: пустой или стандартный JWT_SECRET
+// и стандартный пароль базы. В остальных окружениях значения по умолчанию допустимы
+func (c *Config) Validate() error {
+	var problems []string
+	if c.AutoClose.Enabled {
+		// Нулевой интервал роняет процесс в time.NewTicker, а нулевой возраст закрывает все открытые приемки
+		if c.AutoClose.MaxAgeHours <= 0 {
+			problems = append(problems, "RECEPTION_AUTOCLOSE_MAX_AGE_HOURS must be positive")
+		}
+		if c.AutoClose.IntervalMinutes <= 0 {
+			problems = append(problems, "RECEPTION_AUTOCLOSE_INTERVAL_MINUTES must be positive")
+		}
+	}
+
+	if c.Environment == EnvironmentProduction {
+		if c.JWTSecret == "" || c.JWTSecret == defaultJWTSecret {
+			problems = append(problems, "JWT_SECRET is empty or left at its default value")
+		}
+		if c.Database.Password == "" || c.Database.Password == defaultDBPassword {
+			problems = append(problems, "DB_PASSWORD is empty or left at its default value")
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration for %s: %s", c.Environment, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
 func getEnv(key, defaultValue string) string {
 	if value, exists := os.LookupEnv(key); exists {
 		return value
@@ -61,3 +257,11 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := getEnv(key, "")
+	if value, err := strconv.ParseBool(valueStr); err == nil {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDBConfig_ConnectionString(t *testing.T) {
+	testCases := []struct {
+		name     string
+		password string
+		expected string
+	}{
+		{
+			name:     "Plain Password",
+			password: "secret",
+			expected: "host=localhost port=5432 user=postgres password=secret dbname=pvz_service sslmode=disable",
+		},
+		{
+			name:     "Password With Spaces",
+			password: "my secret pass",
+			expected: "host=localhost port=5432 user=postgres password='my secret pass' dbname=pvz_service sslmode=disable",
+		},
+		{
+			name:     "Password With Equals Sign",
+			password: "a=b",
+			expected: "host=localhost port=5432 user=postgres password='a=b' dbname=pvz_service sslmode=disable",
+		},
+		{
+			name:     "Password With Quote And Backslash",
+			password: `it's\ok`,
+			expected: `host=localhost port=5432 user=postgres password='it\'s\\ok' dbname=pvz_service sslmode=disable`,
+		},
+		{
+			name:     "Empty Password",
+			password: "",
+			expected: "host=localhost port=5432 user=postgres password='' dbname=pvz_service sslmode=disable",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := DBConfig{
+				Host:     "localhost",
+				Port:     5432,
+				User:     "postgres",
+				Password: tc.password,
+				DBName:   "pvz_service",
+				SSLMode:  "disable",
+			}
+
+			dsn := db.ConnectionString()
+			assert.Equal(t, tc.expected, dsn)
+
+			_, err := pq.NewConnector(dsn)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestLoadConfig_AutoClose(t *testing.T) {
+	t.Run("disabled by default", func(t *testing.T) {
+		t.Setenv("RECEPTION_AUTOCLOSE_ENABLED", "")
+
+		cfg := LoadConfig()
+
+		assert.False(t, cfg.AutoClose.Enabled)
+		assert.Equal(t, 72, cfg.AutoClose.MaxAgeHours)
+		assert.Equal(t, 10, cfg.AutoClose.IntervalMinutes)
+	})
+
+	t.Run("enabled from env", func(t *testing.T) {
+		t.Setenv("RECEPTION_AUTOCLOSE_ENABLED", "true")
+		t.Setenv("RECEPTION_AUTOCLOSE_MAX_AGE_HOURS", "24")
+		t.Setenv("RECEPTION_AUTOCLOSE_INTERVAL_MINUTES", "5")
+
+		cfg := LoadConfig()
+
+		assert.True(t, cfg.AutoClose.Enabled)
+		assert.Equal(t, 24, cfg.AutoClose.MaxAgeHours)
+		assert.Equal(t, 5, cfg.AutoClose.IntervalMinutes)
+	})
+}
+
+func TestConfig_Validate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		environment string
+		jwtSecret   string
+		dbPassword  string
+		autoClose   AutoCloseConfig
+		expectedErr []string
+	}{
+		{
+			name:        "Defaults Allowed In Development",
+			environment: "development",
+			jwtSecret:   defaultJWTSecret,
+			dbPassword:  defaultDBPassword,
+		},
+		{
+			name:        "Default JWT Secret In Production",
+			environment: EnvironmentProduction,
+			jwtSecret:   defaultJWTSecret,
+			dbPassword:  "strong-password",
+			expectedErr: []string{"JWT_SECRET"},
+		},
+		{
+			name:        "Empty JWT Secret And Default Password In Production",
+			environment: EnvironmentProduction,
+			jwtSecret:   "",
+			dbPassword:  defaultDBPassword,
+			expectedErr: []string{"JWT_SECRET", "DB_PASSWORD"},
+		},
+		{
+			name:        "Safe Production Config",
+			environment: EnvironmentProduction,
+			jwtSecret:   "a-real-secret",
+			dbPassword:  "strong-password",
+		},
+		{
+			name:        "Non-Positive Auto-Close Values",
+			environment: "development",
+			autoClose:   AutoCloseConfig{Enabled: true, MaxAgeHours: 0, IntervalMinutes: -1},
+			expectedErr: []string{"RECEPTION_AUTOCLOSE_MAX_AGE_HOURS", "RECEPTION_AUTOCLOSE_INTERVAL_MINUTES"},
+		},
+		{
+			name:        "Auto-Close Values Ignored When Disabled",
+			environment: "development",
+			autoClose:   AutoCloseConfig{Enabled: false},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &Config{
+				Environment: tc.environment,
+				JWTSecret:   tc.jwtSecret,
+				Database:    DBConfig{Password: tc.dbPassword},
+				AutoClose:   tc.autoClose,
+			}
+
+			err := cfg.Validate()
+
+			if len(tc.expectedErr) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, expected := range tc.expectedErr {
+				assert.Contains(t, err.Error(), expected)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_DefaultsFailValidationInProduction(t *testing.T) {
+	t.Setenv("ENVIRONMENT", EnvironmentProduction)
+	t.Setenv("JWT_SECRET", defaultJWTSecret)
+	t.Setenv("DB_PASSWORD", "strong-password")
+
+	assert.Error(t, LoadConfig().Validate())
+}
@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"context"
+
+	"pvz-service/internal/domain/models"
+
+	"github.com/google/uuid"
+)
+
+// ReceptionNotifier уведомляет внешние системы о событиях приемок
+type ReceptionNotifier interface {
+	NotifyReceptionClosed(ctx context.Context, notification *models.ReceptionClosedNotification) error
+}
+
+// EventPublisher рассылает события ПВЗ подписчикам. Publish не должен блокировать вызывающего
+type EventPublisher interface {
+	Publish(event models.Event)
+}
+
+// EventSubscriber выдает поток событий ПВЗ. Возвращаемая функция отменяет подписку
+type EventSubscriber interface {
+	Subscribe(pvzID uuid.UUID) (<-chan models.Event, func())
+}
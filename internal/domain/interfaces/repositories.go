@@ -2,6 +2,7 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
 	"pvz-service/internal/domain/models"
 
@@ -15,9 +16,14 @@ type UserRepository interface {
 }
 
 type PVZRepository interface {
-	CreatePVZ(ctx context.Context, city string) (*models.PVZ, error)
+	CreatePVZ(ctx context.Context, city, externalID string) (*models.PVZ, bool, error)
+	CreatePVZBatch(ctx context.Context, cities []string) ([]*models.PVZ, error)
 	GetPVZByID(ctx context.Context, id uuid.UUID) (*models.PVZ, error)
+	GetPVZsByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.PVZ, error)
 	ListPVZ(ctx context.Context, options models.PVZListOptions) ([]*models.PVZWithReceptionsResponse, int, error)
+	CountPVZByCity(ctx context.Context) ([]models.CityPVZCount, error)
+	StreamPVZ(ctx context.Context, fn func(*models.PVZ) error) error
+	ListPVZWithReceptionsBetween(ctx context.Context, from, to time.Time) ([]*models.PVZ, error)
 }
 
 type ReceptionRepository interface {
@@ -25,14 +31,38 @@ type ReceptionRepository interface {
 	GetReceptionByID(ctx context.Context, id uuid.UUID) (*models.Reception, error)
 	GetLastOpenReceptionByPVZID(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error)
 	CloseReception(ctx context.Context, id uuid.UUID) error
+	ListOpenReceptionsOlderThan(ctx context.Context, t time.Time) ([]*models.Reception, error)
+	CloseStaleReception(ctx context.Context, id uuid.UUID, olderThan time.Time) (bool, error)
+	TouchOpenReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error)
 	GetReceptionWithProducts(ctx context.Context, id uuid.UUID) (*models.Reception, error)
+	GetReceptionsWithProducts(ctx context.Context, ids []uuid.UUID) ([]*models.Reception, error)
+	ListReceptionIDsByPVZ(ctx context.Context, pvzID uuid.UUID, from, to time.Time) ([]uuid.UUID, error)
+	ListReceptions(ctx context.Context, options models.ReceptionListOptions) ([]*models.Reception, int, error)
+	CountReceptionsByStatus(ctx context.Context, pvzID *uuid.UUID) (map[string]int, error)
+	PurgeReceptions(ctx context.Context, before time.Time) (int, error)
 }
 
 type ProductRepository interface {
-	CreateProduct(ctx context.Context, productType models.ProductType, receptionID uuid.UUID, sequenceNum int) (*models.Product, error)
+	AddProductToOpenReception(ctx context.Context, productType models.ProductType, receptionID uuid.UUID, limit int) (*models.Product, error)
+	DeleteLastProductFromOpenReception(ctx context.Context, receptionID uuid.UUID) (*models.Product, error)
 	GetProductByID(ctx context.Context, id uuid.UUID) (*models.Product, error)
 	GetLastProductByReceptionID(ctx context.Context, receptionID uuid.UUID) (*models.Product, error)
 	DeleteProductByID(ctx context.Context, id uuid.UUID) error
+	UpdateProductType(ctx context.Context, id uuid.UUID, productType models.ProductType) error
+	MoveProduct(ctx context.Context, productID, targetReceptionID uuid.UUID) (*models.Product, error)
+	DeleteProductsByReceptionID(ctx context.Context, receptionID uuid.UUID) (int, error)
 	CountProductsByReceptionID(ctx context.Context, receptionID uuid.UUID) (int, error)
-	GetProductsByReceptionID(ctx context.Context, receptionID uuid.UUID, page, limit int) ([]*models.Product, int, error)
+	CountProductsByTypeByReceptionID(ctx context.Context, receptionID uuid.UUID) (map[models.ProductType]int, error)
+	GetSequenceGaps(ctx context.Context, receptionID uuid.UUID) ([]int, error)
+	ResequenceProducts(ctx context.Context, receptionID uuid.UUID) (int, error)
+	GetRecentProducts(ctx context.Context, receptionID uuid.UUID, n int) ([]*models.Product, error)
+	GetProductsByReceptionID(ctx context.Context, receptionID uuid.UUID, page, limit int, sort string) ([]*models.Product, int, error)
+	ListProductsByPVZID(ctx context.Context, pvzID uuid.UUID, productType models.ProductType, page, limit int) ([]*models.Product, int, error)
+}
+
+type UserPVZRepository interface {
+	IsAssigned(ctx context.Context, userID, pvzID uuid.UUID) (bool, error)
+	AssignUser(ctx context.Context, userID, pvzID uuid.UUID) error
+	UnassignUser(ctx context.Context, userID, pvzID uuid.UUID) (bool, error)
+	ListUsersByPVZ(ctx context.Context, pvzID uuid.UUID) ([]*models.User, error)
 }
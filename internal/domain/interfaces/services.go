@@ -2,6 +2,7 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
 	"pvz-service/internal/domain/models"
 
@@ -11,23 +12,63 @@ import (
 type AuthService interface {
 	Register(ctx context.Context, email, password string, role models.UserRole) (*models.User, error)
 	Login(ctx context.Context, email, password string) (string, error)
-	GenerateDummyToken(role models.UserRole) (string, error)
+	GenerateDummyToken(role models.UserRole, ttl time.Duration) (string, error)
 	ValidateToken(token string) (*models.User, error)
+	ValidateAPIKey(key string) (*models.User, error)
+	IntrospectToken(token string) (*models.TokenIntrospection, error)
 }
 
 type PVZService interface {
-	CreatePVZ(ctx context.Context, city string) (*models.PVZ, error)
+	CreatePVZ(ctx context.Context, city, externalID string) (*models.PVZ, bool, error)
+	CreatePVZBatch(ctx context.Context, cities []string) (*models.PVZBatchCreateResponse, error)
 	GetPVZByID(ctx context.Context, id uuid.UUID) (*models.PVZ, error)
+	GetPVZsByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.PVZ, error)
 	ListPVZ(ctx context.Context, options models.PVZListOptions) ([]*models.PVZWithReceptionsResponse, int, error)
+	CountPVZByCity(ctx context.Context) ([]models.CityPVZCount, error)
+	ExportPVZ(ctx context.Context, fn func(*models.PVZ) error) error
 }
 
 type ReceptionService interface {
 	CreateReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error)
+	CreateOrReuseReception(ctx context.Context, pvzID uuid.UUID) (reception *models.Reception, reused bool, err error)
 	CloseLastReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error)
+	CloseReceptionByID(ctx context.Context, id uuid.UUID) (*models.Reception, error)
 	GetReceptionByID(ctx context.Context, id uuid.UUID) (*models.Reception, error)
+	GetReceptionSummary(ctx context.Context, id uuid.UUID) (*models.ReceptionSummary, error)
+	GetReceptionTypeCounts(ctx context.Context, id uuid.UUID) (*models.ReceptionTypeCounts, error)
+	GetReceptionSequenceGaps(ctx context.Context, id uuid.UUID) (*models.ReceptionSequenceGaps, error)
+	ResequenceReception(ctx context.Context, id uuid.UUID) (*models.ReceptionResequence, error)
+	ListReceptions(ctx context.Context, options models.ReceptionListOptions) ([]*models.Reception, int, error)
+	ListReceptionsByPVZ(ctx context.Context, pvzID uuid.UUID, options models.ReceptionListOptions) ([]*models.Reception, int, error)
+	GetReceptionStats(ctx context.Context, pvzID *uuid.UUID) (*models.ReceptionStats, error)
+	PurgeReceptions(ctx context.Context, before time.Time) (int, error)
+	ExportReceptions(ctx context.Context, from, to time.Time, fn func(pvz *models.PVZ, receptions []*models.Reception) error) error
+	TouchReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error)
 }
 
 type ProductService interface {
 	AddProduct(ctx context.Context, pvzID uuid.UUID, productType models.ProductType) (*models.Product, error)
 	DeleteLastProduct(ctx context.Context, pvzID uuid.UUID) error
+	ClearReception(ctx context.Context, pvzID uuid.UUID) (int, error)
+	GetProductByID(ctx context.Context, productID uuid.UUID) (*models.Product, error)
+	UpdateProductType(ctx context.Context, productID uuid.UUID, productType models.ProductType) error
+	MoveProduct(ctx context.Context, productID, targetPVZID uuid.UUID) error
+	GetRecentProducts(ctx context.Context, pvzID uuid.UUID, n int) ([]*models.Product, error)
+	GetProductsByReceptionID(ctx context.Context, receptionID uuid.UUID, page, limit int, sort string) ([]*models.Product, int, error)
+	ListPVZProducts(ctx context.Context, pvzID uuid.UUID, productType models.ProductType, page, limit int) ([]*models.Product, int, error)
+}
+
+// PVZAccessChecker проверяет, что сотрудник назначен на ПВЗ, и определяет ПВЗ
+// приемки или товара для маршрутов, в пути которых нет pvzId
+type PVZAccessChecker interface {
+	IsEmployeeAssigned(ctx context.Context, userID, pvzID uuid.UUID) (bool, error)
+	ReceptionPVZID(ctx context.Context, receptionID uuid.UUID) (uuid.UUID, error)
+	ProductPVZID(ctx context.Context, productID uuid.UUID) (uuid.UUID, error)
+}
+
+type AssignmentService interface {
+	PVZAccessChecker
+	AssignEmployee(ctx context.Context, pvzID, userID uuid.UUID) error
+	UnassignEmployee(ctx context.Context, pvzID, userID uuid.UUID) error
+	ListEmployees(ctx context.Context, pvzID uuid.UUID) ([]*models.User, error)
 }
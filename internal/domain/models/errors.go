@@ -0,0 +1,20 @@
+package models
+
+import "errors"
+
+// Типизированные ошибки домена, которые обработчики сопоставляют с HTTP-статусами
+var (
+	ErrReceptionFull   = errors.New("reception is full")
+	ErrReceptionClosed = errors.New("reception is closed")
+	ErrProductNotFound = errors.New("product not found")
+
+	ErrReceptionNotFound    = errors.New("reception not found")
+	ErrReceptionAlreadyOpen = errors.New("there is already an open reception for this pvz")
+
+	ErrPVZNotFound        = errors.New("pvz not found")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrUserNotEmployee    = errors.New("user is not an employee")
+	ErrAssignmentNotFound = errors.New("assignment not found")
+
+	ErrEmailDomainBlocked = errors.New("email domain is not allowed")
+)
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EventType string
+
+const (
+	EventProductAdded    EventType = "product_added"
+	EventReceptionOpened EventType = "reception_opened"
+	EventReceptionClosed EventType = "reception_closed"
+)
+
+// Event - событие ПВЗ для подписчиков (например, потока GET /pvz/{pvzId}/events).
+// Data содержит товар или приемку, к которым относится событие
+type Event struct {
+	Type     EventType   `json:"type"`
+	PVZID    uuid.UUID   `json:"pvzId"`
+	DateTime time.Time   `json:"dateTime"`
+	Data     interface{} `json:"data"`
+}
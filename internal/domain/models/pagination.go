@@ -0,0 +1,8 @@
+package models
+
+// HasNextPage сообщает, есть ли записи после страницы page при размере limit.
+// total может быть как точным количеством, так и нижней границей, которую
+// возвращают списки с SkipTotal: offset + прочитанные строки (не более limit+1)
+func HasNextPage(page, limit, total int) bool {
+	return total > page*limit
+}
@@ -14,6 +14,22 @@ const (
 	TypeFootwear    ProductType = "обувь"
 )
 
+// Valid сообщает, является ли тип товара одним из допустимых
+func (t ProductType) Valid() bool {
+	switch t {
+	case TypeElectronics, TypeClothes, TypeFootwear:
+		return true
+	}
+	return false
+}
+
+// Допустимые значения сортировки товаров внутри приемки.
+// Префикс "-" означает сортировку по убыванию
+const (
+	ProductSortSequence = "sequence"
+	ProductSortDateTime = "dateTime"
+)
+
 type Product struct {
 	ID          uuid.UUID   `json:"id"`
 	DateTime    time.Time   `json:"dateTime"`
@@ -27,3 +43,18 @@ type ProductCreateRequest struct {
 	Type  ProductType `json:"type" validate:"required,oneof=электроника одежда обувь"`
 	PVZID uuid.UUID   `json:"pvzId" validate:"required"`
 }
+
+// ProductUpdateTypeRequest представляет запрос на исправление типа товара
+type ProductUpdateTypeRequest struct {
+	Type ProductType `json:"type" validate:"required,oneof=электроника одежда обувь"`
+}
+
+// ProductMoveRequest представляет запрос на перенос товара в открытую приемку другого ПВЗ
+type ProductMoveRequest struct {
+	PVZID uuid.UUID `json:"pvzId" validate:"required"`
+}
+
+// ClearReceptionResponse представляет результат очистки открытой приемки
+type ClearReceptionResponse struct {
+	Deleted int `json:"deleted"`
+}
@@ -13,23 +13,97 @@ var AllowedCities = map[string]bool{
 	"Казань":          true,
 }
 
+// Допустимые поля сортировки списка ПВЗ
+const (
+	PVZSortRegistrationDate = "registrationDate"
+	PVZSortCity             = "city"
+)
+
+var AllowedPVZSortFields = map[string]bool{
+	PVZSortRegistrationDate: true,
+	PVZSortCity:             true,
+}
+
 type PVZ struct {
 	ID               uuid.UUID `json:"id"`
 	RegistrationDate time.Time `json:"registrationDate"`
 	City             string    `json:"city" validate:"required"`
+	ExternalID       *string   `json:"externalId,omitempty"`
 }
 
-// PVZCreateRequest представляет запрос на создание ПВЗ
+// PVZCreateRequest представляет запрос на создание ПВЗ.
+// ExternalID - необязательный внешний идентификатор площадки; повторный запрос
+// с тем же ExternalID возвращает уже созданный ПВЗ
 type PVZCreateRequest struct {
-	City string `json:"city" validate:"required"`
+	City       string `json:"city" validate:"required"`
+	ExternalID string `json:"externalId,omitempty" validate:"omitempty,max=255"`
+}
+
+// PVZBatchCreateRequest представляет запрос на создание нескольких ПВЗ одной транзакцией
+type PVZBatchCreateRequest struct {
+	Cities []string `json:"cities" validate:"required,min=1,max=100"`
+}
+
+// MaxPVZBatchGetIDs ограничивает число идентификаторов в одном запросе POST /pvz/batch_get
+const MaxPVZBatchGetIDs = 100
+
+// PVZBatchGetRequest представляет запрос на получение нескольких ПВЗ по идентификаторам
+type PVZBatchGetRequest struct {
+	IDs []uuid.UUID `json:"ids" validate:"required,min=1,max=100"`
+}
+
+// PVZValidateCitiesRequest представляет запрос на проверку списка городов перед созданием ПВЗ
+type PVZValidateCitiesRequest struct {
+	Cities []string `json:"cities" validate:"required,min=1,max=100"`
 }
 
-// PVZListOptions представляет параметры для фильтрации списка ПВЗ
+// CityPVZCount - число ПВЗ в городе для выбора региона
+type CityPVZCount struct {
+	City  string `json:"city"`
+	Count int    `json:"count"`
+}
+
+// CityValidationResult сообщает, разрешен ли город для создания ПВЗ
+type CityValidationResult struct {
+	City  string `json:"city"`
+	Valid bool   `json:"valid"`
+}
+
+// PVZBatchItemError описывает элемент пакета, который не был создан
+type PVZBatchItemError struct {
+	Index int    `json:"index"`
+	City  string `json:"city"`
+	Error string `json:"error"`
+}
+
+// PVZBatchCreateResponse представляет результат пакетного создания ПВЗ.
+// Допустимые города создаются одной транзакцией, недопустимые попадают в Errors
+type PVZBatchCreateResponse struct {
+	Created []*PVZ              `json:"created"`
+	Errors  []PVZBatchItemError `json:"errors"`
+}
+
+// PVZListOptions представляет параметры для фильтрации списка ПВЗ.
+// StartDate/EndDate фильтруют по дате приемок, RegisteredFrom/RegisteredTo -
+// по дате регистрации самого ПВЗ. Если заданы оба фильтра, они применяются
+// одновременно: в выборку попадают ПВЗ, зарегистрированные в указанный период
+// и имеющие приемки в заданном интервале
 type PVZListOptions struct {
-	Page      int       `json:"page" form:"page"`
-	Limit     int       `json:"limit" form:"limit"`
-	StartDate time.Time `json:"startDate" form:"startDate"`
-	EndDate   time.Time `json:"endDate" form:"endDate"`
+	Page           int       `json:"page" form:"page"`
+	Limit          int       `json:"limit" form:"limit"`
+	StartDate      time.Time `json:"startDate" form:"startDate"`
+	EndDate        time.Time `json:"endDate" form:"endDate"`
+	RegisteredFrom time.Time `json:"registeredFrom" form:"registeredFrom"`
+	RegisteredTo   time.Time `json:"registeredTo" form:"registeredTo"`
+	SortBy         string    `json:"sortBy" form:"sortBy"`
+	SortDesc       bool      `json:"sortDesc" form:"sortDesc"`
+
+	// OnlyOpen оставляет только ПВЗ, у которых есть приемка в статусе in_progress
+	OnlyOpen bool `json:"onlyOpen" form:"onlyOpen"`
+
+	// SkipTotal отключает COUNT: читается limit+1 строка, а вместо общего количества
+	// возвращается нижняя граница для HasNextPage
+	SkipTotal bool `json:"-" form:"-"`
 }
 
 // PVZWithReceptionsResponse представляет ПВЗ со связанными приемками и товарами
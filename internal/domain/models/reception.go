@@ -19,6 +19,19 @@ type Reception struct {
 	PVZID    uuid.UUID       `json:"pvzId"`
 	Status   ReceptionStatus `json:"status"`
 	Products []*Product      `json:"products,omitempty"`
+
+	// LastActivityAt - время последнего touch; автозакрытие отсчитывает простой от него,
+	// а если touch не было, от DateTime
+	LastActivityAt *time.Time `json:"lastActivityAt,omitempty"`
+}
+
+// ReceptionClosedNotification - полезная нагрузка вебхука о закрытии приемки. Поля
+// приемки встраиваются, поэтому прежние ключи id, dateTime, pvzId и status сохраняются.
+// ProductCount равен null, если число товаров не удалось получить
+type ReceptionClosedNotification struct {
+	Reception
+	ClosedAt     time.Time `json:"closedAt"`
+	ProductCount *int      `json:"productCount"`
 }
 
 // ReceptionCreateRequest представляет запрос на создание приемки
@@ -26,6 +39,84 @@ type ReceptionCreateRequest struct {
 	PVZID uuid.UUID `json:"pvzId" validate:"required"`
 }
 
+// ReceptionListOptions представляет параметры фильтрации списка приемок.
+// ContainsType оставляет только приемки, где есть хотя бы один товар этого типа
+type ReceptionListOptions struct {
+	Page         int
+	Limit        int
+	PVZID        uuid.UUID
+	Status       string
+	FromDate     time.Time
+	ToDate       time.Time
+	ContainsType ProductType
+
+	// SortBy - поле сортировки из AllowedReceptionSortFields, SortOrder - asc или desc.
+	// Пустые значения сохраняют порядок по умолчанию: date_time DESC
+	SortBy    string
+	SortOrder string
+
+	// SkipTotal отключает COUNT: читается limit+1 строка, а вместо общего количества
+	// возвращается нижняя граница для HasNextPage
+	SkipTotal bool
+}
+
+// Допустимые поля сортировки списка приемок
+const (
+	ReceptionSortDateTime = "date_time"
+	ReceptionSortStatus   = "status"
+)
+
+// AllowedReceptionSortFields - единственный список допустимых sortBy для приемок: его
+// проверяет обработчик, а репозиторий подставляет значения как имена колонок receptions
+var AllowedReceptionSortFields = map[string]bool{
+	ReceptionSortDateTime: true,
+	ReceptionSortStatus:   true,
+}
+
+// Направления сортировки списка приемок
+const (
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+// ReceptionSummary представляет сводку по товарам приемки без их списка
+type ReceptionSummary struct {
+	ItemsCount int                 `json:"itemsCount"`
+	ByType     map[ProductType]int `json:"byType"`
+}
+
+// ReceptionTypeCounts - количество товаров приемки по каждому типу, включая нулевые
+type ReceptionTypeCounts struct {
+	Electronics int `json:"electronics"`
+	Clothes     int `json:"clothes"`
+	Footwear    int `json:"footwear"`
+}
+
+// ReceptionSequenceGaps - номера sequence_num от 1 до наибольшего в приемке, которых нет
+// среди ее товаров (остаются после удалений). Пустой Missing - нумерация сплошная
+type ReceptionSequenceGaps struct {
+	ReceptionID uuid.UUID `json:"receptionId"`
+	Missing     []int     `json:"missing"`
+}
+
+// ReceptionResequence - результат перенумерации товаров приемки: после нее номера
+// идут подряд от 1 до Products
+type ReceptionResequence struct {
+	ReceptionID uuid.UUID `json:"receptionId"`
+	Products    int       `json:"products"`
+}
+
+// ReceptionStats - количество приемок по статусам для дашбордов, включая нулевые
+type ReceptionStats struct {
+	ByStatus map[string]int `json:"byStatus"`
+	Total    int            `json:"total"`
+}
+
+// PurgeReceptionsResponse представляет результат удаления старых закрытых приемок
+type PurgeReceptionsResponse struct {
+	Deleted int `json:"deleted"`
+}
+
 // ReceptionWithProducts представляет приемку вместе со списком товаров
 type ReceptionWithProducts struct {
 	Reception *Reception `json:"reception"`
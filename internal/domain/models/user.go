@@ -32,3 +32,17 @@ type AuthRequest struct {
 type TokenResponse struct {
 	Token string `json:"token"`
 }
+
+// EmployeeAssignRequest представляет запрос на назначение сотрудника на ПВЗ
+type EmployeeAssignRequest struct {
+	UserID uuid.UUID `json:"userId" validate:"required"`
+}
+
+// TokenIntrospection представляет результат проверки токена.
+// Для недействительного токена заполняется только Active=false
+type TokenIntrospection struct {
+	Active bool       `json:"active"`
+	UserID *uuid.UUID `json:"userId,omitempty"`
+	Role   UserRole   `json:"role,omitempty"`
+	Exp    int64      `json:"exp,omitempty"`
+}
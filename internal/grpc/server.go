@@ -2,6 +2,7 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"time"
@@ -11,7 +12,10 @@ import (
 	"pvz-service/internal/logger"
 	pb "pvz-service/proto"
 
+	"github.com/google/uuid"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type Server = grpc.Server
@@ -47,18 +51,45 @@ func (s *PVZServer) ListPVZ(ctx context.Context, req *pb.ListPVZRequest) (*pb.Li
 	}
 
 	for _, pvzWithReceptions := range pvzs {
-		pvz := pvzWithReceptions.PVZ
-		response.Items = append(response.Items, &pb.PVZ{
-			Id:               pvz.ID.String(),
-			RegistrationDate: pvz.RegistrationDate.Format(time.RFC3339),
-			City:             pvz.City,
-		})
+		response.Items = append(response.Items, toProtoPVZ(pvzWithReceptions.PVZ))
 	}
 
 	log.Info("gRPC успешно отправлен список ПВЗ", "count", len(response.Items), "total", total)
 	return response, nil
 }
 
+func (s *PVZServer) GetPVZ(ctx context.Context, req *pb.GetPVZRequest) (*pb.PVZ, error) {
+	log := logger.FromContext(ctx)
+	log.Info("получен gRPC запрос на получение ПВЗ", "pvz_id", req.GetId())
+
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		log.Warn("некорректный формат UUID в gRPC запросе", "pvz_id", req.GetId(), "error", err)
+		return nil, status.Errorf(codes.InvalidArgument, "invalid pvz id %q", req.GetId())
+	}
+
+	pvz, err := s.pvzService.GetPVZByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, models.ErrPVZNotFound) {
+			log.Warn("ПВЗ не найден через gRPC", "pvz_id", id)
+			return nil, status.Error(codes.NotFound, "pvz not found")
+		}
+		log.Error("ошибка получения ПВЗ через gRPC", "pvz_id", id, "error", err)
+		return nil, err
+	}
+
+	log.Info("gRPC успешно отправлен ПВЗ", "pvz_id", pvz.ID)
+	return toProtoPVZ(pvz), nil
+}
+
+func toProtoPVZ(pvz *models.PVZ) *pb.PVZ {
+	return &pb.PVZ{
+		Id:               pvz.ID.String(),
+		RegistrationDate: pvz.RegistrationDate.Format(time.RFC3339),
+		City:             pvz.City,
+	}
+}
+
 func StartGRPCServer(pvzService interfaces.PVZService, port int) *Server {
 	addr := fmt.Sprintf(":%d", port)
 	lis, err := net.Listen("tcp", addr)
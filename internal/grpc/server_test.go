@@ -0,0 +1,153 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"pvz-service/internal/domain/models"
+	pb "pvz-service/proto"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type MockPVZService struct {
+	mock.Mock
+}
+
+func (m *MockPVZService) CreatePVZ(ctx context.Context, city, externalID string) (*models.PVZ, bool, error) {
+	args := m.Called(ctx, city, externalID)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).(*models.PVZ), args.Bool(1), args.Error(2)
+}
+
+func (m *MockPVZService) CreatePVZBatch(ctx context.Context, cities []string) (*models.PVZBatchCreateResponse, error) {
+	args := m.Called(ctx, cities)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PVZBatchCreateResponse), args.Error(1)
+}
+
+func (m *MockPVZService) GetPVZByID(ctx context.Context, id uuid.UUID) (*models.PVZ, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PVZ), args.Error(1)
+}
+
+func (m *MockPVZService) GetPVZsByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.PVZ, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.PVZ), args.Error(1)
+}
+
+func (m *MockPVZService) ListPVZ(ctx context.Context, options models.PVZListOptions) ([]*models.PVZWithReceptionsResponse, int, error) {
+	args := m.Called(ctx, options)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.PVZWithReceptionsResponse), args.Int(1), args.Error(2)
+}
+
+func (m *MockPVZService) CountPVZByCity(ctx context.Context) ([]models.CityPVZCount, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.CityPVZCount), args.Error(1)
+}
+
+func (m *MockPVZService) ExportPVZ(ctx context.Context, fn func(*models.PVZ) error) error {
+	args := m.Called(ctx)
+	if pvzs, ok := args.Get(0).([]*models.PVZ); ok {
+		for _, pvz := range pvzs {
+			if err := fn(pvz); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func TestPVZServer_GetPVZ(t *testing.T) {
+	pvzID := uuid.New()
+	registrationDate := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name         string
+		id           string
+		mockSetup    func(*MockPVZService)
+		expectedCode codes.Code
+		expected     *pb.PVZ
+	}{
+		{
+			name: "Found",
+			id:   pvzID.String(),
+			mockSetup: func(m *MockPVZService) {
+				m.On("GetPVZByID", mock.Anything, pvzID).
+					Return(&models.PVZ{ID: pvzID, RegistrationDate: registrationDate, City: "Москва"}, nil)
+			},
+			expectedCode: codes.OK,
+			expected: &pb.PVZ{
+				Id:               pvzID.String(),
+				RegistrationDate: "2025-03-01T10:00:00Z",
+				City:             "Москва",
+			},
+		},
+		{
+			name: "Not Found",
+			id:   pvzID.String(),
+			mockSetup: func(m *MockPVZService) {
+				m.On("GetPVZByID", mock.Anything, pvzID).Return(nil, models.ErrPVZNotFound)
+			},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name: "Service Error",
+			id:   pvzID.String(),
+			mockSetup: func(m *MockPVZService) {
+				m.On("GetPVZByID", mock.Anything, pvzID).Return(nil, errors.New("db error"))
+			},
+			expectedCode: codes.Unknown,
+		},
+		{
+			name:         "Malformed ID",
+			id:           "not-a-uuid",
+			mockSetup:    func(m *MockPVZService) {},
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockService := new(MockPVZService)
+			tc.mockSetup(mockService)
+			server := NewPVZServer(mockService)
+
+			pvz, err := server.GetPVZ(context.Background(), &pb.GetPVZRequest{Id: tc.id})
+
+			assert.Equal(t, tc.expectedCode, status.Code(err))
+			if tc.expectedCode == codes.OK {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected.GetId(), pvz.GetId())
+				assert.Equal(t, tc.expected.GetRegistrationDate(), pvz.GetRegistrationDate())
+				assert.Equal(t, tc.expected.GetCity(), pvz.GetCity())
+			} else {
+				assert.Nil(t, pvz)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
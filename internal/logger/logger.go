@@ -21,6 +21,13 @@ type Config struct {
 	ServiceName string
 	Version     string
 	Environment string
+
+	// TraceContext добавляет в записи trace_id/span_id из OTel span context
+	TraceContext bool
+
+	// DebugSampleRate оставляет одну из DebugSampleRate записей уровня Debug.
+	// 0 и 1 - без выборки; Info и выше не отбрасываются никогда
+	DebugSampleRate int
 }
 
 func New(cfg Config) *slog.Logger {
@@ -49,11 +56,32 @@ func New(cfg Config) *slog.Logger {
 
 	handler = handler.WithAttrs(attrs)
 
+	if cfg.DebugSampleRate > 1 {
+		handler = newSamplingHandler(handler, cfg.DebugSampleRate)
+	}
+
+	if cfg.TraceContext {
+		handler = newTraceHandler(handler)
+	}
+
 	return slog.New(handler)
 }
 
 type loggerKey struct{}
 
+type requestIDKey struct{}
+
+// WithRequestID сохраняет в контексте ID запроса, выданный LoggingMiddleware
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext возвращает ID запроса или пустую строку, если его нет
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
 func WithLogger(ctx context.Context, l *slog.Logger) context.Context {
 	return context.WithValue(ctx, loggerKey{}, l)
 }
@@ -0,0 +1,20 @@
+package logger
+
+import "log/slog"
+
+// OutcomeKey - ключ атрибута с итогом операции сервиса, по которому строятся дашборды
+const OutcomeKey = "outcome"
+
+const (
+	OutcomeSuccess         = "success"
+	OutcomeValidationError = "validation_error"
+	OutcomeNotFound        = "not_found"
+	OutcomeConflict        = "conflict"
+	OutcomeUnauthorized    = "unauthorized"
+	OutcomeError           = "error"
+)
+
+// Outcome возвращает атрибут outcome для итоговой записи операции
+func Outcome(outcome string) slog.Attr {
+	return slog.String(OutcomeKey, outcome)
+}
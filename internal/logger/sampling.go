@@ -0,0 +1,35 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+)
+
+// samplingHandler пропускает только каждую every-ю запись ниже уровня Info.
+// Info и выше проходят всегда. Счетчик общий для всех производных обработчиков
+// (With, WithGroup), поэтому доля сохраняется для логгера в целом
+type samplingHandler struct {
+	slog.Handler
+	every   uint64
+	counter *atomic.Uint64
+}
+
+func newSamplingHandler(h slog.Handler, every int) slog.Handler {
+	return &samplingHandler{Handler: h, every: uint64(every), counter: new(atomic.Uint64)}
+}
+
+func (h *samplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < LevelInfo && h.counter.Add(1)%h.every != 1 {
+		return nil
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{Handler: h.Handler.WithAttrs(attrs), every: h.every, counter: h.counter}
+}
+
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{Handler: h.Handler.WithGroup(name), every: h.every, counter: h.counter}
+}
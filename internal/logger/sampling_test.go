@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countLevels считает записи JSON-лога по уровням
+func countLevels(t *testing.T, buf *bytes.Buffer) map[string]int {
+	t.Helper()
+
+	counts := make(map[string]int)
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(line, &record))
+		counts[record["level"].(string)]++
+	}
+	return counts
+}
+
+func TestDebugSampling(t *testing.T) {
+	const (
+		rate  = 10
+		total = 1000
+	)
+
+	var buf bytes.Buffer
+	log := New(Config{Level: LevelDebug, Format: "json", Output: &buf, DebugSampleRate: rate})
+	repoLog := log.With("component", "repository")
+
+	for i := 0; i < total; i++ {
+		// Производный логгер делит счетчик с исходным
+		if i%2 == 0 {
+			log.Debug("SQL запрос")
+		} else {
+			repoLog.Debug("SQL запрос")
+		}
+		log.Error("ошибка запроса")
+	}
+
+	counts := countLevels(t, &buf)
+	assert.InDelta(t, total/rate, counts["DEBUG"], 1)
+	assert.Equal(t, total, counts["ERROR"])
+}
+
+func TestDebugSampling_InfoNotSampled(t *testing.T) {
+	var buf bytes.Buffer
+	log := New(Config{Level: LevelDebug, Format: "json", Output: &buf, DebugSampleRate: 5})
+
+	for i := 0; i < 20; i++ {
+		log.Info("запрос обработан")
+		log.Warn("медленный запрос")
+	}
+
+	counts := countLevels(t, &buf)
+	assert.Equal(t, 20, counts["INFO"])
+	assert.Equal(t, 20, counts["WARN"])
+}
+
+func TestDebugSampling_Disabled(t *testing.T) {
+	for _, rate := range []int{0, 1} {
+		var buf bytes.Buffer
+		log := New(Config{Level: LevelDebug, Format: "json", Output: &buf, DebugSampleRate: rate})
+
+		for i := 0; i < 10; i++ {
+			log.Debug("SQL запрос")
+		}
+
+		assert.Equal(t, 10, countLevels(t, &buf)["DEBUG"], "rate %d", rate)
+	}
+}
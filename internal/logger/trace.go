@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// traceHandler добавляет trace_id и span_id из OTel span context в каждую запись.
+// Работает только для вызовов с контекстом (InfoContext и т.п.); без активного спана ничего не меняет.
+// bound - идентификаторы уже привязаны к логгеру через WithTraceContext и повторно не добавляются
+type traceHandler struct {
+	slog.Handler
+	bound bool
+}
+
+func newTraceHandler(h slog.Handler) slog.Handler {
+	return &traceHandler{Handler: h}
+}
+
+func (h *traceHandler) Handle(ctx context.Context, r slog.Record) error {
+	if sc := trace.SpanContextFromContext(ctx); !h.bound && sc.IsValid() {
+		r.AddAttrs(traceAttrs(sc)...)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *traceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &traceHandler{Handler: h.Handler.WithAttrs(attrs), bound: h.bound}
+}
+
+func (h *traceHandler) WithGroup(name string) slog.Handler {
+	return &traceHandler{Handler: h.Handler.WithGroup(name), bound: h.bound}
+}
+
+// WithTraceContext привязывает trace_id и span_id спана из ctx к логгеру, чтобы их получали
+// и записи без контекста (log.Info). Если логгер создан без TraceContext или спана нет,
+// возвращает log без изменений
+func WithTraceContext(ctx context.Context, log *slog.Logger) *slog.Logger {
+	h, ok := log.Handler().(*traceHandler)
+	if !ok {
+		return log
+	}
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return log
+	}
+	return slog.New(&traceHandler{Handler: h.Handler.WithAttrs(traceAttrs(sc)), bound: true})
+}
+
+func traceAttrs(sc trace.SpanContext) []slog.Attr {
+	return []slog.Attr{
+		slog.String("trace_id", sc.TraceID().String()),
+		slog.String("span_id", sc.SpanID().String()),
+	}
+}
@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceContext_AddsSpanIDs(t *testing.T) {
+	var buf bytes.Buffer
+	log := New(Config{Level: LevelInfo, Format: "json", Output: &buf, TraceContext: true})
+
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.NoError(t, err)
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	require.NoError(t, err)
+
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	log.With("request_id", "abc").InfoContext(ctx, "запрос обработан")
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", record["trace_id"])
+	assert.Equal(t, "00f067aa0ba902b7", record["span_id"])
+	assert.Equal(t, "abc", record["request_id"])
+}
+
+func TestTraceContext_NoSpan(t *testing.T) {
+	var buf bytes.Buffer
+	log := New(Config{Level: LevelInfo, Format: "json", Output: &buf, TraceContext: true})
+
+	log.InfoContext(context.Background(), "без трассировки")
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.NotContains(t, record, "trace_id")
+	assert.NotContains(t, record, "span_id")
+}
+
+func TestWithTraceContext_BindsSpanIDs(t *testing.T) {
+	var buf bytes.Buffer
+	log := New(Config{Level: LevelInfo, Format: "json", Output: &buf, TraceContext: true})
+
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.NoError(t, err)
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	require.NoError(t, err)
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+
+	bound := WithTraceContext(ctx, log).With("request_id", "abc")
+	bound.Info("без контекста")
+	bound.InfoContext(ctx, "с контекстом")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	for _, line := range lines {
+		assert.Equal(t, 1, bytes.Count(line, []byte(`"trace_id"`)), "trace_id не должен дублироваться")
+
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(line, &record))
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", record["trace_id"])
+		assert.Equal(t, "00f067aa0ba902b7", record["span_id"])
+	}
+}
+
+func TestWithTraceContext_WithoutTraceHandler(t *testing.T) {
+	log := New(Config{Level: LevelInfo, Format: "json", Output: &bytes.Buffer{}})
+
+	assert.Same(t, log, WithTraceContext(context.Background(), log))
+}
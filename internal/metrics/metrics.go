@@ -28,6 +28,32 @@ var (
 		[]string{"method", "path", "status"},
 	)
 
+	httpInFlightRequests = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "http_in_flight_requests",
+			Help: "Количество HTTP запросов, обрабатываемых в данный момент",
+		},
+	)
+
+	dbQueryDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "db_query_duration_seconds",
+			Help:    "Время выполнения запросов к базе данных в секундах",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"operation"},
+	)
+
+	// В отличие от db_query_duration_seconds измеряет отдельные SQL-вызовы, а не методы репозиториев
+	dbStatementDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "db_statement_duration_seconds",
+			Help:    "Время выполнения отдельных SQL-вызовов по их виду",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"kind"},
+	)
+
 	// Бизнес-метрики
 	pvzCreatedTotal = promauto.NewCounter(
 		prometheus.CounterOpts{
@@ -36,11 +62,13 @@ var (
 		},
 	)
 
-	receptionsCreatedTotal = promauto.NewCounter(
+	// Метка city вместо ID ПВЗ: число городов ограничено, число ПВЗ - нет
+	receptionsCreatedTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "receptions_created_total",
-			Help: "Общее количество созданных приёмок заказов",
+			Help: "Общее количество созданных приёмок заказов по городам ПВЗ",
 		},
+		[]string{"city"},
 	)
 
 	productsAddedTotal = promauto.NewCounter(
@@ -49,8 +77,37 @@ var (
 			Help: "Общее количество добавленных товаров",
 		},
 	)
+
+	authLoginAttemptsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "auth_login_attempts_total",
+			Help: "Количество попыток входа по результату",
+		},
+		[]string{"result"},
+	)
+
+	receptionsAutoClosedTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "receptions_auto_closed_total",
+			Help: "Общее количество приёмок, закрытых автоматически по таймауту",
+		},
+	)
+)
+
+// Результаты попыток входа для auth_login_attempts_total
+const (
+	LoginResultSuccess            = "success"
+	LoginResultInvalidCredentials = "invalid_credentials"
+	LoginResultLocked             = "locked"
 )
 
+func init() {
+	// Серии создаются заранее, чтобы rate() по ним работал до первой попытки каждого вида
+	for _, result := range []string{LoginResultSuccess, LoginResultInvalidCredentials, LoginResultLocked} {
+		authLoginAttemptsTotal.WithLabelValues(result)
+	}
+}
+
 // InitMetrics инициализирует метрики (при необходимости)
 func InitMetrics() {
 
@@ -61,9 +118,9 @@ func IncrementPVZCreated() {
 	pvzCreatedTotal.Inc()
 }
 
-// IncrementReceptionCreated увеличивает счетчик созданных приемок
-func IncrementReceptionCreated() {
-	receptionsCreatedTotal.Inc()
+// IncrementReceptionCreated увеличивает счетчик созданных приемок в городе ПВЗ
+func IncrementReceptionCreated(city string) {
+	receptionsCreatedTotal.WithLabelValues(city).Inc()
 }
 
 // IncrementProductAdded увеличивает счетчик добавленных товаров
@@ -71,6 +128,22 @@ func IncrementProductAdded() {
 	productsAddedTotal.Inc()
 }
 
+// IncrementLoginAttempt увеличивает счетчик попыток входа с указанным результатом
+func IncrementLoginAttempt(result string) {
+	authLoginAttemptsTotal.WithLabelValues(result).Inc()
+}
+
+// IncrementReceptionAutoClosed увеличивает счетчик автоматически закрытых приемок
+func IncrementReceptionAutoClosed() {
+	receptionsAutoClosedTotal.Inc()
+}
+
+// ObserveDBQuery записывает длительность операции с базой данных, начатой в start.
+// Удобно вызывать через defer в начале метода репозитория
+func ObserveDBQuery(operation string, start time.Time) {
+	dbQueryDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
+
 // PrometheusMiddleware измеряет HTTP-запросы
 func PrometheusMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -82,8 +155,9 @@ func PrometheusMiddleware(next http.Handler) http.Handler {
 		duration := time.Since(start).Seconds()
 		statusCode := strconv.Itoa(ww.status)
 
-		httpRequestsTotal.WithLabelValues(r.Method, r.URL.Path, statusCode).Inc()
-		httpRequestDuration.WithLabelValues(r.Method, r.URL.Path, statusCode).Observe(duration)
+		path := routePathLabel(r)
+		httpRequestsTotal.WithLabelValues(r.Method, path, statusCode).Inc()
+		httpRequestDuration.WithLabelValues(r.Method, path, statusCode).Observe(duration)
 	})
 }
 
@@ -101,3 +175,14 @@ func (ww *wrappedResponseWriter) WriteHeader(code int) {
 	ww.status = code
 	ww.ResponseWriter.WriteHeader(code)
 }
+
+// Unwrap дает http.ResponseController доступ к исходному writer (Flush, дедлайны)
+func (ww *wrappedResponseWriter) Unwrap() http.ResponseWriter {
+	return ww.ResponseWriter
+}
+
+// ObserveDBStatement записывает длительность отдельного SQL-вызова вида kind
+// (query, query_row, exec, begin)
+func ObserveDBStatement(kind string, duration time.Duration) {
+	dbStatementDuration.WithLabelValues(kind).Observe(duration.Seconds())
+}
@@ -4,6 +4,8 @@ import (
 	"net/http"
 	"strconv"
 	"time"
+
+	"github.com/gorilla/mux"
 )
 
 // ResponseWriter - обертка для http.ResponseWriter для доступа к коду статуса
@@ -21,6 +23,11 @@ func (rw *ResponseWriter) WriteHeader(statusCode int) {
 	rw.ResponseWriter.WriteHeader(statusCode)
 }
 
+// Unwrap дает http.ResponseController доступ к исходному writer (Flush, дедлайны)
+func (rw *ResponseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // MetricsMiddleware создает middleware для сбора метрик по HTTP-запросам
 func MetricsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -32,7 +39,38 @@ func MetricsMiddleware(next http.Handler) http.Handler {
 		duration := time.Since(start).Seconds()
 		statusCode := strconv.Itoa(ww.statusCode)
 
-		httpRequestsTotal.WithLabelValues(r.Method, r.URL.Path, statusCode).Inc()
-		httpRequestDuration.WithLabelValues(r.Method, r.URL.Path, statusCode).Observe(duration)
+		path := routePathLabel(r)
+		httpRequestsTotal.WithLabelValues(r.Method, path, statusCode).Inc()
+		httpRequestDuration.WithLabelValues(r.Method, path, statusCode).Observe(duration)
 	})
 }
+
+// InFlightMiddleware учитывает запрос в http_in_flight_requests на время его обработки.
+// Уменьшение выполняется через defer, поэтому паника обработчика не оставляет счетчик завышенным
+func InFlightMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httpInFlightRequests.Inc()
+		defer httpInFlightRequests.Dec()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// unknownRoutePath используется как метка для запросов, не совпавших ни с одним маршрутом
+const unknownRoutePath = "unknown"
+
+// routePathLabel возвращает шаблон маршрута mux (например, /pvz/{pvzId}/close_last_reception)
+// вместо сырого пути, чтобы идентификаторы не раздували кардинальность метрик
+func routePathLabel(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return unknownRoutePath
+	}
+
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return unknownRoutePath
+	}
+
+	return template
+}
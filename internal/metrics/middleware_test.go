@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheusMiddleware_UsesRouteTemplate(t *testing.T) {
+	router := mux.NewRouter()
+	router.Use(PrometheusMiddleware)
+	router.HandleFunc("/pvz/{pvzId}/close_last_reception", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("POST")
+
+	const template = "/pvz/{pvzId}/close_last_reception"
+	before := testutil.ToFloat64(httpRequestsTotal.WithLabelValues("POST", template, "200"))
+
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest("POST", "/pvz/"+uuid.New().String()+"/close_last_reception", nil)
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	after := testutil.ToFloat64(httpRequestsTotal.WithLabelValues("POST", template, "200"))
+	assert.Equal(t, float64(3), after-before)
+}
+
+func TestMetricsMiddleware_UnmatchedRoute(t *testing.T) {
+	handler := MetricsMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	before := testutil.ToFloat64(httpRequestsTotal.WithLabelValues("GET", unknownRoutePath, "404"))
+
+	req := httptest.NewRequest("GET", "/no/such/"+uuid.New().String(), nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	after := testutil.ToFloat64(httpRequestsTotal.WithLabelValues("GET", unknownRoutePath, "404"))
+	assert.Equal(t, float64(1), after-before)
+}
+
+func TestInFlightMiddleware_TracksBlockedRequest(t *testing.T) {
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	handler := InFlightMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(entered)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	before := testutil.ToFloat64(httpInFlightRequests)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/pvz", nil))
+	}()
+
+	<-entered
+	assert.Equal(t, before+1, testutil.ToFloat64(httpInFlightRequests))
+
+	close(release)
+	<-done
+	assert.Equal(t, before, testutil.ToFloat64(httpInFlightRequests))
+}
+
+func TestInFlightMiddleware_DecrementsOnPanic(t *testing.T) {
+	handler := InFlightMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	before := testutil.ToFloat64(httpInFlightRequests)
+
+	assert.Panics(t, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/pvz", nil))
+	})
+	assert.Equal(t, before, testutil.ToFloat64(httpInFlightRequests))
+}
@@ -0,0 +1,89 @@
+package notifier
+
+import (
+	"sync"
+
+	"pvz-service/internal/domain/models"
+
+	"github.com/google/uuid"
+)
+
+// eventBufferSize - сколько событий может ждать медленного подписчика, прежде чем новые будут отброшены
+const eventBufferSize = 16
+
+// EventBus - внутрипроцессная рассылка событий ПВЗ подписчикам
+type EventBus struct {
+	mu          sync.RWMutex
+	subscribers map[uuid.UUID]map[chan models.Event]struct{}
+	closed      bool
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{
+		subscribers: make(map[uuid.UUID]map[chan models.Event]struct{}),
+	}
+}
+
+// Subscribe подписывает на события ПВЗ. После вызова возвращенной функции или Close канал закрывается
+func (b *EventBus) Subscribe(pvzID uuid.UUID) (<-chan models.Event, func()) {
+	ch := make(chan models.Event, eventBufferSize)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		close(ch)
+		return ch, func() {}
+	}
+
+	if b.subscribers[pvzID] == nil {
+		b.subscribers[pvzID] = make(map[chan models.Event]struct{})
+	}
+	b.subscribers[pvzID][ch] = struct{}{}
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		// Канал уже закрыт, если подписка отменялась раньше или шина остановлена
+		if _, ok := b.subscribers[pvzID][ch]; !ok {
+			return
+		}
+		delete(b.subscribers[pvzID], ch)
+		if len(b.subscribers[pvzID]) == 0 {
+			delete(b.subscribers, pvzID)
+		}
+		close(ch)
+	}
+
+	return ch, unsubscribe
+}
+
+// Publish рассылает событие подписчикам его ПВЗ. Подписчик с заполненным буфером
+// событие пропускает, чтобы медленный клиент не задерживал сервисы
+func (b *EventBus) Publish(event models.Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers[event.PVZID] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Close закрывает каналы всех подписчиков, чтобы открытые потоки завершились
+// до остановки HTTP сервера. Новые подписки после Close сразу получают закрытый канал
+func (b *EventBus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for pvzID, channels := range b.subscribers {
+		for ch := range channels {
+			close(ch)
+		}
+		delete(b.subscribers, pvzID)
+	}
+}
@@ -0,0 +1,78 @@
+package notifier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/domain/models"
+)
+
+func TestEventBus_PublishToSubscribersOfPVZ(t *testing.T) {
+	bus := NewEventBus()
+	pvzID := uuid.New()
+
+	events, unsubscribe := bus.Subscribe(pvzID)
+	defer unsubscribe()
+	otherEvents, unsubscribeOther := bus.Subscribe(uuid.New())
+	defer unsubscribeOther()
+
+	bus.Publish(models.Event{Type: models.EventReceptionOpened, PVZID: pvzID})
+
+	select {
+	case event := <-events:
+		assert.Equal(t, models.EventReceptionOpened, event.Type)
+	case <-time.After(time.Second):
+		t.Fatal("событие не получено")
+	}
+
+	select {
+	case event := <-otherEvents:
+		t.Fatalf("событие чужого ПВЗ доставлено: %v", event)
+	default:
+	}
+}
+
+func TestEventBus_UnsubscribeClosesChannel(t *testing.T) {
+	bus := NewEventBus()
+	pvzID := uuid.New()
+
+	events, unsubscribe := bus.Subscribe(pvzID)
+	unsubscribe()
+	unsubscribe()
+
+	_, ok := <-events
+	assert.False(t, ok)
+
+	// Публикация без подписчиков не должна блокироваться
+	bus.Publish(models.Event{Type: models.EventProductAdded, PVZID: pvzID})
+}
+
+func TestEventBus_SlowSubscriberDoesNotBlock(t *testing.T) {
+	bus := NewEventBus()
+	pvzID := uuid.New()
+
+	_, unsubscribe := bus.Subscribe(pvzID)
+	defer unsubscribe()
+
+	for i := 0; i < eventBufferSize*2; i++ {
+		bus.Publish(models.Event{Type: models.EventProductAdded, PVZID: pvzID})
+	}
+}
+
+func TestEventBus_CloseEndsSubscriptions(t *testing.T) {
+	bus := NewEventBus()
+
+	events, unsubscribe := bus.Subscribe(uuid.New())
+	bus.Close()
+	unsubscribe()
+
+	_, ok := <-events
+	assert.False(t, ok)
+
+	late, _ := bus.Subscribe(uuid.New())
+	_, ok = <-late
+	assert.False(t, ok)
+}
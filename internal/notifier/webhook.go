@@ -0,0 +1,103 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"pvz-service/internal/domain/models"
+	"pvz-service/internal/logger"
+)
+
+// WebhookNotifier отправляет события приемок POST-запросом на заданный URL
+type WebhookNotifier struct {
+	url        string
+	client     *http.Client
+	maxRetries int
+	retryDelay time.Duration
+}
+
+func NewWebhookNotifier(url string, timeout time.Duration, maxRetries int) *WebhookNotifier {
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+
+	return &WebhookNotifier{
+		url:        url,
+		client:     &http.Client{Timeout: timeout},
+		maxRetries: maxRetries,
+		retryDelay: 500 * time.Millisecond,
+	}
+}
+
+// NotifyReceptionClosed отправляет закрытую приемку с моментом закрытия и числом
+// товаров в формате JSON. При ошибке сети или ответе 5xx запрос повторяется до maxRetries раз
+func (n *WebhookNotifier) NotifyReceptionClosed(ctx context.Context, notification *models.ReceptionClosedNotification) error {
+	log := logger.FromContext(ctx)
+	reception := &notification.Reception
+
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("error marshaling reception: %w", err)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= n.maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(n.retryDelay * time.Duration(attempt)):
+			}
+		}
+
+		retry, err := n.send(ctx, payload)
+		if err == nil {
+			log.Info("вебхук о закрытии приемки отправлен",
+				"reception_id", reception.ID,
+				"attempt", attempt+1,
+			)
+			return nil
+		}
+
+		lastErr = err
+		log.Warn("ошибка отправки вебхука",
+			"reception_id", reception.ID,
+			"attempt", attempt+1,
+			"error", err,
+		)
+
+		if !retry {
+			break
+		}
+	}
+
+	return fmt.Errorf("error sending webhook: %w", lastErr)
+}
+
+// send выполняет одну попытку отправки и сообщает, имеет ли смысл повторять запрос
+func (n *WebhookNotifier) send(ctx context.Context, payload []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(payload))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		return true, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	if resp.StatusCode >= 300 {
+		return false, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return false, nil
+}
@@ -0,0 +1,113 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/domain/models"
+)
+
+func newTestNotification() *models.ReceptionClosedNotification {
+	openedAt := time.Now().UTC().Truncate(time.Second)
+	productCount := 12
+	return &models.ReceptionClosedNotification{
+		Reception: models.Reception{
+			ID:       uuid.New(),
+			DateTime: openedAt,
+			PVZID:    uuid.New(),
+			Status:   models.StatusClosed,
+		},
+		ClosedAt:     openedAt.Add(2 * time.Hour),
+		ProductCount: &productCount,
+	}
+}
+
+func TestWebhookNotifier_SendsPayload(t *testing.T) {
+	notification := newTestNotification()
+
+	received := make(chan map[string]interface{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var payload map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		received <- payload
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	n := NewWebhookNotifier(server.URL, time.Second, 0)
+
+	err := n.NotifyReceptionClosed(context.Background(), notification)
+	require.NoError(t, err)
+
+	payload := <-received
+	assert.Equal(t, notification.ID.String(), payload["id"])
+	assert.Equal(t, notification.PVZID.String(), payload["pvzId"])
+	assert.Equal(t, string(models.StatusClosed), payload["status"])
+	assert.Equal(t, notification.ClosedAt.Format(time.RFC3339), payload["closedAt"])
+	assert.Equal(t, float64(12), payload["productCount"])
+}
+
+func TestWebhookNotifier_RetriesOnServerError(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := NewWebhookNotifier(server.URL, time.Second, 2)
+	n.retryDelay = time.Millisecond
+
+	err := n.NotifyReceptionClosed(context.Background(), newTestNotification())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestWebhookNotifier_NoRetryOnClientError(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	n := NewWebhookNotifier(server.URL, time.Second, 3)
+	n.retryDelay = time.Millisecond
+
+	err := n.NotifyReceptionClosed(context.Background(), newTestNotification())
+
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestWebhookNotifier_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := NewWebhookNotifier(server.URL, 20*time.Millisecond, 1)
+	n.retryDelay = time.Millisecond
+
+	err := n.NotifyReceptionClosed(context.Background(), newTestNotification())
+
+	assert.Error(t, err)
+}
@@ -3,16 +3,36 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"pvz-service/internal/config"
+	"pvz-service/internal/logger"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
-func NewDatabase(cfg *config.DBConfig) (*sql.DB, error) {
-	db, err := sql.Open("postgres", cfg.ConnectionString())
+func NewDatabase(cfg *config.DBConfig) (*DB, error) {
+	return openDatabase(cfg.ConnectionString(), cfg)
+}
+
+// NewReplicaDatabase подключается к реплике из cfg.ReplicaDSN. Если реплика
+// не настроена, возвращает nil без ошибки
+func NewReplicaDatabase(cfg *config.DBConfig) (*DB, error) {
+	if cfg.ReplicaDSN == "" {
+		return nil, nil
+	}
+
+	db, err := openDatabase(cfg.ReplicaDSN, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to replica: %w", err)
+	}
+	return db, nil
+}
+
+func openDatabase(dsn string, cfg *config.DBConfig) (*DB, error) {
+	db, err := sql.Open("postgres", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("error opening database connection: %w", err)
 	}
@@ -22,12 +42,92 @@ func NewDatabase(cfg *config.DBConfig) (*sql.DB, error) {
 	db.SetConnMaxLifetime(5 * time.Minute)
 	db.SetConnMaxIdleTime(2 * time.Minute)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	retryInterval := time.Duration(cfg.ConnectRetryIntervalMs) * time.Millisecond
+	if err := pingWithRetry(context.Background(), db, cfg.ConnectAttempts, retryInterval, time.Sleep); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	slowQueryThreshold := time.Duration(cfg.SlowQueryThresholdMs) * time.Millisecond
+	return NewDB(db).WithSlowQueryThreshold(slowQueryThreshold), nil
+}
+
+// maxConnectRetryInterval ограничивает рост паузы между попытками подключения
+const maxConnectRetryInterval = 30 * time.Second
 
-	if err := db.PingContext(ctx); err != nil {
-		return nil, fmt.Errorf("error pinging database: %w", err)
+type pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// pingWithRetry пингует базу до успеха или исчерпания попыток. Пауза между попытками
+// начинается с interval и удваивается, но не превышает maxConnectRetryInterval
+func pingWithRetry(ctx context.Context, db pinger, attempts int, interval time.Duration, sleep func(time.Duration)) error {
+	if attempts < 1 {
+		attempts = 1
 	}
 
-	return db, nil
+	log := logger.FromContext(ctx)
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		err = db.PingContext(pingCtx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+
+		if attempt == attempts {
+			break
+		}
+
+		log.Warn("база данных недоступна, повторная попытка",
+			"attempt", attempt,
+			"attempts", attempts,
+			"retry_in", interval.String(),
+			"error", err,
+		)
+		sleep(interval)
+
+		interval *= 2
+		if interval > maxConnectRetryInterval {
+			interval = maxConnectRetryInterval
+		}
+	}
+
+	return fmt.Errorf("error pinging database after %d attempts: %w", attempts, err)
+}
+
+// nullStringPtr преобразует sql.NullString в указатель на строку
+func nullStringPtr(ns sql.NullString) *string {
+	if !ns.Valid {
+		return nil
+	}
+	return &ns.String
+}
+
+// nullTimePtr преобразует sql.NullTime в указатель на время
+func nullTimePtr(nt sql.NullTime) *time.Time {
+	if !nt.Valid {
+		return nil
+	}
+	return &nt.Time
+}
+
+// uniqueViolationCode - код ошибки PostgreSQL при нарушении уникального ограничения
+const uniqueViolationCode = "23505"
+
+// isUniqueViolation проверяет, что ошибка вызвана нарушением уникального ограничения
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolationCode
+}
+
+// foreignKeyViolationCode - код ошибки PostgreSQL при нарушении внешнего ключа
+const foreignKeyViolationCode = "23503"
+
+// isForeignKeyViolation проверяет, что ошибка вызвана ссылкой на несуществующую запись
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolationCode
 }
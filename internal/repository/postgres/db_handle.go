@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"pvz-service/internal/logger"
+	"pvz-service/internal/metrics"
+)
+
+// Виды SQL-вызовов для метрики db_statement_duration_seconds
+const (
+	statementQuery    = "query"
+	statementQueryRow = "query_row"
+	statementExec     = "exec"
+	statementBegin    = "begin"
+)
+
+// DB оборачивает *sql.DB, через который работают все репозитории. Замер времени
+// и лог медленных запросов живут здесь, а не в каждом методе репозитория
+type DB struct {
+	db *sql.DB
+
+	// slowQueryThreshold - порог длительности вызова для WARN-лога, 0 — без предупреждений
+	slowQueryThreshold time.Duration
+}
+
+// NewDB оборачивает готовое подключение, в том числе заглушку sqlmock в тестах
+func NewDB(db *sql.DB) *DB {
+	return &DB{db: db}
+}
+
+// WithSlowQueryThreshold включает WARN-лог для вызовов дольше threshold
+func (d *DB) WithSlowQueryThreshold(threshold time.Duration) *DB {
+	d.slowQueryThreshold = threshold
+	return d
+}
+
+func (d *DB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	defer d.observe(ctx, statementQuery, query, time.Now())
+	return d.db.QueryContext(ctx, query, args...)
+}
+
+func (d *DB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	defer d.observe(ctx, statementQueryRow, query, time.Now())
+	return d.db.QueryRowContext(ctx, query, args...)
+}
+
+func (d *DB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	defer d.observe(ctx, statementExec, query, time.Now())
+	return d.db.ExecContext(ctx, query, args...)
+}
+
+func (d *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	defer d.observe(ctx, statementBegin, "", time.Now())
+	tx, err := d.db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{tx: tx, db: d}, nil
+}
+
+// PingContext проверяет доступность базы; используется проверкой готовности
+func (d *DB) PingContext(ctx context.Context) error {
+	return d.db.PingContext(ctx)
+}
+
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// Tx оборачивает *sql.Tx: запросы внутри транзакций попадают в те же метрики
+// и лог медленных запросов, что и вызовы через DB
+type Tx struct {
+	tx *sql.Tx
+	db *DB
+}
+
+func (t *Tx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	defer t.db.observe(ctx, statementQuery, query, time.Now())
+	return t.tx.QueryContext(ctx, query, args...)
+}
+
+func (t *Tx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	defer t.db.observe(ctx, statementQueryRow, query, time.Now())
+	return t.tx.QueryRowContext(ctx, query, args...)
+}
+
+func (t *Tx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	defer t.db.observe(ctx, statementExec, query, time.Now())
+	return t.tx.ExecContext(ctx, query, args...)
+}
+
+func (t *Tx) Commit() error {
+	return t.tx.Commit()
+}
+
+func (t *Tx) Rollback() error {
+	return t.tx.Rollback()
+}
+
+// observe записывает длительность вызова и предупреждает о медленных запросах
+func (d *DB) observe(ctx context.Context, kind, query string, start time.Time) {
+	duration := time.Since(start)
+	metrics.ObserveDBStatement(kind, duration)
+
+	if d.slowQueryThreshold <= 0 || duration < d.slowQueryThreshold {
+		return
+	}
+	logger.FromContext(ctx).Warn("медленный SQL запрос",
+		"kind", kind,
+		"query", query,
+		"duration_ms", duration.Milliseconds(),
+		"threshold_ms", d.slowQueryThreshold.Milliseconds(),
+	)
+}
@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type flakyPinger struct {
+	failures int
+	calls    int
+}
+
+func (p *flakyPinger) PingContext(ctx context.Context) error {
+	p.calls++
+	if p.failures < 0 || p.calls <= p.failures {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func TestPingWithRetry_EventuallyConnects(t *testing.T) {
+	db := &flakyPinger{failures: 2}
+	var sleeps []time.Duration
+
+	err := pingWithRetry(createTestContext(), db, 5, 100*time.Millisecond, func(d time.Duration) {
+		sleeps = append(sleeps, d)
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, db.calls)
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, sleeps)
+}
+
+func TestPingWithRetry_GivesUpAfterCap(t *testing.T) {
+	db := &flakyPinger{failures: -1}
+	var sleeps []time.Duration
+
+	err := pingWithRetry(createTestContext(), db, 3, 20*time.Second, func(d time.Duration) {
+		sleeps = append(sleeps, d)
+	})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "after 3 attempts")
+	assert.Contains(t, err.Error(), "connection refused")
+	assert.Equal(t, 3, db.calls)
+	assert.Equal(t, []time.Duration{20 * time.Second, maxConnectRetryInterval}, sleeps)
+}
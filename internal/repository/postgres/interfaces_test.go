@@ -0,0 +1,19 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/domain/interfaces"
+)
+
+// Основная проверка - объявления var _ в файлах репозиториев: расхождение сигнатур
+// ломает сборку пакета. Тест фиксирует соответствие и при запуске тестов
+func TestRepositoriesImplementInterfaces(t *testing.T) {
+	assert.Implements(t, (*interfaces.UserRepository)(nil), new(UserRepository))
+	assert.Implements(t, (*interfaces.PVZRepository)(nil), new(PVZRepository))
+	assert.Implements(t, (*interfaces.ReceptionRepository)(nil), new(ReceptionRepository))
+	assert.Implements(t, (*interfaces.ProductRepository)(nil), new(ProductRepository))
+	assert.Implements(t, (*interfaces.UserPVZRepository)(nil), new(UserPVZRepository))
+}
@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"context"
+	"math"
+
+	"pvz-service/internal/logger"
+)
+
+const (
+	// defaultListLimit подставляется, если limit не задан или не положителен
+	defaultListLimit = 10
+
+	// maxRepoListLimit ограничивает размер страницы списков. HTTP-обработчики режут limit
+	// сильнее (handlers.maxHTTPListLimit), а этот предел защищает от прочих вызывающих
+	// (gRPC, фоновые задачи)
+	maxRepoListLimit = 100
+)
+
+// normalizePagination приводит page и limit к допустимым значениям и вычисляет offset.
+// Отрицательный offset (в том числе при переполнении (page-1)*limit) заменяется нулем.
+// Каждое исправление логируется
+func normalizePagination(ctx context.Context, page, limit int) (int, int, int) {
+	log := logger.FromContext(ctx)
+
+	if limit <= 0 {
+		log.Debug("установлено значение limit по умолчанию", "limit", defaultListLimit, "requested_limit", limit)
+		limit = defaultListLimit
+	}
+	if limit > maxRepoListLimit {
+		log.Warn("limit ограничен максимальным значением", "requested_limit", limit, "limit", maxRepoListLimit)
+		limit = maxRepoListLimit
+	}
+	if page <= 0 {
+		log.Debug("установлено значение page по умолчанию", "page", 1, "requested_page", page)
+		page = 1
+	}
+
+	if page-1 > math.MaxInt/limit {
+		log.Warn("offset переполняется, используется первая страница", "page", page, "limit", limit)
+		return 1, limit, 0
+	}
+
+	offset := (page - 1) * limit
+	if offset < 0 {
+		log.Warn("отрицательный offset заменен нулем", "page", page, "limit", limit, "offset", offset)
+		offset = 0
+	}
+
+	return page, limit, offset
+}
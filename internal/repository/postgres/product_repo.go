@@ -5,75 +5,302 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
+	"pvz-service/internal/domain/interfaces"
 	"pvz-service/internal/domain/models"
 	"pvz-service/internal/logger"
+	"pvz-service/internal/metrics"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
 )
 
+// productSortClauses сопоставляет допустимые значения сортировки с выражениями ORDER BY
+var productSortClauses = map[string][]string{
+	"":                               {"sequence_num"},
+	models.ProductSortSequence:       {"sequence_num ASC"},
+	"-" + models.ProductSortSequence: {"sequence_num DESC"},
+	models.ProductSortDateTime:       {"date_time ASC", "sequence_num ASC"},
+	"-" + models.ProductSortDateTime: {"date_time DESC", "sequence_num DESC"},
+}
+
 type ProductRepository struct {
-	db *sql.DB
+	db *DB
 	sb squirrel.StatementBuilderType
+
+	// replica - необязательная реплика для тяжелых списочных запросов
+	replica *DB
 }
 
-func NewProductRepository(db *sql.DB) *ProductRepository {
+var _ interfaces.ProductRepository = (*ProductRepository)(nil)
+
+func NewProductRepository(db *DB) *ProductRepository {
 	return &ProductRepository{
 		db: db,
 		sb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
 	}
 }
 
-func (r *ProductRepository) CreateProduct(ctx context.Context, productType models.ProductType, receptionID uuid.UUID, sequenceNum int) (*models.Product, error) {
+// WithReplica направляет списочные запросы на реплику. nil оставляет их на основной базе
+func (r *ProductRepository) WithReplica(replica *DB) *ProductRepository {
+	r.replica = replica
+	return r
+}
+
+// reader возвращает базу для запросов только на чтение: реплику, если она задана
+func (r *ProductRepository) reader() *DB {
+	if r.replica != nil {
+		return r.replica
+	}
+	return r.db
+}
+
+// insertProductQuery строит INSERT товара с порядковым номером, вычисляемым в том же запросе.
+// MAX(sequence_num) не защищен от параллельных вставок, поэтому запрос выполняется
+// только под блокировкой lockOpenReception
+func (r *ProductRepository) insertProductQuery(productType models.ProductType, receptionID uuid.UUID) squirrel.InsertBuilder {
+	nextSequence := r.sb.Select().
+		Column("?", uuid.New()).
+		Column("?", productType).
+		Column("?", receptionID).
+		Column("COALESCE(MAX(sequence_num), 0) + 1").
+		From("products").
+		Where(squirrel.Eq{"reception_id": receptionID})
+
+	return r.sb.Insert("products").
+		Columns("id", "type", "reception_id", "sequence_num").
+		Select(nextSequence).
+		Suffix("RETURNING id, date_time, type, reception_id, sequence_num")
+}
+
+// lockOpenReception блокирует строку приемки до конца транзакции (SELECT ... FOR UPDATE),
+// чтобы изменения товаров одной приемки выполнялись последовательно.
+// Возвращает models.ErrReceptionNotFound или models.ErrReceptionClosed, если приемка недоступна
+func (r *ProductRepository) lockOpenReception(ctx context.Context, tx *Tx, receptionID uuid.UUID) error {
+	sqlQuery, args, err := r.sb.Select("status").
+		From("receptions").
+		Where(squirrel.Eq{"id": receptionID}).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("error building SQL: %w", err)
+	}
+
+	var status models.ReceptionStatus
+	if err := tx.QueryRowContext(ctx, sqlQuery, args...).Scan(&status); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ErrReceptionNotFound
+		}
+		return fmt.Errorf("error locking reception: %w", err)
+	}
+
+	if status != models.StatusInProgress {
+		return models.ErrReceptionClosed
+	}
+	return nil
+}
+
+// AddProductToOpenReception добавляет товар в приемку под блокировкой ее строки.
+// Если limit больше нуля и в приемке уже limit товаров, возвращает models.ErrReceptionFull
+func (r *ProductRepository) AddProductToOpenReception(ctx context.Context, productType models.ProductType, receptionID uuid.UUID, limit int) (*models.Product, error) {
+	defer metrics.ObserveDBQuery("add_product_to_open_reception", time.Now())
+
 	log := logger.FromContext(ctx)
-	log.Debug("создание товара",
+	log.Debug("добавление товара в приемку с блокировкой",
 		"product_type", productType,
 		"reception_id", receptionID,
-		"sequence_num", sequenceNum,
 	)
 
-	id := uuid.New()
+	var product models.Product
+	err := withTx(ctx, r.db, nil, func(tx *Tx) error {
+		if err := r.lockOpenReception(ctx, tx, receptionID); err != nil {
+			log.Warn("приемка недоступна для добавления товара", "error", err, "reception_id", receptionID)
+			return err
+		}
 
-	query := r.sb.Insert("products").
-		Columns("id", "type", "reception_id", "sequence_num").
-		Values(id, productType, receptionID, sequenceNum).
-		Suffix("RETURNING id, date_time, type, reception_id, sequence_num")
+		if limit > 0 {
+			var count int
+			countQuery, countArgs, err := r.sb.Select("COUNT(*)").
+				From("products").
+				Where(squirrel.Eq{"reception_id": receptionID}).
+				ToSql()
+			if err != nil {
+				log.Error("ошибка построения SQL", "error", err)
+				return fmt.Errorf("error building SQL: %w", err)
+			}
+
+			if err := tx.QueryRowContext(ctx, countQuery, countArgs...).Scan(&count); err != nil {
+				log.Error("ошибка подсчета товаров в приемке", "error", err, sqlLogAttr(countQuery, countArgs), "reception_id", receptionID)
+				return fmt.Errorf("error counting products: %w", err)
+			}
+			if count >= limit {
+				log.Warn("приемка заполнена", "reception_id", receptionID, "count", count, "limit", limit)
+				return models.ErrReceptionFull
+			}
+		}
 
-	sqlQuery, args, err := query.ToSql()
+		sqlQuery, args, err := r.insertProductQuery(productType, receptionID).ToSql()
+		if err != nil {
+			log.Error("ошибка построения SQL", "error", err)
+			return fmt.Errorf("error building SQL: %w", err)
+		}
+
+		err = tx.QueryRowContext(ctx, sqlQuery, args...).Scan(
+			&product.ID, &product.DateTime, &product.Type, &product.ReceptionID, &product.SequenceNum,
+		)
+		if err != nil {
+			log.Error("ошибка создания товара в БД", "error", err, sqlLogAttr(sqlQuery, args), "reception_id", receptionID)
+			return fmt.Errorf("error creating product: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		log.Error("ошибка построения SQL", "error", err)
-		return nil, fmt.Errorf("error building SQL: %w", err)
+		return nil, err
+	}
+
+	log.Info("товар успешно добавлен в приемку",
+		"product_id", product.ID,
+		"reception_id", receptionID,
+		"sequence_num", product.SequenceNum,
+	)
+	return &product, nil
+}
+
+// DeleteLastProductFromOpenReception удаляет товар с наибольшим порядковым номером
+// под блокировкой строки приемки. Если товаров нет, возвращает nil без ошибки
+func (r *ProductRepository) DeleteLastProductFromOpenReception(ctx context.Context, receptionID uuid.UUID) (*models.Product, error) {
+	defer metrics.ObserveDBQuery("delete_last_product_from_open_reception", time.Now())
+
+	log := logger.FromContext(ctx)
+	log.Debug("удаление последнего товара приемки с блокировкой", "reception_id", receptionID)
+
+	var product models.Product
+	found := false
+	err := withTx(ctx, r.db, nil, func(tx *Tx) error {
+		if err := r.lockOpenReception(ctx, tx, receptionID); err != nil {
+			log.Warn("приемка недоступна для удаления товара", "error", err, "reception_id", receptionID)
+			return err
+		}
+
+		lastProduct := r.sb.Select("id").
+			From("products").
+			Where(squirrel.Eq{"reception_id": receptionID}).
+			OrderBy("sequence_num DESC").
+			Limit(1)
+
+		sqlQuery, args, err := r.sb.Delete("products").
+			Where(lastProduct.Prefix("id = (").Suffix(")")).
+			Suffix("RETURNING id, date_time, type, reception_id, sequence_num").
+			ToSql()
+		if err != nil {
+			log.Error("ошибка построения SQL", "error", err)
+			return fmt.Errorf("error building SQL: %w", err)
+		}
+
+		err = tx.QueryRowContext(ctx, sqlQuery, args...).Scan(
+			&product.ID, &product.DateTime, &product.Type, &product.ReceptionID, &product.SequenceNum,
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			log.Error("ошибка удаления последнего товара", "error", err, sqlLogAttr(sqlQuery, args), "reception_id", receptionID)
+			return fmt.Errorf("error deleting last product: %w", err)
+		}
+		found = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if log.Enabled(ctx, logger.LevelDebug) {
-		log.Debug("SQL запрос", "query", sqlQuery)
+	if !found {
+		log.Info("товары для приемки не найдены", "reception_id", receptionID)
+		return nil, nil
 	}
 
+	log.Info("последний товар приемки успешно удален", "product_id", product.ID, "reception_id", receptionID)
+	return &product, nil
+}
+
+// MoveProduct переносит товар в приемку targetReceptionID и присваивает ему следующий
+// порядковый номер в ней. Обе приемки блокируются в порядке возрастания ID, чтобы
+// встречные переносы не взаимоблокировались. Возвращает models.ErrProductNotFound,
+// models.ErrReceptionNotFound или models.ErrReceptionClosed
+func (r *ProductRepository) MoveProduct(ctx context.Context, productID, targetReceptionID uuid.UUID) (*models.Product, error) {
+	defer metrics.ObserveDBQuery("move_product", time.Now())
+
+	log := logger.FromContext(ctx)
+	log.Debug("перенос товара в другую приемку", "product_id", productID, "target_reception_id", targetReceptionID)
+
 	var product models.Product
-	err = r.db.QueryRowContext(ctx, sqlQuery, args...).Scan(
-		&product.ID, &product.DateTime, &product.Type, &product.ReceptionID, &product.SequenceNum,
-	)
+	err := withTx(ctx, r.db, nil, func(tx *Tx) error {
+		sqlQuery, args, err := r.sb.Select("reception_id").
+			From("products").
+			Where(squirrel.Eq{"id": productID}).
+			Suffix("FOR UPDATE").
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("error building SQL: %w", err)
+		}
 
+		var sourceReceptionID uuid.UUID
+		if err := tx.QueryRowContext(ctx, sqlQuery, args...).Scan(&sourceReceptionID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return models.ErrProductNotFound
+			}
+			return fmt.Errorf("error getting product: %w", err)
+		}
+
+		lockOrder := []uuid.UUID{sourceReceptionID, targetReceptionID}
+		if targetReceptionID.String() < sourceReceptionID.String() {
+			lockOrder[0], lockOrder[1] = lockOrder[1], lockOrder[0]
+		}
+		for _, receptionID := range lockOrder {
+			if err := r.lockOpenReception(ctx, tx, receptionID); err != nil {
+				log.Warn("приемка недоступна для переноса товара", "error", err, "reception_id", receptionID)
+				return err
+			}
+		}
+
+		sqlQuery, args, err = r.sb.Update("products").
+			Set("reception_id", targetReceptionID).
+			Set("sequence_num", squirrel.Expr(
+				"(SELECT COALESCE(MAX(sequence_num), 0) + 1 FROM products WHERE reception_id = ?)", targetReceptionID,
+			)).
+			Where(squirrel.Eq{"id": productID}).
+			Suffix("RETURNING id, date_time, type, reception_id, sequence_num").
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("error building SQL: %w", err)
+		}
+
+		if err := tx.QueryRowContext(ctx, sqlQuery, args...).Scan(
+			&product.ID, &product.DateTime, &product.Type, &product.ReceptionID, &product.SequenceNum,
+		); err != nil {
+			return fmt.Errorf("error moving product: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		log.Error("ошибка создания товара в БД",
-			"error", err,
-			"product_type", productType,
-			"reception_id", receptionID,
-		)
-		return nil, fmt.Errorf("error creating product: %w", err)
+		if !errors.Is(err, models.ErrProductNotFound) && !errors.Is(err, models.ErrReceptionNotFound) && !errors.Is(err, models.ErrReceptionClosed) {
+			log.Error("ошибка переноса товара", "error", err, "product_id", productID, "target_reception_id", targetReceptionID)
+		}
+		return nil, err
 	}
 
-	log.Info("товар успешно создан",
+	log.Info("товар успешно перенесен",
 		"product_id", product.ID,
-		"product_type", product.Type,
 		"reception_id", product.ReceptionID,
+		"sequence_num", product.SequenceNum,
 	)
-
 	return &product, nil
 }
 
 func (r *ProductRepository) GetProductByID(ctx context.Context, id uuid.UUID) (*models.Product, error) {
+	defer metrics.ObserveDBQuery("get_product_by_id", time.Now())
+
 	log := logger.FromContext(ctx)
 	log.Debug("получение товара по ID", "product_id", id)
 
@@ -97,7 +324,7 @@ func (r *ProductRepository) GetProductByID(ctx context.Context, id uuid.UUID) (*
 			log.Info("товар не найден", "product_id", id)
 			return nil, nil
 		}
-		log.Error("ошибка получения товара", "error", err, "product_id", id)
+		log.Error("ошибка получения товара", "error", err, sqlLogAttr(sqlQuery, args), "product_id", id)
 		return nil, fmt.Errorf("error getting product by id: %w", err)
 	}
 
@@ -111,6 +338,8 @@ func (r *ProductRepository) GetProductByID(ctx context.Context, id uuid.UUID) (*
 }
 
 func (r *ProductRepository) GetLastProductByReceptionID(ctx context.Context, receptionID uuid.UUID) (*models.Product, error) {
+	defer metrics.ObserveDBQuery("get_last_product_by_reception_id", time.Now())
+
 	log := logger.FromContext(ctx)
 	log.Debug("получение последнего товара для приемки", "reception_id", receptionID)
 
@@ -136,7 +365,7 @@ func (r *ProductRepository) GetLastProductByReceptionID(ctx context.Context, rec
 			log.Info("товары для приемки не найдены", "reception_id", receptionID)
 			return nil, nil
 		}
-		log.Error("ошибка получения последнего товара", "error", err, "reception_id", receptionID)
+		log.Error("ошибка получения последнего товара", "error", err, sqlLogAttr(sqlQuery, args), "reception_id", receptionID)
 		return nil, fmt.Errorf("error getting last product: %w", err)
 	}
 
@@ -150,6 +379,8 @@ func (r *ProductRepository) GetLastProductByReceptionID(ctx context.Context, rec
 }
 
 func (r *ProductRepository) DeleteProductByID(ctx context.Context, id uuid.UUID) error {
+	defer metrics.ObserveDBQuery("delete_product_by_id", time.Now())
+
 	log := logger.FromContext(ctx)
 	log.Debug("удаление товара", "product_id", id)
 
@@ -163,7 +394,7 @@ func (r *ProductRepository) DeleteProductByID(ctx context.Context, id uuid.UUID)
 
 	result, err := r.db.ExecContext(ctx, sqlQuery, args...)
 	if err != nil {
-		log.Error("ошибка удаления товара", "error", err, "product_id", id)
+		log.Error("ошибка удаления товара", "error", err, sqlLogAttr(sqlQuery, args), "product_id", id)
 		return fmt.Errorf("error deleting product: %w", err)
 	}
 
@@ -179,7 +410,77 @@ func (r *ProductRepository) DeleteProductByID(ctx context.Context, id uuid.UUID)
 	return nil
 }
 
+// UpdateProductType меняет тип товара. Если товар не найден, возвращает models.ErrProductNotFound
+func (r *ProductRepository) UpdateProductType(ctx context.Context, id uuid.UUID, productType models.ProductType) error {
+	defer metrics.ObserveDBQuery("update_product_type", time.Now())
+
+	log := logger.FromContext(ctx)
+	log.Debug("изменение типа товара", "product_id", id, "product_type", productType)
+
+	query := r.sb.Update("products").
+		Set("type", productType).
+		Where(squirrel.Eq{"id": id})
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		log.Error("ошибка построения SQL", "error", err, "product_id", id)
+		return fmt.Errorf("error building SQL: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		log.Error("ошибка изменения типа товара", "error", err, sqlLogAttr(sqlQuery, args), "product_id", id)
+		return fmt.Errorf("error updating product type: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Error("не удалось получить количество затронутых строк", "error", err, "product_id", id)
+		return fmt.Errorf("error getting rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		log.Warn("товар не найден при изменении типа", "product_id", id)
+		return models.ErrProductNotFound
+	}
+
+	log.Info("тип товара успешно изменен", "product_id", id, "product_type", productType)
+	return nil
+}
+
+// DeleteProductsByReceptionID удаляет все товары приемки одним запросом и возвращает их количество
+func (r *ProductRepository) DeleteProductsByReceptionID(ctx context.Context, receptionID uuid.UUID) (int, error) {
+	defer metrics.ObserveDBQuery("delete_products_by_reception_id", time.Now())
+
+	log := logger.FromContext(ctx)
+	log.Debug("удаление всех товаров приемки", "reception_id", receptionID)
+
+	query := r.sb.Delete("products").Where(squirrel.Eq{"reception_id": receptionID})
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		log.Error("ошибка построения SQL", "error", err, "reception_id", receptionID)
+		return 0, fmt.Errorf("error building SQL: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		log.Error("ошибка удаления товаров приемки", "error", err, sqlLogAttr(sqlQuery, args), "reception_id", receptionID)
+		return 0, fmt.Errorf("error deleting products: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Error("не удалось получить количество удаленных товаров", "error", err, "reception_id", receptionID)
+		return 0, fmt.Errorf("error getting rows affected: %w", err)
+	}
+
+	log.Info("товары приемки удалены", "reception_id", receptionID, "deleted", rowsAffected)
+	return int(rowsAffected), nil
+}
+
 func (r *ProductRepository) CountProductsByReceptionID(ctx context.Context, receptionID uuid.UUID) (int, error) {
+	defer metrics.ObserveDBQuery("count_products_by_reception_id", time.Now())
+
 	log := logger.FromContext(ctx)
 	log.Debug("подсчет товаров для приемки", "reception_id", receptionID)
 
@@ -196,7 +497,7 @@ func (r *ProductRepository) CountProductsByReceptionID(ctx context.Context, rece
 	var count int
 	err = r.db.QueryRowContext(ctx, sqlQuery, args...).Scan(&count)
 	if err != nil {
-		log.Error("ошибка подсчета товаров", "error", err, "reception_id", receptionID)
+		log.Error("ошибка подсчета товаров", "error", err, sqlLogAttr(sqlQuery, args), "reception_id", receptionID)
 		return 0, fmt.Errorf("error counting products: %w", err)
 	}
 
@@ -204,29 +505,184 @@ func (r *ProductRepository) CountProductsByReceptionID(ctx context.Context, rece
 	return count, nil
 }
 
-func (r *ProductRepository) GetProductsByReceptionID(ctx context.Context, receptionID uuid.UUID, page, limit int) ([]*models.Product, int, error) {
+const (
+	defaultRecentProducts = 10
+	maxRecentProducts     = 50
+)
+
+// GetRecentProducts возвращает последние n товаров приемки по убыванию sequence_num.
+// n приводится к диапазону [1, 50], по умолчанию 10
+func (r *ProductRepository) GetRecentProducts(ctx context.Context, receptionID uuid.UUID, n int) ([]*models.Product, error) {
+	defer metrics.ObserveDBQuery("get_recent_products", time.Now())
+
+	log := logger.FromContext(ctx)
+	log.Debug("получение последних товаров приемки", "reception_id", receptionID, "n", n)
+
+	if n <= 0 {
+		n = defaultRecentProducts
+	}
+	if n > maxRecentProducts {
+		n = maxRecentProducts
+	}
+
+	query := r.sb.Select("id", "date_time", "type", "reception_id", "sequence_num").
+		From("products").
+		Where(squirrel.Eq{"reception_id": receptionID}).
+		OrderBy("sequence_num DESC").
+		Limit(uint64(n))
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		log.Error("ошибка построения SQL", "error", err, "reception_id", receptionID)
+		return nil, fmt.Errorf("error building SQL: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		log.Error("ошибка выполнения запроса товаров", "error", err, sqlLogAttr(sqlQuery, args), "reception_id", receptionID)
+		return nil, fmt.Errorf("error querying recent products: %w", err)
+	}
+	defer rows.Close()
+
+	products := make([]*models.Product, 0, n)
+	for rows.Next() {
+		var product models.Product
+		if err := rows.Scan(&product.ID, &product.DateTime, &product.Type, &product.ReceptionID, &product.SequenceNum); err != nil {
+			log.Error("ошибка сканирования строки товара", "error", err)
+			return nil, fmt.Errorf("error scanning product row: %w", err)
+		}
+		products = append(products, &product)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Error("ошибка при итерации по товарам", "error", err, "reception_id", receptionID)
+		return nil, fmt.Errorf("error iterating products: %w", err)
+	}
+
+	log.Debug("последние товары приемки получены", "reception_id", receptionID, "count", len(products))
+	return products, nil
+}
+
+// CountProductsByTypeByReceptionID считает товары приемки по типам одним сгруппированным запросом.
+// Типы без товаров в результат не попадают
+func (r *ProductRepository) CountProductsByTypeByReceptionID(ctx context.Context, receptionID uuid.UUID) (map[models.ProductType]int, error) {
+	defer metrics.ObserveDBQuery("count_products_by_type_by_reception_id", time.Now())
+
+	log := logger.FromContext(ctx)
+	log.Debug("подсчет товаров по типам", "reception_id", receptionID)
+
+	query := r.sb.Select("type", "COUNT(*)").
+		From("products").
+		Where(squirrel.Eq{"reception_id": receptionID}).
+		GroupBy("type")
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		log.Error("ошибка построения SQL", "error", err, "reception_id", receptionID)
+		return nil, fmt.Errorf("error building SQL: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		log.Error("ошибка подсчета товаров по типам", "error", err, sqlLogAttr(sqlQuery, args), "reception_id", receptionID)
+		return nil, fmt.Errorf("error counting products by type: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[models.ProductType]int)
+	for rows.Next() {
+		var productType models.ProductType
+		var count int
+		if err := rows.Scan(&productType, &count); err != nil {
+			log.Error("ошибка сканирования результата", "error", err, "reception_id", receptionID)
+			return nil, fmt.Errorf("error scanning product counts: %w", err)
+		}
+		counts[productType] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Error("ошибка при итерации по результатам", "error", err, "reception_id", receptionID)
+		return nil, fmt.Errorf("error iterating product counts: %w", err)
+	}
+
+	log.Debug("подсчет товаров по типам завершен", "reception_id", receptionID, "types", len(counts))
+	return counts, nil
+}
+
+// GetSequenceGaps возвращает по возрастанию номера от 1 до наибольшего sequence_num приемки,
+// которых нет среди ее товаров. Ряд номеров строит generate_series, из него вычитаются
+// существующие. Хвостовых пропусков не бывает: после удаления последнего товара номер
+// переиспользуется следующим добавленным
+func (r *ProductRepository) GetSequenceGaps(ctx context.Context, receptionID uuid.UUID) ([]int, error) {
+	defer metrics.ObserveDBQuery("get_sequence_gaps", time.Now())
+
+	log := logger.FromContext(ctx)
+	log.Debug("поиск пропусков в нумерации товаров", "reception_id", receptionID)
+
+	series := r.sb.Select("generate_series(1, COALESCE(MAX(sequence_num), 0)) AS n").
+		From("products").
+		Where(squirrel.Eq{"reception_id": receptionID})
+
+	query := r.sb.Select("s.n").
+		FromSelect(series, "s").
+		Where("NOT EXISTS (SELECT 1 FROM products p WHERE p.reception_id = ? AND p.sequence_num = s.n)", receptionID).
+		OrderBy("s.n")
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		log.Error("ошибка построения SQL", "error", err, "reception_id", receptionID)
+		return nil, fmt.Errorf("error building SQL: %w", err)
+	}
+
+	rows, err := r.reader().QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		log.Error("ошибка поиска пропусков в нумерации товаров", "error", err, sqlLogAttr(sqlQuery, args), "reception_id", receptionID)
+		return nil, fmt.Errorf("error getting sequence gaps: %w", err)
+	}
+	defer rows.Close()
+
+	missing := []int{}
+	for rows.Next() {
+		var n int
+		if err := rows.Scan(&n); err != nil {
+			log.Error("ошибка сканирования результата", "error", err, "reception_id", receptionID)
+			return nil, fmt.Errorf("error scanning sequence gap: %w", err)
+		}
+		missing = append(missing, n)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Error("ошибка при итерации по результатам", "error", err, "reception_id", receptionID)
+		return nil, fmt.Errorf("error iterating sequence gaps: %w", err)
+	}
+
+	log.Debug("пропуски в нумерации товаров найдены", "reception_id", receptionID, "missing", len(missing))
+	return missing, nil
+}
+
+func (r *ProductRepository) GetProductsByReceptionID(ctx context.Context, receptionID uuid.UUID, page, limit int, sort string) ([]*models.Product, int, error) {
+	defer metrics.ObserveDBQuery("get_products_by_reception_id", time.Now())
+
 	log := logger.FromContext(ctx)
 	log.Debug("получение списка товаров для приемки",
 		"reception_id", receptionID,
 		"page", page,
 		"limit", limit,
+		"sort", sort,
 	)
 
-	if limit <= 0 {
-		limit = 10
-		log.Debug("установлено значение limit по умолчанию", "limit", limit)
-	}
-	if page <= 0 {
-		page = 1
-		log.Debug("установлено значение page по умолчанию", "page", page)
+	orderBy, ok := productSortClauses[sort]
+	if !ok {
+		log.Warn("недопустимое значение сортировки товаров", "sort", sort)
+		return nil, 0, fmt.Errorf("unsupported sort value: %q", sort)
 	}
 
-	offset := (page - 1) * limit
+	page, limit, offset := normalizePagination(ctx, page, limit)
 
 	query := r.sb.Select("id", "date_time", "type", "reception_id", "sequence_num").
 		From("products").
 		Where(squirrel.Eq{"reception_id": receptionID}).
-		OrderBy("sequence_num").
+		OrderBy(orderBy...).
 		Limit(uint64(limit)).
 		Offset(uint64(offset))
 
@@ -236,9 +692,9 @@ func (r *ProductRepository) GetProductsByReceptionID(ctx context.Context, recept
 		return nil, 0, fmt.Errorf("error building SQL: %w", err)
 	}
 
-	rows, err := r.db.QueryContext(ctx, sqlQuery, args...)
+	rows, err := r.reader().QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
-		log.Error("ошибка выполнения запроса товаров", "error", err, "reception_id", receptionID)
+		log.Error("ошибка выполнения запроса товаров", "error", err, sqlLogAttr(sqlQuery, args), "reception_id", receptionID)
 		return nil, 0, fmt.Errorf("error querying products: %w", err)
 	}
 	defer rows.Close()
@@ -264,9 +720,9 @@ func (r *ProductRepository) GetProductsByReceptionID(ctx context.Context, recept
 	}
 
 	var total int
-	err = r.db.QueryRowContext(ctx, countSql, countArgs...).Scan(&total)
+	err = r.reader().QueryRowContext(ctx, countSql, countArgs...).Scan(&total)
 	if err != nil {
-		log.Error("ошибка подсчета товаров", "error", err, "reception_id", receptionID)
+		log.Error("ошибка подсчета товаров", "error", err, sqlLogAttr(countSql, countArgs), "reception_id", receptionID)
 		return nil, 0, fmt.Errorf("error counting products: %w", err)
 	}
 
@@ -278,3 +734,147 @@ func (r *ProductRepository) GetProductsByReceptionID(ctx context.Context, recept
 
 	return products, total, nil
 }
+
+// ListProductsByPVZID возвращает страницу товаров всех приемок ПВЗ, от новых к старым,
+// и их общее число. Пустой productType - товары всех типов
+func (r *ProductRepository) ListProductsByPVZID(ctx context.Context, pvzID uuid.UUID, productType models.ProductType, page, limit int) ([]*models.Product, int, error) {
+	defer metrics.ObserveDBQuery("list_products_by_pvz_id", time.Now())
+
+	log := logger.FromContext(ctx)
+	log.Debug("получение товаров всех приемок ПВЗ",
+		"pvz_id", pvzID,
+		"type", productType,
+		"page", page,
+		"limit", limit,
+	)
+
+	page, limit, offset := normalizePagination(ctx, page, limit)
+
+	where := squirrel.And{squirrel.Eq{"r.pvz_id": pvzID}}
+	if productType != "" {
+		where = append(where, squirrel.Eq{"p.type": productType})
+	}
+
+	query := r.sb.Select("p.id", "p.date_time", "p.type", "p.reception_id", "p.sequence_num").
+		From("products p").
+		Join("receptions r ON r.id = p.reception_id").
+		Where(where).
+		OrderBy("p.date_time DESC", "p.id").
+		Limit(uint64(limit)).
+		Offset(uint64(offset))
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		log.Error("ошибка построения SQL", "error", err, "pvz_id", pvzID)
+		return nil, 0, fmt.Errorf("error building SQL: %w", err)
+	}
+
+	rows, err := r.reader().QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		log.Error("ошибка выполнения запроса товаров ПВЗ", "error", err, sqlLogAttr(sqlQuery, args), "pvz_id", pvzID)
+		return nil, 0, fmt.Errorf("error querying PVZ products: %w", err)
+	}
+	defer rows.Close()
+
+	var products []*models.Product
+	for rows.Next() {
+		var product models.Product
+		if err := rows.Scan(&product.ID, &product.DateTime, &product.Type, &product.ReceptionID, &product.SequenceNum); err != nil {
+			log.Error("ошибка сканирования строки товара", "error", err)
+			return nil, 0, fmt.Errorf("error scanning product row: %w", err)
+		}
+		products = append(products, &product)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Error("ошибка при итерации по товарам ПВЗ", "error", err, "pvz_id", pvzID)
+		return nil, 0, fmt.Errorf("error iterating PVZ products: %w", err)
+	}
+
+	countQuery := r.sb.Select("COUNT(*)").
+		From("products p").
+		Join("receptions r ON r.id = p.reception_id").
+		Where(where)
+
+	countSql, countArgs, err := countQuery.ToSql()
+	if err != nil {
+		log.Error("ошибка построения SQL для подсчета", "error", err, "pvz_id", pvzID)
+		return nil, 0, fmt.Errorf("error building count SQL: %w", err)
+	}
+
+	var total int
+	if err := r.reader().QueryRowContext(ctx, countSql, countArgs...).Scan(&total); err != nil {
+		log.Error("ошибка подсчета товаров ПВЗ", "error", err, sqlLogAttr(countSql, countArgs), "pvz_id", pvzID)
+		return nil, 0, fmt.Errorf("error counting PVZ products: %w", err)
+	}
+
+	log.Debug("товары всех приемок ПВЗ получены", "pvz_id", pvzID, "count", len(products), "total", total)
+	return products, total, nil
+}
+
+// ResequenceProducts перенумеровывает товары открытой приемки подряд от 1, сохраняя их
+// текущий порядок, и возвращает число товаров. Уникальный индекс по (reception_id, sequence_num)
+// проверяется построчно, поэтому номера сначала делаются отрицательными и только затем
+// заменяются на новые. Возвращает models.ErrReceptionNotFound или models.ErrReceptionClosed
+func (r *ProductRepository) ResequenceProducts(ctx context.Context, receptionID uuid.UUID) (int, error) {
+	defer metrics.ObserveDBQuery("resequence_products", time.Now())
+
+	log := logger.FromContext(ctx)
+	log.Debug("перенумерация товаров приемки", "reception_id", receptionID)
+
+	var count int64
+	err := withTx(ctx, r.db, nil, func(tx *Tx) error {
+		if err := r.lockOpenReception(ctx, tx, receptionID); err != nil {
+			log.Warn("приемка недоступна для перенумерации товаров", "error", err, "reception_id", receptionID)
+			return err
+		}
+
+		sqlQuery, args, err := r.sb.Update("products").
+			Set("sequence_num", squirrel.Expr("-sequence_num")).
+			Where(squirrel.Eq{"reception_id": receptionID}).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("error building SQL: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, sqlQuery, args...); err != nil {
+			log.Error("ошибка сдвига номеров товаров", "error", err, sqlLogAttr(sqlQuery, args), "reception_id", receptionID)
+			return fmt.Errorf("error shifting sequence numbers: %w", err)
+		}
+
+		// После сдвига исходный порядок по возрастанию соответствует убыванию отрицательных номеров
+		ordered := r.sb.Select("id", "ROW_NUMBER() OVER (ORDER BY sequence_num DESC) AS rn").
+			From("products").
+			Where(squirrel.Eq{"reception_id": receptionID})
+
+		sqlQuery, args, err = r.sb.Update("products").
+			Set("sequence_num", squirrel.Expr("o.rn")).
+			FromSelect(ordered, "o").
+			Where("products.id = o.id").
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("error building SQL: %w", err)
+		}
+
+		result, err := tx.ExecContext(ctx, sqlQuery, args...)
+		if err != nil {
+			log.Error("ошибка перенумерации товаров", "error", err, sqlLogAttr(sqlQuery, args), "reception_id", receptionID)
+			return fmt.Errorf("error resequencing products: %w", err)
+		}
+
+		count, err = result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("error getting affected rows: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if !errors.Is(err, models.ErrReceptionNotFound) && !errors.Is(err, models.ErrReceptionClosed) {
+			log.Error("ошибка перенумерации товаров приемки", "error", err, "reception_id", receptionID)
+		}
+		return 0, err
+	}
+
+	log.Info("товары приемки перенумерованы", "reception_id", receptionID, "products", count)
+	return int(count), nil
+}
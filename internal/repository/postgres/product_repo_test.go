@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"regexp"
 	"testing"
 	"time"
 
@@ -22,7 +24,7 @@ func setupProductRepoTest(t *testing.T) (*ProductRepository, sqlmock.Sqlmock, fu
 	require.NoError(t, err)
 
 	repo := &ProductRepository{
-		db: db,
+		db: NewDB(db),
 		sb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
 	}
 
@@ -43,55 +45,6 @@ func createTestContext() context.Context {
 	return logger.WithLogger(ctx, testLog)
 }
 
-func TestCreateProduct(t *testing.T) {
-	repo, mock, cleanup := setupProductRepoTest(t)
-	defer cleanup()
-
-	ctx := createTestContext()
-	productID := uuid.New()
-	now := time.Now()
-	productType := models.TypeElectronics
-	receptionID := uuid.New()
-	sequenceNum := 1
-
-	mock.ExpectQuery("INSERT INTO products").
-		WithArgs(sqlmock.AnyArg(), productType, receptionID, sequenceNum).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id", "sequence_num"}).
-			AddRow(productID, now, productType, receptionID, sequenceNum))
-
-	product, err := repo.CreateProduct(ctx, productType, receptionID, sequenceNum)
-
-	assert.NoError(t, err)
-	assert.NotNil(t, product)
-	assert.Equal(t, productType, product.Type)
-	assert.Equal(t, receptionID, product.ReceptionID)
-	assert.Equal(t, sequenceNum, product.SequenceNum)
-
-	assert.NoError(t, mock.ExpectationsWereMet())
-}
-
-func TestCreateProduct_Error(t *testing.T) {
-	repo, mock, cleanup := setupProductRepoTest(t)
-	defer cleanup()
-
-	ctx := createTestContext()
-	productType := models.TypeElectronics
-	receptionID := uuid.New()
-	sequenceNum := 1
-
-	mock.ExpectQuery("INSERT INTO products").
-		WithArgs(sqlmock.AnyArg(), productType, receptionID, sequenceNum).
-		WillReturnError(errors.New("database error"))
-
-	product, err := repo.CreateProduct(ctx, productType, receptionID, sequenceNum)
-
-	assert.Error(t, err)
-	assert.Nil(t, product)
-	assert.Contains(t, err.Error(), "error creating product")
-
-	assert.NoError(t, mock.ExpectationsWereMet())
-}
-
 func TestGetProductByID(t *testing.T) {
 	repo, mock, cleanup := setupProductRepoTest(t)
 	defer cleanup()
@@ -222,6 +175,113 @@ func TestDeleteProductByID_Error(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestUpdateProductType(t *testing.T) {
+	repo, mock, cleanup := setupProductRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	productID := uuid.New()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE products SET type = $1 WHERE id = $2")).
+		WithArgs(models.TypeFootwear, productID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.UpdateProductType(ctx, productID, models.TypeFootwear)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateProductType_NotFound(t *testing.T) {
+	repo, mock, cleanup := setupProductRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	productID := uuid.New()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE products SET type = $1 WHERE id = $2")).
+		WithArgs(models.TypeClothes, productID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.UpdateProductType(ctx, productID, models.TypeClothes)
+
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateProductType_Error(t *testing.T) {
+	repo, mock, cleanup := setupProductRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	productID := uuid.New()
+
+	mock.ExpectExec("UPDATE products").
+		WithArgs(models.TypeClothes, productID).
+		WillReturnError(errors.New("database error"))
+
+	err := repo.UpdateProductType(ctx, productID, models.TypeClothes)
+
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, models.ErrProductNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteProductsByReceptionID(t *testing.T) {
+	repo, mock, cleanup := setupProductRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	receptionID := uuid.New()
+
+	mock.ExpectExec("DELETE FROM products WHERE reception_id").
+		WithArgs(receptionID).
+		WillReturnResult(sqlmock.NewResult(0, 7))
+
+	deleted, err := repo.DeleteProductsByReceptionID(ctx, receptionID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteProductsByReceptionID_Empty(t *testing.T) {
+	repo, mock, cleanup := setupProductRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	receptionID := uuid.New()
+
+	mock.ExpectExec("DELETE FROM products WHERE reception_id").
+		WithArgs(receptionID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	deleted, err := repo.DeleteProductsByReceptionID(ctx, receptionID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteProductsByReceptionID_Error(t *testing.T) {
+	repo, mock, cleanup := setupProductRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	receptionID := uuid.New()
+
+	mock.ExpectExec("DELETE FROM products WHERE reception_id").
+		WithArgs(receptionID).
+		WillReturnError(errors.New("database error"))
+
+	deleted, err := repo.DeleteProductsByReceptionID(ctx, receptionID)
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, deleted)
+	assert.Contains(t, err.Error(), "error deleting products")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestCountProductsByReceptionID(t *testing.T) {
 	repo, mock, cleanup := setupProductRepoTest(t)
 	defer cleanup()
@@ -263,6 +323,109 @@ func TestCountProductsByReceptionID_Error(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestGetRecentProducts(t *testing.T) {
+	testCases := []struct {
+		name          string
+		n             int
+		expectedLimit int
+	}{
+		{name: "Requested N", n: 3, expectedLimit: 3},
+		{name: "Default N", n: 0, expectedLimit: 10},
+		{name: "Clamped N", n: 1000, expectedLimit: 50},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock, cleanup := setupProductRepoTest(t)
+			defer cleanup()
+
+			ctx := createTestContext()
+			receptionID := uuid.New()
+			now := time.Now()
+
+			mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(
+				"SELECT id, date_time, type, reception_id, sequence_num FROM products WHERE reception_id = $1 ORDER BY sequence_num DESC LIMIT %d", tc.expectedLimit))).
+				WithArgs(receptionID).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id", "sequence_num"}).
+					AddRow(uuid.New(), now, models.TypeFootwear, receptionID, 2).
+					AddRow(uuid.New(), now, models.TypeClothes, receptionID, 1))
+
+			products, err := repo.GetRecentProducts(ctx, receptionID, tc.n)
+
+			assert.NoError(t, err)
+			require.Len(t, products, 2)
+			assert.Equal(t, 2, products[0].SequenceNum)
+			assert.Equal(t, 1, products[1].SequenceNum)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetRecentProducts_Empty(t *testing.T) {
+	repo, mock, cleanup := setupProductRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	receptionID := uuid.New()
+
+	mock.ExpectQuery("SELECT (.+) FROM products").
+		WithArgs(receptionID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id", "sequence_num"}))
+
+	products, err := repo.GetRecentProducts(ctx, receptionID, 5)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, products)
+	assert.Empty(t, products)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCountProductsByTypeByReceptionID(t *testing.T) {
+	repo, mock, cleanup := setupProductRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	receptionID := uuid.New()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT type, COUNT(*) FROM products WHERE reception_id = $1 GROUP BY type")).
+		WithArgs(receptionID).
+		WillReturnRows(sqlmock.NewRows([]string{"type", "count"}).
+			AddRow(models.TypeElectronics, 3).
+			AddRow(models.TypeFootwear, 1))
+
+	counts, err := repo.CountProductsByTypeByReceptionID(ctx, receptionID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[models.ProductType]int{
+		models.TypeElectronics: 3,
+		models.TypeFootwear:    1,
+	}, counts)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCountProductsByTypeByReceptionID_Error(t *testing.T) {
+	repo, mock, cleanup := setupProductRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	receptionID := uuid.New()
+
+	mock.ExpectQuery("SELECT type, COUNT").
+		WithArgs(receptionID).
+		WillReturnError(errors.New("database error"))
+
+	counts, err := repo.CountProductsByTypeByReceptionID(ctx, receptionID)
+
+	assert.Error(t, err)
+	assert.Nil(t, counts)
+	assert.Contains(t, err.Error(), "error counting products by type")
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetProductsByReceptionID(t *testing.T) {
 	repo, mock, cleanup := setupProductRepoTest(t)
 	defer cleanup()
@@ -289,7 +452,7 @@ func TestGetProductsByReceptionID(t *testing.T) {
 		WillReturnRows(sqlmock.NewRows([]string{"count"}).
 			AddRow(total))
 
-	products, totalCount, err := repo.GetProductsByReceptionID(ctx, receptionID, page, limit)
+	products, totalCount, err := repo.GetProductsByReceptionID(ctx, receptionID, page, limit, "")
 
 	assert.NoError(t, err)
 	assert.Equal(t, 2, len(products))
@@ -326,7 +489,7 @@ func TestGetProductsByReceptionID_NegativePageAndLimit(t *testing.T) {
 		WillReturnRows(sqlmock.NewRows([]string{"count"}).
 			AddRow(1))
 
-	products, totalCount, err := repo.GetProductsByReceptionID(ctx, receptionID, page, limit)
+	products, totalCount, err := repo.GetProductsByReceptionID(ctx, receptionID, page, limit, "")
 
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(products))
@@ -348,7 +511,7 @@ func TestGetProductsByReceptionID_QueryError(t *testing.T) {
 		WithArgs(receptionID).
 		WillReturnError(errors.New("database error"))
 
-	products, totalCount, err := repo.GetProductsByReceptionID(ctx, receptionID, page, limit)
+	products, totalCount, err := repo.GetProductsByReceptionID(ctx, receptionID, page, limit, "")
 
 	assert.Error(t, err)
 	assert.Nil(t, products)
@@ -372,7 +535,7 @@ func TestGetProductsByReceptionID_ScanError(t *testing.T) {
 		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time"}).
 			AddRow(uuid.New(), time.Now()))
 
-	products, totalCount, err := repo.GetProductsByReceptionID(ctx, receptionID, page, limit)
+	products, totalCount, err := repo.GetProductsByReceptionID(ctx, receptionID, page, limit, "")
 
 	assert.Error(t, err)
 	assert.Nil(t, products)
@@ -404,7 +567,7 @@ func TestGetProductsByReceptionID_CountError(t *testing.T) {
 		WithArgs(receptionID).
 		WillReturnError(errors.New("count error"))
 
-	products, totalCount, err := repo.GetProductsByReceptionID(ctx, receptionID, page, limit)
+	products, totalCount, err := repo.GetProductsByReceptionID(ctx, receptionID, page, limit, "")
 
 	assert.Error(t, err)
 	assert.Nil(t, products)
@@ -413,3 +576,488 @@ func TestGetProductsByReceptionID_CountError(t *testing.T) {
 
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestGetProductsByReceptionID_Sort(t *testing.T) {
+	testCases := []struct {
+		sort            string
+		expectedOrderBy string
+	}{
+		{sort: "", expectedOrderBy: "ORDER BY sequence_num LIMIT"},
+		{sort: "sequence", expectedOrderBy: "ORDER BY sequence_num ASC LIMIT"},
+		{sort: "-sequence", expectedOrderBy: "ORDER BY sequence_num DESC LIMIT"},
+		{sort: "dateTime", expectedOrderBy: "ORDER BY date_time ASC, sequence_num ASC LIMIT"},
+		{sort: "-dateTime", expectedOrderBy: "ORDER BY date_time DESC, sequence_num DESC LIMIT"},
+	}
+
+	for _, tc := range testCases {
+		t.Run("sort="+tc.sort, func(t *testing.T) {
+			repo, mock, cleanup := setupProductRepoTest(t)
+			defer cleanup()
+
+			ctx := createTestContext()
+			receptionID := uuid.New()
+
+			mock.ExpectQuery("SELECT (.+) FROM products WHERE reception_id = \\$1 " + regexp.QuoteMeta(tc.expectedOrderBy)).
+				WithArgs(receptionID).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id", "sequence_num"}))
+
+			mock.ExpectQuery("SELECT COUNT").
+				WithArgs(receptionID).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+			_, _, err := repo.GetProductsByReceptionID(ctx, receptionID, 1, 10, tc.sort)
+
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetProductsByReceptionID_UnknownSort(t *testing.T) {
+	repo, mock, cleanup := setupProductRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+
+	products, total, err := repo.GetProductsByReceptionID(ctx, uuid.New(), 1, 10, "type; DROP TABLE products")
+
+	assert.Error(t, err)
+	assert.Nil(t, products)
+	assert.Equal(t, 0, total)
+	assert.Contains(t, err.Error(), "unsupported sort value")
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddProductToOpenReception_LocksReception(t *testing.T) {
+	repo, mock, cleanup := setupProductRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	receptionID := uuid.New()
+	productID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT status FROM receptions WHERE id = $1 FOR UPDATE")).
+		WithArgs(receptionID).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(models.StatusInProgress))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM products WHERE reception_id = $1")).
+		WithArgs(receptionID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO products (id,type,reception_id,sequence_num) "+
+		"SELECT $1, $2, $3, COALESCE(MAX(sequence_num), 0) + 1 FROM products WHERE reception_id = $4")).
+		WithArgs(sqlmock.AnyArg(), models.TypeClothes, receptionID, receptionID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id", "sequence_num"}).
+			AddRow(productID, time.Now(), models.TypeClothes, receptionID, 3))
+	mock.ExpectCommit()
+
+	product, err := repo.AddProductToOpenReception(ctx, models.TypeClothes, receptionID, 5)
+
+	require.NoError(t, err)
+	assert.Equal(t, productID, product.ID)
+	assert.Equal(t, 3, product.SequenceNum)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddProductToOpenReception_Rejected(t *testing.T) {
+	testCases := []struct {
+		name          string
+		status        models.ReceptionStatus
+		count         int
+		expectedError error
+	}{
+		{name: "Closed", status: models.StatusClosed, expectedError: models.ErrReceptionClosed},
+		{name: "Full", status: models.StatusInProgress, count: 5, expectedError: models.ErrReceptionFull},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock, cleanup := setupProductRepoTest(t)
+			defer cleanup()
+
+			receptionID := uuid.New()
+
+			mock.ExpectBegin()
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT status FROM receptions WHERE id = $1 FOR UPDATE")).
+				WithArgs(receptionID).
+				WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(tc.status))
+			if tc.status == models.StatusInProgress {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM products WHERE reception_id = $1")).
+					WithArgs(receptionID).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(tc.count))
+			}
+			mock.ExpectRollback()
+
+			product, err := repo.AddProductToOpenReception(createTestContext(), models.TypeClothes, receptionID, 5)
+
+			assert.ErrorIs(t, err, tc.expectedError)
+			assert.Nil(t, product)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDeleteLastProductFromOpenReception_LocksReception(t *testing.T) {
+	repo, mock, cleanup := setupProductRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	receptionID := uuid.New()
+	productID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT status FROM receptions WHERE id = $1 FOR UPDATE")).
+		WithArgs(receptionID).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(models.StatusInProgress))
+	mock.ExpectQuery(regexp.QuoteMeta("DELETE FROM products WHERE id = ( SELECT id FROM products WHERE reception_id = $1 " +
+		"ORDER BY sequence_num DESC LIMIT 1 ) RETURNING id, date_time, type, reception_id, sequence_num")).
+		WithArgs(receptionID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id", "sequence_num"}).
+			AddRow(productID, time.Now(), models.TypeFootwear, receptionID, 7))
+	mock.ExpectCommit()
+
+	product, err := repo.DeleteLastProductFromOpenReception(ctx, receptionID)
+
+	require.NoError(t, err)
+	assert.Equal(t, productID, product.ID)
+	assert.Equal(t, 7, product.SequenceNum)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteLastProductFromOpenReception_Empty(t *testing.T) {
+	repo, mock, cleanup := setupProductRepoTest(t)
+	defer cleanup()
+
+	receptionID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT status FROM receptions WHERE id = $1 FOR UPDATE")).
+		WithArgs(receptionID).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(models.StatusInProgress))
+	mock.ExpectQuery("DELETE FROM products").
+		WithArgs(receptionID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id", "sequence_num"}))
+	mock.ExpectCommit()
+
+	product, err := repo.DeleteLastProductFromOpenReception(createTestContext(), receptionID)
+
+	assert.NoError(t, err)
+	assert.Nil(t, product)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMoveProduct_LocksReceptionsInOrder(t *testing.T) {
+	repo, mock, cleanup := setupProductRepoTest(t)
+	defer cleanup()
+
+	productID := uuid.New()
+	sourceReceptionID := uuid.MustParse("20000000-0000-0000-0000-000000000001")
+	targetReceptionID := uuid.MustParse("10000000-0000-0000-0000-000000000001")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT reception_id FROM products WHERE id = $1 FOR UPDATE")).
+		WithArgs(productID).
+		WillReturnRows(sqlmock.NewRows([]string{"reception_id"}).AddRow(sourceReceptionID))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT status FROM receptions WHERE id = $1 FOR UPDATE")).
+		WithArgs(targetReceptionID).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(models.StatusInProgress))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT status FROM receptions WHERE id = $1 FOR UPDATE")).
+		WithArgs(sourceReceptionID).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(models.StatusInProgress))
+	mock.ExpectQuery(regexp.QuoteMeta("UPDATE products SET reception_id = $1, sequence_num = "+
+		"(SELECT COALESCE(MAX(sequence_num), 0) + 1 FROM products WHERE reception_id = $2) WHERE id = $3 "+
+		"RETURNING id, date_time, type, reception_id, sequence_num")).
+		WithArgs(targetReceptionID, targetReceptionID, productID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id", "sequence_num"}).
+			AddRow(productID, time.Now(), models.TypeClothes, targetReceptionID, 5))
+	mock.ExpectCommit()
+
+	product, err := repo.MoveProduct(createTestContext(), productID, targetReceptionID)
+
+	require.NoError(t, err)
+	assert.Equal(t, targetReceptionID, product.ReceptionID)
+	assert.Equal(t, 5, product.SequenceNum)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMoveProduct_Rejected(t *testing.T) {
+	testCases := []struct {
+		name          string
+		productFound  bool
+		targetStatus  models.ReceptionStatus
+		expectedError error
+	}{
+		{name: "Product Not Found", expectedError: models.ErrProductNotFound},
+		{name: "Target Closed", productFound: true, targetStatus: models.StatusClosed, expectedError: models.ErrReceptionClosed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock, cleanup := setupProductRepoTest(t)
+			defer cleanup()
+
+			productID := uuid.New()
+			sourceReceptionID := uuid.MustParse("20000000-0000-0000-0000-000000000001")
+			targetReceptionID := uuid.MustParse("10000000-0000-0000-0000-000000000001")
+
+			mock.ExpectBegin()
+			rows := sqlmock.NewRows([]string{"reception_id"})
+			if tc.productFound {
+				rows.AddRow(sourceReceptionID)
+			}
+			mock.ExpectQuery("SELECT reception_id FROM products").
+				WithArgs(productID).
+				WillReturnRows(rows)
+			if tc.productFound {
+				mock.ExpectQuery("SELECT status FROM receptions").
+					WithArgs(targetReceptionID).
+					WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(tc.targetStatus))
+			}
+			mock.ExpectRollback()
+
+			product, err := repo.MoveProduct(createTestContext(), productID, targetReceptionID)
+
+			assert.ErrorIs(t, err, tc.expectedError)
+			assert.Nil(t, product)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetSequenceGaps(t *testing.T) {
+	const gapsQuery = "SELECT s.n FROM (SELECT generate_series(1, COALESCE(MAX(sequence_num), 0)) AS n FROM products WHERE reception_id = $1) AS s " +
+		"WHERE NOT EXISTS (SELECT 1 FROM products p WHERE p.reception_id = $2 AND p.sequence_num = s.n) ORDER BY s.n"
+
+	testCases := []struct {
+		name     string
+		missing  []int
+		expected []int
+	}{
+		{
+			// Товары 1..5, удалены 2 и 4
+			name:     "Gaps After Deletions",
+			missing:  []int{2, 4},
+			expected: []int{2, 4},
+		},
+		{
+			// Удален первый товар из трех
+			name:     "Gap At Start",
+			missing:  []int{1},
+			expected: []int{1},
+		},
+		{
+			name:     "No Gaps",
+			expected: []int{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock, cleanup := setupProductRepoTest(t)
+			defer cleanup()
+
+			receptionID := uuid.New()
+			rows := sqlmock.NewRows([]string{"n"})
+			for _, n := range tc.missing {
+				rows.AddRow(n)
+			}
+			mock.ExpectQuery("^"+regexp.QuoteMeta(gapsQuery)+"$").
+				WithArgs(receptionID, receptionID).
+				WillReturnRows(rows)
+
+			missing, err := repo.GetSequenceGaps(createTestContext(), receptionID)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, missing)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGetSequenceGaps_Error(t *testing.T) {
+	repo, mock, cleanup := setupProductRepoTest(t)
+	defer cleanup()
+
+	receptionID := uuid.New()
+	mock.ExpectQuery("generate_series").
+		WithArgs(receptionID, receptionID).
+		WillReturnError(errors.New("database error"))
+
+	missing, err := repo.GetSequenceGaps(createTestContext(), receptionID)
+
+	assert.Error(t, err)
+	assert.Nil(t, missing)
+	assert.Contains(t, err.Error(), "error getting sequence gaps")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestResequenceProducts(t *testing.T) {
+	const (
+		lockQuery     = "SELECT status FROM receptions WHERE id = $1 FOR UPDATE"
+		shiftQuery    = "UPDATE products SET sequence_num = -sequence_num WHERE reception_id = $1"
+		renumberQuery = "UPDATE products SET sequence_num = o.rn FROM (SELECT id, ROW_NUMBER() OVER (ORDER BY sequence_num DESC) AS rn " +
+			"FROM products WHERE reception_id = $1) AS o WHERE products.id = o.id"
+	)
+
+	t.Run("Contiguous Numbering", func(t *testing.T) {
+		repo, mock, cleanup := setupProductRepoTest(t)
+		defer cleanup()
+
+		// Товары с номерами 1, 3, 4, 7 после удалений: после сдвига -1, -3, -4, -7,
+		// по убыванию они получают 1, 2, 3, 4 в прежнем порядке
+		receptionID := uuid.New()
+		mock.ExpectBegin()
+		mock.ExpectQuery("^" + regexp.QuoteMeta(lockQuery) + "$").
+			WithArgs(receptionID).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(models.StatusInProgress))
+		mock.ExpectExec("^" + regexp.QuoteMeta(shiftQuery) + "$").
+			WithArgs(receptionID).
+			WillReturnResult(sqlmock.NewResult(0, 4))
+		mock.ExpectExec("^" + regexp.QuoteMeta(renumberQuery) + "$").
+			WithArgs(receptionID).
+			WillReturnResult(sqlmock.NewResult(0, 4))
+		mock.ExpectCommit()
+
+		count, err := repo.ResequenceProducts(createTestContext(), receptionID)
+
+		require.NoError(t, err)
+		assert.Equal(t, 4, count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Rollback On Failure", func(t *testing.T) {
+		repo, mock, cleanup := setupProductRepoTest(t)
+		defer cleanup()
+
+		receptionID := uuid.New()
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(lockQuery)).
+			WithArgs(receptionID).
+			WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(models.StatusInProgress))
+		mock.ExpectExec(regexp.QuoteMeta(shiftQuery)).
+			WithArgs(receptionID).
+			WillReturnResult(sqlmock.NewResult(0, 4))
+		mock.ExpectExec(regexp.QuoteMeta(renumberQuery)).
+			WithArgs(receptionID).
+			WillReturnError(errors.New("database error"))
+		mock.ExpectRollback()
+
+		count, err := repo.ResequenceProducts(createTestContext(), receptionID)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "error resequencing products")
+		assert.Zero(t, count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestResequenceProducts_Rejected(t *testing.T) {
+	testCases := []struct {
+		name          string
+		found         bool
+		status        models.ReceptionStatus
+		expectedError error
+	}{
+		{name: "Reception Not Found", expectedError: models.ErrReceptionNotFound},
+		{name: "Reception Closed", found: true, status: models.StatusClosed, expectedError: models.ErrReceptionClosed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock, cleanup := setupProductRepoTest(t)
+			defer cleanup()
+
+			receptionID := uuid.New()
+			rows := sqlmock.NewRows([]string{"status"})
+			if tc.found {
+				rows.AddRow(tc.status)
+			}
+			mock.ExpectBegin()
+			mock.ExpectQuery("SELECT status FROM receptions").
+				WithArgs(receptionID).
+				WillReturnRows(rows)
+			mock.ExpectRollback()
+
+			count, err := repo.ResequenceProducts(createTestContext(), receptionID)
+
+			assert.ErrorIs(t, err, tc.expectedError)
+			assert.Zero(t, count)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestListProductsByPVZID(t *testing.T) {
+	const (
+		listQuery = "SELECT p.id, p.date_time, p.type, p.reception_id, p.sequence_num FROM products p " +
+			"JOIN receptions r ON r.id = p.reception_id WHERE (r.pvz_id = $1) ORDER BY p.date_time DESC, p.id LIMIT 10 OFFSET 0"
+		countQuery = "SELECT COUNT(*) FROM products p JOIN receptions r ON r.id = p.reception_id WHERE (r.pvz_id = $1)"
+	)
+
+	repo, mock, cleanup := setupProductRepoTest(t)
+	defer cleanup()
+
+	pvzID := uuid.New()
+	firstReceptionID := uuid.New()
+	secondReceptionID := uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery("^" + regexp.QuoteMeta(listQuery) + "$").
+		WithArgs(pvzID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id", "sequence_num"}).
+			AddRow(uuid.New(), now, models.TypeFootwear, secondReceptionID, 1).
+			AddRow(uuid.New(), now.Add(-time.Hour), models.TypeClothes, firstReceptionID, 3))
+	mock.ExpectQuery("^" + regexp.QuoteMeta(countQuery) + "$").
+		WithArgs(pvzID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	products, total, err := repo.ListProductsByPVZID(createTestContext(), pvzID, "", 1, 10)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, products, 2)
+	assert.Equal(t, secondReceptionID, products[0].ReceptionID)
+	assert.Equal(t, firstReceptionID, products[1].ReceptionID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListProductsByPVZID_PaginationAndType(t *testing.T) {
+	repo, mock, cleanup := setupProductRepoTest(t)
+	defer cleanup()
+
+	pvzID := uuid.New()
+
+	mock.ExpectQuery(regexp.QuoteMeta("JOIN receptions r ON r.id = p.reception_id WHERE (r.pvz_id = $1 AND p.type = $2) ORDER BY p.date_time DESC, p.id LIMIT 5 OFFSET 10")).
+		WithArgs(pvzID, models.TypeElectronics).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id", "sequence_num"}).
+			AddRow(uuid.New(), time.Now(), models.TypeElectronics, uuid.New(), 11))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM products p JOIN receptions r ON r.id = p.reception_id WHERE (r.pvz_id = $1 AND p.type = $2)")).
+		WithArgs(pvzID, models.TypeElectronics).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(11))
+
+	products, total, err := repo.ListProductsByPVZID(createTestContext(), pvzID, models.TypeElectronics, 3, 5)
+
+	require.NoError(t, err)
+	assert.Equal(t, 11, total)
+	require.Len(t, products, 1)
+	assert.Equal(t, models.TypeElectronics, products[0].Type)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListProductsByPVZID_QueryError(t *testing.T) {
+	repo, mock, cleanup := setupProductRepoTest(t)
+	defer cleanup()
+
+	pvzID := uuid.New()
+	mock.ExpectQuery("FROM products p JOIN receptions r").
+		WithArgs(pvzID).
+		WillReturnError(errors.New("database error"))
+
+	products, total, err := repo.ListProductsByPVZID(createTestContext(), pvzID, "", 1, 10)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "error querying PVZ products")
+	assert.Nil(t, products)
+	assert.Zero(t, total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
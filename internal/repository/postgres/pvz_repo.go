@@ -7,61 +7,185 @@ import (
 	"fmt"
 	"time"
 
+	"pvz-service/internal/domain/interfaces"
 	"pvz-service/internal/domain/models"
 	"pvz-service/internal/logger"
+	"pvz-service/internal/metrics"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
 )
 
+// pvzSortColumns сопоставляет допустимые поля сортировки с колонками таблицы pvz
+var pvzSortColumns = map[string]string{
+	models.PVZSortRegistrationDate: "registration_date",
+	models.PVZSortCity:             "city",
+}
+
+// pvzExportBatchSize - сколько ПВЗ StreamPVZ читает из базы за один запрос
+const pvzExportBatchSize = 500
+
 type PVZRepository struct {
-	db *sql.DB
+	db *DB
 	sb squirrel.StatementBuilderType
+
+	// exportBatchSize - размер пачки для StreamPVZ. Ноль - pvzExportBatchSize
+	exportBatchSize int
+
+	// replica - необязательная реплика для тяжелых списочных запросов
+	replica *DB
 }
 
-func NewPVZRepository(db *sql.DB) *PVZRepository {
+var _ interfaces.PVZRepository = (*PVZRepository)(nil)
+
+func NewPVZRepository(db *DB) *PVZRepository {
 	return &PVZRepository{
 		db: db,
 		sb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
 	}
 }
 
-func (r *PVZRepository) CreatePVZ(ctx context.Context, city string) (*models.PVZ, error) {
+// WithReplica направляет списочные запросы на реплику. nil оставляет их на основной базе
+func (r *PVZRepository) WithReplica(replica *DB) *PVZRepository {
+	r.replica = replica
+	return r
+}
+
+// reader возвращает базу для запросов только на чтение: реплику, если она задана
+func (r *PVZRepository) reader() *DB {
+	if r.replica != nil {
+		return r.replica
+	}
+	return r.db
+}
+
+// CreatePVZ создает ПВЗ. Второе возвращаемое значение равно false, если ПВЗ
+// с таким внешним ID уже существовал и был возвращен вместо создания нового
+func (r *PVZRepository) CreatePVZ(ctx context.Context, city, externalID string) (*models.PVZ, bool, error) {
+	defer metrics.ObserveDBQuery("create_pvz", time.Now())
+
 	log := logger.FromContext(ctx)
-	log.Debug("создание ПВЗ", "city", city)
+	log.Debug("создание ПВЗ", "city", city, "external_id", externalID)
 
 	query := r.sb.Insert("pvz").
 		Columns("city").
 		Values(city).
-		Suffix("RETURNING id, registration_date, city")
+		Suffix("RETURNING id, registration_date, city, external_id")
+
+	if externalID != "" {
+		// При повторном запросе с тем же внешним ID вставка пропускается,
+		// а существующий ПВЗ возвращается ниже
+		query = r.sb.Insert("pvz").
+			Columns("city", "external_id").
+			Values(city, externalID).
+			Suffix("ON CONFLICT (external_id) DO NOTHING RETURNING id, registration_date, city, external_id")
+	}
 
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
 		log.Error("ошибка построения SQL", "error", err)
-		return nil, fmt.Errorf("error building SQL: %w", err)
+		return nil, false, fmt.Errorf("error building SQL: %w", err)
 	}
 
-	if log.Enabled(ctx, logger.LevelDebug) {
-		log.Debug("SQL запрос", "query", sqlQuery, "args", args)
-	}
+	logSQL(ctx, sqlQuery, args, "city", "external_id")
 
 	var pvz models.PVZ
-	err = r.db.QueryRowContext(ctx, sqlQuery, args...).Scan(&pvz.ID, &pvz.RegistrationDate, &pvz.City)
+	var extID sql.NullString
+	err = r.db.QueryRowContext(ctx, sqlQuery, args...).Scan(&pvz.ID, &pvz.RegistrationDate, &pvz.City, &extID)
 
 	if err != nil {
-		log.Error("ошибка создания ПВЗ в БД", "error", err, "city", city)
-		return nil, fmt.Errorf("error creating PVZ: %w", err)
+		// ErrNoRows - конфликт обработан ON CONFLICT, уникальное нарушение - гонка
+		// с параллельной вставкой; в обоих случаях возвращаем существующий ПВЗ
+		if externalID != "" && (errors.Is(err, sql.ErrNoRows) || isUniqueViolation(err)) {
+			log.Info("ПВЗ с таким внешним ID уже существует", "external_id", externalID)
+			existing, err := r.getPVZByExternalID(ctx, externalID)
+			if err != nil {
+				return nil, false, err
+			}
+			return existing, false, nil
+		}
+		log.Error("ошибка создания ПВЗ в БД", "error", err, sqlLogAttr(sqlQuery, args), "city", city)
+		return nil, false, fmt.Errorf("error creating PVZ: %w", err)
 	}
+	pvz.ExternalID = nullStringPtr(extID)
 
 	log.Info("ПВЗ успешно создан", "pvz_id", pvz.ID, "city", pvz.City)
+	return &pvz, true, nil
+}
+
+// CreatePVZBatch создает несколько ПВЗ в одной транзакции: либо создаются все, либо ни один
+func (r *PVZRepository) CreatePVZBatch(ctx context.Context, cities []string) ([]*models.PVZ, error) {
+	defer metrics.ObserveDBQuery("create_pvz_batch", time.Now())
+
+	log := logger.FromContext(ctx)
+	log.Debug("пакетное создание ПВЗ", "count", len(cities))
+
+	pvzs := make([]*models.PVZ, 0, len(cities))
+	err := withTx(ctx, r.db, nil, func(tx *Tx) error {
+		for _, city := range cities {
+			sqlQuery, args, err := r.sb.Insert("pvz").
+				Columns("city").
+				Values(city).
+				Suffix("RETURNING id, registration_date, city").
+				ToSql()
+			if err != nil {
+				log.Error("ошибка построения SQL", "error", err)
+				return fmt.Errorf("error building SQL: %w", err)
+			}
+
+			var pvz models.PVZ
+			err = tx.QueryRowContext(ctx, sqlQuery, args...).Scan(&pvz.ID, &pvz.RegistrationDate, &pvz.City)
+			if err != nil {
+				log.Error("ошибка создания ПВЗ в БД", "error", err, sqlLogAttr(sqlQuery, args), "city", city)
+				return fmt.Errorf("error creating PVZ: %w", err)
+			}
+			pvzs = append(pvzs, &pvz)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("ПВЗ успешно созданы пакетом", "count", len(pvzs))
+	return pvzs, nil
+}
+
+func (r *PVZRepository) getPVZByExternalID(ctx context.Context, externalID string) (*models.PVZ, error) {
+	log := logger.FromContext(ctx)
+
+	query := r.sb.Select("id", "registration_date", "city", "external_id").
+		From("pvz").
+		Where(squirrel.Eq{"external_id": externalID})
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		log.Error("ошибка построения SQL", "error", err, "external_id", externalID)
+		return nil, fmt.Errorf("error building SQL: %w", err)
+	}
+
+	var pvz models.PVZ
+	var extID sql.NullString
+	err = r.db.QueryRowContext(ctx, sqlQuery, args...).Scan(
+		&pvz.ID, &pvz.RegistrationDate, &pvz.City, &extID,
+	)
+	if err != nil {
+		log.Error("ошибка получения ПВЗ по внешнему ID", "error", err, sqlLogAttr(sqlQuery, args), "external_id", externalID)
+		return nil, fmt.Errorf("error getting PVZ by external id: %w", err)
+	}
+	pvz.ExternalID = nullStringPtr(extID)
+
+	log.Debug("получен существующий ПВЗ", "pvz_id", pvz.ID, "external_id", externalID)
 	return &pvz, nil
 }
 
 func (r *PVZRepository) GetPVZByID(ctx context.Context, id uuid.UUID) (*models.PVZ, error) {
+	defer metrics.ObserveDBQuery("get_pvz_by_id", time.Now())
+
 	log := logger.FromContext(ctx)
 	log.Debug("получение ПВЗ по ID", "pvz_id", id)
 
-	query := r.sb.Select("id", "registration_date", "city").
+	query := r.sb.Select("id", "registration_date", "city", "external_id").
 		From("pvz").
 		Where(squirrel.Eq{"id": id})
 
@@ -72,8 +196,9 @@ func (r *PVZRepository) GetPVZByID(ctx context.Context, id uuid.UUID) (*models.P
 	}
 
 	var pvz models.PVZ
+	var extID sql.NullString
 	err = r.db.QueryRowContext(ctx, sqlQuery, args...).Scan(
-		&pvz.ID, &pvz.RegistrationDate, &pvz.City,
+		&pvz.ID, &pvz.RegistrationDate, &pvz.City, &extID,
 	)
 
 	if err != nil {
@@ -81,51 +206,276 @@ func (r *PVZRepository) GetPVZByID(ctx context.Context, id uuid.UUID) (*models.P
 			log.Info("ПВЗ не найден", "pvz_id", id)
 			return nil, nil
 		}
-		log.Error("ошибка получения ПВЗ", "error", err, "pvz_id", id)
+		log.Error("ошибка получения ПВЗ", "error", err, sqlLogAttr(sqlQuery, args), "pvz_id", id)
 		return nil, fmt.Errorf("error getting PVZ by id: %w", err)
 	}
+	pvz.ExternalID = nullStringPtr(extID)
 
 	log.Debug("ПВЗ успешно получен", "pvz_id", pvz.ID, "city", pvz.City)
 	return &pvz, nil
 }
 
+// GetPVZsByIDs возвращает найденные ПВЗ из списка идентификаторов одним запросом.
+// Отсутствующие идентификаторы пропускаются, порядок - по дате регистрации
+func (r *PVZRepository) GetPVZsByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.PVZ, error) {
+	defer metrics.ObserveDBQuery("get_pvzs_by_ids", time.Now())
+
+	log := logger.FromContext(ctx)
+	log.Debug("получение ПВЗ по списку ID", "count", len(ids))
+
+	if len(ids) == 0 {
+		return []*models.PVZ{}, nil
+	}
+
+	query := r.sb.Select("id", "registration_date", "city", "external_id").
+		From("pvz").
+		Where(squirrel.Eq{"id": ids}).
+		OrderBy("registration_date", "id")
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		log.Error("ошибка построения SQL", "error", err)
+		return nil, fmt.Errorf("error building SQL: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		log.Error("ошибка получения ПВЗ по списку ID", "error", err, sqlLogAttr(sqlQuery, args))
+		return nil, fmt.Errorf("error querying PVZ by ids: %w", err)
+	}
+	defer rows.Close()
+
+	pvzs := make([]*models.PVZ, 0, len(ids))
+	for rows.Next() {
+		var pvz models.PVZ
+		var extID sql.NullString
+		if err := rows.Scan(&pvz.ID, &pvz.RegistrationDate, &pvz.City, &extID); err != nil {
+			log.Error("ошибка сканирования строки ПВЗ", "error", err)
+			return nil, fmt.Errorf("error scanning PVZ row: %w", err)
+		}
+		pvz.ExternalID = nullStringPtr(extID)
+		pvzs = append(pvzs, &pvz)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Error("ошибка при итерации по ПВЗ", "error", err)
+		return nil, fmt.Errorf("error iterating PVZ: %w", err)
+	}
+
+	log.Debug("ПВЗ по списку ID получены", "requested", len(ids), "found", len(pvzs))
+	return pvzs, nil
+}
+
+// CountPVZByCity возвращает число ПВЗ в каждом городе, упорядоченное по названию города
+func (r *PVZRepository) CountPVZByCity(ctx context.Context) ([]models.CityPVZCount, error) {
+	defer metrics.ObserveDBQuery("count_pvz_by_city", time.Now())
+
+	log := logger.FromContext(ctx)
+	log.Debug("подсчет ПВЗ по городам")
+
+	query := r.sb.Select("city", "COUNT(*)").
+		From("pvz").
+		GroupBy("city").
+		OrderBy("city")
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		log.Error("ошибка построения SQL", "error", err)
+		return nil, fmt.Errorf("error building SQL: %w", err)
+	}
+
+	rows, err := r.reader().QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		log.Error("ошибка подсчета ПВЗ по городам", "error", err, sqlLogAttr(sqlQuery, args))
+		return nil, fmt.Errorf("error counting PVZ by city: %w", err)
+	}
+	defer rows.Close()
+
+	cities := []models.CityPVZCount{}
+	for rows.Next() {
+		var city models.CityPVZCount
+		if err := rows.Scan(&city.City, &city.Count); err != nil {
+			log.Error("ошибка сканирования строки подсчета ПВЗ", "error", err)
+			return nil, fmt.Errorf("error scanning city count row: %w", err)
+		}
+		cities = append(cities, city)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Error("ошибка при итерации по городам", "error", err)
+		return nil, fmt.Errorf("error iterating city counts: %w", err)
+	}
+
+	log.Debug("ПВЗ подсчитаны по городам", "cities", len(cities))
+	return cities, nil
+}
+
+// StreamPVZ передает в fn все ПВЗ по порядку регистрации. Таблица читается пачками
+// по курсору (registration_date, id), поэтому в памяти одновременно находится не
+// больше одной пачки. Ошибка fn прерывает обход и возвращается как есть
+func (r *PVZRepository) StreamPVZ(ctx context.Context, fn func(*models.PVZ) error) error {
+	defer metrics.ObserveDBQuery("stream_pvz", time.Now())
+
+	log := logger.FromContext(ctx)
+	log.Debug("выгрузка всех ПВЗ")
+
+	batchSize := r.exportBatchSize
+	if batchSize <= 0 {
+		batchSize = pvzExportBatchSize
+	}
+
+	var last *models.PVZ
+	total := 0
+	for {
+		query := r.sb.Select("id", "registration_date", "city", "external_id").
+			From("pvz").
+			OrderBy("registration_date", "id").
+			Limit(uint64(batchSize))
+		if last != nil {
+			query = query.Where("(registration_date, id) > (?, ?)", last.RegistrationDate, last.ID)
+		}
+
+		sqlQuery, args, err := query.ToSql()
+		if err != nil {
+			log.Error("ошибка построения SQL", "error", err)
+			return fmt.Errorf("error building SQL: %w", err)
+		}
+
+		batch, err := r.queryPVZBatch(ctx, sqlQuery, args)
+		if err != nil {
+			log.Error("ошибка выгрузки ПВЗ", "exported", total, "error", err, sqlLogAttr(sqlQuery, args))
+			return err
+		}
+
+		for _, pvz := range batch {
+			if err := fn(pvz); err != nil {
+				return err
+			}
+		}
+		total += len(batch)
+
+		if len(batch) < batchSize {
+			break
+		}
+		last = batch[len(batch)-1]
+	}
+
+	log.Debug("ПВЗ выгружены", "count", total)
+	return nil
+}
+
+// ListPVZWithReceptionsBetween одним запросом возвращает ПВЗ, у которых есть приемки
+// с date_time в полуинтервале [from, to), по порядку регистрации
+func (r *PVZRepository) ListPVZWithReceptionsBetween(ctx context.Context, from, to time.Time) ([]*models.PVZ, error) {
+	defer metrics.ObserveDBQuery("list_pvz_with_receptions_between", time.Now())
+
+	log := logger.FromContext(ctx)
+	log.Debug("получение ПВЗ с приемками за период", "from", from, "to", to)
+
+	receptionsInRange := r.sb.Select("1").
+		From("receptions r").
+		Where("r.pvz_id = pvz.id").
+		Where(squirrel.GtOrEq{"r.date_time": from}).
+		Where(squirrel.Lt{"r.date_time": to})
+
+	sqlQuery, args, err := r.sb.Select("id", "registration_date", "city", "external_id").
+		From("pvz").
+		Where(squirrel.Expr("EXISTS (?)", receptionsInRange)).
+		OrderBy("registration_date", "id").
+		ToSql()
+	if err != nil {
+		log.Error("ошибка построения SQL", "error", err)
+		return nil, fmt.Errorf("error building SQL: %w", err)
+	}
+
+	pvzs, err := r.queryPVZBatch(ctx, sqlQuery, args)
+	if err != nil {
+		log.Error("ошибка получения ПВЗ с приемками за период", "error", err, sqlLogAttr(sqlQuery, args))
+		return nil, err
+	}
+
+	log.Debug("ПВЗ с приемками за период получены", "count", len(pvzs))
+	return pvzs, nil
+}
+
+// queryPVZBatch читает ПВЗ по готовому запросу (например, одну пачку StreamPVZ) и сразу
+// освобождает соединение, чтобы медленный получатель не держал его между пачками
+func (r *PVZRepository) queryPVZBatch(ctx context.Context, sqlQuery string, args []interface{}) ([]*models.PVZ, error) {
+	rows, err := r.reader().QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying PVZ: %w", err)
+	}
+	defer rows.Close()
+
+	var batch []*models.PVZ
+	for rows.Next() {
+		var pvz models.PVZ
+		var extID sql.NullString
+		if err := rows.Scan(&pvz.ID, &pvz.RegistrationDate, &pvz.City, &extID); err != nil {
+			return nil, fmt.Errorf("error scanning PVZ row: %w", err)
+		}
+		pvz.ExternalID = nullStringPtr(extID)
+		batch = append(batch, &pvz)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating PVZ: %w", err)
+	}
+	return batch, nil
+}
+
 func (r *PVZRepository) ListPVZ(ctx context.Context, options models.PVZListOptions) ([]*models.PVZWithReceptionsResponse, int, error) {
+	defer metrics.ObserveDBQuery("list_pvz", time.Now())
+
 	log := logger.FromContext(ctx)
 	log.Debug("получение списка ПВЗ",
 		"page", options.Page,
 		"limit", options.Limit,
 		"has_start_date", !options.StartDate.IsZero(),
 		"has_end_date", !options.EndDate.IsZero(),
+		"has_registered_from", !options.RegisteredFrom.IsZero(),
+		"has_registered_to", !options.RegisteredTo.IsZero(),
+		"skip_total", options.SkipTotal,
+		"only_open", options.OnlyOpen,
 	)
 
-	tx, err := r.db.BeginTx(ctx, nil)
+	// Каждая граница периода приемок применяется независимо, как в ListReceptions
+	dateFilter := receptionDateFilter("r.date_time", options.StartDate, options.EndDate)
+	hasDateFilter := len(dateFilter) > 0
+
+	orderBy, err := pvzOrderBy(options.SortBy, options.SortDesc, hasDateFilter)
 	if err != nil {
-		log.Error("ошибка начала транзакции", "error", err)
-		return nil, 0, fmt.Errorf("error starting transaction: %w", err)
+		log.Warn("недопустимое поле сортировки", "sort_by", options.SortBy)
+		return nil, 0, err
 	}
 
-	defer func() {
-		if err != nil {
-			log.Debug("откат транзакции из-за ошибки")
-			tx.Rollback()
-		}
-	}()
+	var offset int
+	options.Page, options.Limit, offset = normalizePagination(ctx, options.Page, options.Limit)
 
-	if options.Limit <= 0 {
-		options.Limit = 10
-		log.Debug("установлено значение limit по умолчанию", "limit", options.Limit)
-	}
-	if options.Page <= 0 {
-		options.Page = 1
-		log.Debug("установлено значение page по умолчанию", "page", options.Page)
+	// Без COUNT лишняя строка показывает, есть ли следующая страница
+	fetchLimit := options.Limit
+	if options.SkipTotal {
+		fetchLimit++
 	}
 
-	offset := (options.Page - 1) * options.Limit
-
 	var pvzQuery squirrel.SelectBuilder
 	var countQuery squirrel.SelectBuilder
 
-	if !options.StartDate.IsZero() && !options.EndDate.IsZero() {
+	regColumn := "registration_date"
+	if hasDateFilter {
+		regColumn = "p.registration_date"
+	}
+	registrationFilter := squirrel.And{}
+	if !options.RegisteredFrom.IsZero() {
+		registrationFilter = append(registrationFilter, squirrel.GtOrEq{regColumn: options.RegisteredFrom})
+		log.Debug("добавлен фильтр по начальной дате регистрации", "registered_from", options.RegisteredFrom.Format(time.RFC3339))
+	}
+	if !options.RegisteredTo.IsZero() {
+		registrationFilter = append(registrationFilter, squirrel.LtOrEq{regColumn: options.RegisteredTo})
+		log.Debug("добавлен фильтр по конечной дате регистрации", "registered_to", options.RegisteredTo.Format(time.RFC3339))
+	}
+
+	if hasDateFilter {
 		log.Debug("применение фильтра по датам",
 			"start_date", options.StartDate.Format(time.RFC3339),
 			"end_date", options.EndDate.Format(time.RFC3339),
@@ -134,33 +484,44 @@ func (r *PVZRepository) ListPVZ(ctx context.Context, options models.PVZListOptio
 		pvzQuery = r.sb.Select("DISTINCT p.id", "p.registration_date", "p.city").
 			From("pvz p").
 			Join("receptions r ON p.id = r.pvz_id").
-			Where(squirrel.And{
-				squirrel.GtOrEq{"r.date_time": options.StartDate},
-				squirrel.LtOrEq{"r.date_time": options.EndDate},
-			}).
-			OrderBy("p.id").
-			Limit(uint64(options.Limit)).
+			Where(dateFilter).
+			OrderBy(orderBy...).
+			Limit(uint64(fetchLimit)).
 			Offset(uint64(offset))
 
 		countQuery = r.sb.Select("COUNT(DISTINCT p.id)").
 			From("pvz p").
 			Join("receptions r ON p.id = r.pvz_id").
-			Where(squirrel.And{
-				squirrel.GtOrEq{"r.date_time": options.StartDate},
-				squirrel.LtOrEq{"r.date_time": options.EndDate},
-			})
+			Where(dateFilter)
 	} else {
 		log.Debug("получение всех ПВЗ без фильтра по датам")
 
 		pvzQuery = r.sb.Select("id", "registration_date", "city").
 			From("pvz").
-			OrderBy("id").
-			Limit(uint64(options.Limit)).
+			OrderBy(orderBy...).
+			Limit(uint64(fetchLimit)).
 			Offset(uint64(offset))
 
 		countQuery = r.sb.Select("COUNT(*)").From("pvz")
 	}
 
+	if len(registrationFilter) > 0 {
+		pvzQuery = pvzQuery.Where(registrationFilter)
+		countQuery = countQuery.Where(registrationFilter)
+	}
+
+	if options.OnlyOpen {
+		// Внешний столбец квалифицируется всегда: голый id внутри подзапроса связался бы с open_r.id
+		idColumn := "pvz.id"
+		if hasDateFilter {
+			idColumn = "p.id"
+		}
+		openFilter := squirrel.Expr("EXISTS (SELECT 1 FROM receptions open_r WHERE open_r.pvz_id = "+idColumn+" AND open_r.status = ?)", models.StatusInProgress)
+		pvzQuery = pvzQuery.Where(openFilter)
+		countQuery = countQuery.Where(openFilter)
+		log.Debug("добавлен фильтр по открытой приемке")
+	}
+
 	pvzSql, pvzArgs, err := pvzQuery.ToSql()
 	if err != nil {
 		log.Error("ошибка построения SQL для списка ПВЗ", "error", err)
@@ -171,68 +532,96 @@ func (r *PVZRepository) ListPVZ(ctx context.Context, options models.PVZListOptio
 		log.Debug("SQL запрос для списка ПВЗ", "query", pvzSql)
 	}
 
-	rows, err := tx.QueryContext(ctx, pvzSql, pvzArgs...)
+	countSql, countArgs, err := countQuery.ToSql()
 	if err != nil {
-		log.Error("ошибка выполнения запроса списка ПВЗ", "error", err)
-		return nil, 0, fmt.Errorf("error querying PVZ list: %w", err)
+		log.Error("ошибка построения SQL для подсчета ПВЗ", "error", err)
+		return nil, 0, fmt.Errorf("error building count query: %w", err)
 	}
-	defer rows.Close()
 
 	var pvzsWithReceptions []*models.PVZWithReceptionsResponse
-	for rows.Next() {
-		var pvz models.PVZ
-		if err := rows.Scan(&pvz.ID, &pvz.RegistrationDate, &pvz.City); err != nil {
-			log.Error("ошибка сканирования строки ПВЗ", "error", err)
-			return nil, 0, fmt.Errorf("error scanning PVZ row: %w", err)
-		}
+	var total int
 
-		log.Debug("получение приемок для ПВЗ", "pvz_id", pvz.ID)
-		receptions, err := r.getReceptionsByPVZIDTx(ctx, tx, pvz.ID, options.StartDate, options.EndDate)
+	err = withTx(ctx, r.reader(), nil, func(tx *Tx) error {
+		rows, err := tx.QueryContext(ctx, pvzSql, pvzArgs...)
 		if err != nil {
-			log.Error("ошибка получения приемок для ПВЗ", "error", err, "pvz_id", pvz.ID)
-			return nil, 0, err
+			log.Error("ошибка выполнения запроса списка ПВЗ", "error", err, sqlLogAttr(pvzSql, pvzArgs))
+			return fmt.Errorf("error querying PVZ list: %w", err)
 		}
+		defer rows.Close()
+
+		var pagePVZ []*models.PVZ
+		rowsRead := 0
+		for rows.Next() {
+			rowsRead++
+			if rowsRead > options.Limit {
+				// Строка сверх limit только отмечает наличие следующей страницы
+				break
+			}
 
-		receptionWithProducts := make([]*models.ReceptionWithProducts, 0)
-		for _, reception := range receptions {
-			log.Debug("получение товаров для приемки", "reception_id", reception.ID)
-			products, err := r.getProductsByReceptionIDTx(ctx, tx, reception.ID)
+			var pvz models.PVZ
+			if err := rows.Scan(&pvz.ID, &pvz.RegistrationDate, &pvz.City); err != nil {
+				log.Error("ошибка сканирования строки ПВЗ", "error", err)
+				return fmt.Errorf("error scanning PVZ row: %w", err)
+			}
+			pagePVZ = append(pagePVZ, &pvz)
+		}
+		if err := rows.Err(); err != nil {
+			log.Error("ошибка при итерации по ПВЗ", "error", err)
+			return fmt.Errorf("error iterating PVZ: %w", err)
+		}
+		// Следующие запросы идут по тому же соединению транзакции, курсор нужно закрыть
+		rows.Close()
+
+		byReceptionID := make(map[uuid.UUID]*models.ReceptionWithProducts)
+		var receptionIDs []uuid.UUID
+		for _, pvz := range pagePVZ {
+			log.Debug("получение приемок для ПВЗ", "pvz_id", pvz.ID)
+			receptions, err := r.getReceptionsByPVZIDTx(ctx, tx, pvz.ID, options.StartDate, options.EndDate)
 			if err != nil {
-				log.Error("ошибка получения товаров для приемки",
-					"error", err,
-					"reception_id", reception.ID,
-				)
-				return nil, 0, err
+				log.Error("ошибка получения приемок для ПВЗ", "error", err, "pvz_id", pvz.ID)
+				return err
+			}
+
+			receptionWithProducts := make([]*models.ReceptionWithProducts, 0, len(receptions))
+			for _, reception := range receptions {
+				item := &models.ReceptionWithProducts{Reception: reception}
+				receptionWithProducts = append(receptionWithProducts, item)
+				byReceptionID[reception.ID] = item
+				receptionIDs = append(receptionIDs, reception.ID)
 			}
 
-			receptionWithProducts = append(receptionWithProducts, &models.ReceptionWithProducts{
-				Reception: reception,
-				Products:  products,
+			pvzsWithReceptions = append(pvzsWithReceptions, &models.PVZWithReceptionsResponse{
+				PVZ:        pvz,
+				Receptions: receptionWithProducts,
 			})
 		}
 
-		pvzsWithReceptions = append(pvzsWithReceptions, &models.PVZWithReceptionsResponse{
-			PVZ:        &pvz,
-			Receptions: receptionWithProducts,
-		})
-	}
+		// Товары всех приемок страницы загружаются одним запросом
+		products, err := r.getProductsByReceptionIDsTx(ctx, tx, receptionIDs)
+		if err != nil {
+			log.Error("ошибка получения товаров для приемок", "error", err, "receptions", len(receptionIDs))
+			return err
+		}
+		for _, product := range products {
+			if item, ok := byReceptionID[product.ReceptionID]; ok {
+				item.Products = append(item.Products, product)
+			}
+		}
 
-	countSql, countArgs, err := countQuery.ToSql()
-	if err != nil {
-		log.Error("ошибка построения SQL для подсчета ПВЗ", "error", err)
-		return nil, 0, fmt.Errorf("error building count query: %w", err)
-	}
+		if options.SkipTotal {
+			total = offset + rowsRead
+			return nil
+		}
 
-	var total int
-	err = tx.QueryRowContext(ctx, countSql, countArgs...).Scan(&total)
-	if err != nil {
-		log.Error("ошибка подсчета общего количества ПВЗ", "error", err)
-		return nil, 0, fmt.Errorf("error counting total PVZ: %w", err)
-	}
+		if err := tx.QueryRowContext(ctx, countSql, countArgs...).Scan(&total); err != nil {
+			log.Error("ошибка подсчета общего количества ПВЗ", "error", err, sqlLogAttr(countSql, countArgs))
+			return fmt.Errorf("error counting total PVZ: %w", err)
+		}
 
-	if err = tx.Commit(); err != nil {
-		log.Error("ошибка фиксации транзакции", "error", err)
-		return nil, 0, fmt.Errorf("error committing transaction: %w", err)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
 	}
 
 	log.Info("список ПВЗ успешно получен",
@@ -243,27 +632,54 @@ func (r *PVZRepository) ListPVZ(ctx context.Context, options models.PVZListOptio
 	return pvzsWithReceptions, total, nil
 }
 
-func (r *PVZRepository) getReceptionsByPVZIDTx(ctx context.Context, tx *sql.Tx, pvzID uuid.UUID, startDate, endDate time.Time) ([]*models.Reception, error) {
-	log := logger.FromContext(ctx)
+// receptionDateFilter возвращает условия по дате приемки для заданных границ.
+// Нулевая граница не ограничивает период; без обеих границ условий нет
+func receptionDateFilter(column string, startDate, endDate time.Time) squirrel.And {
+	filter := squirrel.And{}
+	if !startDate.IsZero() {
+		filter = append(filter, squirrel.GtOrEq{column: startDate})
+	}
+	if !endDate.IsZero() {
+		filter = append(filter, squirrel.LtOrEq{column: endDate})
+	}
+	return filter
+}
 
-	var query squirrel.SelectBuilder
-
-	if !startDate.IsZero() && !endDate.IsZero() {
-		query = r.sb.Select("id", "date_time", "pvz_id", "status").
-			From("receptions").
-			Where(squirrel.And{
-				squirrel.Eq{"pvz_id": pvzID},
-				squirrel.GtOrEq{"date_time": startDate},
-				squirrel.LtOrEq{"date_time": endDate},
-			}).
-			OrderBy("date_time")
-	} else {
-		query = r.sb.Select("id", "date_time", "pvz_id", "status").
-			From("receptions").
-			Where(squirrel.Eq{"pvz_id": pvzID}).
-			OrderBy("date_time")
+// pvzOrderBy строит выражение ORDER BY только из разрешенных колонок,
+// исключая подстановку пользовательского ввода в SQL
+func pvzOrderBy(sortBy string, desc bool, withAlias bool) ([]string, error) {
+	prefix := ""
+	if withAlias {
+		prefix = "p."
 	}
 
+	if sortBy == "" {
+		return []string{prefix + "id"}, nil
+	}
+
+	column, ok := pvzSortColumns[sortBy]
+	if !ok {
+		return nil, fmt.Errorf("unsupported sort field: %q", sortBy)
+	}
+
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+
+	return []string{prefix + column + " " + direction, prefix + "id"}, nil
+}
+
+func (r *PVZRepository) getReceptionsByPVZIDTx(ctx context.Context, tx *Tx, pvzID uuid.UUID, startDate, endDate time.Time) ([]*models.Reception, error) {
+	log := logger.FromContext(ctx)
+
+	where := append(squirrel.And{squirrel.Eq{"pvz_id": pvzID}}, receptionDateFilter("date_time", startDate, endDate)...)
+
+	query := r.sb.Select("id", "date_time", "pvz_id", "status").
+		From("receptions").
+		Where(where).
+		OrderBy("date_time")
+
 	sql, args, err := query.ToSql()
 	if err != nil {
 		log.Error("ошибка построения SQL для приемок", "error", err, "pvz_id", pvzID)
@@ -272,7 +688,7 @@ func (r *PVZRepository) getReceptionsByPVZIDTx(ctx context.Context, tx *sql.Tx,
 
 	rows, err := tx.QueryContext(ctx, sql, args...)
 	if err != nil {
-		log.Error("ошибка получения приемок для ПВЗ", "error", err, "pvz_id", pvzID)
+		log.Error("ошибка получения приемок для ПВЗ", "error", err, sqlLogAttr(sql, args), "pvz_id", pvzID)
 		return nil, fmt.Errorf("error getting receptions for PVZ: %w", err)
 	}
 	defer rows.Close()
@@ -291,24 +707,30 @@ func (r *PVZRepository) getReceptionsByPVZIDTx(ctx context.Context, tx *sql.Tx,
 	return receptions, nil
 }
 
-func (r *PVZRepository) getProductsByReceptionIDTx(ctx context.Context, tx *sql.Tx, receptionID uuid.UUID) ([]*models.Product, error) {
+// getProductsByReceptionIDsTx загружает товары нескольких приемок одним запросом,
+// упорядоченные по приемке и sequence_num. Пустой список ID не обращается к базе
+func (r *PVZRepository) getProductsByReceptionIDsTx(ctx context.Context, tx *Tx, receptionIDs []uuid.UUID) ([]*models.Product, error) {
 	log := logger.FromContext(ctx)
 
+	if len(receptionIDs) == 0 {
+		return nil, nil
+	}
+
 	query := r.sb.Select("id", "date_time", "type", "reception_id", "sequence_num").
 		From("products").
-		Where(squirrel.Eq{"reception_id": receptionID}).
-		OrderBy("sequence_num")
+		Where(squirrel.Eq{"reception_id": receptionIDs}).
+		OrderBy("reception_id", "sequence_num")
 
 	sql, args, err := query.ToSql()
 	if err != nil {
-		log.Error("ошибка построения SQL для товаров", "error", err, "reception_id", receptionID)
+		log.Error("ошибка построения SQL для товаров", "error", err)
 		return nil, fmt.Errorf("error building products query: %w", err)
 	}
 
 	rows, err := tx.QueryContext(ctx, sql, args...)
 	if err != nil {
-		log.Error("ошибка получения товаров для приемки", "error", err, "reception_id", receptionID)
-		return nil, fmt.Errorf("error getting products for reception: %w", err)
+		log.Error("ошибка получения товаров для приемок", "error", err, sqlLogAttr(sql, args))
+		return nil, fmt.Errorf("error getting products for receptions: %w", err)
 	}
 	defer rows.Close()
 
@@ -322,6 +744,11 @@ func (r *PVZRepository) getProductsByReceptionIDTx(ctx context.Context, tx *sql.
 		products = append(products, &product)
 	}
 
-	log.Debug("получены товары для приемки", "reception_id", receptionID, "count", len(products))
+	if err := rows.Err(); err != nil {
+		log.Error("ошибка при итерации по товарам", "error", err)
+		return nil, fmt.Errorf("error iterating products: %w", err)
+	}
+
+	log.Debug("получены товары для приемок", "receptions", len(receptionIDs), "count", len(products))
 	return products, nil
 }
@@ -2,13 +2,16 @@ package postgres
 
 import (
 	"database/sql"
+	"database/sql/driver"
 	"errors"
+	"regexp"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -20,7 +23,7 @@ func setupPVZRepoTest(t *testing.T) (*PVZRepository, sqlmock.Sqlmock, func()) {
 	require.NoError(t, err)
 
 	repo := &PVZRepository{
-		db: db,
+		db: NewDB(db),
 		sb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
 	}
 
@@ -42,16 +45,198 @@ func TestCreatePVZ(t *testing.T) {
 
 	mock.ExpectQuery("INSERT INTO pvz").
 		WithArgs(city).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}).
-			AddRow(pvzID, regDate, city))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city", "external_id"}).
+			AddRow(pvzID, regDate, city, nil))
 
-	pvz, err := repo.CreatePVZ(ctx, city)
+	pvz, created, err := repo.CreatePVZ(ctx, city, "")
 
 	assert.NoError(t, err)
+	assert.True(t, created)
 	assert.NotNil(t, pvz)
 	assert.Equal(t, pvzID, pvz.ID)
 	assert.Equal(t, city, pvz.City)
+	assert.Nil(t, pvz.ExternalID)
+	assert.WithinDuration(t, regDate, pvz.RegistrationDate, time.Second)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreatePVZBatch(t *testing.T) {
+	repo, mock, cleanup := setupPVZRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	cities := []string{"Москва", "Казань"}
+	regDate := time.Now()
+
+	mock.ExpectBegin()
+	for _, city := range cities {
+		mock.ExpectQuery("INSERT INTO pvz").
+			WithArgs(city).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}).
+				AddRow(uuid.New(), regDate, city))
+	}
+	mock.ExpectCommit()
+
+	pvzs, err := repo.CreatePVZBatch(ctx, cities)
+
+	assert.NoError(t, err)
+	require.Len(t, pvzs, 2)
+	assert.Equal(t, "Москва", pvzs[0].City)
+	assert.Equal(t, "Казань", pvzs[1].City)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreatePVZBatch_InsertErrorRollsBack(t *testing.T) {
+	repo, mock, cleanup := setupPVZRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO pvz").
+		WithArgs("Москва").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}).
+			AddRow(uuid.New(), time.Now(), "Москва"))
+	mock.ExpectQuery("INSERT INTO pvz").
+		WithArgs("Тверь").
+		WillReturnError(errors.New("violates check constraint"))
+	mock.ExpectRollback()
+
+	pvzs, err := repo.CreatePVZBatch(ctx, []string{"Москва", "Тверь"})
+
+	assert.Error(t, err)
+	assert.Nil(t, pvzs)
+	assert.Contains(t, err.Error(), "error creating PVZ")
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreatePVZBatch_BeginError(t *testing.T) {
+	repo, mock, cleanup := setupPVZRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+
+	mock.ExpectBegin().WillReturnError(errors.New("connection refused"))
+
+	pvzs, err := repo.CreatePVZBatch(ctx, []string{"Москва"})
+
+	assert.Error(t, err)
+	assert.Nil(t, pvzs)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreatePVZ_WithExternalID(t *testing.T) {
+	repo, mock, cleanup := setupPVZRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	pvzID := uuid.New()
+	city := "Москва"
+	externalID := "site-42"
+	regDate := time.Now()
+
+	mock.ExpectQuery("INSERT INTO pvz (.+) ON CONFLICT \\(external_id\\) DO NOTHING").
+		WithArgs(city, externalID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city", "external_id"}).
+			AddRow(pvzID, regDate, city, externalID))
+
+	pvz, created, err := repo.CreatePVZ(ctx, city, externalID)
+
+	assert.NoError(t, err)
+	assert.True(t, created)
+	require.NotNil(t, pvz)
+	assert.Equal(t, pvzID, pvz.ID)
+	require.NotNil(t, pvz.ExternalID)
+	assert.Equal(t, externalID, *pvz.ExternalID)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreatePVZ_ExternalIDConflictReturnsExisting(t *testing.T) {
+	repo, mock, cleanup := setupPVZRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	existingID := uuid.New()
+	city := "Казань"
+	externalID := "site-42"
+	regDate := time.Now().Add(-time.Hour)
+
+	mock.ExpectQuery("INSERT INTO pvz (.+) ON CONFLICT \\(external_id\\) DO NOTHING").
+		WithArgs(city, externalID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city", "external_id"}))
+
+	mock.ExpectQuery("SELECT (.+) FROM pvz WHERE external_id").
+		WithArgs(externalID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city", "external_id"}).
+			AddRow(existingID, regDate, city, externalID))
+
+	pvz, created, err := repo.CreatePVZ(ctx, city, externalID)
+
+	assert.NoError(t, err)
+	assert.False(t, created)
+	require.NotNil(t, pvz)
+	assert.Equal(t, existingID, pvz.ID)
 	assert.WithinDuration(t, regDate, pvz.RegistrationDate, time.Second)
+	require.NotNil(t, pvz.ExternalID)
+	assert.Equal(t, externalID, *pvz.ExternalID)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreatePVZ_ExternalIDConflictLookupError(t *testing.T) {
+	repo, mock, cleanup := setupPVZRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	city := "Казань"
+	externalID := "site-42"
+
+	mock.ExpectQuery("INSERT INTO pvz").
+		WithArgs(city, externalID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city", "external_id"}))
+
+	mock.ExpectQuery("SELECT (.+) FROM pvz WHERE external_id").
+		WithArgs(externalID).
+		WillReturnError(errors.New("database error"))
+
+	pvz, _, err := repo.CreatePVZ(ctx, city, externalID)
+
+	assert.Error(t, err)
+	assert.Nil(t, pvz)
+	assert.Contains(t, err.Error(), "error getting PVZ by external id")
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreatePVZ_ExternalIDUniqueViolationReturnsExisting(t *testing.T) {
+	repo, mock, cleanup := setupPVZRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	existingID := uuid.New()
+	city := "Москва"
+	externalID := "site-7"
+
+	mock.ExpectQuery("INSERT INTO pvz").
+		WithArgs(city, externalID).
+		WillReturnError(&pq.Error{Code: "23505", Constraint: "idx_pvz_external_id"})
+
+	mock.ExpectQuery("SELECT (.+) FROM pvz WHERE external_id").
+		WithArgs(externalID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city", "external_id"}).
+			AddRow(existingID, time.Now(), city, externalID))
+
+	pvz, created, err := repo.CreatePVZ(ctx, city, externalID)
+
+	assert.NoError(t, err)
+	assert.False(t, created)
+	require.NotNil(t, pvz)
+	assert.Equal(t, existingID, pvz.ID)
 
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -67,7 +252,7 @@ func TestCreatePVZ_SQLError(t *testing.T) {
 		WithArgs(city).
 		WillReturnError(errors.New("database error"))
 
-	pvz, err := repo.CreatePVZ(ctx, city)
+	pvz, _, err := repo.CreatePVZ(ctx, city, "")
 
 	assert.Error(t, err)
 	assert.Nil(t, pvz)
@@ -87,8 +272,8 @@ func TestGetPVZByID(t *testing.T) {
 
 	mock.ExpectQuery("SELECT (.+) FROM pvz").
 		WithArgs(pvzID).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}).
-			AddRow(pvzID, regDate, city))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city", "external_id"}).
+			AddRow(pvzID, regDate, city, nil))
 
 	pvz, err := repo.GetPVZByID(ctx, pvzID)
 
@@ -140,6 +325,85 @@ func TestGetPVZByID_SQLError(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestGetPVZsByIDs(t *testing.T) {
+	repo, mock, cleanup := setupPVZRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	firstID := uuid.New()
+	secondID := uuid.New()
+	externalID := "ext-1"
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, registration_date, city, external_id FROM pvz WHERE id IN ($1,$2) ORDER BY registration_date, id")).
+		WithArgs(firstID, secondID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city", "external_id"}).
+			AddRow(firstID, time.Now(), "Москва", nil).
+			AddRow(secondID, time.Now(), "Казань", externalID))
+
+	pvzs, err := repo.GetPVZsByIDs(ctx, []uuid.UUID{firstID, secondID})
+
+	require.NoError(t, err)
+	require.Len(t, pvzs, 2)
+	assert.Equal(t, firstID, pvzs[0].ID)
+	assert.Nil(t, pvzs[0].ExternalID)
+	assert.Equal(t, secondID, pvzs[1].ID)
+	require.NotNil(t, pvzs[1].ExternalID)
+	assert.Equal(t, externalID, *pvzs[1].ExternalID)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetPVZsByIDs_PartialMiss(t *testing.T) {
+	repo, mock, cleanup := setupPVZRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	foundID := uuid.New()
+	missingID := uuid.New()
+
+	mock.ExpectQuery("SELECT (.+) FROM pvz WHERE id IN").
+		WithArgs(foundID, missingID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city", "external_id"}).
+			AddRow(foundID, time.Now(), "Москва", nil))
+
+	pvzs, err := repo.GetPVZsByIDs(ctx, []uuid.UUID{foundID, missingID})
+
+	require.NoError(t, err)
+	require.Len(t, pvzs, 1)
+	assert.Equal(t, foundID, pvzs[0].ID)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetPVZsByIDs_Empty(t *testing.T) {
+	repo, mock, cleanup := setupPVZRepoTest(t)
+	defer cleanup()
+
+	pvzs, err := repo.GetPVZsByIDs(createTestContext(), nil)
+
+	assert.NoError(t, err)
+	assert.Empty(t, pvzs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetPVZsByIDs_SQLError(t *testing.T) {
+	repo, mock, cleanup := setupPVZRepoTest(t)
+	defer cleanup()
+
+	pvzID := uuid.New()
+
+	mock.ExpectQuery("SELECT (.+) FROM pvz WHERE id IN").
+		WithArgs(pvzID).
+		WillReturnError(errors.New("database connection lost"))
+
+	pvzs, err := repo.GetPVZsByIDs(createTestContext(), []uuid.UUID{pvzID})
+
+	assert.Error(t, err)
+	assert.Nil(t, pvzs)
+	assert.Contains(t, err.Error(), "error querying PVZ by ids")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestListPVZ_NoDateFilter(t *testing.T) {
 	repo, mock, cleanup := setupPVZRepoTest(t)
 	defer cleanup()
@@ -198,6 +462,48 @@ func TestListPVZ_NoDateFilter(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestListPVZ_LoadsProductsInOneQuery(t *testing.T) {
+	repo, mock, cleanup := setupPVZRepoTest(t)
+	defer cleanup()
+
+	firstPVZ, secondPVZ := uuid.New(), uuid.New()
+	firstReception, secondReception := uuid.New(), uuid.New()
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM pvz").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}).
+			AddRow(firstPVZ, now, "Москва").
+			AddRow(secondPVZ, now, "Казань"))
+	mock.ExpectQuery("SELECT (.+) FROM receptions").
+		WithArgs(firstPVZ).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status"}).
+			AddRow(firstReception, now, firstPVZ, models.StatusClosed))
+	mock.ExpectQuery("SELECT (.+) FROM receptions").
+		WithArgs(secondPVZ).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status"}).
+			AddRow(secondReception, now, secondPVZ, models.StatusInProgress))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, date_time, type, reception_id, sequence_num FROM products "+
+		"WHERE reception_id IN ($1,$2) ORDER BY reception_id, sequence_num")).
+		WithArgs(firstReception, secondReception).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id", "sequence_num"}).
+			AddRow(uuid.New(), now, models.TypeClothes, firstReception, 1).
+			AddRow(uuid.New(), now, models.TypeFootwear, secondReception, 1).
+			AddRow(uuid.New(), now, models.TypeElectronics, secondReception, 2))
+	mock.ExpectQuery("SELECT COUNT").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectCommit()
+
+	pvzs, total, err := repo.ListPVZ(createTestContext(), models.PVZListOptions{Page: 1, Limit: 10})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, pvzs, 2)
+	assert.Len(t, pvzs[0].Receptions[0].Products, 1)
+	assert.Len(t, pvzs[1].Receptions[0].Products, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestListPVZ_WithDateFilter(t *testing.T) {
 	repo, mock, cleanup := setupPVZRepoTest(t)
 	defer cleanup()
@@ -257,6 +563,77 @@ func TestListPVZ_WithDateFilter(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestListPVZ_DateBoundsAppliedIndependently(t *testing.T) {
+	startDate := time.Now().AddDate(0, -1, 0)
+	endDate := time.Now()
+
+	testCases := []struct {
+		name       string
+		startDate  time.Time
+		endDate    time.Time
+		listWhere  string
+		listArgs   []driver.Value
+		receptions string
+	}{
+		{
+			name:       "Start Date Only",
+			startDate:  startDate,
+			listWhere:  `WHERE \(r\.date_time >= \$1\) ORDER BY`,
+			listArgs:   []driver.Value{startDate},
+			receptions: `WHERE \(pvz_id = \$1 AND date_time >= \$2\) ORDER BY`,
+		},
+		{
+			name:       "End Date Only",
+			endDate:    endDate,
+			listWhere:  `WHERE \(r\.date_time <= \$1\) ORDER BY`,
+			listArgs:   []driver.Value{endDate},
+			receptions: `WHERE \(pvz_id = \$1 AND date_time <= \$2\) ORDER BY`,
+		},
+		{
+			name:       "Both Dates",
+			startDate:  startDate,
+			endDate:    endDate,
+			listWhere:  `WHERE \(r\.date_time >= \$1 AND r\.date_time <= \$2\) ORDER BY`,
+			listArgs:   []driver.Value{startDate, endDate},
+			receptions: `WHERE \(pvz_id = \$1 AND date_time >= \$2 AND date_time <= \$3\) ORDER BY`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock, cleanup := setupPVZRepoTest(t)
+			defer cleanup()
+
+			pvzID := uuid.New()
+
+			mock.ExpectBegin()
+			mock.ExpectQuery(`SELECT DISTINCT (.+) JOIN receptions r ON p\.id = r\.pvz_id ` + tc.listWhere).
+				WithArgs(tc.listArgs...).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}).
+					AddRow(pvzID, time.Now(), "Москва"))
+			mock.ExpectQuery(`SELECT (.+) FROM receptions ` + tc.receptions).
+				WithArgs(append([]driver.Value{pvzID}, tc.listArgs...)...).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status"}))
+			mock.ExpectQuery(`SELECT COUNT\(DISTINCT p\.id\) (.+) WHERE`).
+				WithArgs(tc.listArgs...).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+			mock.ExpectCommit()
+
+			pvzs, total, err := repo.ListPVZ(createTestContext(), models.PVZListOptions{
+				Page:      1,
+				Limit:     10,
+				StartDate: tc.startDate,
+				EndDate:   tc.endDate,
+			})
+
+			require.NoError(t, err)
+			assert.Len(t, pvzs, 1)
+			assert.Equal(t, 1, total)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
 func TestListPVZ_WithNegativePageAndLimit(t *testing.T) {
 	repo, mock, cleanup := setupPVZRepoTest(t)
 	defer cleanup()
@@ -451,3 +828,500 @@ func TestListPVZ_CommitError(t *testing.T) {
 
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestListPVZ_WithRegistrationDateFilter(t *testing.T) {
+	repo, mock, cleanup := setupPVZRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	registeredFrom := time.Now().AddDate(0, -6, 0)
+	registeredTo := time.Now()
+
+	options := models.PVZListOptions{
+		Page:           1,
+		Limit:          10,
+		RegisteredFrom: registeredFrom,
+		RegisteredTo:   registeredTo,
+	}
+
+	pvzID := uuid.New()
+
+	mock.ExpectBegin()
+
+	mock.ExpectQuery("SELECT id, registration_date, city FROM pvz WHERE \\(registration_date >= \\$1 AND registration_date <= \\$2\\)").
+		WithArgs(registeredFrom, registeredTo).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}).
+			AddRow(pvzID, time.Now().AddDate(0, -1, 0), "Москва"))
+
+	mock.ExpectQuery("SELECT (.+) FROM receptions").
+		WithArgs(pvzID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status"}))
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM pvz WHERE \\(registration_date >= \\$1 AND registration_date <= \\$2\\)").
+		WithArgs(registeredFrom, registeredTo).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	mock.ExpectCommit()
+
+	pvzs, total, err := repo.ListPVZ(ctx, options)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(pvzs))
+	assert.Equal(t, 1, total)
+	assert.Equal(t, pvzID, pvzs[0].PVZ.ID)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListPVZ_WithRegistrationAndReceptionDateFilters(t *testing.T) {
+	repo, mock, cleanup := setupPVZRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	startDate := time.Now().AddDate(0, -1, 0)
+	endDate := time.Now()
+	registeredFrom := time.Now().AddDate(-1, 0, 0)
+
+	options := models.PVZListOptions{
+		Page:           1,
+		Limit:          10,
+		StartDate:      startDate,
+		EndDate:        endDate,
+		RegisteredFrom: registeredFrom,
+	}
+
+	mock.ExpectBegin()
+
+	mock.ExpectQuery("SELECT DISTINCT (.+) WHERE \\(r.date_time >= \\$1 AND r.date_time <= \\$2\\) AND \\(p.registration_date >= \\$3\\)").
+		WithArgs(startDate, endDate, registeredFrom).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}))
+
+	mock.ExpectQuery("SELECT COUNT\\(DISTINCT p.id\\) (.+) AND \\(p.registration_date >= \\$3\\)").
+		WithArgs(startDate, endDate, registeredFrom).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	mock.ExpectCommit()
+
+	pvzs, total, err := repo.ListPVZ(ctx, options)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(pvzs))
+	assert.Equal(t, 0, total)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListPVZ_OnlyOpen(t *testing.T) {
+	repo, mock, cleanup := setupPVZRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	options := models.PVZListOptions{
+		Page:     1,
+		Limit:    10,
+		OnlyOpen: true,
+	}
+
+	pvzID := uuid.New()
+
+	mock.ExpectBegin()
+
+	mock.ExpectQuery("SELECT id, registration_date, city FROM pvz WHERE EXISTS \\(SELECT 1 FROM receptions open_r WHERE open_r.pvz_id = pvz.id AND open_r.status = \\$1\\)").
+		WithArgs(models.StatusInProgress).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}).
+			AddRow(pvzID, time.Now().AddDate(0, -1, 0), "Москва"))
+
+	mock.ExpectQuery("SELECT (.+) FROM receptions").
+		WithArgs(pvzID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status"}))
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM pvz WHERE EXISTS (.+) AND open_r.status = \\$1\\)").
+		WithArgs(models.StatusInProgress).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	mock.ExpectCommit()
+
+	pvzs, total, err := repo.ListPVZ(ctx, options)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(pvzs))
+	assert.Equal(t, 1, total)
+	assert.Equal(t, pvzID, pvzs[0].PVZ.ID)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListPVZ_OnlyOpenWithOtherFilters(t *testing.T) {
+	repo, mock, cleanup := setupPVZRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	startDate := time.Now().AddDate(0, -1, 0)
+	endDate := time.Now()
+	registeredFrom := time.Now().AddDate(-1, 0, 0)
+
+	options := models.PVZListOptions{
+		Page:           1,
+		Limit:          10,
+		StartDate:      startDate,
+		EndDate:        endDate,
+		RegisteredFrom: registeredFrom,
+		OnlyOpen:       true,
+	}
+
+	mock.ExpectBegin()
+
+	mock.ExpectQuery("SELECT DISTINCT (.+) WHERE \\(r.date_time >= \\$1 AND r.date_time <= \\$2\\) AND \\(p.registration_date >= \\$3\\) AND EXISTS \\(SELECT 1 FROM receptions open_r WHERE open_r.pvz_id = p.id AND open_r.status = \\$4\\)").
+		WithArgs(startDate, endDate, registeredFrom, models.StatusInProgress).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}))
+
+	mock.ExpectQuery("SELECT COUNT\\(DISTINCT p.id\\) (.+) AND \\(p.registration_date >= \\$3\\) AND EXISTS (.+) open_r.pvz_id = p.id AND open_r.status = \\$4\\)").
+		WithArgs(startDate, endDate, registeredFrom, models.StatusInProgress).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	mock.ExpectCommit()
+
+	pvzs, total, err := repo.ListPVZ(ctx, options)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(pvzs))
+	assert.Equal(t, 0, total)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListPVZ_Sort(t *testing.T) {
+	testCases := []struct {
+		name            string
+		sortBy          string
+		sortDesc        bool
+		expectedOrderBy string
+	}{
+		{
+			name:            "default",
+			expectedOrderBy: "ORDER BY id LIMIT",
+		},
+		{
+			name:            "registrationDate ascending",
+			sortBy:          models.PVZSortRegistrationDate,
+			expectedOrderBy: "ORDER BY registration_date ASC, id LIMIT",
+		},
+		{
+			name:            "registrationDate descending",
+			sortBy:          models.PVZSortRegistrationDate,
+			sortDesc:        true,
+			expectedOrderBy: "ORDER BY registration_date DESC, id LIMIT",
+		},
+		{
+			name:            "city",
+			sortBy:          models.PVZSortCity,
+			expectedOrderBy: "ORDER BY city ASC, id LIMIT",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock, cleanup := setupPVZRepoTest(t)
+			defer cleanup()
+
+			ctx := createTestContext()
+			options := models.PVZListOptions{
+				Page:     1,
+				Limit:    10,
+				SortBy:   tc.sortBy,
+				SortDesc: tc.sortDesc,
+			}
+
+			mock.ExpectBegin()
+
+			mock.ExpectQuery("SELECT (.+) FROM pvz " + regexp.QuoteMeta(tc.expectedOrderBy)).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}))
+
+			mock.ExpectQuery("SELECT COUNT").
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+			mock.ExpectCommit()
+
+			_, _, err := repo.ListPVZ(ctx, options)
+
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestListPVZ_SortWithDateFilterUsesAlias(t *testing.T) {
+	repo, mock, cleanup := setupPVZRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	startDate := time.Now().AddDate(0, -1, 0)
+	endDate := time.Now()
+
+	options := models.PVZListOptions{
+		Page:      1,
+		Limit:     10,
+		StartDate: startDate,
+		EndDate:   endDate,
+		SortBy:    models.PVZSortCity,
+		SortDesc:  true,
+	}
+
+	mock.ExpectBegin()
+
+	mock.ExpectQuery("SELECT DISTINCT (.+) "+regexp.QuoteMeta("ORDER BY p.city DESC, p.id LIMIT")).
+		WithArgs(startDate, endDate).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}))
+
+	mock.ExpectQuery("SELECT COUNT").
+		WithArgs(startDate, endDate).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	mock.ExpectCommit()
+
+	_, _, err := repo.ListPVZ(ctx, options)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListPVZ_UnknownSortRejected(t *testing.T) {
+	repo, mock, cleanup := setupPVZRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	options := models.PVZListOptions{
+		Page:   1,
+		Limit:  10,
+		SortBy: "id; DROP TABLE pvz",
+	}
+
+	pvzs, total, err := repo.ListPVZ(ctx, options)
+
+	assert.Error(t, err)
+	assert.Nil(t, pvzs)
+	assert.Equal(t, 0, total)
+	assert.Contains(t, err.Error(), "unsupported sort field")
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListPVZ_SkipTotalHasNext(t *testing.T) {
+	testCases := []struct {
+		name            string
+		rows            int
+		expectedHasNext bool
+	}{
+		{name: "Extra Row Means Next Page", rows: 2, expectedHasNext: true},
+		{name: "Last Page", rows: 1, expectedHasNext: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock, cleanup := setupPVZRepoTest(t)
+			defer cleanup()
+
+			firstID := uuid.New()
+			rows := sqlmock.NewRows([]string{"id", "registration_date", "city"}).
+				AddRow(firstID, time.Now(), "Москва")
+			if tc.rows > 1 {
+				rows.AddRow(uuid.New(), time.Now(), "Казань")
+			}
+
+			mock.ExpectBegin()
+			mock.ExpectQuery(regexp.QuoteMeta("LIMIT 2 OFFSET 0")).
+				WillReturnRows(rows)
+			// Приемки загружаются только для строк страницы, не для лишней строки
+			mock.ExpectQuery("SELECT (.+) FROM receptions").
+				WithArgs(firstID).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status"}))
+			mock.ExpectCommit()
+
+			options := models.PVZListOptions{Page: 1, Limit: 1, SkipTotal: true}
+			pvzs, total, err := repo.ListPVZ(createTestContext(), options)
+
+			require.NoError(t, err)
+			require.Len(t, pvzs, 1)
+			assert.Equal(t, firstID, pvzs[0].PVZ.ID)
+			assert.Equal(t, tc.expectedHasNext, models.HasNextPage(options.Page, options.Limit, total))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCountPVZByCity(t *testing.T) {
+	repo, mock, cleanup := setupPVZRepoTest(t)
+	defer cleanup()
+
+	mock.ExpectQuery("^" + regexp.QuoteMeta("SELECT city, COUNT(*) FROM pvz GROUP BY city ORDER BY city") + "$").
+		WithoutArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"city", "count"}).
+			AddRow("Казань", 2).
+			AddRow("Москва", 5))
+
+	cities, err := repo.CountPVZByCity(createTestContext())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.CityPVZCount{
+		{City: "Казань", Count: 2},
+		{City: "Москва", Count: 5},
+	}, cities)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCountPVZByCity_Empty(t *testing.T) {
+	repo, mock, cleanup := setupPVZRepoTest(t)
+	defer cleanup()
+
+	mock.ExpectQuery("SELECT city, COUNT").
+		WillReturnRows(sqlmock.NewRows([]string{"city", "count"}))
+
+	cities, err := repo.CountPVZByCity(createTestContext())
+
+	assert.NoError(t, err)
+	assert.NotNil(t, cities)
+	assert.Empty(t, cities)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCountPVZByCity_QueryError(t *testing.T) {
+	repo, mock, cleanup := setupPVZRepoTest(t)
+	defer cleanup()
+
+	mock.ExpectQuery("SELECT city, COUNT").
+		WillReturnError(sql.ErrConnDone)
+
+	cities, err := repo.CountPVZByCity(createTestContext())
+
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+	assert.Nil(t, cities)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStreamPVZ_Batches(t *testing.T) {
+	repo, mock, cleanup := setupPVZRepoTest(t)
+	defer cleanup()
+	repo.exportBatchSize = 2
+
+	columns := []string{"id", "registration_date", "city", "external_id"}
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	dates := []time.Time{
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+	}
+
+	mock.ExpectQuery("^" + regexp.QuoteMeta("SELECT id, registration_date, city, external_id FROM pvz ORDER BY registration_date, id LIMIT 2") + "$").
+		WithoutArgs().
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(ids[0], dates[0], "Москва", nil).
+			AddRow(ids[1], dates[1], "Казань", "ext-2"))
+	mock.ExpectQuery("^"+regexp.QuoteMeta("SELECT id, registration_date, city, external_id FROM pvz WHERE (registration_date, id) > ($1, $2) ORDER BY registration_date, id LIMIT 2")+"$").
+		WithArgs(dates[1], ids[1]).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(ids[2], dates[2], "Санкт-Петербург", nil))
+
+	var streamed []*models.PVZ
+	err := repo.StreamPVZ(createTestContext(), func(pvz *models.PVZ) error {
+		streamed = append(streamed, pvz)
+		return nil
+	})
+
+	require.NoError(t, err)
+	require.Len(t, streamed, 3)
+	for i, pvz := range streamed {
+		assert.Equal(t, ids[i], pvz.ID)
+		assert.Equal(t, dates[i], pvz.RegistrationDate)
+	}
+	assert.Nil(t, streamed[0].ExternalID)
+	require.NotNil(t, streamed[1].ExternalID)
+	assert.Equal(t, "ext-2", *streamed[1].ExternalID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStreamPVZ_FullLastBatchQueriesAgain(t *testing.T) {
+	repo, mock, cleanup := setupPVZRepoTest(t)
+	defer cleanup()
+	repo.exportBatchSize = 1
+
+	columns := []string{"id", "registration_date", "city", "external_id"}
+	id := uuid.New()
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT id, registration_date, city, external_id FROM pvz ORDER BY").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(id, date, "Москва", nil))
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE (registration_date, id) > ($1, $2)")).
+		WithArgs(date, id).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	count := 0
+	err := repo.StreamPVZ(createTestContext(), func(pvz *models.PVZ) error {
+		count++
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStreamPVZ_CallbackErrorStopsScan(t *testing.T) {
+	repo, mock, cleanup := setupPVZRepoTest(t)
+	defer cleanup()
+	repo.exportBatchSize = 2
+
+	columns := []string{"id", "registration_date", "city", "external_id"}
+	mock.ExpectQuery("SELECT id, registration_date, city, external_id FROM pvz").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(uuid.New(), time.Now(), "Москва", nil).
+			AddRow(uuid.New(), time.Now(), "Казань", nil))
+
+	writeErr := errors.New("client gone")
+	calls := 0
+	err := repo.StreamPVZ(createTestContext(), func(pvz *models.PVZ) error {
+		calls++
+		return writeErr
+	})
+
+	assert.ErrorIs(t, err, writeErr)
+	assert.Equal(t, 1, calls)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStreamPVZ_QueryError(t *testing.T) {
+	repo, mock, cleanup := setupPVZRepoTest(t)
+	defer cleanup()
+
+	mock.ExpectQuery("SELECT id, registration_date, city, external_id FROM pvz").
+		WillReturnError(sql.ErrConnDone)
+
+	err := repo.StreamPVZ(createTestContext(), func(pvz *models.PVZ) error {
+		return nil
+	})
+
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListPVZWithReceptionsBetween(t *testing.T) {
+	repo, mock, cleanup := setupPVZRepoTest(t)
+	defer cleanup()
+
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	pvzID := uuid.New()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, registration_date, city, external_id FROM pvz "+
+		"WHERE EXISTS (SELECT 1 FROM receptions r WHERE r.pvz_id = pvz.id AND r.date_time >= $1 AND r.date_time < $2) "+
+		"ORDER BY registration_date, id")).
+		WithArgs(from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city", "external_id"}).
+			AddRow(pvzID, from, "Москва", nil))
+
+	pvzs, err := repo.ListPVZWithReceptionsBetween(createTestContext(), from, to)
+
+	require.NoError(t, err)
+	require.Len(t, pvzs, 1)
+	assert.Equal(t, pvzID, pvzs[0].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
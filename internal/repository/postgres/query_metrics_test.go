@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/domain/models"
+	"pvz-service/internal/logger"
+)
+
+// dbQueryObservations возвращает число наблюдений db_query_duration_seconds для операции
+func dbQueryObservations(t *testing.T, operation string) uint64 {
+	return histogramObservations(t, "db_query_duration_seconds", "operation", operation)
+}
+
+// histogramObservations возвращает число наблюдений гистограммы name с меткой labelName=labelValue
+func histogramObservations(t *testing.T, name, labelName, labelValue string) uint64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == labelName && label.GetValue() == labelValue {
+					return metric.GetHistogram().GetSampleCount()
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func TestDBQueryDuration_AddProductToOpenReception(t *testing.T) {
+	repo, mock, cleanup := setupProductRepoTest(t)
+	defer cleanup()
+
+	before := dbQueryObservations(t, "add_product_to_open_reception")
+
+	mock.ExpectBegin().WillReturnError(sql.ErrConnDone)
+
+	_, err := repo.AddProductToOpenReception(createTestContext(), models.TypeClothes, uuid.New(), 0)
+	assert.Error(t, err)
+
+	assert.Equal(t, before+1, dbQueryObservations(t, "add_product_to_open_reception"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDBQueryDuration_GetPVZByID(t *testing.T) {
+	repo, mock, cleanup := setupPVZRepoTest(t)
+	defer cleanup()
+
+	before := dbQueryObservations(t, "get_pvz_by_id")
+
+	mock.ExpectQuery("SELECT (.+) FROM pvz").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city", "external_id"}))
+
+	pvz, err := repo.GetPVZByID(createTestContext(), uuid.New())
+	assert.NoError(t, err)
+	assert.Nil(t, pvz)
+
+	assert.Equal(t, before+1, dbQueryObservations(t, "get_pvz_by_id"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDBStatementDuration_QueryThroughWrapper(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+	db := NewDB(sqlDB)
+
+	before := histogramObservations(t, "db_statement_duration_seconds", "kind", statementQuery)
+
+	mock.ExpectQuery("SELECT 1").
+		WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(1))
+
+	rows, err := db.QueryContext(createTestContext(), "SELECT 1")
+	require.NoError(t, err)
+	rows.Close()
+
+	assert.Equal(t, before+1, histogramObservations(t, "db_statement_duration_seconds", "kind", statementQuery))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDBStatementDuration_QueryInsideTransaction(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+	db := NewDB(sqlDB).WithSlowQueryThreshold(10 * time.Millisecond)
+
+	var buf bytes.Buffer
+	ctx := logger.WithLogger(context.Background(), logger.New(logger.Config{
+		Level:  logger.LevelDebug,
+		Format: "text",
+		Output: &buf,
+	}))
+
+	before := histogramObservations(t, "db_statement_duration_seconds", "kind", statementExec)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE products").
+		WillDelayFor(30 * time.Millisecond).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = withTx(ctx, db, nil, func(tx *Tx) error {
+		_, err := tx.ExecContext(ctx, "UPDATE products SET sequence_num = 1")
+		return err
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, before+1, histogramObservations(t, "db_statement_duration_seconds", "kind", statementExec))
+	assert.Contains(t, buf.String(), "медленный SQL запрос")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDB_SlowQueryWarning(t *testing.T) {
+	testCases := []struct {
+		name       string
+		delay      time.Duration
+		expectWarn bool
+	}{
+		{name: "Slow Query", delay: 30 * time.Millisecond, expectWarn: true},
+		{name: "Fast Query", expectWarn: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer sqlDB.Close()
+			db := NewDB(sqlDB).WithSlowQueryThreshold(10 * time.Millisecond)
+
+			var buf bytes.Buffer
+			ctx := logger.WithLogger(context.Background(), logger.New(logger.Config{
+				Level:  logger.LevelDebug,
+				Format: "text",
+				Output: &buf,
+			}))
+
+			mock.ExpectExec("DELETE FROM receptions").
+				WillDelayFor(tc.delay).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			_, err = db.ExecContext(ctx, "DELETE FROM receptions")
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expectWarn, strings.Contains(buf.String(), "медленный SQL запрос"))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -7,26 +7,51 @@ import (
 	"fmt"
 	"time"
 
+	"pvz-service/internal/domain/interfaces"
 	"pvz-service/internal/domain/models"
 	"pvz-service/internal/logger"
+	"pvz-service/internal/metrics"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
 )
 
 type ReceptionRepository struct {
-	db *sql.DB
+	db *DB
 	sb squirrel.StatementBuilderType
+
+	// replica - необязательная реплика для тяжелых списочных запросов
+	replica *DB
 }
 
-func NewReceptionRepository(db *sql.DB) *ReceptionRepository {
+var _ interfaces.ReceptionRepository = (*ReceptionRepository)(nil)
+
+func NewReceptionRepository(db *DB) *ReceptionRepository {
 	return &ReceptionRepository{
 		db: db,
 		sb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
 	}
 }
 
+// WithReplica направляет списочные запросы на реплику. nil оставляет их на основной базе
+func (r *ReceptionRepository) WithReplica(replica *DB) *ReceptionRepository {
+	r.replica = replica
+	return r
+}
+
+// reader возвращает базу для запросов только на чтение: реплику, если она задана
+func (r *ReceptionRepository) reader() *DB {
+	if r.replica != nil {
+		return r.replica
+	}
+	return r.db
+}
+
+// CreateReception открывает приемку в ПВЗ. Если открытая приемка уже есть (уникальный
+// индекс idx_receptions_pvz_open), возвращает models.ErrReceptionAlreadyOpen
 func (r *ReceptionRepository) CreateReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error) {
+	defer metrics.ObserveDBQuery("create_reception", time.Now())
+
 	log := logger.FromContext(ctx)
 	log.Debug("создание приемки", "pvz_id", pvzID)
 
@@ -41,9 +66,7 @@ func (r *ReceptionRepository) CreateReception(ctx context.Context, pvzID uuid.UU
 		return nil, fmt.Errorf("error building SQL: %w", err)
 	}
 
-	if log.Enabled(ctx, logger.LevelDebug) {
-		log.Debug("SQL запрос", "query", sqlQuery, "args", args)
-	}
+	logSQL(ctx, sqlQuery, args, "pvz_id", "status")
 
 	var reception models.Reception
 	err = r.db.QueryRowContext(ctx, sqlQuery, args...).Scan(
@@ -51,7 +74,16 @@ func (r *ReceptionRepository) CreateReception(ctx context.Context, pvzID uuid.UU
 	)
 
 	if err != nil {
-		log.Error("ошибка создания приемки в БД", "error", err, "pvz_id", pvzID)
+		if isForeignKeyViolation(err) {
+			log.Warn("ПВЗ для приемки не найден", "pvz_id", pvzID)
+			return nil, models.ErrPVZNotFound
+		}
+		if isUniqueViolation(err) {
+			// idx_receptions_pvz_open: в ПВЗ уже есть открытая приемка
+			log.Warn("в ПВЗ уже есть открытая приемка", "pvz_id", pvzID)
+			return nil, models.ErrReceptionAlreadyOpen
+		}
+		log.Error("ошибка создания приемки в БД", "error", err, sqlLogAttr(sqlQuery, args), "pvz_id", pvzID)
 		return nil, fmt.Errorf("error creating reception: %w", err)
 	}
 
@@ -65,6 +97,8 @@ func (r *ReceptionRepository) CreateReception(ctx context.Context, pvzID uuid.UU
 }
 
 func (r *ReceptionRepository) GetReceptionByID(ctx context.Context, id uuid.UUID) (*models.Reception, error) {
+	defer metrics.ObserveDBQuery("get_reception_by_id", time.Now())
+
 	log := logger.FromContext(ctx)
 	log.Debug("получение приемки по ID", "reception_id", id)
 
@@ -88,7 +122,7 @@ func (r *ReceptionRepository) GetReceptionByID(ctx context.Context, id uuid.UUID
 			log.Info("приемка не найдена", "reception_id", id)
 			return nil, nil
 		}
-		log.Error("ошибка получения приемки", "error", err, "reception_id", id)
+		log.Error("ошибка получения приемки", "error", err, sqlLogAttr(sqlQuery, args), "reception_id", id)
 		return nil, fmt.Errorf("error getting reception by id: %w", err)
 	}
 
@@ -102,6 +136,8 @@ func (r *ReceptionRepository) GetReceptionByID(ctx context.Context, id uuid.UUID
 }
 
 func (r *ReceptionRepository) GetLastOpenReceptionByPVZID(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error) {
+	defer metrics.ObserveDBQuery("get_last_open_reception_by_pvz_id", time.Now())
+
 	log := logger.FromContext(ctx)
 	log.Debug("получение последней открытой приемки для ПВЗ", "pvz_id", pvzID)
 
@@ -130,7 +166,7 @@ func (r *ReceptionRepository) GetLastOpenReceptionByPVZID(ctx context.Context, p
 			log.Info("открытая приемка не найдена для ПВЗ", "pvz_id", pvzID)
 			return nil, nil
 		}
-		log.Error("ошибка получения последней открытой приемки", "error", err, "pvz_id", pvzID)
+		log.Error("ошибка получения последней открытой приемки", "error", err, sqlLogAttr(sqlQuery, args), "pvz_id", pvzID)
 		return nil, fmt.Errorf("error getting last open reception: %w", err)
 	}
 
@@ -142,13 +178,20 @@ func (r *ReceptionRepository) GetLastOpenReceptionByPVZID(ctx context.Context, p
 	return &reception, nil
 }
 
+// CloseReception закрывает приемку, только если она еще открыта. Возвращает
+// models.ErrReceptionClosed, если ее уже закрыли (в том числе параллельным запросом) или удалили
 func (r *ReceptionRepository) CloseReception(ctx context.Context, id uuid.UUID) error {
+	defer metrics.ObserveDBQuery("close_reception", time.Now())
+
 	log := logger.FromContext(ctx)
 	log.Debug("закрытие приемки", "reception_id", id)
 
 	query := r.sb.Update("receptions").
 		Set("status", models.StatusClosed).
-		Where(squirrel.Eq{"id": id})
+		Where(squirrel.And{
+			squirrel.Eq{"id": id},
+			squirrel.Eq{"status": models.StatusInProgress},
+		})
 
 	sqlQuery, args, err := query.ToSql()
 	if err != nil {
@@ -158,32 +201,266 @@ func (r *ReceptionRepository) CloseReception(ctx context.Context, id uuid.UUID)
 
 	result, err := r.db.ExecContext(ctx, sqlQuery, args...)
 	if err != nil {
-		log.Error("ошибка закрытия приемки", "error", err, "reception_id", id)
+		log.Error("ошибка закрытия приемки", "error", err, sqlLogAttr(sqlQuery, args), "reception_id", id)
 		return fmt.Errorf("error closing reception: %w", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		log.Warn("не удалось получить количество затронутых строк", "error", err)
-	} else if rowsAffected == 0 {
-		log.Warn("приемка не найдена при закрытии", "reception_id", id)
-	} else {
-		log.Info("приемка успешно закрыта", "reception_id", id)
+		return fmt.Errorf("error getting rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		log.Warn("приемка уже закрыта или не найдена при закрытии", "reception_id", id)
+		return models.ErrReceptionClosed
 	}
 
+	log.Info("приемка успешно закрыта", "reception_id", id)
 	return nil
 }
 
-type ReceptionListOptions struct {
-	Page     int
-	Limit    int
-	PVZID    uuid.UUID
-	Status   string
-	FromDate time.Time
-	ToDate   time.Time
+// lastActivityColumn - момент последней активности приемки: touch или, если его не было, создание
+const lastActivityColumn = "COALESCE(last_activity_at, date_time)"
+
+// ListOpenReceptionsOlderThan возвращает открытые приемки, последняя активность в которых
+// была раньше указанного момента
+func (r *ReceptionRepository) ListOpenReceptionsOlderThan(ctx context.Context, t time.Time) ([]*models.Reception, error) {
+	defer metrics.ObserveDBQuery("list_open_receptions_older_than", time.Now())
+
+	log := logger.FromContext(ctx)
+	log.Debug("получение открытых приемок старше порога", "older_than", t.Format(time.RFC3339))
+
+	query := r.sb.Select("id", "date_time", "pvz_id", "status", "last_activity_at").
+		From("receptions").
+		Where(squirrel.And{
+			squirrel.Eq{"status": models.StatusInProgress},
+			squirrel.Lt{lastActivityColumn: t},
+		}).
+		OrderBy(lastActivityColumn)
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		log.Error("ошибка построения SQL", "error", err)
+		return nil, fmt.Errorf("error building SQL: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		log.Error("ошибка получения открытых приемок", "error", err, sqlLogAttr(sqlQuery, args))
+		return nil, fmt.Errorf("error querying open receptions: %w", err)
+	}
+	defer rows.Close()
+
+	var receptions []*models.Reception
+	for rows.Next() {
+		var reception models.Reception
+		var lastActivity sql.NullTime
+		if err := rows.Scan(&reception.ID, &reception.DateTime, &reception.PVZID, &reception.Status, &lastActivity); err != nil {
+			log.Error("ошибка сканирования строки приемки", "error", err)
+			return nil, fmt.Errorf("error scanning reception row: %w", err)
+		}
+		reception.LastActivityAt = nullTimePtr(lastActivity)
+		receptions = append(receptions, &reception)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Error("ошибка при итерации по приемкам", "error", err)
+		return nil, fmt.Errorf("error iterating receptions: %w", err)
+	}
+
+	log.Debug("открытые приемки старше порога получены", "count", len(receptions))
+	return receptions, nil
+}
+
+// CloseStaleReception закрывает приемку, только если она все еще открыта и неактивна с
+// olderThan. Условия проверяются в самом UPDATE, поэтому touch, пришедший после выборки
+// кандидатов, не дает закрыть приемку. Возвращает false, если приемка не закрыта
+func (r *ReceptionRepository) CloseStaleReception(ctx context.Context, id uuid.UUID, olderThan time.Time) (bool, error) {
+	defer metrics.ObserveDBQuery("close_stale_reception", time.Now())
+
+	log := logger.FromContext(ctx)
+	log.Debug("автозакрытие приемки", "reception_id", id, "older_than", olderThan.Format(time.RFC3339))
+
+	query := r.sb.Update("receptions").
+		Set("status", models.StatusClosed).
+		Where(squirrel.And{
+			squirrel.Eq{"id": id},
+			squirrel.Eq{"status": models.StatusInProgress},
+			squirrel.Lt{lastActivityColumn: olderThan},
+		})
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		log.Error("ошибка построения SQL", "error", err, "reception_id", id)
+		return false, fmt.Errorf("error building SQL: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		log.Error("ошибка автозакрытия приемки", "error", err, sqlLogAttr(sqlQuery, args), "reception_id", id)
+		return false, fmt.Errorf("error closing stale reception: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error getting rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// TouchOpenReception отмечает активность в открытой приемке ПВЗ, откладывая ее
+// автозакрытие. Возвращает nil, если открытой приемки нет
+func (r *ReceptionRepository) TouchOpenReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error) {
+	defer metrics.ObserveDBQuery("touch_open_reception", time.Now())
+
+	log := logger.FromContext(ctx)
+	log.Debug("отметка активности в открытой приемке", "pvz_id", pvzID)
+
+	query := r.sb.Update("receptions").
+		Set("last_activity_at", squirrel.Expr("NOW()")).
+		Where(squirrel.And{
+			squirrel.Eq{"pvz_id": pvzID},
+			squirrel.Eq{"status": models.StatusInProgress},
+		}).
+		Suffix("RETURNING id, date_time, pvz_id, status, last_activity_at")
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		log.Error("ошибка построения SQL", "error", err)
+		return nil, fmt.Errorf("error building SQL: %w", err)
+	}
+
+	var reception models.Reception
+	var lastActivity sql.NullTime
+	err = r.db.QueryRowContext(ctx, sqlQuery, args...).
+		Scan(&reception.ID, &reception.DateTime, &reception.PVZID, &reception.Status, &lastActivity)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Debug("открытая приемка для отметки активности не найдена", "pvz_id", pvzID)
+			return nil, nil
+		}
+		log.Error("ошибка отметки активности в приемке", "pvz_id", pvzID, "error", err, sqlLogAttr(sqlQuery, args))
+		return nil, fmt.Errorf("error touching open reception: %w", err)
+	}
+	reception.LastActivityAt = nullTimePtr(lastActivity)
+
+	log.Debug("активность в приемке отмечена", "reception_id", reception.ID)
+	return &reception, nil
+}
+
+// PurgeReceptions удаляет закрытые приемки старше before вместе с их товарами и
+// возвращает число удаленных приемок. Товары удаляются явно в той же транзакции,
+// чтобы результат не зависел от ON DELETE CASCADE. Открытые приемки не затрагиваются
+func (r *ReceptionRepository) PurgeReceptions(ctx context.Context, before time.Time) (int, error) {
+	defer metrics.ObserveDBQuery("purge_receptions", time.Now())
+
+	log := logger.FromContext(ctx)
+	log.Debug("удаление старых закрытых приемок", "before", before.Format(time.RFC3339))
+
+	purgeable := squirrel.And{
+		squirrel.Eq{"status": models.StatusClosed},
+		squirrel.Lt{"date_time": before},
+	}
+
+	receptionIDs := r.sb.Select("id").From("receptions").Where(purgeable)
+	productsSql, productsArgs, err := r.sb.Delete("products").
+		Where(receptionIDs.Prefix("reception_id IN (").Suffix(")")).
+		ToSql()
+	if err != nil {
+		log.Error("ошибка построения SQL", "error", err)
+		return 0, fmt.Errorf("error building SQL: %w", err)
+	}
+
+	receptionsSql, receptionsArgs, err := r.sb.Delete("receptions").Where(purgeable).ToSql()
+	if err != nil {
+		log.Error("ошибка построения SQL", "error", err)
+		return 0, fmt.Errorf("error building SQL: %w", err)
+	}
+
+	var productsDeleted, receptionsDeleted int64
+	err = withTx(ctx, r.db, nil, func(tx *Tx) error {
+		result, err := tx.ExecContext(ctx, productsSql, productsArgs...)
+		if err != nil {
+			return fmt.Errorf("error deleting products: %w", err)
+		}
+		if productsDeleted, err = result.RowsAffected(); err != nil {
+			return fmt.Errorf("error getting rows affected: %w", err)
+		}
+
+		result, err = tx.ExecContext(ctx, receptionsSql, receptionsArgs...)
+		if err != nil {
+			return fmt.Errorf("error deleting receptions: %w", err)
+		}
+		if receptionsDeleted, err = result.RowsAffected(); err != nil {
+			return fmt.Errorf("error getting rows affected: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error("ошибка удаления старых приемок", "error", err)
+		return 0, err
+	}
+
+	log.Info("старые закрытые приемки удалены",
+		"before", before.Format(time.RFC3339),
+		"receptions", receptionsDeleted,
+		"products", productsDeleted,
+	)
+	return int(receptionsDeleted), nil
+}
+
+// CountReceptionsByStatus считает приемки по статусам одним сгруппированным запросом.
+// Если pvzID не nil, учитываются только приемки этого ПВЗ. Статусы без приемок
+// в результат не попадают
+func (r *ReceptionRepository) CountReceptionsByStatus(ctx context.Context, pvzID *uuid.UUID) (map[string]int, error) {
+	defer metrics.ObserveDBQuery("count_receptions_by_status", time.Now())
+
+	log := logger.FromContext(ctx)
+	log.Debug("подсчет приемок по статусам", "pvz_id", pvzID)
+
+	query := r.sb.Select("status", "COUNT(*)").
+		From("receptions").
+		GroupBy("status")
+	if pvzID != nil {
+		query = query.Where(squirrel.Eq{"pvz_id": *pvzID})
+	}
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		log.Error("ошибка построения SQL", "error", err)
+		return nil, fmt.Errorf("error building SQL: %w", err)
+	}
+
+	rows, err := r.reader().QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		log.Error("ошибка подсчета приемок по статусам", "error", err, sqlLogAttr(sqlQuery, args), "pvz_id", pvzID)
+		return nil, fmt.Errorf("error counting receptions by status: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			log.Error("ошибка сканирования результата", "error", err)
+			return nil, fmt.Errorf("error scanning reception counts: %w", err)
+		}
+		counts[status] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Error("ошибка при итерации по результатам", "error", err)
+		return nil, fmt.Errorf("error iterating reception counts: %w", err)
+	}
+
+	log.Debug("подсчет приемок по статусам завершен", "pvz_id", pvzID, "statuses", len(counts))
+	return counts, nil
 }
 
-func (r *ReceptionRepository) ListReceptions(ctx context.Context, options ReceptionListOptions) ([]*models.Reception, int, error) {
+func (r *ReceptionRepository) ListReceptions(ctx context.Context, options models.ReceptionListOptions) ([]*models.Reception, int, error) {
+	defer metrics.ObserveDBQuery("list_receptions", time.Now())
+
 	log := logger.FromContext(ctx)
 	log.Debug("получение списка приемок",
 		"page", options.Page,
@@ -192,23 +469,29 @@ func (r *ReceptionRepository) ListReceptions(ctx context.Context, options Recept
 		"status", options.Status,
 		"has_from_date", !options.FromDate.IsZero(),
 		"has_to_date", !options.ToDate.IsZero(),
+		"contains_type", options.ContainsType,
+		"skip_total", options.SkipTotal,
 	)
 
-	if options.Limit <= 0 {
-		options.Limit = 10
-		log.Debug("установлено значение limit по умолчанию", "limit", options.Limit)
-	}
-	if options.Page <= 0 {
-		options.Page = 1
-		log.Debug("установлено значение page по умолчанию", "page", options.Page)
+	var offset int
+	options.Page, options.Limit, offset = normalizePagination(ctx, options.Page, options.Limit)
+
+	// Без COUNT лишняя строка показывает, есть ли следующая страница
+	fetchLimit := options.Limit
+	if options.SkipTotal {
+		fetchLimit++
 	}
 
-	offset := (options.Page - 1) * options.Limit
+	orderBy, err := receptionOrderBy(options.SortBy, options.SortOrder)
+	if err != nil {
+		log.Warn("недопустимые параметры сортировки", "sort_by", options.SortBy, "sort_order", options.SortOrder)
+		return nil, 0, err
+	}
 
 	builder := r.sb.Select("id", "date_time", "pvz_id", "status").
 		From("receptions").
-		OrderBy("date_time DESC").
-		Limit(uint64(options.Limit)).
+		OrderBy(orderBy...).
+		Limit(uint64(fetchLimit)).
 		Offset(uint64(offset))
 
 	countBuilder := r.sb.Select("COUNT(*)").
@@ -236,6 +519,16 @@ func (r *ReceptionRepository) ListReceptions(ctx context.Context, options Recept
 		log.Debug("добавлен фильтр по конечной дате", "to_date", options.ToDate.Format(time.RFC3339))
 	}
 
+	if options.ContainsType != "" {
+		// Полусоединение с products: каждая приемка попадает в выборку один раз,
+		// сколько бы подходящих товаров в ней ни было
+		whereBuilder = append(whereBuilder, squirrel.Expr(
+			"EXISTS (SELECT 1 FROM products p WHERE p.reception_id = receptions.id AND p.type = ?)",
+			options.ContainsType,
+		))
+		log.Debug("добавлен фильтр по типу товара", "contains_type", options.ContainsType)
+	}
+
 	if len(whereBuilder) > 0 {
 		builder = builder.Where(whereBuilder)
 		countBuilder = countBuilder.Where(whereBuilder)
@@ -251,9 +544,9 @@ func (r *ReceptionRepository) ListReceptions(ctx context.Context, options Recept
 		log.Debug("SQL запрос для списка приемок", "query", sqlQuery)
 	}
 
-	rows, err := r.db.QueryContext(ctx, sqlQuery, args...)
+	rows, err := r.reader().QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
-		log.Error("ошибка выполнения запроса списка приемок", "error", err)
+		log.Error("ошибка выполнения запроса списка приемок", "error", err, sqlLogAttr(sqlQuery, args))
 		return nil, 0, fmt.Errorf("error querying receptions: %w", err)
 	}
 	defer rows.Close()
@@ -268,6 +561,18 @@ func (r *ReceptionRepository) ListReceptions(ctx context.Context, options Recept
 		receptions = append(receptions, &reception)
 	}
 
+	if options.SkipTotal {
+		total := offset + len(receptions)
+		if len(receptions) > options.Limit {
+			receptions = receptions[:options.Limit]
+		}
+		log.Info("список приемок успешно получен без подсчета",
+			"count", len(receptions),
+			"has_next", models.HasNextPage(options.Page, options.Limit, total),
+		)
+		return receptions, total, nil
+	}
+
 	countSql, countArgs, err := countBuilder.ToSql()
 	if err != nil {
 		log.Error("ошибка построения SQL для подсчета", "error", err)
@@ -275,9 +580,9 @@ func (r *ReceptionRepository) ListReceptions(ctx context.Context, options Recept
 	}
 
 	var total int
-	err = r.db.QueryRowContext(ctx, countSql, countArgs...).Scan(&total)
+	err = r.reader().QueryRowContext(ctx, countSql, countArgs...).Scan(&total)
 	if err != nil {
-		log.Error("ошибка подсчета общего количества приемок", "error", err)
+		log.Error("ошибка подсчета общего количества приемок", "error", err, sqlLogAttr(countSql, countArgs))
 		return nil, 0, fmt.Errorf("error counting total receptions: %w", err)
 	}
 
@@ -290,24 +595,11 @@ func (r *ReceptionRepository) ListReceptions(ctx context.Context, options Recept
 }
 
 func (r *ReceptionRepository) GetReceptionWithProducts(ctx context.Context, id uuid.UUID) (*models.Reception, error) {
+	defer metrics.ObserveDBQuery("get_reception_with_products", time.Now())
+
 	log := logger.FromContext(ctx)
 	log.Debug("получение приемки с товарами", "reception_id", id)
 
-	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{
-		ReadOnly: true,
-	})
-	if err != nil {
-		log.Error("ошибка начала транзакции", "error", err)
-		return nil, fmt.Errorf("error starting transaction: %w", err)
-	}
-
-	defer func() {
-		if err != nil {
-			log.Debug("откат транзакции из-за ошибки")
-			tx.Rollback()
-		}
-	}()
-
 	receptionQuery := r.sb.Select("id", "date_time", "pvz_id", "status").
 		From("receptions").
 		Where(squirrel.Eq{"id": id})
@@ -318,20 +610,6 @@ func (r *ReceptionRepository) GetReceptionWithProducts(ctx context.Context, id u
 		return nil, fmt.Errorf("error building reception SQL: %w", err)
 	}
 
-	var reception models.Reception
-	err = tx.QueryRowContext(ctx, receptionSql, receptionArgs...).Scan(
-		&reception.ID, &reception.DateTime, &reception.PVZID, &reception.Status,
-	)
-
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			log.Info("приемка не найдена", "reception_id", id)
-			return nil, nil
-		}
-		log.Error("ошибка получения приемки", "error", err, "reception_id", id)
-		return nil, fmt.Errorf("error getting reception by id: %w", err)
-	}
-
 	productsQuery := r.sb.Select("id", "date_time", "type", "reception_id", "sequence_num").
 		From("products").
 		Where(squirrel.Eq{"reception_id": id}).
@@ -343,26 +621,45 @@ func (r *ReceptionRepository) GetReceptionWithProducts(ctx context.Context, id u
 		return nil, fmt.Errorf("error building products SQL: %w", err)
 	}
 
-	rows, err := tx.QueryContext(ctx, productsSql, productsArgs...)
-	if err != nil {
-		log.Error("ошибка получения товаров для приемки", "error", err, "reception_id", id)
-		return nil, fmt.Errorf("error querying products for reception: %w", err)
-	}
-	defer rows.Close()
-
+	var reception models.Reception
 	var products []*models.Product
-	for rows.Next() {
-		var product models.Product
-		if err := rows.Scan(&product.ID, &product.DateTime, &product.Type, &product.ReceptionID, &product.SequenceNum); err != nil {
-			log.Error("ошибка сканирования строки товара", "error", err)
-			return nil, fmt.Errorf("error scanning product row: %w", err)
+
+	err = withTx(ctx, r.db, &sql.TxOptions{ReadOnly: true}, func(tx *Tx) error {
+		err := tx.QueryRowContext(ctx, receptionSql, receptionArgs...).Scan(
+			&reception.ID, &reception.DateTime, &reception.PVZID, &reception.Status,
+		)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+			log.Error("ошибка получения приемки", "error", err, sqlLogAttr(receptionSql, receptionArgs), "reception_id", id)
+			return fmt.Errorf("error getting reception by id: %w", err)
 		}
-		products = append(products, &product)
-	}
 
-	if err = tx.Commit(); err != nil {
-		log.Error("ошибка фиксации транзакции", "error", err)
-		return nil, fmt.Errorf("error committing transaction: %w", err)
+		rows, err := tx.QueryContext(ctx, productsSql, productsArgs...)
+		if err != nil {
+			log.Error("ошибка получения товаров для приемки", "error", err, sqlLogAttr(productsSql, productsArgs), "reception_id", id)
+			return fmt.Errorf("error querying products for reception: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var product models.Product
+			if err := rows.Scan(&product.ID, &product.DateTime, &product.Type, &product.ReceptionID, &product.SequenceNum); err != nil {
+				log.Error("ошибка сканирования строки товара", "error", err)
+				return fmt.Errorf("error scanning product row: %w", err)
+			}
+			products = append(products, &product)
+		}
+
+		return nil
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		log.Info("приемка не найдена", "reception_id", id)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
 	}
 
 	reception.Products = products
@@ -375,3 +672,172 @@ func (r *ReceptionRepository) GetReceptionWithProducts(ctx context.Context, id u
 
 	return &reception, nil
 }
+
+// ListReceptionIDsByPVZ возвращает ID приемок ПВЗ с date_time в полуинтервале [from, to)
+// от новых к старым, как их затем отдает GetReceptionsWithProducts
+func (r *ReceptionRepository) ListReceptionIDsByPVZ(ctx context.Context, pvzID uuid.UUID, from, to time.Time) ([]uuid.UUID, error) {
+	defer metrics.ObserveDBQuery("list_reception_ids_by_pvz", time.Now())
+
+	log := logger.FromContext(ctx)
+	log.Debug("получение ID приемок ПВЗ за период", "pvz_id", pvzID, "from", from, "to", to)
+
+	sqlQuery, args, err := r.sb.Select("id").
+		From("receptions").
+		Where(squirrel.Eq{"pvz_id": pvzID}).
+		Where(squirrel.GtOrEq{"date_time": from}).
+		Where(squirrel.Lt{"date_time": to}).
+		OrderBy("date_time DESC", "id DESC").
+		ToSql()
+	if err != nil {
+		log.Error("ошибка построения SQL", "error", err, "pvz_id", pvzID)
+		return nil, fmt.Errorf("error building SQL: %w", err)
+	}
+
+	rows, err := r.reader().QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		log.Error("ошибка получения ID приемок ПВЗ", "error", err, sqlLogAttr(sqlQuery, args), "pvz_id", pvzID)
+		return nil, fmt.Errorf("error listing reception ids: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			log.Error("ошибка сканирования ID приемки", "error", err, "pvz_id", pvzID)
+			return nil, fmt.Errorf("error scanning reception id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error("ошибка при итерации по ID приемок", "error", err, "pvz_id", pvzID)
+		return nil, fmt.Errorf("error iterating reception ids: %w", err)
+	}
+
+	log.Debug("ID приемок ПВЗ за период получены", "pvz_id", pvzID, "count", len(ids))
+	return ids, nil
+}
+
+// GetReceptionsWithProducts загружает приемки с указанными ID вместе с их товарами двумя
+// запросами в одной читающей транзакции: приемки по списку ID и товары всех найденных
+// приемок сразу. Приемки идут от новых к старым, товары внутри - по sequence_num.
+// Несуществующие ID пропускаются
+func (r *ReceptionRepository) GetReceptionsWithProducts(ctx context.Context, ids []uuid.UUID) ([]*models.Reception, error) {
+	defer metrics.ObserveDBQuery("get_receptions_with_products", time.Now())
+
+	log := logger.FromContext(ctx)
+	log.Debug("получение приемок с товарами по списку ID", "requested", len(ids))
+
+	receptions := []*models.Reception{}
+	if len(ids) == 0 {
+		return receptions, nil
+	}
+
+	receptionSql, receptionArgs, err := r.sb.Select("id", "date_time", "pvz_id", "status").
+		From("receptions").
+		Where(squirrel.Eq{"id": ids}).
+		OrderBy("date_time DESC", "id").
+		ToSql()
+	if err != nil {
+		log.Error("ошибка построения SQL для приемок", "error", err)
+		return nil, fmt.Errorf("error building receptions SQL: %w", err)
+	}
+
+	err = withTx(ctx, r.reader(), &sql.TxOptions{ReadOnly: true}, func(tx *Tx) error {
+		rows, err := tx.QueryContext(ctx, receptionSql, receptionArgs...)
+		if err != nil {
+			log.Error("ошибка получения приемок", "error", err, sqlLogAttr(receptionSql, receptionArgs))
+			return fmt.Errorf("error querying receptions: %w", err)
+		}
+		defer rows.Close()
+
+		byID := make(map[uuid.UUID]*models.Reception, len(ids))
+		foundIDs := make([]uuid.UUID, 0, len(ids))
+		for rows.Next() {
+			var reception models.Reception
+			if err := rows.Scan(&reception.ID, &reception.DateTime, &reception.PVZID, &reception.Status); err != nil {
+				log.Error("ошибка сканирования строки приемки", "error", err)
+				return fmt.Errorf("error scanning reception row: %w", err)
+			}
+			receptions = append(receptions, &reception)
+			byID[reception.ID] = &reception
+			foundIDs = append(foundIDs, reception.ID)
+		}
+		if err := rows.Err(); err != nil {
+			log.Error("ошибка при итерации по приемкам", "error", err)
+			return fmt.Errorf("error iterating receptions: %w", err)
+		}
+		if len(foundIDs) == 0 {
+			return nil
+		}
+
+		productsSql, productsArgs, err := r.sb.Select("id", "date_time", "type", "reception_id", "sequence_num").
+			From("products").
+			Where(squirrel.Eq{"reception_id": foundIDs}).
+			OrderBy("reception_id", "sequence_num").
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("error building products SQL: %w", err)
+		}
+
+		productRows, err := tx.QueryContext(ctx, productsSql, productsArgs...)
+		if err != nil {
+			log.Error("ошибка получения товаров для приемок", "error", err, sqlLogAttr(productsSql, productsArgs))
+			return fmt.Errorf("error querying products for receptions: %w", err)
+		}
+		defer productRows.Close()
+
+		for productRows.Next() {
+			var product models.Product
+			if err := productRows.Scan(&product.ID, &product.DateTime, &product.Type, &product.ReceptionID, &product.SequenceNum); err != nil {
+				log.Error("ошибка сканирования строки товара", "error", err)
+				return fmt.Errorf("error scanning product row: %w", err)
+			}
+			if reception, ok := byID[product.ReceptionID]; ok {
+				reception.Products = append(reception.Products, &product)
+			}
+		}
+		if err := productRows.Err(); err != nil {
+			log.Error("ошибка при итерации по товарам", "error", err)
+			return fmt.Errorf("error iterating products: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("приемки с товарами успешно получены", "requested", len(ids), "found", len(receptions))
+	return receptions, nil
+}
+
+// receptionOrderBy строит выражение ORDER BY только из разрешенных колонок и направлений.
+// Без параметров сохраняется прежний порядок date_time DESC
+func receptionOrderBy(sortBy, sortOrder string) ([]string, error) {
+	if sortBy == "" && sortOrder == "" {
+		return []string{"date_time DESC"}, nil
+	}
+
+	column := models.ReceptionSortDateTime
+	if sortBy != "" {
+		// Поля сортировки приемок совпадают с именами колонок, поэтому в SQL попадает
+		// только значение, прошедшее проверку по списку
+		if !models.AllowedReceptionSortFields[sortBy] {
+			return nil, fmt.Errorf("unsupported sort field: %q", sortBy)
+		}
+		column = sortBy
+	}
+
+	var direction string
+	switch sortOrder {
+	case "", models.SortOrderDesc:
+		direction = "DESC"
+	case models.SortOrderAsc:
+		direction = "ASC"
+	default:
+		return nil, fmt.Errorf("unsupported sort order: %q", sortOrder)
+	}
+
+	return []string{column + " " + direction, "id"}, nil
+}
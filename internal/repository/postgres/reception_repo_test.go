@@ -2,13 +2,18 @@ package postgres
 
 import (
 	"database/sql"
+	"database/sql/driver"
 	"errors"
+	"fmt"
+	"math"
+	"regexp"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -20,7 +25,7 @@ func setupReceptionRepoTest(t *testing.T) (*ReceptionRepository, sqlmock.Sqlmock
 	require.NoError(t, err)
 
 	repo := &ReceptionRepository{
-		db: db,
+		db: NewDB(db),
 		sb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
 	}
 
@@ -57,6 +62,44 @@ func TestCreateReception(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestCreateReception_PVZForeignKeyViolation(t *testing.T) {
+	repo, mock, cleanup := setupReceptionRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	pvzID := uuid.New()
+
+	mock.ExpectQuery("INSERT INTO receptions").
+		WithArgs(pvzID, models.StatusInProgress).
+		WillReturnError(&pq.Error{Code: "23503", Constraint: "receptions_pvz_id_fkey"})
+
+	reception, err := repo.CreateReception(ctx, pvzID)
+
+	assert.ErrorIs(t, err, models.ErrPVZNotFound)
+	assert.Nil(t, reception)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateReception_OpenReceptionExists(t *testing.T) {
+	repo, mock, cleanup := setupReceptionRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	pvzID := uuid.New()
+
+	mock.ExpectQuery("INSERT INTO receptions").
+		WithArgs(pvzID, models.StatusInProgress).
+		WillReturnError(&pq.Error{Code: "23505", Constraint: "idx_receptions_pvz_open"})
+
+	reception, err := repo.CreateReception(ctx, pvzID)
+
+	assert.ErrorIs(t, err, models.ErrReceptionAlreadyOpen)
+	assert.Nil(t, reception)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestCreateReception_SQLError(t *testing.T) {
 	repo, mock, cleanup := setupReceptionRepoTest(t)
 	defer cleanup()
@@ -177,7 +220,7 @@ func TestCloseReception(t *testing.T) {
 	result := sqlmock.NewResult(0, 1)
 
 	mock.ExpectExec("UPDATE receptions").
-		WithArgs(models.StatusClosed, receptionID).
+		WithArgs(models.StatusClosed, receptionID, models.StatusInProgress).
 		WillReturnResult(result)
 
 	err := repo.CloseReception(ctx, receptionID)
@@ -195,7 +238,7 @@ func TestCloseReception_SQLError(t *testing.T) {
 	receptionID := uuid.New()
 
 	mock.ExpectExec("UPDATE receptions").
-		WithArgs(models.StatusClosed, receptionID).
+		WithArgs(models.StatusClosed, receptionID, models.StatusInProgress).
 		WillReturnError(errors.New("database error"))
 
 	err := repo.CloseReception(ctx, receptionID)
@@ -206,13 +249,180 @@ func TestCloseReception_SQLError(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestCloseReception_AlreadyClosed(t *testing.T) {
+	repo, mock, cleanup := setupReceptionRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	receptionID := uuid.New()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE receptions SET status = $1 WHERE (id = $2 AND status = $3)")).
+		WithArgs(models.StatusClosed, receptionID, models.StatusInProgress).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.CloseReception(ctx, receptionID)
+
+	assert.ErrorIs(t, err, models.ErrReceptionClosed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCloseStaleReception(t *testing.T) {
+	testCases := []struct {
+		name         string
+		rowsAffected int64
+		expected     bool
+	}{
+		{name: "Closed", rowsAffected: 1, expected: true},
+		{name: "Touched Or Closed Concurrently", rowsAffected: 0, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock, cleanup := setupReceptionRepoTest(t)
+			defer cleanup()
+
+			ctx := createTestContext()
+			receptionID := uuid.New()
+			threshold := time.Now().Add(-72 * time.Hour)
+
+			mock.ExpectExec(regexp.QuoteMeta("UPDATE receptions SET status = $1 WHERE (id = $2 AND status = $3 AND COALESCE(last_activity_at, date_time) < $4)")).
+				WithArgs(models.StatusClosed, receptionID, models.StatusInProgress, threshold).
+				WillReturnResult(sqlmock.NewResult(0, tc.rowsAffected))
+
+			closed, err := repo.CloseStaleReception(ctx, receptionID, threshold)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, closed)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestListOpenReceptionsOlderThan(t *testing.T) {
+	repo, mock, cleanup := setupReceptionRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	threshold := time.Now().Add(-72 * time.Hour)
+	receptionID := uuid.New()
+	pvzID := uuid.New()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, date_time, pvz_id, status, last_activity_at FROM receptions WHERE (status = $1 AND COALESCE(last_activity_at, date_time) < $2) ORDER BY COALESCE(last_activity_at, date_time)")).
+		WithArgs(models.StatusInProgress, threshold).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status", "last_activity_at"}).
+			AddRow(receptionID, threshold.Add(-time.Hour), pvzID, models.StatusInProgress, nil))
+
+	receptions, err := repo.ListOpenReceptionsOlderThan(ctx, threshold)
+
+	assert.NoError(t, err)
+	require.Len(t, receptions, 1)
+	assert.Equal(t, receptionID, receptions[0].ID)
+	assert.Equal(t, models.StatusInProgress, receptions[0].Status)
+	assert.Nil(t, receptions[0].LastActivityAt)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListOpenReceptionsOlderThan_Empty(t *testing.T) {
+	repo, mock, cleanup := setupReceptionRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	threshold := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM receptions").
+		WithArgs(models.StatusInProgress, threshold).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status", "last_activity_at"}))
+
+	receptions, err := repo.ListOpenReceptionsOlderThan(ctx, threshold)
+
+	assert.NoError(t, err)
+	assert.Empty(t, receptions)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTouchOpenReception(t *testing.T) {
+	repo, mock, cleanup := setupReceptionRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	pvzID := uuid.New()
+	receptionID := uuid.New()
+	openedAt := time.Now().Add(-100 * time.Hour)
+	touchedAt := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta("UPDATE receptions SET last_activity_at = NOW() WHERE (pvz_id = $1 AND status = $2) RETURNING id, date_time, pvz_id, status, last_activity_at")).
+		WithArgs(pvzID, models.StatusInProgress).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status", "last_activity_at"}).
+			AddRow(receptionID, openedAt, pvzID, models.StatusInProgress, touchedAt))
+
+	reception, err := repo.TouchOpenReception(ctx, pvzID)
+
+	require.NoError(t, err)
+	require.NotNil(t, reception)
+	assert.Equal(t, receptionID, reception.ID)
+	require.NotNil(t, reception.LastActivityAt)
+	assert.Equal(t, touchedAt, *reception.LastActivityAt)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTouchOpenReception_NoOpenReception(t *testing.T) {
+	repo, mock, cleanup := setupReceptionRepoTest(t)
+	defer cleanup()
+
+	mock.ExpectQuery("UPDATE receptions SET last_activity_at").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status", "last_activity_at"}))
+
+	reception, err := repo.TouchOpenReception(createTestContext(), uuid.New())
+
+	assert.NoError(t, err)
+	assert.Nil(t, reception)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTouchOpenReception_QueryError(t *testing.T) {
+	repo, mock, cleanup := setupReceptionRepoTest(t)
+	defer cleanup()
+
+	mock.ExpectQuery("UPDATE receptions SET last_activity_at").
+		WillReturnError(sql.ErrConnDone)
+
+	reception, err := repo.TouchOpenReception(createTestContext(), uuid.New())
+
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+	assert.Nil(t, reception)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListOpenReceptionsOlderThan_QueryError(t *testing.T) {
+	repo, mock, cleanup := setupReceptionRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	threshold := time.Now()
+
+	mock.ExpectQuery("SELECT (.+) FROM receptions").
+		WithArgs(models.StatusInProgress, threshold).
+		WillReturnError(errors.New("database error"))
+
+	receptions, err := repo.ListOpenReceptionsOlderThan(ctx, threshold)
+
+	assert.Error(t, err)
+	assert.Nil(t, receptions)
+	assert.Contains(t, err.Error(), "error querying open receptions")
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestListReceptions(t *testing.T) {
 	repo, mock, cleanup := setupReceptionRepoTest(t)
 	defer cleanup()
 
 	ctx := createTestContext()
 
-	options := ReceptionListOptions{
+	options := models.ReceptionListOptions{
 		Page:   1,
 		Limit:  10,
 		PVZID:  uuid.New(),
@@ -245,7 +455,7 @@ func TestListReceptions_EmptyResult(t *testing.T) {
 
 	ctx := createTestContext()
 
-	options := ReceptionListOptions{
+	options := models.ReceptionListOptions{
 		Page:  1,
 		Limit: 10,
 	}
@@ -265,13 +475,138 @@ func TestListReceptions_EmptyResult(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestListReceptions_ContainsType(t *testing.T) {
+	testCases := []struct {
+		name          string
+		matchingRows  int
+		expectedTotal int
+	}{
+		{name: "With Matching Products", matchingRows: 2, expectedTotal: 2},
+		{name: "Without Matching Products", matchingRows: 0, expectedTotal: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock, cleanup := setupReceptionRepoTest(t)
+			defer cleanup()
+
+			ctx := createTestContext()
+			pvzID := uuid.New()
+			fromDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+			options := models.ReceptionListOptions{
+				Page:         1,
+				Limit:        10,
+				FromDate:     fromDate,
+				ContainsType: models.TypeFootwear,
+			}
+
+			rows := sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status"})
+			for i := 0; i < tc.matchingRows; i++ {
+				rows.AddRow(uuid.New(), fromDate.Add(time.Hour), pvzID, models.StatusClosed)
+			}
+
+			existsClause := regexp.QuoteMeta("EXISTS (SELECT 1 FROM products p WHERE p.reception_id = receptions.id AND p.type = $2)")
+
+			mock.ExpectQuery("SELECT id, date_time, pvz_id, status FROM receptions WHERE \\(date_time >= \\$1 AND "+existsClause+"\\)").
+				WithArgs(fromDate, models.TypeFootwear).
+				WillReturnRows(rows)
+
+			mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM receptions WHERE .*"+existsClause).
+				WithArgs(fromDate, models.TypeFootwear).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(tc.expectedTotal))
+
+			receptions, total, err := repo.ListReceptions(ctx, options)
+
+			assert.NoError(t, err)
+			assert.Len(t, receptions, tc.matchingRows)
+			assert.Equal(t, tc.expectedTotal, total)
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestListReceptions_Sort(t *testing.T) {
+	testCases := []struct {
+		name            string
+		sortBy          string
+		sortOrder       string
+		expectedOrderBy string
+	}{
+		{name: "Default", expectedOrderBy: "ORDER BY date_time DESC LIMIT"},
+		{name: "Date Ascending", sortBy: models.ReceptionSortDateTime, sortOrder: models.SortOrderAsc, expectedOrderBy: "ORDER BY date_time ASC, id LIMIT"},
+		{name: "Date Descending", sortBy: models.ReceptionSortDateTime, sortOrder: models.SortOrderDesc, expectedOrderBy: "ORDER BY date_time DESC, id LIMIT"},
+		{name: "Status Ascending", sortBy: models.ReceptionSortStatus, sortOrder: models.SortOrderAsc, expectedOrderBy: "ORDER BY status ASC, id LIMIT"},
+		{name: "Status Without Order", sortBy: models.ReceptionSortStatus, expectedOrderBy: "ORDER BY status DESC, id LIMIT"},
+		{name: "Order Without Field", sortOrder: models.SortOrderAsc, expectedOrderBy: "ORDER BY date_time ASC, id LIMIT"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock, cleanup := setupReceptionRepoTest(t)
+			defer cleanup()
+
+			options := models.ReceptionListOptions{
+				Page:      1,
+				Limit:     10,
+				SortBy:    tc.sortBy,
+				SortOrder: tc.sortOrder,
+			}
+
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT id, date_time, pvz_id, status FROM receptions " + tc.expectedOrderBy)).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status"}))
+			mock.ExpectQuery("SELECT COUNT").
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+			_, _, err := repo.ListReceptions(createTestContext(), options)
+
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestListReceptions_SortInjectionRejected(t *testing.T) {
+	testCases := []struct {
+		name      string
+		sortBy    string
+		sortOrder string
+	}{
+		{name: "Injected Field", sortBy: "date_time; DROP TABLE receptions; --"},
+		{name: "Unknown Field", sortBy: "pvz_id"},
+		{name: "Injected Order", sortBy: models.ReceptionSortStatus, sortOrder: "ASC, (SELECT pg_sleep(10))"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock, cleanup := setupReceptionRepoTest(t)
+			defer cleanup()
+
+			options := models.ReceptionListOptions{
+				Page:      1,
+				Limit:     10,
+				SortBy:    tc.sortBy,
+				SortOrder: tc.sortOrder,
+			}
+
+			receptions, total, err := repo.ListReceptions(createTestContext(), options)
+
+			assert.Error(t, err)
+			assert.Nil(t, receptions)
+			assert.Equal(t, 0, total)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
 func TestListReceptions_QueryError(t *testing.T) {
 	repo, mock, cleanup := setupReceptionRepoTest(t)
 	defer cleanup()
 
 	ctx := createTestContext()
 
-	options := ReceptionListOptions{
+	options := models.ReceptionListOptions{
 		Page:  1,
 		Limit: 10,
 	}
@@ -294,7 +629,7 @@ func TestListReceptions_ScanError(t *testing.T) {
 
 	ctx := createTestContext()
 
-	options := ReceptionListOptions{
+	options := models.ReceptionListOptions{
 		Page:  1,
 		Limit: 10,
 	}
@@ -320,7 +655,7 @@ func TestListReceptions_CountError(t *testing.T) {
 
 	ctx := createTestContext()
 
-	options := ReceptionListOptions{
+	options := models.ReceptionListOptions{
 		Page:  1,
 		Limit: 10,
 	}
@@ -384,6 +719,128 @@ func TestGetReceptionWithProducts(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestGetReceptionsWithProducts(t *testing.T) {
+	repo, mock, cleanup := setupReceptionRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	firstID := uuid.New()
+	secondID := uuid.New()
+	missingID := uuid.New()
+	pvzID := uuid.New()
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, date_time, pvz_id, status FROM receptions WHERE id IN ($1,$2,$3) ORDER BY date_time DESC, id")).
+		WithArgs(firstID, secondID, missingID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status"}).
+			AddRow(secondID, now, pvzID, models.StatusInProgress).
+			AddRow(firstID, now.Add(-time.Hour), pvzID, models.StatusClosed))
+
+	firstProducts := []uuid.UUID{uuid.New(), uuid.New()}
+	secondProduct := uuid.New()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, date_time, type, reception_id, sequence_num FROM products WHERE reception_id IN ($1,$2) ORDER BY reception_id, sequence_num")).
+		WithArgs(secondID, firstID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id", "sequence_num"}).
+			AddRow(firstProducts[0], now, models.TypeClothes, firstID, 1).
+			AddRow(firstProducts[1], now, models.TypeFootwear, firstID, 2).
+			AddRow(secondProduct, now, models.TypeElectronics, secondID, 1))
+	mock.ExpectCommit()
+
+	receptions, err := repo.GetReceptionsWithProducts(ctx, []uuid.UUID{firstID, secondID, missingID})
+
+	require.NoError(t, err)
+	require.Len(t, receptions, 2)
+
+	assert.Equal(t, secondID, receptions[0].ID)
+	require.Len(t, receptions[0].Products, 1)
+	assert.Equal(t, secondProduct, receptions[0].Products[0].ID)
+
+	assert.Equal(t, firstID, receptions[1].ID)
+	require.Len(t, receptions[1].Products, 2)
+	for i, product := range receptions[1].Products {
+		assert.Equal(t, firstProducts[i], product.ID)
+		assert.Equal(t, firstID, product.ReceptionID)
+		assert.Equal(t, i+1, product.SequenceNum)
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListReceptionIDsByPVZ(t *testing.T) {
+	repo, mock, cleanup := setupReceptionRepoTest(t)
+	defer cleanup()
+
+	pvzID := uuid.New()
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM receptions WHERE pvz_id = $1 AND date_time >= $2 AND date_time < $3 ORDER BY date_time DESC, id DESC")).
+		WithArgs(pvzID, from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(ids[0]).AddRow(ids[1]))
+
+	result, err := repo.ListReceptionIDsByPVZ(createTestContext(), pvzID, from, to)
+
+	require.NoError(t, err)
+	assert.Equal(t, ids, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetReceptionsWithProducts_Empty(t *testing.T) {
+	t.Run("No IDs", func(t *testing.T) {
+		repo, mock, cleanup := setupReceptionRepoTest(t)
+		defer cleanup()
+
+		receptions, err := repo.GetReceptionsWithProducts(createTestContext(), nil)
+
+		require.NoError(t, err)
+		assert.Empty(t, receptions)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("No Receptions Found", func(t *testing.T) {
+		repo, mock, cleanup := setupReceptionRepoTest(t)
+		defer cleanup()
+
+		id := uuid.New()
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT (.+) FROM receptions").
+			WithArgs(id).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status"}))
+		mock.ExpectCommit()
+
+		receptions, err := repo.GetReceptionsWithProducts(createTestContext(), []uuid.UUID{id})
+
+		require.NoError(t, err)
+		assert.Empty(t, receptions)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetReceptionsWithProducts_ProductsError(t *testing.T) {
+	repo, mock, cleanup := setupReceptionRepoTest(t)
+	defer cleanup()
+
+	id := uuid.New()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM receptions").
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status"}).
+			AddRow(id, time.Now(), uuid.New(), models.StatusClosed))
+	mock.ExpectQuery("SELECT (.+) FROM products").
+		WithArgs(id).
+		WillReturnError(errors.New("database error"))
+	mock.ExpectRollback()
+
+	receptions, err := repo.GetReceptionsWithProducts(createTestContext(), []uuid.UUID{id})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "error querying products for receptions")
+	assert.Nil(t, receptions)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetReceptionWithProducts_NotFound(t *testing.T) {
 	repo, mock, cleanup := setupReceptionRepoTest(t)
 	defer cleanup()
@@ -454,3 +911,185 @@ func TestGetReceptionWithProducts_CommitError(t *testing.T) {
 
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestCountReceptionsByStatus(t *testing.T) {
+	pvzID := uuid.New()
+
+	testCases := []struct {
+		name          string
+		pvzID         *uuid.UUID
+		expectedQuery string
+		expectedArgs  []driver.Value
+	}{
+		{
+			name:          "All PVZ",
+			expectedQuery: "SELECT status, COUNT(*) FROM receptions GROUP BY status",
+		},
+		{
+			name:          "Single PVZ",
+			pvzID:         &pvzID,
+			expectedQuery: "SELECT status, COUNT(*) FROM receptions WHERE pvz_id = $1 GROUP BY status",
+			expectedArgs:  []driver.Value{pvzID},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock, cleanup := setupReceptionRepoTest(t)
+			defer cleanup()
+
+			expectation := mock.ExpectQuery("^" + regexp.QuoteMeta(tc.expectedQuery) + "$")
+			if tc.expectedArgs != nil {
+				expectation = expectation.WithArgs(tc.expectedArgs...)
+			} else {
+				expectation = expectation.WithoutArgs()
+			}
+			expectation.WillReturnRows(sqlmock.NewRows([]string{"status", "count"}).
+				AddRow(string(models.StatusInProgress), 2).
+				AddRow(string(models.StatusClosed), 5))
+
+			counts, err := repo.CountReceptionsByStatus(createTestContext(), tc.pvzID)
+
+			require.NoError(t, err)
+			assert.Equal(t, map[string]int{
+				string(models.StatusInProgress): 2,
+				string(models.StatusClosed):     5,
+			}, counts)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCountReceptionsByStatus_QueryError(t *testing.T) {
+	repo, mock, cleanup := setupReceptionRepoTest(t)
+	defer cleanup()
+
+	mock.ExpectQuery("SELECT status, COUNT").WillReturnError(errors.New("database error"))
+
+	counts, err := repo.CountReceptionsByStatus(createTestContext(), nil)
+
+	assert.Error(t, err)
+	assert.Nil(t, counts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListReceptions_SkipTotalHasNext(t *testing.T) {
+	const limit = 2
+
+	testCases := []struct {
+		name            string
+		page            int
+		rows            int
+		expectedOffset  int
+		expectedLen     int
+		expectedHasNext bool
+	}{
+		{name: "Extra Row Means Next Page", page: 1, rows: limit + 1, expectedOffset: 0, expectedLen: limit, expectedHasNext: true},
+		{name: "Exactly Full Last Page", page: 1, rows: limit, expectedOffset: 0, expectedLen: limit, expectedHasNext: false},
+		{name: "Partial Last Page", page: 2, rows: 1, expectedOffset: 2, expectedLen: 1, expectedHasNext: false},
+		{name: "Page Past End", page: 3, rows: 0, expectedOffset: 4, expectedLen: 0, expectedHasNext: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock, cleanup := setupReceptionRepoTest(t)
+			defer cleanup()
+
+			rows := sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status"})
+			for i := 0; i < tc.rows; i++ {
+				rows.AddRow(uuid.New(), time.Now(), uuid.New(), models.StatusClosed)
+			}
+
+			// COUNT не ожидается: лишний запрос провалил бы ExpectationsWereMet
+			mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf("ORDER BY date_time DESC LIMIT %d OFFSET %d", limit+1, tc.expectedOffset))).
+				WillReturnRows(rows)
+
+			options := models.ReceptionListOptions{Page: tc.page, Limit: limit, SkipTotal: true}
+			receptions, total, err := repo.ListReceptions(createTestContext(), options)
+
+			require.NoError(t, err)
+			assert.Len(t, receptions, tc.expectedLen)
+			assert.Equal(t, tc.expectedHasNext, models.HasNextPage(tc.page, limit, total))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestListReceptions_NormalizesPagination(t *testing.T) {
+	testCases := []struct {
+		name           string
+		page           int
+		limit          int
+		expectedLimit  int
+		expectedOffset int
+	}{
+		{name: "Zero Page", page: 0, limit: 10, expectedLimit: 10, expectedOffset: 0},
+		{name: "Huge Limit", page: 2, limit: 1_000_000, expectedLimit: maxRepoListLimit, expectedOffset: maxRepoListLimit},
+		{name: "Negative Page And Limit", page: -3, limit: -1, expectedLimit: defaultListLimit, expectedOffset: 0},
+		{name: "Offset Overflow", page: math.MaxInt, limit: 50, expectedLimit: 50, expectedOffset: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock, cleanup := setupReceptionRepoTest(t)
+			defer cleanup()
+
+			mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf("LIMIT %d OFFSET %d", tc.expectedLimit, tc.expectedOffset))).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status"}))
+			mock.ExpectQuery("SELECT COUNT").
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+			_, _, err := repo.ListReceptions(createTestContext(), models.ReceptionListOptions{Page: tc.page, Limit: tc.limit})
+
+			require.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPurgeReceptions_DeletesProductsOfClosedReceptions(t *testing.T) {
+	repo, mock, cleanup := setupReceptionRepoTest(t)
+	defer cleanup()
+
+	before := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Оба удаления ограничены закрытыми приемками старше границы, поэтому
+	// открытые приемки и их товары не затрагиваются
+	mock.ExpectBegin()
+	mock.ExpectExec("^"+regexp.QuoteMeta("DELETE FROM products WHERE reception_id IN "+
+		"( SELECT id FROM receptions WHERE (status = $1 AND date_time < $2) )")+"$").
+		WithArgs(models.StatusClosed, before).
+		WillReturnResult(sqlmock.NewResult(0, 7))
+	mock.ExpectExec("^"+regexp.QuoteMeta("DELETE FROM receptions WHERE (status = $1 AND date_time < $2)")+"$").
+		WithArgs(models.StatusClosed, before).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	deleted, err := repo.PurgeReceptions(createTestContext(), before)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPurgeReceptions_RollsBackOnError(t *testing.T) {
+	repo, mock, cleanup := setupReceptionRepoTest(t)
+	defer cleanup()
+
+	before := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM products").
+		WithArgs(models.StatusClosed, before).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("DELETE FROM receptions").
+		WithArgs(models.StatusClosed, before).
+		WillReturnError(errors.New("database error"))
+	mock.ExpectRollback()
+
+	deleted, err := repo.PurgeReceptions(createTestContext(), before)
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/config"
+	"pvz-service/internal/domain/models"
+)
+
+// setupReplicaTest создает две независимые заглушки: основную базу, от которой
+// не ожидается ни одного запроса, и реплику
+func setupReplicaTest(t *testing.T) (*DB, sqlmock.Sqlmock, *DB, sqlmock.Sqlmock) {
+	primary, primaryMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { primary.Close() })
+
+	replica, replicaMock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { replica.Close() })
+
+	return NewDB(primary), primaryMock, NewDB(replica), replicaMock
+}
+
+func TestListPVZ_UsesReplica(t *testing.T) {
+	primary, primaryMock, replica, replicaMock := setupReplicaTest(t)
+	repo := NewPVZRepository(primary).WithReplica(replica)
+
+	pvzID := uuid.New()
+
+	replicaMock.ExpectBegin()
+	replicaMock.ExpectQuery("SELECT (.+) FROM pvz").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}).
+			AddRow(pvzID, time.Now(), "Москва"))
+	replicaMock.ExpectQuery("SELECT (.+) FROM receptions").
+		WithArgs(pvzID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status"}))
+	replicaMock.ExpectQuery("SELECT COUNT").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	replicaMock.ExpectCommit()
+
+	pvzs, total, err := repo.ListPVZ(createTestContext(), models.PVZListOptions{Page: 1, Limit: 10})
+
+	require.NoError(t, err)
+	assert.Len(t, pvzs, 1)
+	assert.Equal(t, 1, total)
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+	assert.NoError(t, primaryMock.ExpectationsWereMet())
+}
+
+func TestListReceptions_UsesReplica(t *testing.T) {
+	primary, primaryMock, replica, replicaMock := setupReplicaTest(t)
+	repo := NewReceptionRepository(primary).WithReplica(replica)
+
+	pvzID := uuid.New()
+
+	replicaMock.ExpectQuery("SELECT (.+) FROM receptions").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status"}).
+			AddRow(uuid.New(), time.Now(), pvzID, string(models.StatusInProgress)))
+	replicaMock.ExpectQuery("SELECT COUNT").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	receptions, total, err := repo.ListReceptions(createTestContext(), models.ReceptionListOptions{
+		Page:  1,
+		Limit: 10,
+		PVZID: pvzID,
+	})
+
+	require.NoError(t, err)
+	assert.Len(t, receptions, 1)
+	assert.Equal(t, 1, total)
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+	assert.NoError(t, primaryMock.ExpectationsWereMet())
+}
+
+func TestGetProductsByReceptionID_UsesReplica(t *testing.T) {
+	primary, primaryMock, replica, replicaMock := setupReplicaTest(t)
+	repo := NewProductRepository(primary).WithReplica(replica)
+
+	receptionID := uuid.New()
+
+	replicaMock.ExpectQuery("SELECT (.+) FROM products").
+		WithArgs(receptionID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id", "sequence_num"}).
+			AddRow(uuid.New(), time.Now(), models.TypeElectronics, receptionID, 1))
+	replicaMock.ExpectQuery("SELECT COUNT").
+		WithArgs(receptionID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	products, total, err := repo.GetProductsByReceptionID(createTestContext(), receptionID, 1, 10, "")
+
+	require.NoError(t, err)
+	assert.Len(t, products, 1)
+	assert.Equal(t, 1, total)
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+	assert.NoError(t, primaryMock.ExpectationsWereMet())
+}
+
+func TestWritesStayOnPrimaryWithReplica(t *testing.T) {
+	primary, primaryMock, replica, replicaMock := setupReplicaTest(t)
+	repo := NewReceptionRepository(primary).WithReplica(replica)
+
+	pvzID := uuid.New()
+
+	primaryMock.ExpectQuery("INSERT INTO receptions").
+		WithArgs(pvzID, models.StatusInProgress).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status"}).
+			AddRow(uuid.New(), time.Now(), pvzID, models.StatusInProgress))
+
+	_, err := repo.CreateReception(createTestContext(), pvzID)
+
+	require.NoError(t, err)
+	assert.NoError(t, primaryMock.ExpectationsWereMet())
+	assert.NoError(t, replicaMock.ExpectationsWereMet())
+}
+
+func TestReader_FallsBackToPrimary(t *testing.T) {
+	primary, _, replica, _ := setupReplicaTest(t)
+
+	assert.Same(t, primary, NewPVZRepository(primary).reader())
+	assert.Same(t, primary, NewReceptionRepository(primary).WithReplica(nil).reader())
+	assert.Same(t, replica, NewProductRepository(primary).WithReplica(replica).reader())
+}
+
+func TestNewReplicaDatabase_NotConfigured(t *testing.T) {
+	db, err := NewReplicaDatabase(&config.DBConfig{})
+
+	assert.NoError(t, err)
+	assert.Nil(t, db)
+}
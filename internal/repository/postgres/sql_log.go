@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"pvz-service/internal/logger"
+)
+
+// redactedValue подставляется в логи вместо значений чувствительных колонок
+const redactedValue = "***"
+
+// sensitiveColumns - колонки, значения которых не должны попадать в логи
+var sensitiveColumns = map[string]bool{
+	"password": true,
+}
+
+// logSQL пишет запрос и его аргументы в debug-лог. columns перечисляет колонки,
+// которым по порядку соответствуют args; значения чувствительных колонок маскируются
+func logSQL(ctx context.Context, query string, args []interface{}, columns ...string) {
+	log := logger.FromContext(ctx)
+	if !log.Enabled(ctx, logger.LevelDebug) {
+		return
+	}
+
+	log.Debug("SQL запрос", "query", query, "args", redactArgs(args, columns))
+}
+
+// redactArgs возвращает копию args, в которой значения чувствительных колонок заменены на ***
+func redactArgs(args []interface{}, columns []string) []interface{} {
+	redacted := make([]interface{}, len(args))
+	copy(redacted, args)
+
+	for i, column := range columns {
+		if i >= len(redacted) {
+			break
+		}
+		if sensitiveColumns[column] {
+			redacted[i] = redactedValue
+		}
+	}
+
+	return redacted
+}
+
+// sqlLogAttr описывает упавший запрос для лога ошибки: текст SQL, число аргументов
+// и их типы. В отличие от logSQL значения не пишутся вовсе: лог ошибок включен
+// в production, а среди аргументов бывают email и другие персональные данные
+func sqlLogAttr(query string, args []interface{}) slog.Attr {
+	return slog.Group("sql",
+		slog.String("query", query),
+		slog.Int("arg_count", len(args)),
+		slog.Any("arg_types", sqlArgTypes(args)),
+	)
+}
+
+// sqlArgTypes возвращает Go-типы аргументов запроса; nil обозначается как "nil"
+func sqlArgTypes(args []interface{}) []string {
+	types := make([]string, len(args))
+	for i, arg := range args {
+		if arg == nil {
+			types[i] = "nil"
+			continue
+		}
+		types[i] = fmt.Sprintf("%T", arg)
+	}
+	return types
+}
@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/domain/models"
+	"pvz-service/internal/logger"
+)
+
+// errorRecord возвращает единственную ERROR-запись из JSON-лога
+func errorRecord(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+
+	var found []map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(line, &record))
+		if record["level"] == "ERROR" {
+			found = append(found, record)
+		}
+	}
+	require.Len(t, found, 1)
+	return found[0]
+}
+
+func TestSQLErrorLog_IncludesQueryAndArgTypes(t *testing.T) {
+	repo, mock, cleanup := setupProductRepoTest(t)
+	defer cleanup()
+
+	var buf bytes.Buffer
+	ctx := logger.WithLogger(context.Background(), logger.New(logger.Config{Level: logger.LevelInfo, Format: "json", Output: &buf}))
+
+	productID := uuid.New()
+	const query = "UPDATE products SET type = $1 WHERE id = $2"
+	mock.ExpectExec("^"+regexp.QuoteMeta(query)+"$").
+		WithArgs(models.TypeFootwear, productID).
+		WillReturnError(errors.New("connection reset by peer"))
+
+	err := repo.UpdateProductType(ctx, productID, models.TypeFootwear)
+	require.Error(t, err)
+
+	record := errorRecord(t, &buf)
+	sqlGroup, ok := record["sql"].(map[string]any)
+	require.True(t, ok, "в логе ошибки нет группы sql")
+
+	assert.Equal(t, query, sqlGroup["query"])
+	assert.Equal(t, float64(2), sqlGroup["arg_count"])
+	assert.Equal(t, []any{"models.ProductType", "string"}, sqlGroup["arg_types"])
+
+	// Значения аргументов в группу sql не попадают
+	encoded, err := json.Marshal(sqlGroup)
+	require.NoError(t, err)
+	assert.NotContains(t, string(encoded), productID.String())
+	assert.NotContains(t, string(encoded), string(models.TypeFootwear))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLErrorLog_NeverLogsUserEmailInSQLGroup(t *testing.T) {
+	repo, mock, cleanup := setupUserRepoTest(t)
+	defer cleanup()
+
+	var buf bytes.Buffer
+	ctx := logger.WithLogger(context.Background(), logger.New(logger.Config{Level: logger.LevelInfo, Format: "json", Output: &buf}))
+
+	const email = "secret.person@example.com"
+	mock.ExpectQuery("SELECT (.+) FROM users WHERE email").
+		WithArgs(email).
+		WillReturnError(errors.New("connection reset by peer"))
+
+	_, err := repo.GetUserByEmail(ctx, email)
+	require.Error(t, err)
+
+	sqlGroup, ok := errorRecord(t, &buf)["sql"].(map[string]any)
+	require.True(t, ok)
+	assert.Contains(t, sqlGroup["query"], "FROM users WHERE email = $1")
+	assert.Equal(t, []any{"string"}, sqlGroup["arg_types"])
+
+	encoded, err := json.Marshal(sqlGroup)
+	require.NoError(t, err)
+	assert.NotContains(t, string(encoded), email)
+}
+
+func TestSQLArgTypes(t *testing.T) {
+	assert.Equal(t, []string{"int", "nil", "[]string"}, sqlArgTypes([]interface{}{1, nil, []string{"a"}}))
+	assert.Empty(t, sqlArgTypes(nil))
+}
@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"pvz-service/internal/logger"
+)
+
+// withTx выполняет fn в транзакции. Если fn вернула ошибку или запаниковала,
+// транзакция откатывается (паника пробрасывается дальше), иначе фиксируется.
+// Ошибка fn возвращается без обертки
+func withTx(ctx context.Context, db *DB, opts *sql.TxOptions, fn func(tx *Tx) error) (err error) {
+	log := logger.FromContext(ctx)
+
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		log.Error("ошибка начала транзакции", "error", err)
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if committed {
+			return
+		}
+		if p := recover(); p != nil {
+			log.Error("откат транзакции из-за паники", "panic", p)
+			tx.Rollback()
+			panic(p)
+		}
+		log.Debug("откат транзакции из-за ошибки")
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Error("ошибка отката транзакции", "error", rbErr)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	committed = true
+	if err := tx.Commit(); err != nil {
+		log.Error("ошибка фиксации транзакции", "error", err)
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTxTest(t *testing.T) (*DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return NewDB(db), mock
+}
+
+func TestWithTx_CommitsOnSuccess(t *testing.T) {
+	db, mock := setupTxTest(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE receptions").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := withTx(createTestContext(), db, nil, func(tx *Tx) error {
+		_, err := tx.ExecContext(createTestContext(), "UPDATE receptions SET status = 'close'")
+		return err
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithTx_RollsBackOnError(t *testing.T) {
+	db, mock := setupTxTest(t)
+	fnErr := errors.New("fn failed")
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	err := withTx(createTestContext(), db, nil, func(tx *Tx) error {
+		return fnErr
+	})
+
+	assert.Same(t, fnErr, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithTx_RollsBackOnPanic(t *testing.T) {
+	db, mock := setupTxTest(t)
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	assert.PanicsWithValue(t, "boom", func() {
+		_ = withTx(createTestContext(), db, nil, func(tx *Tx) error {
+			panic("boom")
+		})
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithTx_BeginError(t *testing.T) {
+	db, mock := setupTxTest(t)
+
+	mock.ExpectBegin().WillReturnError(errors.New("connection refused"))
+
+	called := false
+	err := withTx(createTestContext(), db, nil, func(tx *Tx) error {
+		called = true
+		return nil
+	})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "error starting transaction")
+	assert.False(t, called)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithTx_CommitError(t *testing.T) {
+	db, mock := setupTxTest(t)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit().WillReturnError(errors.New("commit error"))
+
+	err := withTx(createTestContext(), db, nil, func(tx *Tx) error {
+		return nil
+	})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "error committing transaction")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
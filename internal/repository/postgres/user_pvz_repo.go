@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"pvz-service/internal/domain/interfaces"
+	"pvz-service/internal/domain/models"
+	"pvz-service/internal/logger"
+	"pvz-service/internal/metrics"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+)
+
+// UserPVZRepository хранит назначения сотрудников на ПВЗ
+type UserPVZRepository struct {
+	db *DB
+	sb squirrel.StatementBuilderType
+}
+
+var _ interfaces.UserPVZRepository = (*UserPVZRepository)(nil)
+
+func NewUserPVZRepository(db *DB) *UserPVZRepository {
+	return &UserPVZRepository{
+		db: db,
+		sb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+}
+
+// IsAssigned проверяет, назначен ли пользователь на ПВЗ
+func (r *UserPVZRepository) IsAssigned(ctx context.Context, userID, pvzID uuid.UUID) (bool, error) {
+	defer metrics.ObserveDBQuery("is_assigned", time.Now())
+
+	log := logger.FromContext(ctx)
+	log.Debug("проверка назначения сотрудника на ПВЗ", "user_id", userID, "pvz_id", pvzID)
+
+	query := r.sb.Select("1").
+		Prefix("SELECT EXISTS (").
+		From("user_pvz").
+		Where(squirrel.Eq{"user_id": userID, "pvz_id": pvzID}).
+		Suffix(")")
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		log.Error("ошибка построения SQL", "error", err, "user_id", userID, "pvz_id", pvzID)
+		return false, fmt.Errorf("error building SQL: %w", err)
+	}
+
+	var assigned bool
+	if err := r.db.QueryRowContext(ctx, sqlQuery, args...).Scan(&assigned); err != nil {
+		log.Error("ошибка проверки назначения", "error", err, sqlLogAttr(sqlQuery, args), "user_id", userID, "pvz_id", pvzID)
+		return false, fmt.Errorf("error checking user pvz assignment: %w", err)
+	}
+
+	log.Debug("проверка назначения завершена", "user_id", userID, "pvz_id", pvzID, "assigned", assigned)
+	return assigned, nil
+}
+
+// AssignUser назначает пользователя на ПВЗ. Повторное назначение не считается ошибкой
+func (r *UserPVZRepository) AssignUser(ctx context.Context, userID, pvzID uuid.UUID) error {
+	defer metrics.ObserveDBQuery("assign_user", time.Now())
+
+	log := logger.FromContext(ctx)
+	log.Debug("назначение сотрудника на ПВЗ", "user_id", userID, "pvz_id", pvzID)
+
+	query := r.sb.Insert("user_pvz").
+		Columns("user_id", "pvz_id").
+		Values(userID, pvzID).
+		Suffix("ON CONFLICT (user_id, pvz_id) DO NOTHING")
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		log.Error("ошибка построения SQL", "error", err, "user_id", userID, "pvz_id", pvzID)
+		return fmt.Errorf("error building SQL: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, sqlQuery, args...); err != nil {
+		log.Error("ошибка назначения сотрудника", "error", err, sqlLogAttr(sqlQuery, args), "user_id", userID, "pvz_id", pvzID)
+		return fmt.Errorf("error assigning user to pvz: %w", err)
+	}
+
+	log.Info("сотрудник назначен на ПВЗ", "user_id", userID, "pvz_id", pvzID)
+	return nil
+}
+
+// UnassignUser снимает назначение. Возвращает false, если назначения не было
+func (r *UserPVZRepository) UnassignUser(ctx context.Context, userID, pvzID uuid.UUID) (bool, error) {
+	defer metrics.ObserveDBQuery("unassign_user", time.Now())
+
+	log := logger.FromContext(ctx)
+	log.Debug("снятие сотрудника с ПВЗ", "user_id", userID, "pvz_id", pvzID)
+
+	query := r.sb.Delete("user_pvz").
+		Where(squirrel.Eq{"user_id": userID, "pvz_id": pvzID})
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		log.Error("ошибка построения SQL", "error", err, "user_id", userID, "pvz_id", pvzID)
+		return false, fmt.Errorf("error building SQL: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		log.Error("ошибка снятия сотрудника с ПВЗ", "error", err, sqlLogAttr(sqlQuery, args), "user_id", userID, "pvz_id", pvzID)
+		return false, fmt.Errorf("error unassigning user from pvz: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Error("не удалось получить количество затронутых строк", "error", err)
+		return false, fmt.Errorf("error getting rows affected: %w", err)
+	}
+
+	log.Info("снятие сотрудника с ПВЗ завершено", "user_id", userID, "pvz_id", pvzID, "removed", rowsAffected > 0)
+	return rowsAffected > 0, nil
+}
+
+// ListUsersByPVZ возвращает пользователей, назначенных на ПВЗ, в порядке email
+func (r *UserPVZRepository) ListUsersByPVZ(ctx context.Context, pvzID uuid.UUID) ([]*models.User, error) {
+	defer metrics.ObserveDBQuery("list_users_by_pvz", time.Now())
+
+	log := logger.FromContext(ctx)
+	log.Debug("получение сотрудников ПВЗ", "pvz_id", pvzID)
+
+	query := r.sb.Select("u.id", "u.email", "u.role", "u.created_at").
+		From("user_pvz up").
+		Join("users u ON u.id = up.user_id").
+		Where(squirrel.Eq{"up.pvz_id": pvzID}).
+		OrderBy("u.email")
+
+	sqlQuery, args, err := query.ToSql()
+	if err != nil {
+		log.Error("ошибка построения SQL", "error", err, "pvz_id", pvzID)
+		return nil, fmt.Errorf("error building SQL: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		log.Error("ошибка получения сотрудников ПВЗ", "error", err, sqlLogAttr(sqlQuery, args), "pvz_id", pvzID)
+		return nil, fmt.Errorf("error listing pvz users: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]*models.User, 0)
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.ID, &user.Email, &user.Role, &user.CreatedAt); err != nil {
+			log.Error("ошибка сканирования строки пользователя", "error", err)
+			return nil, fmt.Errorf("error scanning user row: %w", err)
+		}
+		users = append(users, &user)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Error("ошибка при итерации по пользователям", "error", err, "pvz_id", pvzID)
+		return nil, fmt.Errorf("error iterating users: %w", err)
+	}
+
+	log.Debug("сотрудники ПВЗ получены", "pvz_id", pvzID, "count", len(users))
+	return users, nil
+}
@@ -0,0 +1,185 @@
+package postgres
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/domain/models"
+)
+
+func setupUserPVZRepoTest(t *testing.T) (*UserPVZRepository, sqlmock.Sqlmock, func()) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+
+	repo := &UserPVZRepository{
+		db: NewDB(db),
+		sb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
+	}
+
+	cleanup := func() {
+		db.Close()
+	}
+
+	return repo, mock, cleanup
+}
+
+func TestIsAssigned(t *testing.T) {
+	testCases := []struct {
+		name     string
+		assigned bool
+	}{
+		{name: "Assigned", assigned: true},
+		{name: "Not Assigned", assigned: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock, cleanup := setupUserPVZRepoTest(t)
+			defer cleanup()
+
+			ctx := createTestContext()
+			userID := uuid.New()
+			pvzID := uuid.New()
+
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS ( SELECT 1 FROM user_pvz WHERE pvz_id = $1 AND user_id = $2 )")).
+				WithArgs(pvzID, userID).
+				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(tc.assigned))
+
+			assigned, err := repo.IsAssigned(ctx, userID, pvzID)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.assigned, assigned)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestIsAssigned_Error(t *testing.T) {
+	repo, mock, cleanup := setupUserPVZRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+
+	mock.ExpectQuery("SELECT EXISTS").
+		WillReturnError(errors.New("database error"))
+
+	assigned, err := repo.IsAssigned(ctx, uuid.New(), uuid.New())
+
+	assert.Error(t, err)
+	assert.False(t, assigned)
+	assert.Contains(t, err.Error(), "error checking user pvz assignment")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAssignUser(t *testing.T) {
+	repo, mock, cleanup := setupUserPVZRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	userID := uuid.New()
+	pvzID := uuid.New()
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO user_pvz (user_id,pvz_id) VALUES ($1,$2) ON CONFLICT (user_id, pvz_id) DO NOTHING")).
+		WithArgs(userID, pvzID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.AssignUser(ctx, userID, pvzID)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAssignUser_Error(t *testing.T) {
+	repo, mock, cleanup := setupUserPVZRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+
+	mock.ExpectExec("INSERT INTO user_pvz").
+		WillReturnError(errors.New("database error"))
+
+	err := repo.AssignUser(ctx, uuid.New(), uuid.New())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "error assigning user to pvz")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUnassignUser(t *testing.T) {
+	testCases := []struct {
+		name            string
+		rowsAffected    int64
+		expectedRemoved bool
+	}{
+		{name: "Removed", rowsAffected: 1, expectedRemoved: true},
+		{name: "Not Assigned", rowsAffected: 0, expectedRemoved: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock, cleanup := setupUserPVZRepoTest(t)
+			defer cleanup()
+
+			ctx := createTestContext()
+			userID := uuid.New()
+			pvzID := uuid.New()
+
+			mock.ExpectExec(regexp.QuoteMeta("DELETE FROM user_pvz WHERE pvz_id = $1 AND user_id = $2")).
+				WithArgs(pvzID, userID).
+				WillReturnResult(sqlmock.NewResult(0, tc.rowsAffected))
+
+			removed, err := repo.UnassignUser(ctx, userID, pvzID)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedRemoved, removed)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestListUsersByPVZ(t *testing.T) {
+	repo, mock, cleanup := setupUserPVZRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+	pvzID := uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT u.id, u.email, u.role, u.created_at FROM user_pvz up JOIN users u ON u.id = up.user_id WHERE up.pvz_id = $1 ORDER BY u.email")).
+		WithArgs(pvzID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "role", "created_at"}).
+			AddRow(uuid.New(), "a@example.com", models.RoleEmployee, now).
+			AddRow(uuid.New(), "b@example.com", models.RoleEmployee, now))
+
+	users, err := repo.ListUsersByPVZ(ctx, pvzID)
+
+	assert.NoError(t, err)
+	require.Len(t, users, 2)
+	assert.Equal(t, "a@example.com", users[0].Email)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListUsersByPVZ_Empty(t *testing.T) {
+	repo, mock, cleanup := setupUserPVZRepoTest(t)
+	defer cleanup()
+
+	ctx := createTestContext()
+
+	mock.ExpectQuery("SELECT (.+) FROM user_pvz").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "role", "created_at"}))
+
+	users, err := repo.ListUsersByPVZ(ctx, uuid.New())
+
+	assert.NoError(t, err)
+	assert.NotNil(t, users)
+	assert.Empty(t, users)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -5,20 +5,25 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
+	"pvz-service/internal/domain/interfaces"
 	"pvz-service/internal/domain/models"
 	"pvz-service/internal/logger"
+	"pvz-service/internal/metrics"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
 )
 
 type UserRepository struct {
-	db *sql.DB
+	db *DB
 	sb squirrel.StatementBuilderType
 }
 
-func NewUserRepository(db *sql.DB) *UserRepository {
+var _ interfaces.UserRepository = (*UserRepository)(nil)
+
+func NewUserRepository(db *DB) *UserRepository {
 	return &UserRepository{
 		db: db,
 		sb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
@@ -26,6 +31,8 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 }
 
 func (r *UserRepository) CreateUser(ctx context.Context, email, password string, role models.UserRole) (*models.User, error) {
+	defer metrics.ObserveDBQuery("create_user", time.Now())
+
 	log := logger.FromContext(ctx)
 	log.Debug("создание пользователя",
 		"email", email,
@@ -34,8 +41,10 @@ func (r *UserRepository) CreateUser(ctx context.Context, email, password string,
 
 	id := uuid.New()
 
+	// Колонки с аргументами перечислены отдельно, чтобы logSQL замаскировал пароль
+	columns := []string{"id", "email", "password", "role"}
 	query := r.sb.Insert("users").
-		Columns("id", "email", "password", "role", "created_at").
+		Columns(append(columns, "created_at")...).
 		Values(id, email, password, role, squirrel.Expr("NOW()")).
 		Suffix("RETURNING id, email, role, created_at")
 
@@ -45,6 +54,8 @@ func (r *UserRepository) CreateUser(ctx context.Context, email, password string,
 		return nil, fmt.Errorf("error building SQL: %w", err)
 	}
 
+	logSQL(ctx, sqlQuery, args, columns...)
+
 	var user models.User
 	err = r.db.QueryRowContext(ctx, sqlQuery, args...).Scan(
 		&user.ID, &user.Email, &user.Role, &user.CreatedAt,
@@ -53,6 +64,7 @@ func (r *UserRepository) CreateUser(ctx context.Context, email, password string,
 	if err != nil {
 		log.Error("ошибка создания пользователя в БД",
 			"error", err,
+			sqlLogAttr(sqlQuery, args),
 			"email", email,
 		)
 		return nil, fmt.Errorf("error creating user: %w", err)
@@ -68,6 +80,8 @@ func (r *UserRepository) CreateUser(ctx context.Context, email, password string,
 }
 
 func (r *UserRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	defer metrics.ObserveDBQuery("get_user_by_id", time.Now())
+
 	log := logger.FromContext(ctx)
 	log.Debug("получение пользователя по ID", "user_id", id)
 
@@ -91,7 +105,7 @@ func (r *UserRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*models
 			log.Info("пользователь не найден", "user_id", id)
 			return nil, nil
 		}
-		log.Error("ошибка получения пользователя", "error", err, "user_id", id)
+		log.Error("ошибка получения пользователя", "error", err, sqlLogAttr(sqlQuery, args), "user_id", id)
 		return nil, fmt.Errorf("error getting user by id: %w", err)
 	}
 
@@ -105,6 +119,8 @@ func (r *UserRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*models
 }
 
 func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	defer metrics.ObserveDBQuery("get_user_by_email", time.Now())
+
 	log := logger.FromContext(ctx)
 	log.Debug("получение пользователя по email", "email", email)
 
@@ -128,7 +144,7 @@ func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*mod
 			log.Info("пользователь не найден по email", "email", email)
 			return nil, nil
 		}
-		log.Error("ошибка получения пользователя по email", "error", err, "email", email)
+		log.Error("ошибка получения пользователя по email", "error", err, sqlLogAttr(sqlQuery, args), "email", email)
 		return nil, fmt.Errorf("error getting user by email: %w", err)
 	}
 
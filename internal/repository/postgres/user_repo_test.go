@@ -1,6 +1,8 @@
 package postgres
 
 import (
+	"bytes"
+	"context"
 	"database/sql"
 	"errors"
 	"testing"
@@ -13,6 +15,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"pvz-service/internal/domain/models"
+	"pvz-service/internal/logger"
 )
 
 func setupUserRepoTest(t *testing.T) (*UserRepository, sqlmock.Sqlmock, func()) {
@@ -20,7 +23,7 @@ func setupUserRepoTest(t *testing.T) (*UserRepository, sqlmock.Sqlmock, func())
 	require.NoError(t, err)
 
 	repo := &UserRepository{
-		db: db,
+		db: NewDB(db),
 		sb: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar),
 	}
 
@@ -57,6 +60,32 @@ func TestCreateUser(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestCreateUser_RedactsPasswordInDebugLog(t *testing.T) {
+	repo, mock, cleanup := setupUserRepoTest(t)
+	defer cleanup()
+
+	var buf bytes.Buffer
+	ctx := logger.WithLogger(context.Background(), logger.New(logger.Config{
+		Level:  logger.LevelDebug,
+		Format: "text",
+		Output: &buf,
+	}))
+	password := "s3cret-hash"
+
+	mock.ExpectQuery(`INSERT INTO users`).
+		WithArgs(sqlmock.AnyArg(), "test@example.com", password, models.RoleEmployee).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "role", "created_at"}).
+			AddRow(uuid.New(), "test@example.com", models.RoleEmployee, time.Now()))
+
+	_, err := repo.CreateUser(ctx, "test@example.com", password, models.RoleEmployee)
+
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "SQL запрос")
+	assert.Contains(t, buf.String(), redactedValue)
+	assert.NotContains(t, buf.String(), password)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestCreateUser_SQLError(t *testing.T) {
 	repo, mock, cleanup := setupUserRepoTest(t)
 	defer cleanup()
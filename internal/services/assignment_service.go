@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+
+	"pvz-service/internal/domain/interfaces"
+	"pvz-service/internal/domain/models"
+	"pvz-service/internal/logger"
+
+	"github.com/google/uuid"
+)
+
+// AssignmentService управляет назначениями сотрудников на ПВЗ
+type AssignmentService struct {
+	userPVZRepo   interfaces.UserPVZRepository
+	userRepo      interfaces.UserRepository
+	pvzRepo       interfaces.PVZRepository
+	receptionRepo interfaces.ReceptionRepository
+	productRepo   interfaces.ProductRepository
+}
+
+var _ interfaces.AssignmentService = (*AssignmentService)(nil)
+
+func NewAssignmentService(
+	userPVZRepo interfaces.UserPVZRepository,
+	userRepo interfaces.UserRepository,
+	pvzRepo interfaces.PVZRepository,
+	receptionRepo interfaces.ReceptionRepository,
+	productRepo interfaces.ProductRepository,
+) *AssignmentService {
+	return &AssignmentService{
+		userPVZRepo:   userPVZRepo,
+		userRepo:      userRepo,
+		pvzRepo:       pvzRepo,
+		receptionRepo: receptionRepo,
+		productRepo:   productRepo,
+	}
+}
+
+func (s *AssignmentService) IsEmployeeAssigned(ctx context.Context, userID, pvzID uuid.UUID) (bool, error) {
+	log := logger.FromContext(ctx)
+	log.Debug("IsEmployeeAssigned called", "user_id", userID, "pvz_id", pvzID)
+
+	assigned, err := s.userPVZRepo.IsAssigned(ctx, userID, pvzID)
+	if err != nil {
+		log.Error("Error checking employee assignment", "error", err, "user_id", userID, "pvz_id", pvzID)
+		return false, err
+	}
+
+	if !assigned {
+		log.Warn("Employee is not assigned to PVZ", "user_id", userID, "pvz_id", pvzID)
+	}
+	return assigned, nil
+}
+
+// ReceptionPVZID возвращает ПВЗ приемки или models.ErrReceptionNotFound
+func (s *AssignmentService) ReceptionPVZID(ctx context.Context, receptionID uuid.UUID) (uuid.UUID, error) {
+	reception, err := s.receptionRepo.GetReceptionByID(ctx, receptionID)
+	if err != nil {
+		logger.FromContext(ctx).Error("Error getting reception", "error", err, "reception_id", receptionID)
+		return uuid.Nil, err
+	}
+	if reception == nil {
+		return uuid.Nil, models.ErrReceptionNotFound
+	}
+	return reception.PVZID, nil
+}
+
+// ProductPVZID возвращает ПВЗ приемки, к которой относится товар, или models.ErrProductNotFound
+func (s *AssignmentService) ProductPVZID(ctx context.Context, productID uuid.UUID) (uuid.UUID, error) {
+	product, err := s.productRepo.GetProductByID(ctx, productID)
+	if err != nil {
+		logger.FromContext(ctx).Error("Error getting product", "error", err, "product_id", productID)
+		return uuid.Nil, err
+	}
+	if product == nil {
+		return uuid.Nil, models.ErrProductNotFound
+	}
+	return s.ReceptionPVZID(ctx, product.ReceptionID)
+}
+
+func (s *AssignmentService) AssignEmployee(ctx context.Context, pvzID, userID uuid.UUID) error {
+	log := logger.FromContext(ctx)
+	log.Debug("AssignEmployee called", "pvz_id", pvzID, "user_id", userID)
+
+	if err := s.ensurePVZExists(ctx, pvzID); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Error("Error getting user", "error", err, "user_id", userID)
+		return err
+	}
+	if user == nil {
+		log.Warn("User not found", "user_id", userID)
+		return models.ErrUserNotFound
+	}
+	if user.Role != models.RoleEmployee {
+		log.Warn("User is not an employee", "user_id", userID, "role", user.Role)
+		return models.ErrUserNotEmployee
+	}
+
+	if err := s.userPVZRepo.AssignUser(ctx, userID, pvzID); err != nil {
+		log.Error("Error assigning employee", "error", err, "pvz_id", pvzID, "user_id", userID)
+		return err
+	}
+
+	log.Info("Employee assigned to PVZ", "pvz_id", pvzID, "user_id", userID)
+	return nil
+}
+
+func (s *AssignmentService) UnassignEmployee(ctx context.Context, pvzID, userID uuid.UUID) error {
+	log := logger.FromContext(ctx)
+	log.Debug("UnassignEmployee called", "pvz_id", pvzID, "user_id", userID)
+
+	if err := s.ensurePVZExists(ctx, pvzID); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Error("Error getting user", "error", err, "user_id", userID)
+		return err
+	}
+	if user == nil {
+		log.Warn("User not found", "user_id", userID)
+		return models.ErrUserNotFound
+	}
+
+	removed, err := s.userPVZRepo.UnassignUser(ctx, userID, pvzID)
+	if err != nil {
+		log.Error("Error unassigning employee", "error", err, "pvz_id", pvzID, "user_id", userID)
+		return err
+	}
+	if !removed {
+		log.Warn("Assignment not found", "pvz_id", pvzID, "user_id", userID)
+		return models.ErrAssignmentNotFound
+	}
+
+	log.Info("Employee unassigned from PVZ", "pvz_id", pvzID, "user_id", userID)
+	return nil
+}
+
+func (s *AssignmentService) ListEmployees(ctx context.Context, pvzID uuid.UUID) ([]*models.User, error) {
+	log := logger.FromContext(ctx)
+	log.Debug("ListEmployees called", "pvz_id", pvzID)
+
+	if err := s.ensurePVZExists(ctx, pvzID); err != nil {
+		return nil, err
+	}
+
+	users, err := s.userPVZRepo.ListUsersByPVZ(ctx, pvzID)
+	if err != nil {
+		log.Error("Error listing employees", "error", err, "pvz_id", pvzID)
+		return nil, err
+	}
+
+	log.Debug("Employees listed", "pvz_id", pvzID, "count", len(users))
+	return users, nil
+}
+
+func (s *AssignmentService) ensurePVZExists(ctx context.Context, pvzID uuid.UUID) error {
+	pvz, err := s.pvzRepo.GetPVZByID(ctx, pvzID)
+	if err != nil {
+		logger.FromContext(ctx).Error("Error getting PVZ", "error", err, "pvz_id", pvzID)
+		return err
+	}
+	if pvz == nil {
+		logger.FromContext(ctx).Warn("PVZ not found", "pvz_id", pvzID)
+		return models.ErrPVZNotFound
+	}
+	return nil
+}
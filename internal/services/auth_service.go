@@ -3,21 +3,40 @@ package services
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
 	"pvz-service/internal/auth"
 	"pvz-service/internal/domain/interfaces"
 	"pvz-service/internal/domain/models"
 	"pvz-service/internal/logger"
+	"pvz-service/internal/metrics"
 
 	"github.com/google/uuid"
 )
 
+// defaultDummyTokenTTL - время жизни тестового токена, если TTL не указан
+const defaultDummyTokenTTL = 24 * time.Hour
+
 type AuthService struct {
-	userRepo  interfaces.UserRepository
-	jwtSecret string
+	userRepo    interfaces.UserRepository
+	jwtSecret   string
+	tokenClaims auth.TokenClaimsConfig
+
+	// previousJWTSecret продолжает приниматься при проверке токенов во время ротации секрета
+	previousJWTSecret string
+
+	maxDummyTokenTTL time.Duration
+
+	// apiKeys - ключи внутренних клиентов, которые аутентифицируются без JWT
+	apiKeys auth.APIKeys
+
+	// blockedEmailDomains - домены (в нижнем регистре), с адресов которых регистрация запрещена
+	blockedEmailDomains map[string]bool
 }
 
+var _ interfaces.AuthService = (*AuthService)(nil)
+
 func NewAuthService(userRepo interfaces.UserRepository, jwtSecret string) *AuthService {
 	return &AuthService{
 		userRepo:  userRepo,
@@ -25,32 +44,100 @@ func NewAuthService(userRepo interfaces.UserRepository, jwtSecret string) *AuthS
 	}
 }
 
+// WithMaxDummyTokenTTL ограничивает запрашиваемое время жизни тестовых токенов. Ноль - без ограничения
+func (s *AuthService) WithMaxDummyTokenTTL(ttl time.Duration) *AuthService {
+	s.maxDummyTokenTTL = ttl
+	return s
+}
+
+// WithTokenClaims задает issuer и audience, которые устанавливаются в токены и проверяются при валидации
+func (s *AuthService) WithTokenClaims(issuer, audience string) *AuthService {
+	s.tokenClaims = auth.TokenClaimsConfig{Issuer: issuer, Audience: audience}
+	return s
+}
+
+// WithPreviousJWTSecret задает прежний секрет, токены которого остаются валидными до истечения.
+// Новые токены всегда подписываются текущим секретом
+func (s *AuthService) WithPreviousJWTSecret(secret string) *AuthService {
+	s.previousJWTSecret = secret
+	return s
+}
+
+// WithBlockedEmailDomains запрещает регистрацию с адресов на указанных доменах и их поддоменах.
+// Регистр и ведущий "@" не учитываются, пустые значения пропускаются
+func (s *AuthService) WithBlockedEmailDomains(domains []string) *AuthService {
+	blocked := make(map[string]bool, len(domains))
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "@"))
+		if domain != "" {
+			blocked[domain] = true
+		}
+	}
+	s.blockedEmailDomains = blocked
+	return s
+}
+
+// isEmailDomainBlocked проверяет домен адреса и все его родительские домены,
+// так что блокировка mailinator.com распространяется и на eu.mailinator.com
+func (s *AuthService) isEmailDomainBlocked(email string) bool {
+	if len(s.blockedEmailDomains) == 0 {
+		return false
+	}
+
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+
+	domain := strings.ToLower(email[at+1:])
+	for domain != "" {
+		if s.blockedEmailDomains[domain] {
+			return true
+		}
+		_, parent, ok := strings.Cut(domain, ".")
+		if !ok {
+			break
+		}
+		domain = parent
+	}
+	return false
+}
+
+func (s *AuthService) keyset() auth.Keyset {
+	return auth.Keyset{Current: s.jwtSecret, Previous: s.previousJWTSecret}
+}
+
 func (s *AuthService) Register(ctx context.Context, email, password string, role models.UserRole) (*models.User, error) {
 	log := logger.FromContext(ctx)
 	log.Debug("Register called", "email", email, "role", role)
 
+	if s.isEmailDomainBlocked(email) {
+		log.Warn("Email domain is blocked", logger.Outcome(logger.OutcomeValidationError), "email", email)
+		return nil, models.ErrEmailDomainBlocked
+	}
+
 	existingUser, err := s.userRepo.GetUserByEmail(ctx, email)
 	if err != nil {
-		log.Error("Error checking existing user", "error", err)
+		log.Error("Error checking existing user", logger.Outcome(logger.OutcomeError), "error", err)
 		return nil, err
 	}
 	if existingUser != nil {
-		log.Warn("User with this email already exists", "email", email)
+		log.Warn("User with this email already exists", logger.Outcome(logger.OutcomeConflict), "email", email)
 		return nil, errors.New("user with this email already exists")
 	}
 
 	if role != models.RoleEmployee && role != models.RoleModerator {
-		log.Warn("Invalid role provided", "role", role)
+		log.Warn("Invalid role provided", logger.Outcome(logger.OutcomeValidationError), "role", role)
 		return nil, errors.New("invalid role")
 	}
 
 	user, err := s.userRepo.CreateUser(ctx, email, password, role)
 	if err != nil {
-		log.Error("Error creating user", "error", err)
+		log.Error("Error creating user", logger.Outcome(logger.OutcomeError), "error", err)
 		return nil, err
 	}
 
-	log.Info("User registered successfully", "user_id", user.ID, "email", user.Email, "role", user.Role)
+	log.Info("User registered successfully", logger.Outcome(logger.OutcomeSuccess), "user_id", user.ID, "email", user.Email, "role", user.Role)
 	return user, nil
 }
 
@@ -60,35 +147,39 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (string
 
 	user, err := s.userRepo.GetUserByEmail(ctx, email)
 	if err != nil {
-		log.Error("Error getting user by email", "error", err)
+		log.Error("Error getting user by email", logger.Outcome(logger.OutcomeError), "error", err)
 		return "", err
 	}
 	if user == nil {
-		log.Warn("Invalid login attempt: user not found", "email", email)
+		log.Warn("Invalid login attempt: user not found", logger.Outcome(logger.OutcomeUnauthorized), "email", email)
+		metrics.IncrementLoginAttempt(metrics.LoginResultInvalidCredentials)
 		return "", errors.New("invalid email or password")
 	}
 
 	if !auth.CheckPasswordHash(password, user.Password) {
-		log.Warn("Invalid login attempt: wrong password", "email", email)
+		log.Warn("Invalid login attempt: wrong password", logger.Outcome(logger.OutcomeUnauthorized), "email", email)
+		metrics.IncrementLoginAttempt(metrics.LoginResultInvalidCredentials)
 		return "", errors.New("invalid email or password")
 	}
 
-	token, err := auth.GenerateToken(user, s.jwtSecret, 24*time.Hour)
+	token, err := auth.GenerateToken(user, s.jwtSecret, 24*time.Hour, s.tokenClaims)
 	if err != nil {
-		log.Error("Error generating token", "error", err)
+		log.Error("Error generating token", logger.Outcome(logger.OutcomeError), "error", err)
 		return "", err
 	}
 
-	log.Info("User logged in successfully", "user_id", user.ID, "email", user.Email)
+	metrics.IncrementLoginAttempt(metrics.LoginResultSuccess)
+
+	log.Info("User logged in successfully", logger.Outcome(logger.OutcomeSuccess), "user_id", user.ID, "email", user.Email)
 	return token, nil
 }
 
-func (s *AuthService) GenerateDummyToken(role models.UserRole) (string, error) {
+func (s *AuthService) GenerateDummyToken(role models.UserRole, ttl time.Duration) (string, error) {
 	log := logger.New(logger.Config{})
-	log.Debug("GenerateDummyToken called", "role", role)
+	log.Debug("GenerateDummyToken called", "role", role, "ttl", ttl.String())
 
 	if role != models.RoleEmployee && role != models.RoleModerator {
-		log.Warn("Invalid role for dummy token", "role", role)
+		log.Warn("Invalid role for dummy token", logger.Outcome(logger.OutcomeValidationError), "role", role)
 		return "", errors.New("invalid role")
 	}
 
@@ -99,13 +190,21 @@ func (s *AuthService) GenerateDummyToken(role models.UserRole) (string, error) {
 		CreatedAt: time.Now(),
 	}
 
-	token, err := auth.GenerateToken(dummyUser, s.jwtSecret, 24*time.Hour)
+	if ttl <= 0 {
+		ttl = defaultDummyTokenTTL
+	}
+	if s.maxDummyTokenTTL > 0 && ttl > s.maxDummyTokenTTL {
+		log.Debug("Dummy token TTL clamped", "requested", ttl.String(), "max", s.maxDummyTokenTTL.String())
+		ttl = s.maxDummyTokenTTL
+	}
+
+	token, err := auth.GenerateToken(dummyUser, s.jwtSecret, ttl, s.tokenClaims)
 	if err != nil {
-		log.Error("Error generating dummy token", "error", err)
+		log.Error("Error generating dummy token", logger.Outcome(logger.OutcomeError), "error", err)
 		return "", err
 	}
 
-	log.Info("Dummy token generated successfully", "role", role)
+	log.Info("Dummy token generated successfully", logger.Outcome(logger.OutcomeSuccess), "role", role)
 	return token, nil
 }
 
@@ -113,9 +212,9 @@ func (s *AuthService) ValidateToken(token string) (*models.User, error) {
 	log := logger.New(logger.Config{})
 	log.Debug("ValidateToken called")
 
-	claims, err := auth.ValidateToken(token, s.jwtSecret)
+	claims, err := auth.ValidateToken(token, s.keyset(), s.tokenClaims)
 	if err != nil {
-		log.Error("Error validating token", "error", err)
+		log.Error("Error validating token", logger.Outcome(logger.OutcomeError), "error", err)
 		return nil, err
 	}
 
@@ -125,6 +224,58 @@ func (s *AuthService) ValidateToken(token string) (*models.User, error) {
 		Role:  claims.Role,
 	}
 
-	log.Info("Token validated successfully", "user_id", user.ID, "email", user.Email, "role", user.Role)
+	log.Info("Token validated successfully", logger.Outcome(logger.OutcomeSuccess), "user_id", user.ID, "email", user.Email, "role", user.Role)
 	return user, nil
 }
+
+// WithAPIKeys задает API-ключи внутренних клиентов и их роли
+func (s *AuthService) WithAPIKeys(keys auth.APIKeys) *AuthService {
+	s.apiKeys = keys
+	return s
+}
+
+// ValidateAPIKey проверяет ключ из заголовка X-API-Key и возвращает пользователя с ролью ключа.
+// Идентификатор пользователя выводится из ключа, поэтому стабилен между запросами
+func (s *AuthService) ValidateAPIKey(key string) (*models.User, error) {
+	log := logger.New(logger.Config{})
+	log.Debug("ValidateAPIKey called")
+
+	role, err := s.apiKeys.Lookup(key)
+	if err != nil {
+		log.Warn("Invalid API key", logger.Outcome(logger.OutcomeUnauthorized))
+		return nil, err
+	}
+
+	keyID := auth.KeyID(key)
+	user := &models.User{
+		ID:    uuid.NewSHA1(uuid.NameSpaceOID, []byte("api-key:"+keyID)),
+		Email: "api-key:" + keyID,
+		Role:  role,
+	}
+
+	log.Info("API key validated successfully", logger.Outcome(logger.OutcomeSuccess), "key_id", keyID, "role", role)
+	return user, nil
+}
+
+func (s *AuthService) IntrospectToken(token string) (*models.TokenIntrospection, error) {
+	log := logger.New(logger.Config{})
+	log.Debug("IntrospectToken called")
+
+	claims, err := auth.ValidateToken(token, s.keyset(), s.tokenClaims)
+	if err != nil {
+		log.Info("Token introspection: token is not active", logger.Outcome(logger.OutcomeUnauthorized), "error", err)
+		return nil, err
+	}
+
+	result := &models.TokenIntrospection{
+		Active: true,
+		UserID: &claims.UserID,
+		Role:   claims.Role,
+	}
+	if claims.ExpiresAt != nil {
+		result.Exp = claims.ExpiresAt.Unix()
+	}
+
+	log.Info("Token introspected successfully", logger.Outcome(logger.OutcomeSuccess), "user_id", claims.UserID, "role", claims.Role)
+	return result, nil
+}
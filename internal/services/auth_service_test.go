@@ -6,12 +6,15 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
 	"pvz-service/internal/auth"
 	"pvz-service/internal/domain/models"
+	"pvz-service/internal/metrics"
 )
 
 type MockUserRepository struct {
@@ -167,6 +170,48 @@ func TestAuthService_Register(t *testing.T) {
 	}
 }
 
+func TestAuthService_Register_BlockedEmailDomains(t *testing.T) {
+	testCases := []struct {
+		name        string
+		email       string
+		expectedErr error
+	}{
+		{name: "Blocked Domain", email: "user@mailinator.com", expectedErr: models.ErrEmailDomainBlocked},
+		{name: "Blocked Domain Case Insensitive", email: "user@MailInator.COM", expectedErr: models.ErrEmailDomainBlocked},
+		{name: "Blocked Subdomain", email: "user@eu.mailinator.com", expectedErr: models.ErrEmailDomainBlocked},
+		{name: "Second Blocked Domain", email: "user@tempmail.org", expectedErr: models.ErrEmailDomainBlocked},
+		{name: "Allowed Domain", email: "user@example.com"},
+		{name: "Allowed Domain With Blocked Suffix", email: "user@notmailinator.com"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepository)
+			if tc.expectedErr == nil {
+				mockRepo.On("GetUserByEmail", mock.Anything, tc.email).Return(nil, nil)
+				mockRepo.On("CreateUser", mock.Anything, tc.email, "password123", models.RoleEmployee).
+					Return(&models.User{ID: uuid.New(), Email: tc.email, Role: models.RoleEmployee}, nil)
+			}
+
+			service := NewAuthService(mockRepo, "test_jwt_secret").
+				WithBlockedEmailDomains([]string{" mailinator.com", "@TempMail.org", ""})
+
+			user, err := service.Register(context.Background(), tc.email, "password123", models.RoleEmployee)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				assert.Nil(t, user)
+				mockRepo.AssertNotCalled(t, "GetUserByEmail", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.email, user.Email)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
 func TestAuthService_Login(t *testing.T) {
 	hashedPassword, _ := auth.HashPassword("password123")
 
@@ -286,7 +331,7 @@ func TestAuthService_GenerateDummyToken(t *testing.T) {
 			mockRepo := new(MockUserRepository)
 			service := NewAuthService(mockRepo, "test_jwt_secret")
 
-			token, err := service.GenerateDummyToken(tc.role)
+			token, err := service.GenerateDummyToken(tc.role, 0)
 
 			if tc.expectedError {
 				assert.Error(t, err)
@@ -307,7 +352,7 @@ func TestAuthService_ValidateToken(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	service := NewAuthService(mockRepo, "test_jwt_secret")
 
-	validToken, _ := service.GenerateDummyToken(models.RoleEmployee)
+	validToken, _ := service.GenerateDummyToken(models.RoleEmployee, 0)
 
 	testCases := []struct {
 		name          string
@@ -358,3 +403,278 @@ func TestAuthService_ValidateToken(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthService_IntrospectToken(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	service := NewAuthService(mockRepo, "test_jwt_secret")
+
+	userID := uuid.New()
+	user := &models.User{ID: userID, Email: "test@example.com", Role: models.RoleModerator}
+
+	validToken, err := auth.GenerateToken(user, "test_jwt_secret", time.Hour, auth.TokenClaimsConfig{})
+	assert.NoError(t, err)
+
+	expiredToken, err := auth.GenerateToken(user, "test_jwt_secret", -time.Hour, auth.TokenClaimsConfig{})
+	assert.NoError(t, err)
+
+	foreignToken, err := auth.GenerateToken(user, "another_secret", time.Hour, auth.TokenClaimsConfig{})
+	assert.NoError(t, err)
+
+	t.Run("Success - Valid Token", func(t *testing.T) {
+		result, err := service.IntrospectToken(validToken)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, result)
+		assert.True(t, result.Active)
+		assert.Equal(t, userID, *result.UserID)
+		assert.Equal(t, models.RoleModerator, result.Role)
+		assert.InDelta(t, time.Now().Add(time.Hour).Unix(), result.Exp, 5)
+	})
+
+	for name, token := range map[string]string{
+		"Failure - Expired Token":   expiredToken,
+		"Failure - Wrong Signature": foreignToken,
+		"Failure - Malformed Token": "not-a-jwt",
+	} {
+		t.Run(name, func(t *testing.T) {
+			result, err := service.IntrospectToken(token)
+
+			assert.Error(t, err)
+			assert.Nil(t, result)
+		})
+	}
+}
+
+func TestAuthService_ValidateToken_IssuerAudience(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com", Role: models.RoleEmployee}
+	expected := auth.TokenClaimsConfig{Issuer: "pvz-service", Audience: "pvz-clients"}
+
+	testCases := []struct {
+		name        string
+		tokenClaims auth.TokenClaimsConfig
+		expectedErr error
+	}{
+		{
+			name:        "Success - Matching Claims",
+			tokenClaims: expected,
+		},
+		{
+			name:        "Failure - Wrong Issuer",
+			tokenClaims: auth.TokenClaimsConfig{Issuer: "other-service", Audience: "pvz-clients"},
+			expectedErr: auth.ErrInvalidIssuer,
+		},
+		{
+			name:        "Failure - Wrong Audience",
+			tokenClaims: auth.TokenClaimsConfig{Issuer: "pvz-service", Audience: "other-clients"},
+			expectedErr: auth.ErrInvalidAudience,
+		},
+		{
+			name:        "Failure - Missing Claims",
+			tokenClaims: auth.TokenClaimsConfig{},
+			expectedErr: auth.ErrInvalidIssuer,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			service := NewAuthService(new(MockUserRepository), "test_jwt_secret").
+				WithTokenClaims(expected.Issuer, expected.Audience)
+
+			token, err := auth.GenerateToken(user, "test_jwt_secret", time.Hour, tc.tokenClaims)
+			assert.NoError(t, err)
+
+			result, err := service.ValidateToken(token)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, user.ID, result.ID)
+			}
+		})
+	}
+}
+
+func TestAuthService_ValidateToken_ClaimsCheckDisabled(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com", Role: models.RoleEmployee}
+	service := NewAuthService(new(MockUserRepository), "test_jwt_secret")
+
+	token, err := auth.GenerateToken(user, "test_jwt_secret", time.Hour,
+		auth.TokenClaimsConfig{Issuer: "any-issuer", Audience: "any-audience"})
+	assert.NoError(t, err)
+
+	result, err := service.ValidateToken(token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, result.ID)
+}
+
+func TestAuthService_GenerateDummyToken_TTL(t *testing.T) {
+	testCases := []struct {
+		name        string
+		requested   time.Duration
+		expectedTTL time.Duration
+	}{
+		{name: "Default TTL When Omitted", requested: 0, expectedTTL: 24 * time.Hour},
+		{name: "Short TTL Honored", requested: 90 * time.Second, expectedTTL: 90 * time.Second},
+		{name: "Long TTL Clamped To Max", requested: 30 * 24 * time.Hour, expectedTTL: 48 * time.Hour},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			service := NewAuthService(new(MockUserRepository), "test_jwt_secret").
+				WithMaxDummyTokenTTL(48 * time.Hour)
+
+			token, err := service.GenerateDummyToken(models.RoleEmployee, tc.requested)
+			assert.NoError(t, err)
+
+			claims, err := auth.ValidateToken(token, auth.Keyset{Current: "test_jwt_secret"}, auth.TokenClaimsConfig{})
+			assert.NoError(t, err)
+			assert.InDelta(t, time.Now().Add(tc.expectedTTL).Unix(), claims.ExpiresAt.Unix(), 5)
+		})
+	}
+}
+
+func TestAuthService_ValidateToken_KeyRotation(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "test@example.com", Role: models.RoleEmployee}
+
+	oldToken, err := auth.GenerateToken(user, "old_jwt_secret", time.Hour, auth.TokenClaimsConfig{})
+	assert.NoError(t, err)
+
+	t.Run("Success - Token Signed With Previous Key", func(t *testing.T) {
+		service := NewAuthService(new(MockUserRepository), "new_jwt_secret").
+			WithPreviousJWTSecret("old_jwt_secret")
+
+		validated, err := service.ValidateToken(oldToken)
+		assert.NoError(t, err)
+		assert.Equal(t, user.ID, validated.ID)
+	})
+
+	t.Run("Success - New Tokens Use Current Key", func(t *testing.T) {
+		service := NewAuthService(new(MockUserRepository), "new_jwt_secret").
+			WithPreviousJWTSecret("old_jwt_secret")
+
+		token, err := service.GenerateDummyToken(models.RoleModerator, 0)
+		assert.NoError(t, err)
+
+		_, err = auth.ValidateToken(token, auth.Keyset{Current: "new_jwt_secret"}, auth.TokenClaimsConfig{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Failure - Previous Key Removed After Overlap", func(t *testing.T) {
+		service := NewAuthService(new(MockUserRepository), "new_jwt_secret")
+
+		_, err := service.ValidateToken(oldToken)
+		assert.ErrorIs(t, err, auth.ErrUnknownKeyID)
+	})
+
+	t.Run("Success - Legacy Token Without Kid", func(t *testing.T) {
+		claims := &auth.Claims{
+			UserID:           user.ID,
+			Role:             user.Role,
+			RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+		}
+		legacyToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("old_jwt_secret"))
+		assert.NoError(t, err)
+
+		service := NewAuthService(new(MockUserRepository), "new_jwt_secret").
+			WithPreviousJWTSecret("old_jwt_secret")
+
+		validated, err := service.ValidateToken(legacyToken)
+		assert.NoError(t, err)
+		assert.Equal(t, user.ID, validated.ID)
+	})
+}
+
+// loginAttempts возвращает текущее значение auth_login_attempts_total для результата
+func loginAttempts(t *testing.T, result string) float64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	assert.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != "auth_login_attempts_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "result" && label.GetValue() == result {
+					return metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func TestAuthService_Login_AttemptsMetric(t *testing.T) {
+	hashedPassword, _ := auth.HashPassword("password123")
+	user := &models.User{
+		ID:       uuid.New(),
+		Email:    "user@example.com",
+		Password: hashedPassword,
+		Role:     models.RoleEmployee,
+	}
+
+	testCases := []struct {
+		name     string
+		email    string
+		password string
+		result   string
+	}{
+		{name: "Success", email: "user@example.com", password: "password123", result: metrics.LoginResultSuccess},
+		{name: "Wrong Password", email: "user@example.com", password: "wrong", result: metrics.LoginResultInvalidCredentials},
+		{name: "Unknown User", email: "nobody@example.com", password: "password123", result: metrics.LoginResultInvalidCredentials},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepository)
+			mockRepo.On("GetUserByEmail", mock.Anything, "user@example.com").Return(user, nil)
+			mockRepo.On("GetUserByEmail", mock.Anything, "nobody@example.com").Return(nil, nil)
+
+			service := NewAuthService(mockRepo, "test_jwt_secret")
+
+			before := loginAttempts(t, tc.result)
+			_, _ = service.Login(context.Background(), tc.email, tc.password)
+
+			assert.Equal(t, before+1, loginAttempts(t, tc.result))
+		})
+	}
+}
+
+func TestAuthService_ValidateAPIKey(t *testing.T) {
+	keys, err := auth.ParseAPIKeys("reporting-key:moderator, sync-key:employee")
+	assert.NoError(t, err)
+
+	service := NewAuthService(new(MockUserRepository), "test_jwt_secret").WithAPIKeys(keys)
+
+	t.Run("Success - Key Grants Mapped Role", func(t *testing.T) {
+		user, err := service.ValidateAPIKey("reporting-key")
+		assert.NoError(t, err)
+		assert.Equal(t, models.RoleModerator, user.Role)
+
+		again, err := service.ValidateAPIKey("reporting-key")
+		assert.NoError(t, err)
+		assert.Equal(t, user.ID, again.ID)
+	})
+
+	t.Run("Failure - Unknown Key", func(t *testing.T) {
+		user, err := service.ValidateAPIKey("unknown-key")
+		assert.ErrorIs(t, err, auth.ErrInvalidAPIKey)
+		assert.Nil(t, user)
+	})
+
+	t.Run("Failure - No Keys Configured", func(t *testing.T) {
+		user, err := NewAuthService(new(MockUserRepository), "test_jwt_secret").ValidateAPIKey("reporting-key")
+		assert.ErrorIs(t, err, auth.ErrInvalidAPIKey)
+		assert.Nil(t, user)
+	})
+}
+
+func TestParseAPIKeys_InvalidEntries(t *testing.T) {
+	for _, spec := range []string{"no-role", "key:admin", ":employee"} {
+		_, err := auth.ParseAPIKeys(spec)
+		assert.Error(t, err, spec)
+	}
+}
@@ -0,0 +1,19 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/domain/interfaces"
+)
+
+// Основная проверка - объявления var _ в файлах сервисов: расхождение сигнатур
+// ломает сборку пакета. Тест фиксирует соответствие и при запуске тестов
+func TestServicesImplementInterfaces(t *testing.T) {
+	assert.Implements(t, (*interfaces.AuthService)(nil), new(AuthService))
+	assert.Implements(t, (*interfaces.PVZService)(nil), new(PVZService))
+	assert.Implements(t, (*interfaces.ReceptionService)(nil), new(ReceptionService))
+	assert.Implements(t, (*interfaces.ProductService)(nil), new(ProductService))
+	assert.Implements(t, (*interfaces.AssignmentService)(nil), new(AssignmentService))
+}
@@ -16,8 +16,13 @@ type ProductService struct {
 	productRepo   interfaces.ProductRepository
 	receptionRepo interfaces.ReceptionRepository
 	pvzRepo       interfaces.PVZRepository
+
+	maxProductsPerReception int
+	events                  interfaces.EventPublisher
 }
 
+var _ interfaces.ProductService = (*ProductService)(nil)
+
 func NewProductService(productRepo interfaces.ProductRepository, receptionRepo interfaces.ReceptionRepository, pvzRepo interfaces.PVZRepository) *ProductService {
 	return &ProductService{
 		productRepo:   productRepo,
@@ -26,51 +31,68 @@ func NewProductService(productRepo interfaces.ProductRepository, receptionRepo i
 	}
 }
 
+// WithMaxProductsPerReception задает максимальное число товаров в одной приемке.
+// Ноль означает отсутствие ограничения
+func (s *ProductService) WithMaxProductsPerReception(limit int) *ProductService {
+	s.maxProductsPerReception = limit
+	return s
+}
+
+// WithEventPublisher задает получателя событий о добавленных товарах
+func (s *ProductService) WithEventPublisher(events interfaces.EventPublisher) *ProductService {
+	s.events = events
+	return s
+}
+
 func (s *ProductService) AddProduct(ctx context.Context, pvzID uuid.UUID, productType models.ProductType) (*models.Product, error) {
 	log := logger.FromContext(ctx)
 	log.Debug("AddProduct called", "pvz_id", pvzID, "product_type", productType)
 
 	pvz, err := s.pvzRepo.GetPVZByID(ctx, pvzID)
 	if err != nil {
-		log.Error("Error getting PVZ", "error", err, "pvz_id", pvzID)
+		log.Error("Error getting PVZ", logger.Outcome(logger.OutcomeError), "error", err, "pvz_id", pvzID)
 		return nil, err
 	}
 	if pvz == nil {
-		log.Warn("PVZ not found", "pvz_id", pvzID)
+		log.Warn("PVZ not found", logger.Outcome(logger.OutcomeNotFound), "pvz_id", pvzID)
 		return nil, errors.New("pvz not found")
 	}
 
-	if productType != models.TypeElectronics && productType != models.TypeClothes && productType != models.TypeFootwear {
-		log.Warn("Invalid product type", "product_type", productType)
+	if !productType.Valid() {
+		log.Warn("Invalid product type", logger.Outcome(logger.OutcomeValidationError), "product_type", productType)
 		return nil, errors.New("invalid product type")
 	}
 
 	openReception, err := s.receptionRepo.GetLastOpenReceptionByPVZID(ctx, pvzID)
 	if err != nil {
-		log.Error("Error getting last open reception", "error", err, "pvz_id", pvzID)
+		log.Error("Error getting last open reception", logger.Outcome(logger.OutcomeError), "error", err, "pvz_id", pvzID)
 		return nil, err
 	}
 	if openReception == nil {
-		log.Warn("No open reception found", "pvz_id", pvzID)
+		log.Warn("No open reception found", logger.Outcome(logger.OutcomeConflict), "pvz_id", pvzID)
 		return nil, errors.New("no open reception found for this pvz")
 	}
 
-	count, err := s.productRepo.CountProductsByReceptionID(ctx, openReception.ID)
+	// Строка приемки блокируется в репозитории, поэтому проверка лимита, нумерация
+	// и параллельное удаление последнего товара выполняются последовательно
+	log.Debug("Creating product", "reception_id", openReception.ID)
+	product, err := s.productRepo.AddProductToOpenReception(ctx, productType, openReception.ID, s.maxProductsPerReception)
 	if err != nil {
-		log.Error("Error counting products", "error", err, "reception_id", openReception.ID)
-		return nil, err
-	}
-
-	log.Debug("Creating product with sequence number", "reception_id", openReception.ID, "sequence_num", count+1)
-	product, err := s.productRepo.CreateProduct(ctx, productType, openReception.ID, count+1)
-	if err != nil {
-		log.Error("Error creating product", "error", err)
+		switch {
+		case errors.Is(err, models.ErrReceptionFull):
+			log.Warn("Reception is full", logger.Outcome(logger.OutcomeConflict), "reception_id", openReception.ID, "limit", s.maxProductsPerReception)
+		case errors.Is(err, models.ErrReceptionClosed):
+			log.Warn("Reception was closed concurrently", logger.Outcome(logger.OutcomeConflict), "reception_id", openReception.ID)
+		default:
+			log.Error("Error creating product", logger.Outcome(logger.OutcomeError), "error", err)
+		}
 		return nil, err
 	}
 
 	metrics.IncrementProductAdded()
 
-	log.Info("Product added successfully", "product_id", product.ID, "pvz_id", pvzID, "reception_id", openReception.ID)
+	log.Info("Product added successfully", logger.Outcome(logger.OutcomeSuccess), "product_id", product.ID, "pvz_id", pvzID, "reception_id", openReception.ID)
+	publishEvent(s.events, models.EventProductAdded, pvzID, *product)
 	return product, nil
 }
 
@@ -80,54 +102,233 @@ func (s *ProductService) DeleteLastProduct(ctx context.Context, pvzID uuid.UUID)
 
 	openReception, err := s.receptionRepo.GetLastOpenReceptionByPVZID(ctx, pvzID)
 	if err != nil {
-		log.Error("Error getting last open reception", "error", err, "pvz_id", pvzID)
+		log.Error("Error getting last open reception", logger.Outcome(logger.OutcomeError), "error", err, "pvz_id", pvzID)
 		return err
 	}
 	if openReception == nil {
-		log.Warn("No open reception found", "pvz_id", pvzID)
+		log.Warn("No open reception found", logger.Outcome(logger.OutcomeConflict), "pvz_id", pvzID)
 		return errors.New("no open reception found for this pvz")
 	}
 
-	lastProduct, err := s.productRepo.GetLastProductByReceptionID(ctx, openReception.ID)
+	lastProduct, err := s.productRepo.DeleteLastProductFromOpenReception(ctx, openReception.ID)
 	if err != nil {
-		log.Error("Error getting last product", "error", err, "reception_id", openReception.ID)
+		if errors.Is(err, models.ErrReceptionClosed) {
+			log.Warn("Reception was closed concurrently", logger.Outcome(logger.OutcomeConflict), "reception_id", openReception.ID)
+			return err
+		}
+		log.Error("Error deleting last product", logger.Outcome(logger.OutcomeError), "error", err, "reception_id", openReception.ID)
 		return err
 	}
 	if lastProduct == nil {
-		log.Warn("No products in reception", "reception_id", openReception.ID)
+		log.Warn("No products in reception", logger.Outcome(logger.OutcomeConflict), "reception_id", openReception.ID)
 		return errors.New("no products in this reception")
 	}
 
-	err = s.productRepo.DeleteProductByID(ctx, lastProduct.ID)
+	log.Info("Product deleted successfully", logger.Outcome(logger.OutcomeSuccess), "product_id", lastProduct.ID, "pvz_id", pvzID)
+	return nil
+}
+
+// GetProductByID возвращает товар по идентификатору или models.ErrProductNotFound
+func (s *ProductService) GetProductByID(ctx context.Context, productID uuid.UUID) (*models.Product, error) {
+	log := logger.FromContext(ctx)
+	log.Debug("GetProductByID called", "product_id", productID)
+
+	product, err := s.productRepo.GetProductByID(ctx, productID)
+	if err != nil {
+		log.Error("Error getting product", logger.Outcome(logger.OutcomeError), "error", err, "product_id", productID)
+		return nil, err
+	}
+	if product == nil {
+		log.Warn("Product not found", logger.Outcome(logger.OutcomeNotFound), "product_id", productID)
+		return nil, models.ErrProductNotFound
+	}
+
+	log.Info("Product retrieved successfully", logger.Outcome(logger.OutcomeSuccess), "product_id", productID)
+	return product, nil
+}
+
+func (s *ProductService) UpdateProductType(ctx context.Context, productID uuid.UUID, productType models.ProductType) error {
+	log := logger.FromContext(ctx)
+	log.Debug("UpdateProductType called", "product_id", productID, "product_type", productType)
+
+	if !productType.Valid() {
+		log.Warn("Invalid product type", logger.Outcome(logger.OutcomeValidationError), "product_type", productType)
+		return errors.New("invalid product type")
+	}
+
+	product, err := s.productRepo.GetProductByID(ctx, productID)
 	if err != nil {
-		log.Error("Error deleting product", "error", err, "product_id", lastProduct.ID)
+		log.Error("Error getting product", logger.Outcome(logger.OutcomeError), "error", err, "product_id", productID)
 		return err
 	}
+	if product == nil {
+		log.Warn("Product not found", logger.Outcome(logger.OutcomeNotFound), "product_id", productID)
+		return models.ErrProductNotFound
+	}
 
-	log.Info("Product deleted successfully", "product_id", lastProduct.ID, "pvz_id", pvzID)
+	reception, err := s.receptionRepo.GetReceptionByID(ctx, product.ReceptionID)
+	if err != nil {
+		log.Error("Error getting reception", logger.Outcome(logger.OutcomeError), "error", err, "reception_id", product.ReceptionID)
+		return err
+	}
+	if reception == nil || reception.Status != models.StatusInProgress {
+		log.Warn("Reception is not open", logger.Outcome(logger.OutcomeConflict), "product_id", productID, "reception_id", product.ReceptionID)
+		return models.ErrReceptionClosed
+	}
+
+	if err := s.productRepo.UpdateProductType(ctx, productID, productType); err != nil {
+		log.Error("Error updating product type", logger.Outcome(logger.OutcomeError), "error", err, "product_id", productID)
+		return err
+	}
+
+	log.Info("Product type updated successfully", logger.Outcome(logger.OutcomeSuccess), "product_id", productID, "product_type", productType)
 	return nil
 }
 
-func (s *ProductService) GetProductsByReceptionID(ctx context.Context, receptionID uuid.UUID, page, limit int) ([]*models.Product, int, error) {
+// MoveProduct переносит ошибочно отсканированный товар в открытую приемку другого ПВЗ.
+// Исходная и целевая приемки должны быть открыты, иначе возвращается models.ErrReceptionClosed
+func (s *ProductService) MoveProduct(ctx context.Context, productID, targetPVZID uuid.UUID) error {
 	log := logger.FromContext(ctx)
-	log.Debug("GetProductsByReceptionID called", "reception_id", receptionID, "page", page, "limit", limit)
+	log.Debug("MoveProduct called", "product_id", productID, "target_pvz_id", targetPVZID)
+
+	pvz, err := s.pvzRepo.GetPVZByID(ctx, targetPVZID)
+	if err != nil {
+		log.Error("Error getting PVZ", logger.Outcome(logger.OutcomeError), "error", err, "pvz_id", targetPVZID)
+		return err
+	}
+	if pvz == nil {
+		log.Warn("Target PVZ not found", logger.Outcome(logger.OutcomeNotFound), "pvz_id", targetPVZID)
+		return models.ErrPVZNotFound
+	}
+
+	targetReception, err := s.receptionRepo.GetLastOpenReceptionByPVZID(ctx, targetPVZID)
+	if err != nil {
+		log.Error("Error getting last open reception", logger.Outcome(logger.OutcomeError), "error", err, "pvz_id", targetPVZID)
+		return err
+	}
+	if targetReception == nil {
+		log.Warn("No open reception in target PVZ", logger.Outcome(logger.OutcomeConflict), "pvz_id", targetPVZID)
+		return models.ErrReceptionClosed
+	}
+
+	product, err := s.productRepo.MoveProduct(ctx, productID, targetReception.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrProductNotFound):
+			log.Warn("Product not found", logger.Outcome(logger.OutcomeNotFound), "product_id", productID)
+		case errors.Is(err, models.ErrReceptionClosed), errors.Is(err, models.ErrReceptionNotFound):
+			log.Warn("Reception is not open", logger.Outcome(logger.OutcomeConflict), "product_id", productID, "target_reception_id", targetReception.ID)
+		default:
+			log.Error("Error moving product", logger.Outcome(logger.OutcomeError), "error", err, "product_id", productID)
+		}
+		return err
+	}
+
+	log.Info("Product moved successfully", logger.Outcome(logger.OutcomeSuccess),
+		"product_id", productID,
+		"pvz_id", targetPVZID,
+		"reception_id", product.ReceptionID,
+		"sequence_num", product.SequenceNum,
+	)
+	return nil
+}
+
+// GetRecentProducts возвращает последние товары открытой приемки ПВЗ.
+// Если открытой приемки нет, возвращается пустой список
+func (s *ProductService) GetRecentProducts(ctx context.Context, pvzID uuid.UUID, n int) ([]*models.Product, error) {
+	log := logger.FromContext(ctx)
+	log.Debug("GetRecentProducts called", "pvz_id", pvzID, "n", n)
+
+	openReception, err := s.receptionRepo.GetLastOpenReceptionByPVZID(ctx, pvzID)
+	if err != nil {
+		log.Error("Error getting last open reception", logger.Outcome(logger.OutcomeError), "error", err, "pvz_id", pvzID)
+		return nil, err
+	}
+	if openReception == nil {
+		log.Debug("No open reception found", "pvz_id", pvzID)
+		return []*models.Product{}, nil
+	}
+
+	products, err := s.productRepo.GetRecentProducts(ctx, openReception.ID, n)
+	if err != nil {
+		log.Error("Error getting recent products", logger.Outcome(logger.OutcomeError), "error", err, "reception_id", openReception.ID)
+		return nil, err
+	}
+
+	log.Debug("Recent products retrieved", "reception_id", openReception.ID, "count", len(products))
+	return emptyIfNil(products), nil
+}
+
+func (s *ProductService) ClearReception(ctx context.Context, pvzID uuid.UUID) (int, error) {
+	log := logger.FromContext(ctx)
+	log.Debug("ClearReception called", "pvz_id", pvzID)
+
+	openReception, err := s.receptionRepo.GetLastOpenReceptionByPVZID(ctx, pvzID)
+	if err != nil {
+		log.Error("Error getting last open reception", logger.Outcome(logger.OutcomeError), "error", err, "pvz_id", pvzID)
+		return 0, err
+	}
+	if openReception == nil {
+		log.Warn("No open reception found", logger.Outcome(logger.OutcomeConflict), "pvz_id", pvzID)
+		return 0, errors.New("no open reception found for this pvz")
+	}
+
+	deleted, err := s.productRepo.DeleteProductsByReceptionID(ctx, openReception.ID)
+	if err != nil {
+		log.Error("Error clearing reception", logger.Outcome(logger.OutcomeError), "error", err, "reception_id", openReception.ID)
+		return 0, err
+	}
+
+	log.Info("Reception cleared successfully", logger.Outcome(logger.OutcomeSuccess), "pvz_id", pvzID, "reception_id", openReception.ID, "deleted", deleted)
+	return deleted, nil
+}
+
+func (s *ProductService) GetProductsByReceptionID(ctx context.Context, receptionID uuid.UUID, page, limit int, sort string) ([]*models.Product, int, error) {
+	log := logger.FromContext(ctx)
+	log.Debug("GetProductsByReceptionID called", "reception_id", receptionID, "page", page, "limit", limit, "sort", sort)
 
 	reception, err := s.receptionRepo.GetReceptionByID(ctx, receptionID)
 	if err != nil {
-		log.Error("Error getting reception", "error", err, "reception_id", receptionID)
+		log.Error("Error getting reception", logger.Outcome(logger.OutcomeError), "error", err, "reception_id", receptionID)
 		return nil, 0, err
 	}
 	if reception == nil {
-		log.Warn("Reception not found", "reception_id", receptionID)
+		log.Warn("Reception not found", logger.Outcome(logger.OutcomeNotFound), "reception_id", receptionID)
 		return nil, 0, errors.New("reception not found")
 	}
 
-	products, total, err := s.productRepo.GetProductsByReceptionID(ctx, receptionID, page, limit)
+	products, total, err := s.productRepo.GetProductsByReceptionID(ctx, receptionID, page, limit, sort)
+	if err != nil {
+		log.Error("Error getting products", logger.Outcome(logger.OutcomeError), "error", err, "reception_id", receptionID)
+		return nil, 0, err
+	}
+
+	log.Info("Products retrieved successfully", logger.Outcome(logger.OutcomeSuccess), "reception_id", receptionID, "count", len(products), "total", total)
+	return emptyIfNil(products), total, nil
+}
+
+// ListPVZProducts возвращает страницу товаров всех приемок ПВЗ и их общее число.
+// Если ПВЗ не найден, возвращает models.ErrPVZNotFound
+func (s *ProductService) ListPVZProducts(ctx context.Context, pvzID uuid.UUID, productType models.ProductType, page, limit int) ([]*models.Product, int, error) {
+	log := logger.FromContext(ctx)
+	log.Debug("ListPVZProducts called", "pvz_id", pvzID, "type", productType, "page", page, "limit", limit)
+
+	pvz, err := s.pvzRepo.GetPVZByID(ctx, pvzID)
+	if err != nil {
+		log.Error("Error getting PVZ", logger.Outcome(logger.OutcomeError), "error", err, "pvz_id", pvzID)
+		return nil, 0, err
+	}
+	if pvz == nil {
+		log.Warn("PVZ not found", logger.Outcome(logger.OutcomeNotFound), "pvz_id", pvzID)
+		return nil, 0, models.ErrPVZNotFound
+	}
+
+	products, total, err := s.productRepo.ListProductsByPVZID(ctx, pvzID, productType, page, limit)
 	if err != nil {
-		log.Error("Error getting products", "error", err, "reception_id", receptionID)
+		log.Error("Error listing PVZ products", logger.Outcome(logger.OutcomeError), "error", err, "pvz_id", pvzID)
 		return nil, 0, err
 	}
 
-	log.Info("Products retrieved successfully", "reception_id", receptionID, "count", len(products), "total", total)
-	return products, total, nil
+	log.Info("PVZ products listed successfully", logger.Outcome(logger.OutcomeSuccess), "pvz_id", pvzID, "count", len(products), "total", total)
+	return emptyIfNil(products), total, nil
 }
@@ -2,14 +2,17 @@ package services
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"pvz-service/internal/domain/models"
+	"pvz-service/internal/notifier"
 )
 
 var (
@@ -31,12 +34,28 @@ func (m *ProductTestMockPVZRepository) GetPVZByID(ctx context.Context, id uuid.U
 	return args.Get(0).(*models.PVZ), args.Error(1)
 }
 
-func (m *ProductTestMockPVZRepository) CreatePVZ(ctx context.Context, city string) (*models.PVZ, error) {
-	args := m.Called(ctx, city)
+func (m *ProductTestMockPVZRepository) GetPVZsByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.PVZ, error) {
+	args := m.Called(ctx, ids)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*models.PVZ), args.Error(1)
+	return args.Get(0).([]*models.PVZ), args.Error(1)
+}
+
+func (m *ProductTestMockPVZRepository) CreatePVZ(ctx context.Context, city, externalID string) (*models.PVZ, bool, error) {
+	args := m.Called(ctx, city, externalID)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).(*models.PVZ), args.Bool(1), args.Error(2)
+}
+
+func (m *ProductTestMockPVZRepository) CreatePVZBatch(ctx context.Context, cities []string) ([]*models.PVZ, error) {
+	args := m.Called(ctx, cities)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.PVZ), args.Error(1)
 }
 
 func (m *ProductTestMockPVZRepository) ListPVZ(ctx context.Context, options models.PVZListOptions) ([]*models.PVZWithReceptionsResponse, int, error) {
@@ -47,6 +66,34 @@ func (m *ProductTestMockPVZRepository) ListPVZ(ctx context.Context, options mode
 	return args.Get(0).([]*models.PVZWithReceptionsResponse), args.Int(1), args.Error(2)
 }
 
+func (m *ProductTestMockPVZRepository) CountPVZByCity(ctx context.Context) ([]models.CityPVZCount, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.CityPVZCount), args.Error(1)
+}
+
+func (m *ProductTestMockPVZRepository) StreamPVZ(ctx context.Context, fn func(*models.PVZ) error) error {
+	args := m.Called(ctx)
+	if pvzs, ok := args.Get(0).([]*models.PVZ); ok {
+		for _, pvz := range pvzs {
+			if err := fn(pvz); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *ProductTestMockPVZRepository) ListPVZWithReceptionsBetween(ctx context.Context, from, to time.Time) ([]*models.PVZ, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.PVZ), args.Error(1)
+}
+
 type ProductTestMockReceptionRepository struct {
 	mock.Mock
 }
@@ -59,11 +106,45 @@ func (m *ProductTestMockReceptionRepository) GetLastOpenReceptionByPVZID(ctx con
 	return args.Get(0).(*models.Reception), args.Error(1)
 }
 
+func (m *ProductTestMockReceptionRepository) CloseStaleReception(ctx context.Context, id uuid.UUID, olderThan time.Time) (bool, error) {
+	args := m.Called(ctx, id, olderThan)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *ProductTestMockReceptionRepository) CloseReception(ctx context.Context, id uuid.UUID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
+func (m *ProductTestMockReceptionRepository) CountReceptionsByStatus(ctx context.Context, pvzID *uuid.UUID) (map[string]int, error) {
+	args := m.Called(ctx, pvzID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int), args.Error(1)
+}
+
+func (m *ProductTestMockReceptionRepository) PurgeReceptions(ctx context.Context, before time.Time) (int, error) {
+	args := m.Called(ctx, before)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *ProductTestMockReceptionRepository) ListOpenReceptionsOlderThan(ctx context.Context, t time.Time) ([]*models.Reception, error) {
+	args := m.Called(ctx, t)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Reception), args.Error(1)
+}
+
+func (m *ProductTestMockReceptionRepository) TouchOpenReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error) {
+	args := m.Called(ctx, pvzID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Reception), args.Error(1)
+}
+
 func (m *ProductTestMockReceptionRepository) CreateReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error) {
 	args := m.Called(ctx, pvzID)
 	if args.Get(0) == nil {
@@ -80,6 +161,14 @@ func (m *ProductTestMockReceptionRepository) GetReceptionByID(ctx context.Contex
 	return args.Get(0).(*models.Reception), args.Error(1)
 }
 
+func (m *ProductTestMockReceptionRepository) ListReceptions(ctx context.Context, options models.ReceptionListOptions) ([]*models.Reception, int, error) {
+	args := m.Called(ctx, options)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.Reception), args.Int(1), args.Error(2)
+}
+
 func (m *ProductTestMockReceptionRepository) GetReceptionWithProducts(ctx context.Context, id uuid.UUID) (*models.Reception, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -88,12 +177,36 @@ func (m *ProductTestMockReceptionRepository) GetReceptionWithProducts(ctx contex
 	return args.Get(0).(*models.Reception), args.Error(1)
 }
 
+func (m *ProductTestMockReceptionRepository) GetReceptionsWithProducts(ctx context.Context, ids []uuid.UUID) ([]*models.Reception, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Reception), args.Error(1)
+}
+
+func (m *ProductTestMockReceptionRepository) ListReceptionIDsByPVZ(ctx context.Context, pvzID uuid.UUID, from, to time.Time) ([]uuid.UUID, error) {
+	args := m.Called(ctx, pvzID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
 type ProductTestMockProductRepository struct {
 	mock.Mock
 }
 
-func (m *ProductTestMockProductRepository) CreateProduct(ctx context.Context, productType models.ProductType, receptionID uuid.UUID, sequenceNum int) (*models.Product, error) {
-	args := m.Called(ctx, productType, receptionID, sequenceNum)
+func (m *ProductTestMockProductRepository) AddProductToOpenReception(ctx context.Context, productType models.ProductType, receptionID uuid.UUID, limit int) (*models.Product, error) {
+	args := m.Called(ctx, productType, receptionID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *ProductTestMockProductRepository) DeleteLastProductFromOpenReception(ctx context.Context, receptionID uuid.UUID) (*models.Product, error) {
+	args := m.Called(ctx, receptionID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -113,6 +226,61 @@ func (m *ProductTestMockProductRepository) DeleteProductByID(ctx context.Context
 	return args.Error(0)
 }
 
+func (m *ProductTestMockProductRepository) UpdateProductType(ctx context.Context, id uuid.UUID, productType models.ProductType) error {
+	args := m.Called(ctx, id, productType)
+	return args.Error(0)
+}
+
+func (m *ProductTestMockProductRepository) MoveProduct(ctx context.Context, productID, targetReceptionID uuid.UUID) (*models.Product, error) {
+	args := m.Called(ctx, productID, targetReceptionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *ProductTestMockProductRepository) DeleteProductsByReceptionID(ctx context.Context, receptionID uuid.UUID) (int, error) {
+	args := m.Called(ctx, receptionID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *ProductTestMockProductRepository) GetRecentProducts(ctx context.Context, receptionID uuid.UUID, n int) ([]*models.Product, error) {
+	args := m.Called(ctx, receptionID, n)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Product), args.Error(1)
+}
+
+func (m *ProductTestMockProductRepository) CountProductsByTypeByReceptionID(ctx context.Context, receptionID uuid.UUID) (map[models.ProductType]int, error) {
+	args := m.Called(ctx, receptionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[models.ProductType]int), args.Error(1)
+}
+
+func (m *ProductTestMockProductRepository) ListProductsByPVZID(ctx context.Context, pvzID uuid.UUID, productType models.ProductType, page, limit int) ([]*models.Product, int, error) {
+	args := m.Called(ctx, pvzID, productType, page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.Product), args.Int(1), args.Error(2)
+}
+
+func (m *ProductTestMockProductRepository) GetSequenceGaps(ctx context.Context, receptionID uuid.UUID) ([]int, error) {
+	args := m.Called(ctx, receptionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *ProductTestMockProductRepository) ResequenceProducts(ctx context.Context, receptionID uuid.UUID) (int, error) {
+	args := m.Called(ctx, receptionID)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *ProductTestMockProductRepository) CountProductsByReceptionID(ctx context.Context, receptionID uuid.UUID) (int, error) {
 	args := m.Called(ctx, receptionID)
 	return args.Int(0), args.Error(1)
@@ -126,8 +294,8 @@ func (m *ProductTestMockProductRepository) GetProductByID(ctx context.Context, i
 	return args.Get(0).(*models.Product), args.Error(1)
 }
 
-func (m *ProductTestMockProductRepository) GetProductsByReceptionID(ctx context.Context, receptionID uuid.UUID, page, limit int) ([]*models.Product, int, error) {
-	args := m.Called(ctx, receptionID, page, limit)
+func (m *ProductTestMockProductRepository) GetProductsByReceptionID(ctx context.Context, receptionID uuid.UUID, page, limit int, sort string) ([]*models.Product, int, error) {
+	args := m.Called(ctx, receptionID, page, limit, sort)
 	if args.Get(0) == nil {
 		return nil, args.Int(1), args.Error(2)
 	}
@@ -169,9 +337,7 @@ func TestProductService_AddProduct(t *testing.T) {
 					Status:   models.StatusInProgress,
 				}, nil)
 
-				prodRepo.On("CountProductsByReceptionID", mock.Anything, productTestReceptionUUID1).Return(5, nil)
-
-				prodRepo.On("CreateProduct", mock.Anything, models.TypeElectronics, productTestReceptionUUID1, 6).Return(&models.Product{
+				prodRepo.On("AddProductToOpenReception", mock.Anything, models.TypeElectronics, productTestReceptionUUID1, 0).Return(&models.Product{
 					ID:          productTestProductUUID1,
 					DateTime:    now,
 					Type:        models.TypeElectronics,
@@ -237,18 +403,44 @@ func TestProductService_DeleteLastProduct(t *testing.T) {
 					Status:   models.StatusInProgress,
 				}, nil)
 
-				prodRepo.On("GetLastProductByReceptionID", mock.Anything, productTestReceptionUUID1).Return(&models.Product{
+				prodRepo.On("DeleteLastProductFromOpenReception", mock.Anything, productTestReceptionUUID1).Return(&models.Product{
 					ID:          productTestProductUUID1,
 					DateTime:    now,
 					Type:        models.TypeElectronics,
 					ReceptionID: productTestReceptionUUID1,
 					SequenceNum: 5,
 				}, nil)
-
-				prodRepo.On("DeleteProductByID", mock.Anything, productTestProductUUID1).Return(nil)
 			},
 			expectedError: false,
 		},
+		{
+			name:  "Failure - No Products",
+			pvzID: productTestPvzUUID1,
+			setupMocks: func(pvzRepo *ProductTestMockPVZRepository, recRepo *ProductTestMockReceptionRepository, prodRepo *ProductTestMockProductRepository, now time.Time) {
+				recRepo.On("GetLastOpenReceptionByPVZID", mock.Anything, productTestPvzUUID1).Return(&models.Reception{
+					ID:     productTestReceptionUUID1,
+					PVZID:  productTestPvzUUID1,
+					Status: models.StatusInProgress,
+				}, nil)
+
+				prodRepo.On("DeleteLastProductFromOpenReception", mock.Anything, productTestReceptionUUID1).Return(nil, nil)
+			},
+			expectedError: true,
+		},
+		{
+			name:  "Failure - Reception Closed Concurrently",
+			pvzID: productTestPvzUUID1,
+			setupMocks: func(pvzRepo *ProductTestMockPVZRepository, recRepo *ProductTestMockReceptionRepository, prodRepo *ProductTestMockProductRepository, now time.Time) {
+				recRepo.On("GetLastOpenReceptionByPVZID", mock.Anything, productTestPvzUUID1).Return(&models.Reception{
+					ID:     productTestReceptionUUID1,
+					PVZID:  productTestPvzUUID1,
+					Status: models.StatusInProgress,
+				}, nil)
+
+				prodRepo.On("DeleteLastProductFromOpenReception", mock.Anything, productTestReceptionUUID1).Return(nil, models.ErrReceptionClosed)
+			},
+			expectedError: true,
+		},
 		{
 			name:  "Failure - No Open Reception",
 			pvzID: productTestPvzUUID2,
@@ -279,3 +471,620 @@ func TestProductService_DeleteLastProduct(t *testing.T) {
 		})
 	}
 }
+
+func TestProductService_ClearReception(t *testing.T) {
+	testCases := []struct {
+		name            string
+		pvzID           uuid.UUID
+		setupMocks      func(*ProductTestMockReceptionRepository, *ProductTestMockProductRepository, time.Time)
+		expectedDeleted int
+		expectedError   bool
+	}{
+		{
+			name:  "Success - Clear Reception",
+			pvzID: productTestPvzUUID1,
+			setupMocks: func(recRepo *ProductTestMockReceptionRepository, prodRepo *ProductTestMockProductRepository, now time.Time) {
+				recRepo.On("GetLastOpenReceptionByPVZID", mock.Anything, productTestPvzUUID1).Return(&models.Reception{
+					ID:       productTestReceptionUUID1,
+					DateTime: now,
+					PVZID:    productTestPvzUUID1,
+					Status:   models.StatusInProgress,
+				}, nil)
+
+				prodRepo.On("DeleteProductsByReceptionID", mock.Anything, productTestReceptionUUID1).Return(4, nil)
+			},
+			expectedDeleted: 4,
+		},
+		{
+			name:  "Success - Empty Reception",
+			pvzID: productTestPvzUUID1,
+			setupMocks: func(recRepo *ProductTestMockReceptionRepository, prodRepo *ProductTestMockProductRepository, now time.Time) {
+				recRepo.On("GetLastOpenReceptionByPVZID", mock.Anything, productTestPvzUUID1).Return(&models.Reception{
+					ID:       productTestReceptionUUID1,
+					DateTime: now,
+					PVZID:    productTestPvzUUID1,
+					Status:   models.StatusInProgress,
+				}, nil)
+
+				prodRepo.On("DeleteProductsByReceptionID", mock.Anything, productTestReceptionUUID1).Return(0, nil)
+			},
+			expectedDeleted: 0,
+		},
+		{
+			name:  "Failure - No Open Reception",
+			pvzID: productTestPvzUUID2,
+			setupMocks: func(recRepo *ProductTestMockReceptionRepository, prodRepo *ProductTestMockProductRepository, now time.Time) {
+				recRepo.On("GetLastOpenReceptionByPVZID", mock.Anything, productTestPvzUUID2).Return(nil, nil)
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockPVZRepo, mockReceptionRepo, mockProductRepo, now := setupProductTestMocks(t)
+			tc.setupMocks(mockReceptionRepo, mockProductRepo, now)
+
+			service := NewProductService(mockProductRepo, mockReceptionRepo, mockPVZRepo)
+
+			deleted, err := service.ClearReception(context.Background(), tc.pvzID)
+
+			if tc.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedDeleted, deleted)
+
+			mockReceptionRepo.AssertExpectations(t)
+			mockProductRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductService_UpdateProductType(t *testing.T) {
+	productID := productTestProductUUID1
+
+	testCases := []struct {
+		name          string
+		productType   models.ProductType
+		setupMocks    func(*ProductTestMockReceptionRepository, *ProductTestMockProductRepository, time.Time)
+		expectedError error
+		expectError   bool
+	}{
+		{
+			name:        "Success - Open Reception",
+			productType: models.TypeClothes,
+			setupMocks: func(recRepo *ProductTestMockReceptionRepository, prodRepo *ProductTestMockProductRepository, now time.Time) {
+				prodRepo.On("GetProductByID", mock.Anything, productID).Return(&models.Product{
+					ID:          productID,
+					DateTime:    now,
+					Type:        models.TypeElectronics,
+					ReceptionID: productTestReceptionUUID1,
+				}, nil)
+				recRepo.On("GetReceptionByID", mock.Anything, productTestReceptionUUID1).Return(&models.Reception{
+					ID:       productTestReceptionUUID1,
+					DateTime: now,
+					PVZID:    productTestPvzUUID1,
+					Status:   models.StatusInProgress,
+				}, nil)
+				prodRepo.On("UpdateProductType", mock.Anything, productID, models.TypeClothes).Return(nil)
+			},
+		},
+		{
+			name:        "Failure - Closed Reception",
+			productType: models.TypeClothes,
+			setupMocks: func(recRepo *ProductTestMockReceptionRepository, prodRepo *ProductTestMockProductRepository, now time.Time) {
+				prodRepo.On("GetProductByID", mock.Anything, productID).Return(&models.Product{
+					ID:          productID,
+					DateTime:    now,
+					Type:        models.TypeElectronics,
+					ReceptionID: productTestReceptionUUID1,
+				}, nil)
+				recRepo.On("GetReceptionByID", mock.Anything, productTestReceptionUUID1).Return(&models.Reception{
+					ID:       productTestReceptionUUID1,
+					DateTime: now,
+					PVZID:    productTestPvzUUID1,
+					Status:   models.StatusClosed,
+				}, nil)
+			},
+			expectedError: models.ErrReceptionClosed,
+			expectError:   true,
+		},
+		{
+			name:        "Failure - Product Not Found",
+			productType: models.TypeFootwear,
+			setupMocks: func(recRepo *ProductTestMockReceptionRepository, prodRepo *ProductTestMockProductRepository, now time.Time) {
+				prodRepo.On("GetProductByID", mock.Anything, productID).Return(nil, nil)
+			},
+			expectedError: models.ErrProductNotFound,
+			expectError:   true,
+		},
+		{
+			name:        "Failure - Invalid Product Type",
+			productType: models.ProductType("мебель"),
+			setupMocks: func(recRepo *ProductTestMockReceptionRepository, prodRepo *ProductTestMockProductRepository, now time.Time) {
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockPVZRepo, mockReceptionRepo, mockProductRepo, now := setupProductTestMocks(t)
+			tc.setupMocks(mockReceptionRepo, mockProductRepo, now)
+
+			service := NewProductService(mockProductRepo, mockReceptionRepo, mockPVZRepo)
+
+			err := service.UpdateProductType(context.Background(), productID, tc.productType)
+
+			if tc.expectError {
+				assert.Error(t, err)
+				if tc.expectedError != nil {
+					assert.ErrorIs(t, err, tc.expectedError)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockReceptionRepo.AssertExpectations(t)
+			mockProductRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductService_MoveProduct(t *testing.T) {
+	productID := productTestProductUUID1
+	targetPVZID := productTestPvzUUID2
+	targetReceptionID := uuid.MustParse("10000000-0000-0000-0000-000000000002")
+
+	testCases := []struct {
+		name          string
+		setupMocks    func(*ProductTestMockPVZRepository, *ProductTestMockReceptionRepository, *ProductTestMockProductRepository, time.Time)
+		expectedError error
+	}{
+		{
+			name: "Success - Both Receptions Open",
+			setupMocks: func(pvzRepo *ProductTestMockPVZRepository, recRepo *ProductTestMockReceptionRepository, prodRepo *ProductTestMockProductRepository, now time.Time) {
+				pvzRepo.On("GetPVZByID", mock.Anything, targetPVZID).Return(&models.PVZ{ID: targetPVZID, City: "Москва"}, nil)
+				recRepo.On("GetLastOpenReceptionByPVZID", mock.Anything, targetPVZID).Return(&models.Reception{
+					ID:       targetReceptionID,
+					DateTime: now,
+					PVZID:    targetPVZID,
+					Status:   models.StatusInProgress,
+				}, nil)
+				prodRepo.On("MoveProduct", mock.Anything, productID, targetReceptionID).Return(&models.Product{
+					ID:          productID,
+					DateTime:    now,
+					Type:        models.TypeElectronics,
+					ReceptionID: targetReceptionID,
+					SequenceNum: 4,
+				}, nil)
+			},
+		},
+		{
+			name: "Failure - Target Has No Open Reception",
+			setupMocks: func(pvzRepo *ProductTestMockPVZRepository, recRepo *ProductTestMockReceptionRepository, prodRepo *ProductTestMockProductRepository, now time.Time) {
+				pvzRepo.On("GetPVZByID", mock.Anything, targetPVZID).Return(&models.PVZ{ID: targetPVZID, City: "Москва"}, nil)
+				recRepo.On("GetLastOpenReceptionByPVZID", mock.Anything, targetPVZID).Return(nil, nil)
+			},
+			expectedError: models.ErrReceptionClosed,
+		},
+		{
+			name: "Failure - Target Reception Closed Concurrently",
+			setupMocks: func(pvzRepo *ProductTestMockPVZRepository, recRepo *ProductTestMockReceptionRepository, prodRepo *ProductTestMockProductRepository, now time.Time) {
+				pvzRepo.On("GetPVZByID", mock.Anything, targetPVZID).Return(&models.PVZ{ID: targetPVZID, City: "Москва"}, nil)
+				recRepo.On("GetLastOpenReceptionByPVZID", mock.Anything, targetPVZID).Return(&models.Reception{
+					ID:       targetReceptionID,
+					DateTime: now,
+					PVZID:    targetPVZID,
+					Status:   models.StatusInProgress,
+				}, nil)
+				prodRepo.On("MoveProduct", mock.Anything, productID, targetReceptionID).Return(nil, models.ErrReceptionClosed)
+			},
+			expectedError: models.ErrReceptionClosed,
+		},
+		{
+			name: "Failure - Target PVZ Not Found",
+			setupMocks: func(pvzRepo *ProductTestMockPVZRepository, recRepo *ProductTestMockReceptionRepository, prodRepo *ProductTestMockProductRepository, now time.Time) {
+				pvzRepo.On("GetPVZByID", mock.Anything, targetPVZID).Return(nil, nil)
+			},
+			expectedError: models.ErrPVZNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockPVZRepo, mockReceptionRepo, mockProductRepo, now := setupProductTestMocks(t)
+			tc.setupMocks(mockPVZRepo, mockReceptionRepo, mockProductRepo, now)
+
+			service := NewProductService(mockProductRepo, mockReceptionRepo, mockPVZRepo)
+
+			err := service.MoveProduct(context.Background(), productID, targetPVZID)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockPVZRepo.AssertExpectations(t)
+			mockReceptionRepo.AssertExpectations(t)
+			mockProductRepo.AssertExpectations(t)
+		})
+	}
+}
+func TestProductService_AddProduct_MaxProductsPerReception(t *testing.T) {
+	testCases := []struct {
+		name          string
+		limit         int
+		count         int
+		expectedError error
+	}{
+		{name: "Failure - At Limit", limit: 3, count: 3, expectedError: models.ErrReceptionFull},
+		{name: "Success - Under Limit", limit: 3, count: 2},
+		{name: "Success - Unlimited", limit: 0, count: 5000},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockPVZRepo, mockReceptionRepo, mockProductRepo, now := setupProductTestMocks(t)
+
+			mockPVZRepo.On("GetPVZByID", mock.Anything, productTestPvzUUID1).Return(&models.PVZ{
+				ID:               productTestPvzUUID1,
+				RegistrationDate: now,
+				City:             "Москва",
+			}, nil)
+			mockReceptionRepo.On("GetLastOpenReceptionByPVZID", mock.Anything, productTestPvzUUID1).Return(&models.Reception{
+				ID:       productTestReceptionUUID1,
+				DateTime: now,
+				PVZID:    productTestPvzUUID1,
+				Status:   models.StatusInProgress,
+			}, nil)
+			// Лимит передается в репозиторий и проверяется под блокировкой приемки
+			if tc.expectedError != nil {
+				mockProductRepo.On("AddProductToOpenReception", mock.Anything, models.TypeClothes, productTestReceptionUUID1, tc.limit).Return(nil, tc.expectedError)
+			} else {
+				mockProductRepo.On("AddProductToOpenReception", mock.Anything, models.TypeClothes, productTestReceptionUUID1, tc.limit).Return(&models.Product{
+					ID:          productTestProductUUID1,
+					DateTime:    now,
+					Type:        models.TypeClothes,
+					ReceptionID: productTestReceptionUUID1,
+					SequenceNum: tc.count + 1,
+				}, nil)
+			}
+
+			service := NewProductService(mockProductRepo, mockReceptionRepo, mockPVZRepo).
+				WithMaxProductsPerReception(tc.limit)
+
+			product, err := service.AddProduct(context.Background(), productTestPvzUUID1, models.TypeClothes)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				assert.Nil(t, product)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.count+1, product.SequenceNum)
+			}
+			mockProductRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductService_AddProduct_PublishesEvent(t *testing.T) {
+	mockPVZRepo, mockReceptionRepo, mockProductRepo, now := setupProductTestMocks(t)
+
+	mockPVZRepo.On("GetPVZByID", mock.Anything, productTestPvzUUID1).Return(&models.PVZ{
+		ID:               productTestPvzUUID1,
+		RegistrationDate: now,
+		City:             "Москва",
+	}, nil)
+	mockReceptionRepo.On("GetLastOpenReceptionByPVZID", mock.Anything, productTestPvzUUID1).Return(&models.Reception{
+		ID:       productTestReceptionUUID1,
+		DateTime: now,
+		PVZID:    productTestPvzUUID1,
+		Status:   models.StatusInProgress,
+	}, nil)
+	mockProductRepo.On("AddProductToOpenReception", mock.Anything, models.TypeClothes, productTestReceptionUUID1, 0).Return(&models.Product{
+		ID:          productTestProductUUID1,
+		DateTime:    now,
+		Type:        models.TypeClothes,
+		ReceptionID: productTestReceptionUUID1,
+		SequenceNum: 1,
+	}, nil)
+
+	bus := notifier.NewEventBus()
+	events, unsubscribe := bus.Subscribe(productTestPvzUUID1)
+	defer unsubscribe()
+
+	service := NewProductService(mockProductRepo, mockReceptionRepo, mockPVZRepo).
+		WithEventPublisher(bus)
+
+	_, err := service.AddProduct(context.Background(), productTestPvzUUID1, models.TypeClothes)
+	require.NoError(t, err)
+
+	select {
+	case event := <-events:
+		assert.Equal(t, models.EventProductAdded, event.Type)
+		assert.Equal(t, productTestPvzUUID1, event.PVZID)
+		product, ok := event.Data.(models.Product)
+		require.True(t, ok)
+		assert.Equal(t, productTestProductUUID1, product.ID)
+	case <-time.After(time.Second):
+		t.Fatal("событие о добавлении товара не получено")
+	}
+}
+
+// productTestSequenceRepository имитирует атомарное назначение порядкового номера в БД
+type productTestSequenceRepository struct {
+	ProductTestMockProductRepository
+	mu       sync.Mutex
+	sequence map[uuid.UUID]int
+}
+
+func (r *productTestSequenceRepository) AddProductToOpenReception(ctx context.Context, productType models.ProductType, receptionID uuid.UUID, limit int) (*models.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sequence[receptionID]++
+	return &models.Product{
+		ID:          uuid.New(),
+		DateTime:    time.Now(),
+		Type:        productType,
+		ReceptionID: receptionID,
+		SequenceNum: r.sequence[receptionID],
+	}, nil
+}
+
+func TestProductService_AddProduct_ConcurrentSequence(t *testing.T) {
+	mockPVZRepo, mockReceptionRepo, _, now := setupProductTestMocks(t)
+	productRepo := &productTestSequenceRepository{sequence: make(map[uuid.UUID]int)}
+
+	mockPVZRepo.On("GetPVZByID", mock.Anything, productTestPvzUUID1).Return(&models.PVZ{
+		ID:               productTestPvzUUID1,
+		RegistrationDate: now,
+		City:             "Москва",
+	}, nil)
+	mockReceptionRepo.On("GetLastOpenReceptionByPVZID", mock.Anything, productTestPvzUUID1).Return(&models.Reception{
+		ID:       productTestReceptionUUID1,
+		DateTime: now,
+		PVZID:    productTestPvzUUID1,
+		Status:   models.StatusInProgress,
+	}, nil)
+
+	service := NewProductService(productRepo, mockReceptionRepo, mockPVZRepo)
+
+	const adds = 2
+	results := make(chan int, adds)
+
+	var wg sync.WaitGroup
+	for i := 0; i < adds; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			product, err := service.AddProduct(context.Background(), productTestPvzUUID1, models.TypeElectronics)
+			if assert.NoError(t, err) {
+				results <- product.SequenceNum
+			}
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	seen := make(map[int]bool)
+	for seq := range results {
+		assert.False(t, seen[seq], "порядковый номер %d выдан дважды", seq)
+		seen[seq] = true
+	}
+	assert.Len(t, seen, adds)
+}
+
+// productTestLockingRepository имитирует SELECT ... FOR UPDATE: операции над одной приемкой
+// выполняются под ее собственной блокировкой и фиксируют, не пересеклись ли они
+type productTestLockingRepository struct {
+	ProductTestMockProductRepository
+
+	mu        sync.Mutex
+	locks     map[uuid.UUID]*sync.Mutex
+	products  map[uuid.UUID][]*models.Product
+	active    map[uuid.UUID]int
+	maxActive int
+}
+
+func newProductTestLockingRepository() *productTestLockingRepository {
+	return &productTestLockingRepository{
+		locks:    make(map[uuid.UUID]*sync.Mutex),
+		products: make(map[uuid.UUID][]*models.Product),
+		active:   make(map[uuid.UUID]int),
+	}
+}
+
+func (r *productTestLockingRepository) lockFor(receptionID uuid.UUID) *sync.Mutex {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.locks[receptionID] == nil {
+		r.locks[receptionID] = &sync.Mutex{}
+	}
+	return r.locks[receptionID]
+}
+
+// enter отмечает начало операции под блокировкой; возвращает функцию завершения
+func (r *productTestLockingRepository) enter(receptionID uuid.UUID) func() {
+	r.mu.Lock()
+	r.active[receptionID]++
+	if r.active[receptionID] > r.maxActive {
+		r.maxActive = r.active[receptionID]
+	}
+	r.mu.Unlock()
+
+	// Даем планировщику шанс переключиться, чтобы пересечение проявилось без блокировки
+	time.Sleep(time.Millisecond)
+
+	return func() {
+		r.mu.Lock()
+		r.active[receptionID]--
+		r.mu.Unlock()
+	}
+}
+
+func (r *productTestLockingRepository) AddProductToOpenReception(ctx context.Context, productType models.ProductType, receptionID uuid.UUID, limit int) (*models.Product, error) {
+	lock := r.lockFor(receptionID)
+	lock.Lock()
+	defer lock.Unlock()
+	defer r.enter(receptionID)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sequence := 1
+	if products := r.products[receptionID]; len(products) > 0 {
+		sequence = products[len(products)-1].SequenceNum + 1
+	}
+	product := &models.Product{
+		ID:          uuid.New(),
+		DateTime:    time.Now(),
+		Type:        productType,
+		ReceptionID: receptionID,
+		SequenceNum: sequence,
+	}
+	r.products[receptionID] = append(r.products[receptionID], product)
+	return product, nil
+}
+
+func (r *productTestLockingRepository) DeleteLastProductFromOpenReception(ctx context.Context, receptionID uuid.UUID) (*models.Product, error) {
+	lock := r.lockFor(receptionID)
+	lock.Lock()
+	defer lock.Unlock()
+	defer r.enter(receptionID)()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	products := r.products[receptionID]
+	if len(products) == 0 {
+		return nil, nil
+	}
+	last := products[len(products)-1]
+	r.products[receptionID] = products[:len(products)-1]
+	return last, nil
+}
+
+func TestProductService_AddAndDeleteSerializedPerReception(t *testing.T) {
+	productTestReceptionUUID2 := uuid.MustParse("10000000-0000-0000-0000-000000000002")
+
+	mockPVZRepo, mockReceptionRepo, _, now := setupProductTestMocks(t)
+	productRepo := newProductTestLockingRepository()
+
+	for pvzID, receptionID := range map[uuid.UUID]uuid.UUID{
+		productTestPvzUUID1: productTestReceptionUUID1,
+		productTestPvzUUID2: productTestReceptionUUID2,
+	} {
+		mockPVZRepo.On("GetPVZByID", mock.Anything, pvzID).Return(&models.PVZ{
+			ID:               pvzID,
+			RegistrationDate: now,
+			City:             "Москва",
+		}, nil)
+		mockReceptionRepo.On("GetLastOpenReceptionByPVZID", mock.Anything, pvzID).Return(&models.Reception{
+			ID:       receptionID,
+			DateTime: now,
+			PVZID:    pvzID,
+			Status:   models.StatusInProgress,
+		}, nil)
+	}
+
+	service := NewProductService(productRepo, mockReceptionRepo, mockPVZRepo)
+
+	t.Run("Add And Delete Do Not Interleave", func(t *testing.T) {
+		const adds, deletes = 20, 10
+
+		var (
+			wg      sync.WaitGroup
+			mu      sync.Mutex
+			deleted int
+		)
+		for i := 0; i < adds+deletes; i++ {
+			wg.Add(1)
+			go func(add bool) {
+				defer wg.Done()
+				if add {
+					_, err := service.AddProduct(context.Background(), productTestPvzUUID1, models.TypeElectronics)
+					assert.NoError(t, err)
+					return
+				}
+				// Удаление может прийти раньше добавлений и не найти товаров - это допустимо
+				if err := service.DeleteLastProduct(context.Background(), productTestPvzUUID1); err == nil {
+					mu.Lock()
+					deleted++
+					mu.Unlock()
+				}
+			}(i%3 != 2)
+		}
+		wg.Wait()
+
+		assert.Equal(t, 1, productRepo.maxActive, "операции над одной приемкой пересеклись")
+
+		remaining := productRepo.products[productTestReceptionUUID1]
+		assert.Len(t, remaining, adds-deleted)
+		for i := 1; i < len(remaining); i++ {
+			assert.Greater(t, remaining[i].SequenceNum, remaining[i-1].SequenceNum)
+		}
+	})
+
+	t.Run("Other PVZ Not Blocked", func(t *testing.T) {
+		lock := productRepo.lockFor(productTestReceptionUUID1)
+		lock.Lock()
+		defer lock.Unlock()
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := service.AddProduct(context.Background(), productTestPvzUUID2, models.TypeClothes)
+			done <- err
+		}()
+
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(2 * time.Second):
+			t.Fatal("добавление в приемку другого ПВЗ ждет чужую блокировку")
+		}
+	})
+}
+
+func TestProductService_EmptyProductListsAreNotNil(t *testing.T) {
+	t.Run("Recent Products", func(t *testing.T) {
+		mockPVZRepo, mockReceptionRepo, mockProductRepo, now := setupProductTestMocks(t)
+		mockReceptionRepo.On("GetLastOpenReceptionByPVZID", mock.Anything, productTestPvzUUID1).Return(&models.Reception{
+			ID:       productTestReceptionUUID1,
+			DateTime: now,
+			PVZID:    productTestPvzUUID1,
+			Status:   models.StatusInProgress,
+		}, nil)
+		mockProductRepo.On("GetRecentProducts", mock.Anything, productTestReceptionUUID1, 10).Return(nil, nil)
+
+		products, err := NewProductService(mockProductRepo, mockReceptionRepo, mockPVZRepo).
+			GetRecentProducts(context.Background(), productTestPvzUUID1, 10)
+
+		require.NoError(t, err)
+		assert.NotNil(t, products)
+		assert.Empty(t, products)
+	})
+
+	t.Run("Products By Reception", func(t *testing.T) {
+		mockPVZRepo, mockReceptionRepo, mockProductRepo, now := setupProductTestMocks(t)
+		mockReceptionRepo.On("GetReceptionByID", mock.Anything, productTestReceptionUUID1).Return(&models.Reception{
+			ID:       productTestReceptionUUID1,
+			DateTime: now,
+			PVZID:    productTestPvzUUID1,
+			Status:   models.StatusInProgress,
+		}, nil)
+		mockProductRepo.On("GetProductsByReceptionID", mock.Anything, productTestReceptionUUID1, 1, 10, "").Return(nil, 0, nil)
+
+		products, total, err := NewProductService(mockProductRepo, mockReceptionRepo, mockPVZRepo).
+			GetProductsByReceptionID(context.Background(), productTestReceptionUUID1, 1, 10, "")
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, total)
+		assert.NotNil(t, products)
+		assert.Empty(t, products)
+	})
+}
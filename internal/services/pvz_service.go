@@ -3,6 +3,8 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"pvz-service/internal/domain/interfaces"
 	"pvz-service/internal/domain/models"
@@ -10,37 +12,100 @@ import (
 	"pvz-service/internal/metrics"
 
 	"github.com/google/uuid"
+	"golang.org/x/sync/singleflight"
 )
 
 type PVZService struct {
 	pvzRepo interfaces.PVZRepository
+
+	// listGroup объединяет одновременные одинаковые запросы списка ПВЗ в один запрос к БД
+	listGroup singleflight.Group
 }
 
+var _ interfaces.PVZService = (*PVZService)(nil)
+
 func NewPVZService(pvzRepo interfaces.PVZRepository) *PVZService {
 	return &PVZService{
 		pvzRepo: pvzRepo,
 	}
 }
 
-func (s *PVZService) CreatePVZ(ctx context.Context, city string) (*models.PVZ, error) {
+func (s *PVZService) CreatePVZ(ctx context.Context, city, externalID string) (*models.PVZ, bool, error) {
 	log := logger.FromContext(ctx)
-	log.Debug("CreatePVZ called", "city", city)
+	log.Debug("CreatePVZ called", "city", city, "external_id", externalID)
 
 	if !models.AllowedCities[city] {
-		log.Warn("Invalid city provided", "city", city)
-		return nil, errors.New("city must be one of: Москва, Санкт-Петербург, Казань")
+		log.Warn("Invalid city provided", logger.Outcome(logger.OutcomeValidationError), "city", city)
+		return nil, false, errors.New("city must be one of: Москва, Санкт-Петербург, Казань")
 	}
 
-	pvz, err := s.pvzRepo.CreatePVZ(ctx, city)
+	pvz, created, err := s.pvzRepo.CreatePVZ(ctx, city, externalID)
 	if err != nil {
-		log.Error("Error creating PVZ", "error", err, "city", city)
-		return nil, err
+		log.Error("Error creating PVZ", logger.Outcome(logger.OutcomeError), "error", err, "city", city)
+		return nil, false, err
+	}
+
+	if !created {
+		log.Info("PVZ with external ID already exists", logger.Outcome(logger.OutcomeSuccess), "pvz_id", pvz.ID, "external_id", externalID)
+		return pvz, false, nil
 	}
 
 	metrics.IncrementPVZCreated()
 
-	log.Info("PVZ created successfully", "pvz_id", pvz.ID, "city", pvz.City)
-	return pvz, nil
+	log.Info("PVZ created successfully", logger.Outcome(logger.OutcomeSuccess), "pvz_id", pvz.ID, "city", pvz.City)
+	return pvz, true, nil
+}
+
+// CreatePVZBatch создает ПВЗ для всех допустимых городов одной транзакцией
+// (либо все допустимые, либо ни одного), а недопустимые города возвращает
+// поэлементными ошибками, не прерывая остальной пакет
+func (s *PVZService) CreatePVZBatch(ctx context.Context, cities []string) (*models.PVZBatchCreateResponse, error) {
+	log := logger.FromContext(ctx)
+	log.Debug("CreatePVZBatch called", "count", len(cities))
+
+	if len(cities) == 0 {
+		log.Warn("Empty PVZ batch", logger.Outcome(logger.OutcomeValidationError))
+		return nil, errors.New("cities must not be empty")
+	}
+
+	result := &models.PVZBatchCreateResponse{
+		Created: []*models.PVZ{},
+		Errors:  []models.PVZBatchItemError{},
+	}
+
+	valid := make([]string, 0, len(cities))
+	for i, city := range cities {
+		if !models.AllowedCities[city] {
+			result.Errors = append(result.Errors, models.PVZBatchItemError{
+				Index: i,
+				City:  city,
+				Error: "city must be one of: Москва, Санкт-Петербург, Казань",
+			})
+			continue
+		}
+		valid = append(valid, city)
+	}
+	if len(result.Errors) > 0 {
+		log.Warn("Invalid cities in batch", logger.Outcome(logger.OutcomeValidationError), "invalid_count", len(result.Errors), "valid_count", len(valid))
+	}
+
+	if len(valid) == 0 {
+		return result, nil
+	}
+
+	pvzs, err := s.pvzRepo.CreatePVZBatch(ctx, valid)
+	if err != nil {
+		log.Error("Error creating PVZ batch", logger.Outcome(logger.OutcomeError), "error", err, "count", len(valid))
+		return nil, err
+	}
+
+	for range pvzs {
+		metrics.IncrementPVZCreated()
+	}
+
+	result.Created = pvzs
+	log.Info("PVZ batch created", logger.Outcome(logger.OutcomeSuccess), "created", len(pvzs), "failed", len(result.Errors))
+	return result, nil
 }
 
 func (s *PVZService) GetPVZByID(ctx context.Context, id uuid.UUID) (*models.PVZ, error) {
@@ -49,18 +114,72 @@ func (s *PVZService) GetPVZByID(ctx context.Context, id uuid.UUID) (*models.PVZ,
 
 	pvz, err := s.pvzRepo.GetPVZByID(ctx, id)
 	if err != nil {
-		log.Error("Error getting PVZ", "error", err, "pvz_id", id)
+		log.Error("Error getting PVZ", logger.Outcome(logger.OutcomeError), "error", err, "pvz_id", id)
 		return nil, err
 	}
 	if pvz == nil {
-		log.Warn("PVZ not found", "pvz_id", id)
-		return nil, errors.New("pvz not found")
+		log.Warn("PVZ not found", logger.Outcome(logger.OutcomeNotFound), "pvz_id", id)
+		return nil, models.ErrPVZNotFound
 	}
 
-	log.Info("PVZ retrieved successfully", "pvz_id", pvz.ID, "city", pvz.City)
+	log.Info("PVZ retrieved successfully", logger.Outcome(logger.OutcomeSuccess), "pvz_id", pvz.ID, "city", pvz.City)
 	return pvz, nil
 }
 
+// GetPVZsByIDs возвращает найденные ПВЗ; отсутствующие идентификаторы не считаются ошибкой
+func (s *PVZService) GetPVZsByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.PVZ, error) {
+	log := logger.FromContext(ctx)
+	log.Debug("GetPVZsByIDs called", "count", len(ids))
+
+	if len(ids) > models.MaxPVZBatchGetIDs {
+		log.Warn("Too many PVZ IDs requested", logger.Outcome(logger.OutcomeValidationError), "count", len(ids), "max", models.MaxPVZBatchGetIDs)
+		return nil, fmt.Errorf("too many ids: at most %d allowed", models.MaxPVZBatchGetIDs)
+	}
+
+	pvzs, err := s.pvzRepo.GetPVZsByIDs(ctx, ids)
+	if err != nil {
+		log.Error("Error getting PVZ by ids", logger.Outcome(logger.OutcomeError), "error", err, "count", len(ids))
+		return nil, err
+	}
+
+	log.Info("PVZ batch retrieved", logger.Outcome(logger.OutcomeSuccess), "requested", len(ids), "found", len(pvzs))
+	return pvzs, nil
+}
+
+// CountPVZByCity возвращает города с числом ПВЗ в каждом
+func (s *PVZService) CountPVZByCity(ctx context.Context) ([]models.CityPVZCount, error) {
+	log := logger.FromContext(ctx)
+	log.Debug("CountPVZByCity called")
+
+	cities, err := s.pvzRepo.CountPVZByCity(ctx)
+	if err != nil {
+		log.Error("Error counting PVZ by city", logger.Outcome(logger.OutcomeError), "error", err)
+		return nil, err
+	}
+
+	log.Info("PVZ counted by city", logger.Outcome(logger.OutcomeSuccess), "cities", len(cities))
+	return cities, nil
+}
+
+// ExportPVZ передает в fn все ПВЗ по порядку регистрации, не загружая их в память целиком
+func (s *PVZService) ExportPVZ(ctx context.Context, fn func(*models.PVZ) error) error {
+	log := logger.FromContext(ctx)
+	log.Debug("ExportPVZ called")
+
+	count := 0
+	err := s.pvzRepo.StreamPVZ(ctx, func(pvz *models.PVZ) error {
+		count++
+		return fn(pvz)
+	})
+	if err != nil {
+		log.Error("Error exporting PVZs", logger.Outcome(logger.OutcomeError), "exported", count, "error", err)
+		return err
+	}
+
+	log.Info("PVZs exported", logger.Outcome(logger.OutcomeSuccess), "count", count)
+	return nil
+}
+
 func (s *PVZService) ListPVZ(ctx context.Context, options models.PVZListOptions) ([]*models.PVZWithReceptionsResponse, int, error) {
 	log := logger.FromContext(ctx)
 	log.Debug("ListPVZ called",
@@ -70,12 +189,64 @@ func (s *PVZService) ListPVZ(ctx context.Context, options models.PVZListOptions)
 		"has_end_date", !options.EndDate.IsZero(),
 	)
 
-	pvzs, total, err := s.pvzRepo.ListPVZ(ctx, options)
-	if err != nil {
-		log.Error("Error listing PVZs", "error", err)
-		return nil, 0, err
+	// Запрос выполняется без отмены от первого вызывающего, чтобы его отключение
+	// не обрывало результат для остальных ожидающих. Результат (в том числе ошибка)
+	// живет только пока запрос выполняется и не кэшируется после него
+	resultCh := s.listGroup.DoChan(pvzListKey(options), func() (interface{}, error) {
+		pvzs, total, err := s.pvzRepo.ListPVZ(context.WithoutCancel(ctx), options)
+		if err != nil {
+			return nil, err
+		}
+		return pvzListResult{pvzs: normalizePVZList(pvzs), total: total}, nil
+	})
+
+	var result singleflight.Result
+	select {
+	case result = <-resultCh:
+	case <-ctx.Done():
+		log.Warn("ListPVZ canceled while waiting for result", logger.Outcome(logger.OutcomeError), "error", ctx.Err())
+		return nil, 0, ctx.Err()
+	}
+
+	if result.Err != nil {
+		log.Error("Error listing PVZs", logger.Outcome(logger.OutcomeError), "error", result.Err)
+		return nil, 0, result.Err
 	}
 
-	log.Info("PVZs listed successfully", "count", len(pvzs), "total", total)
-	return pvzs, total, nil
+	list := result.Val.(pvzListResult)
+	log.Info("PVZs listed successfully", logger.Outcome(logger.OutcomeSuccess), "count", len(list.pvzs), "total", list.total, "shared", result.Shared)
+	return list.pvzs, list.total, nil
+}
+
+// pvzListResult - результат ListPVZ, разделяемый между одновременными вызовами.
+// Срез общий для всех получателей, поэтому изменять его нельзя
+type pvzListResult struct {
+	pvzs  []*models.PVZWithReceptionsResponse
+	total int
+}
+
+// pvzListKey строит ключ для объединения запросов. Значения по умолчанию и часовые пояса
+// приводятся к тому же виду, что использует репозиторий, чтобы равнозначные запросы совпадали
+func pvzListKey(options models.PVZListOptions) string {
+	page, limit := options.Page, options.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+	if page <= 0 {
+		page = 1
+	}
+
+	formatTime := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339Nano)
+	}
+
+	return fmt.Sprintf("page=%d|limit=%d|start=%s|end=%s|reg_from=%s|reg_to=%s|sort=%s|desc=%t|skip_total=%t|only_open=%t",
+		page, limit,
+		formatTime(options.StartDate), formatTime(options.EndDate),
+		formatTime(options.RegisteredFrom), formatTime(options.RegisteredTo),
+		options.SortBy, options.SortDesc, options.SkipTotal, options.OnlyOpen,
+	)
 }
@@ -1,15 +1,23 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"pvz-service/internal/domain/models"
+	"pvz-service/internal/logger"
 )
 
 var (
@@ -21,12 +29,12 @@ type PVZTestMockRepository struct {
 	mock.Mock
 }
 
-func (m *PVZTestMockRepository) CreatePVZ(ctx context.Context, city string) (*models.PVZ, error) {
-	args := m.Called(ctx, city)
+func (m *PVZTestMockRepository) CreatePVZ(ctx context.Context, city, externalID string) (*models.PVZ, bool, error) {
+	args := m.Called(ctx, city, externalID)
 	if args.Get(0) == nil {
-		return nil, args.Error(1)
+		return nil, args.Bool(1), args.Error(2)
 	}
-	return args.Get(0).(*models.PVZ), args.Error(1)
+	return args.Get(0).(*models.PVZ), args.Bool(1), args.Error(2)
 }
 
 func (m *PVZTestMockRepository) GetPVZByID(ctx context.Context, id uuid.UUID) (*models.PVZ, error) {
@@ -37,11 +45,55 @@ func (m *PVZTestMockRepository) GetPVZByID(ctx context.Context, id uuid.UUID) (*
 	return args.Get(0).(*models.PVZ), args.Error(1)
 }
 
+func (m *PVZTestMockRepository) GetPVZsByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.PVZ, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.PVZ), args.Error(1)
+}
+
+func (m *PVZTestMockRepository) CreatePVZBatch(ctx context.Context, cities []string) ([]*models.PVZ, error) {
+	args := m.Called(ctx, cities)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.PVZ), args.Error(1)
+}
+
 func (m *PVZTestMockRepository) ListPVZ(ctx context.Context, options models.PVZListOptions) ([]*models.PVZWithReceptionsResponse, int, error) {
 	args := m.Called(ctx, options)
 	return args.Get(0).([]*models.PVZWithReceptionsResponse), args.Int(1), args.Error(2)
 }
 
+func (m *PVZTestMockRepository) CountPVZByCity(ctx context.Context) ([]models.CityPVZCount, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.CityPVZCount), args.Error(1)
+}
+
+func (m *PVZTestMockRepository) StreamPVZ(ctx context.Context, fn func(*models.PVZ) error) error {
+	args := m.Called(ctx)
+	if pvzs, ok := args.Get(0).([]*models.PVZ); ok {
+		for _, pvz := range pvzs {
+			if err := fn(pvz); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *PVZTestMockRepository) ListPVZWithReceptionsBetween(ctx context.Context, from, to time.Time) ([]*models.PVZ, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.PVZ), args.Error(1)
+}
+
 func TestPVZService_CreatePVZ(t *testing.T) {
 	now := time.Now()
 
@@ -56,12 +108,12 @@ func TestPVZService_CreatePVZ(t *testing.T) {
 			name: "Success - Moscow",
 			city: "Москва",
 			mockSetup: func(repo *PVZTestMockRepository) {
-				repo.On("CreatePVZ", mock.Anything, "Москва").
+				repo.On("CreatePVZ", mock.Anything, "Москва", "").
 					Return(&models.PVZ{
 						ID:               pvzTestUUID1,
 						RegistrationDate: now,
 						City:             "Москва",
-					}, nil)
+					}, true, nil)
 			},
 			expectedPVZ: &models.PVZ{
 				ID:               pvzTestUUID1,
@@ -86,7 +138,7 @@ func TestPVZService_CreatePVZ(t *testing.T) {
 			tc.mockSetup(mockRepo)
 			service := NewPVZService(mockRepo)
 
-			pvz, err := service.CreatePVZ(context.Background(), tc.city)
+			pvz, _, err := service.CreatePVZ(context.Background(), tc.city, "")
 
 			if tc.expectedError {
 				assert.Error(t, err)
@@ -103,6 +155,132 @@ func TestPVZService_CreatePVZ(t *testing.T) {
 	}
 }
 
+func TestPVZService_CreatePVZ_ExternalID(t *testing.T) {
+	now := time.Now()
+	externalID := "site-42"
+	pvz := &models.PVZ{
+		ID:               pvzTestUUID1,
+		RegistrationDate: now,
+		City:             "Казань",
+		ExternalID:       &externalID,
+	}
+
+	testCases := []struct {
+		name            string
+		repoCreated     bool
+		expectedCreated bool
+	}{
+		{name: "Success - New External ID", repoCreated: true, expectedCreated: true},
+		{name: "Success - Existing External ID Returned", repoCreated: false, expectedCreated: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(PVZTestMockRepository)
+			mockRepo.On("CreatePVZ", mock.Anything, "Казань", externalID).Return(pvz, tc.repoCreated, nil)
+			service := NewPVZService(mockRepo)
+
+			result, created, err := service.CreatePVZ(context.Background(), "Казань", externalID)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedCreated, created)
+			assert.Equal(t, pvzTestUUID1, result.ID)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPVZService_CreatePVZBatch(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		name           string
+		cities         []string
+		mockSetup      func(*PVZTestMockRepository)
+		expectedCount  int
+		expectedErrors []models.PVZBatchItemError
+		expectedError  bool
+	}{
+		{
+			name:   "Success - All Cities Valid",
+			cities: []string{"Москва", "Казань"},
+			mockSetup: func(repo *PVZTestMockRepository) {
+				repo.On("CreatePVZBatch", mock.Anything, []string{"Москва", "Казань"}).
+					Return([]*models.PVZ{
+						{ID: uuid.New(), RegistrationDate: now, City: "Москва"},
+						{ID: uuid.New(), RegistrationDate: now, City: "Казань"},
+					}, nil)
+			},
+			expectedCount: 2,
+		},
+		{
+			name:   "Partial - Mix Of Valid And Invalid Cities",
+			cities: []string{"Новосибирск", "Москва", "Тверь", "Казань"},
+			mockSetup: func(repo *PVZTestMockRepository) {
+				repo.On("CreatePVZBatch", mock.Anything, []string{"Москва", "Казань"}).
+					Return([]*models.PVZ{
+						{ID: uuid.New(), RegistrationDate: now, City: "Москва"},
+						{ID: uuid.New(), RegistrationDate: now, City: "Казань"},
+					}, nil)
+			},
+			expectedCount: 2,
+			expectedErrors: []models.PVZBatchItemError{
+				{Index: 0, City: "Новосибирск", Error: "city must be one of: Москва, Санкт-Петербург, Казань"},
+				{Index: 2, City: "Тверь", Error: "city must be one of: Москва, Санкт-Петербург, Казань"},
+			},
+		},
+		{
+			name:          "Partial - All Cities Invalid Skips Repository",
+			cities:        []string{"Тверь"},
+			mockSetup:     func(repo *PVZTestMockRepository) {},
+			expectedCount: 0,
+			expectedErrors: []models.PVZBatchItemError{
+				{Index: 0, City: "Тверь", Error: "city must be one of: Москва, Санкт-Петербург, Казань"},
+			},
+		},
+		{
+			name:          "Failure - Empty Batch",
+			cities:        []string{},
+			mockSetup:     func(repo *PVZTestMockRepository) {},
+			expectedError: true,
+		},
+		{
+			name:   "Failure - Repository Error",
+			cities: []string{"Москва"},
+			mockSetup: func(repo *PVZTestMockRepository) {
+				repo.On("CreatePVZBatch", mock.Anything, []string{"Москва"}).
+					Return(nil, errors.New("database error"))
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(PVZTestMockRepository)
+			tc.mockSetup(mockRepo)
+			service := NewPVZService(mockRepo)
+
+			result, err := service.CreatePVZBatch(context.Background(), tc.cities)
+
+			if tc.expectedError {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, result.Created, tc.expectedCount)
+				if tc.expectedErrors == nil {
+					assert.Empty(t, result.Errors)
+				} else {
+					assert.Equal(t, tc.expectedErrors, result.Errors)
+				}
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
 func TestPVZService_GetPVZByID(t *testing.T) {
 	now := time.Now()
 
@@ -166,6 +344,97 @@ func TestPVZService_GetPVZByID(t *testing.T) {
 	}
 }
 
+func TestPVZService_GetPVZsByIDs(t *testing.T) {
+	t.Run("Partial Miss", func(t *testing.T) {
+		mockRepo := new(PVZTestMockRepository)
+		ids := []uuid.UUID{pvzTestUUID1, pvzTestNonexistentUUID}
+		mockRepo.On("GetPVZsByIDs", mock.Anything, ids).
+			Return([]*models.PVZ{{ID: pvzTestUUID1, City: "Москва"}}, nil)
+
+		pvzs, err := NewPVZService(mockRepo).GetPVZsByIDs(context.Background(), ids)
+
+		require.NoError(t, err)
+		require.Len(t, pvzs, 1)
+		assert.Equal(t, pvzTestUUID1, pvzs[0].ID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Too Many IDs", func(t *testing.T) {
+		mockRepo := new(PVZTestMockRepository)
+		ids := make([]uuid.UUID, models.MaxPVZBatchGetIDs+1)
+
+		pvzs, err := NewPVZService(mockRepo).GetPVZsByIDs(context.Background(), ids)
+
+		assert.Error(t, err)
+		assert.Nil(t, pvzs)
+		mockRepo.AssertNotCalled(t, "GetPVZsByIDs", mock.Anything, mock.Anything)
+	})
+}
+
+// loggedOutcomes возвращает значения outcome из JSON-записей лога
+func loggedOutcomes(t *testing.T, buf *bytes.Buffer) []string {
+	var outcomes []string
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		if outcome, ok := record[logger.OutcomeKey].(string); ok {
+			outcomes = append(outcomes, outcome)
+		}
+	}
+	return outcomes
+}
+
+func TestPVZService_GetPVZByID_LogsOutcome(t *testing.T) {
+	testCases := []struct {
+		name            string
+		mockSetup       func(*PVZTestMockRepository)
+		expectedOutcome string
+	}{
+		{
+			name: "Success",
+			mockSetup: func(repo *PVZTestMockRepository) {
+				repo.On("GetPVZByID", mock.Anything, pvzTestUUID1).
+					Return(&models.PVZ{ID: pvzTestUUID1, City: "Москва"}, nil)
+			},
+			expectedOutcome: logger.OutcomeSuccess,
+		},
+		{
+			name: "Not Found",
+			mockSetup: func(repo *PVZTestMockRepository) {
+				repo.On("GetPVZByID", mock.Anything, pvzTestUUID1).Return(nil, nil)
+			},
+			expectedOutcome: logger.OutcomeNotFound,
+		},
+		{
+			name: "Repository Error",
+			mockSetup: func(repo *PVZTestMockRepository) {
+				repo.On("GetPVZByID", mock.Anything, pvzTestUUID1).Return(nil, errors.New("db down"))
+			},
+			expectedOutcome: logger.OutcomeError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			ctx := logger.WithLogger(context.Background(), logger.New(logger.Config{
+				Level:  logger.LevelDebug,
+				Format: "json",
+				Output: &buf,
+			}))
+
+			mockRepo := new(PVZTestMockRepository)
+			tc.mockSetup(mockRepo)
+
+			_, _ = NewPVZService(mockRepo).GetPVZByID(ctx, pvzTestUUID1)
+
+			assert.Equal(t, []string{tc.expectedOutcome}, loggedOutcomes(t, &buf))
+		})
+	}
+}
+
 func TestPVZService_ListPVZ(t *testing.T) {
 	now := time.Now()
 
@@ -220,3 +489,126 @@ func TestPVZService_ListPVZ(t *testing.T) {
 		})
 	}
 }
+
+// pvzTestBlockingRepository держит ListPVZ, пока тест не отпустит release
+type pvzTestBlockingRepository struct {
+	PVZTestMockRepository
+	calls   atomic.Int32
+	entered chan struct{}
+	release chan struct{}
+	err     error
+}
+
+func (r *pvzTestBlockingRepository) ListPVZ(ctx context.Context, options models.PVZListOptions) ([]*models.PVZWithReceptionsResponse, int, error) {
+	r.calls.Add(1)
+	r.entered <- struct{}{}
+	<-r.release
+	if r.err != nil {
+		return nil, 0, r.err
+	}
+	return []*models.PVZWithReceptionsResponse{{PVZ: &models.PVZ{ID: pvzTestUUID1, City: "Москва"}}}, 1, nil
+}
+
+func TestPVZService_ListPVZ_EmptyCollectionsSerializeAsArrays(t *testing.T) {
+	testCases := []struct {
+		name     string
+		repoPVZs []*models.PVZWithReceptionsResponse
+		expected string
+	}{
+		{
+			name:     "Nil List",
+			repoPVZs: nil,
+			expected: `[]`,
+		},
+		{
+			name: "Nil Receptions And Products",
+			repoPVZs: []*models.PVZWithReceptionsResponse{
+				{PVZ: &models.PVZ{ID: pvzTestUUID1, City: "Москва"}},
+				{
+					PVZ:        &models.PVZ{ID: uuid.New(), City: "Казань"},
+					Receptions: []*models.ReceptionWithProducts{{Reception: &models.Reception{ID: uuid.New()}}},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(PVZTestMockRepository)
+			mockRepo.On("ListPVZ", mock.Anything, mock.Anything).Return(tc.repoPVZs, len(tc.repoPVZs), nil)
+
+			pvzs, _, err := NewPVZService(mockRepo).ListPVZ(context.Background(), models.PVZListOptions{Page: 1, Limit: 10})
+			require.NoError(t, err)
+
+			body, err := json.Marshal(pvzs)
+			require.NoError(t, err)
+			assert.NotContains(t, string(body), "null")
+			if tc.expected != "" {
+				assert.JSONEq(t, tc.expected, string(body))
+			} else {
+				assert.Contains(t, string(body), `"receptions":[]`)
+				assert.Contains(t, string(body), `"products":[]`)
+			}
+		})
+	}
+}
+
+func TestPVZService_ListPVZ_SharesConcurrentIdenticalCalls(t *testing.T) {
+	const callers = 20
+
+	repo := &pvzTestBlockingRepository{
+		entered: make(chan struct{}, callers),
+		release: make(chan struct{}),
+	}
+	service := NewPVZService(repo)
+
+	// Page и Limit по умолчанию приводятся к тем же значениям, что и явные
+	options := []models.PVZListOptions{{Page: 1, Limit: 10}, {}}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(opts models.PVZListOptions) {
+			defer wg.Done()
+			pvzs, total, err := service.ListPVZ(context.Background(), opts)
+			if err == nil && (len(pvzs) != 1 || total != 1) {
+				err = errors.New("unexpected result")
+			}
+			errs <- err
+		}(options[i%len(options)])
+	}
+
+	<-repo.entered
+	// Даем остальным вызовам присоединиться к выполняющемуся запросу
+	time.Sleep(100 * time.Millisecond)
+	close(repo.release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), repo.calls.Load())
+}
+
+func TestPVZService_ListPVZ_ErrorNotCached(t *testing.T) {
+	repo := &pvzTestBlockingRepository{
+		entered: make(chan struct{}, 2),
+		release: make(chan struct{}),
+		err:     errors.New("database error"),
+	}
+	close(repo.release)
+	service := NewPVZService(repo)
+
+	_, _, err := service.ListPVZ(context.Background(), models.PVZListOptions{Page: 1, Limit: 10})
+	assert.Error(t, err)
+
+	repo.err = nil
+	pvzs, total, err := service.ListPVZ(context.Background(), models.PVZListOptions{Page: 1, Limit: 10})
+
+	assert.NoError(t, err)
+	assert.Len(t, pvzs, 1)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, int32(2), repo.calls.Load())
+}
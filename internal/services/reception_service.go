@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"time"
 
 	"pvz-service/internal/domain/interfaces"
 	"pvz-service/internal/domain/models"
@@ -16,8 +17,13 @@ type ReceptionService struct {
 	receptionRepo interfaces.ReceptionRepository
 	pvzRepo       interfaces.PVZRepository
 	productRepo   interfaces.ProductRepository
+
+	closeNotifier interfaces.ReceptionNotifier
+	events        interfaces.EventPublisher
 }
 
+var _ interfaces.ReceptionService = (*ReceptionService)(nil)
+
 func NewReceptionService(receptionRepo interfaces.ReceptionRepository, pvzRepo interfaces.PVZRepository, productRepo interfaces.ProductRepository) *ReceptionService {
 	return &ReceptionService{
 		receptionRepo: receptionRepo,
@@ -26,40 +32,101 @@ func NewReceptionService(receptionRepo interfaces.ReceptionRepository, pvzRepo i
 	}
 }
 
+// WithCloseNotifier задает получателя уведомлений о закрытии приемок.
+// Уведомления отправляются асинхронно и не влияют на результат закрытия
+func (s *ReceptionService) WithCloseNotifier(notifier interfaces.ReceptionNotifier) *ReceptionService {
+	s.closeNotifier = notifier
+	return s
+}
+
+// WithEventPublisher задает получателя событий об открытии и закрытии приемок
+func (s *ReceptionService) WithEventPublisher(events interfaces.EventPublisher) *ReceptionService {
+	s.events = events
+	return s
+}
+
 func (s *ReceptionService) CreateReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error) {
 	log := logger.FromContext(ctx)
 	log.Debug("CreateReception called", "pvz_id", pvzID)
 
+	reception, _, err := s.createReception(ctx, pvzID, false)
+	return reception, err
+}
+
+// CreateOrReuseReception открывает приемку в ПВЗ, а если открытая уже есть, возвращает ее
+// вместо ошибки. reused сообщает, что приемка не создана, а найдена
+func (s *ReceptionService) CreateOrReuseReception(ctx context.Context, pvzID uuid.UUID) (reception *models.Reception, reused bool, err error) {
+	log := logger.FromContext(ctx)
+	log.Debug("CreateOrReuseReception called", "pvz_id", pvzID)
+
+	return s.createReception(ctx, pvzID, true)
+}
+
+func (s *ReceptionService) createReception(ctx context.Context, pvzID uuid.UUID, reuseOpen bool) (*models.Reception, bool, error) {
+	log := logger.FromContext(ctx)
+
 	pvz, err := s.pvzRepo.GetPVZByID(ctx, pvzID)
 	if err != nil {
-		log.Error("Error getting PVZ", "error", err, "pvz_id", pvzID)
-		return nil, err
+		log.Error("Error getting PVZ", logger.Outcome(logger.OutcomeError), "error", err, "pvz_id", pvzID)
+		return nil, false, err
 	}
 	if pvz == nil {
-		log.Warn("PVZ not found", "pvz_id", pvzID)
-		return nil, errors.New("pvz not found")
+		log.Warn("PVZ not found", logger.Outcome(logger.OutcomeNotFound), "pvz_id", pvzID)
+		return nil, false, errors.New("pvz not found")
 	}
 
 	openReception, err := s.receptionRepo.GetLastOpenReceptionByPVZID(ctx, pvzID)
 	if err != nil {
-		log.Error("Error checking for open receptions", "error", err, "pvz_id", pvzID)
-		return nil, err
+		log.Error("Error checking for open receptions", logger.Outcome(logger.OutcomeError), "error", err, "pvz_id", pvzID)
+		return nil, false, err
 	}
 	if openReception != nil {
-		log.Warn("Open reception already exists", "pvz_id", pvzID, "reception_id", openReception.ID)
-		return nil, errors.New("there is already an open reception for this pvz")
+		if reuseOpen {
+			log.Info("Open reception reused", logger.Outcome(logger.OutcomeSuccess), "pvz_id", pvzID, "reception_id", openReception.ID)
+			return openReception, true, nil
+		}
+		log.Warn("Open reception already exists", logger.Outcome(logger.OutcomeConflict), "pvz_id", pvzID, "reception_id", openReception.ID)
+		return nil, false, models.ErrReceptionAlreadyOpen
 	}
 
 	reception, err := s.receptionRepo.CreateReception(ctx, pvzID)
+	if errors.Is(err, models.ErrReceptionAlreadyOpen) {
+		// Приемку открыл параллельный запрос между проверкой и вставкой
+		if !reuseOpen {
+			log.Warn("Open reception created concurrently", logger.Outcome(logger.OutcomeConflict), "pvz_id", pvzID)
+			return nil, false, err
+		}
+		return s.reuseConcurrentReception(ctx, pvzID)
+	}
 	if err != nil {
-		log.Error("Error creating reception", "error", err, "pvz_id", pvzID)
-		return nil, err
+		log.Error("Error creating reception", logger.Outcome(logger.OutcomeError), "error", err, "pvz_id", pvzID)
+		return nil, false, err
 	}
 
-	metrics.IncrementReceptionCreated()
+	metrics.IncrementReceptionCreated(pvz.City)
 
-	log.Info("Reception created successfully", "reception_id", reception.ID, "pvz_id", pvzID)
-	return reception, nil
+	log.Info("Reception created successfully", logger.Outcome(logger.OutcomeSuccess), "reception_id", reception.ID, "pvz_id", pvzID)
+	publishEvent(s.events, models.EventReceptionOpened, pvzID, *reception)
+	return reception, false, nil
+}
+
+// reuseConcurrentReception возвращает приемку, открытую параллельным запросом
+func (s *ReceptionService) reuseConcurrentReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, bool, error) {
+	log := logger.FromContext(ctx)
+
+	openReception, err := s.receptionRepo.GetLastOpenReceptionByPVZID(ctx, pvzID)
+	if err != nil {
+		log.Error("Error getting concurrently opened reception", logger.Outcome(logger.OutcomeError), "error", err, "pvz_id", pvzID)
+		return nil, false, err
+	}
+	if openReception == nil {
+		// Параллельная приемка уже закрыта: повтор запроса откроет новую
+		log.Warn("Concurrently opened reception is already closed", logger.Outcome(logger.OutcomeConflict), "pvz_id", pvzID)
+		return nil, false, models.ErrReceptionAlreadyOpen
+	}
+
+	log.Info("Open reception reused", logger.Outcome(logger.OutcomeSuccess), "pvz_id", pvzID, "reception_id", openReception.ID)
+	return openReception, true, nil
 }
 
 func (s *ReceptionService) CloseLastReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error) {
@@ -68,51 +135,425 @@ func (s *ReceptionService) CloseLastReception(ctx context.Context, pvzID uuid.UU
 
 	openReception, err := s.receptionRepo.GetLastOpenReceptionByPVZID(ctx, pvzID)
 	if err != nil {
-		log.Error("Error getting last open reception", "error", err, "pvz_id", pvzID)
+		log.Error("Error getting last open reception", logger.Outcome(logger.OutcomeError), "error", err, "pvz_id", pvzID)
 		return nil, err
 	}
 	if openReception == nil {
-		log.Warn("No open reception found", "pvz_id", pvzID)
+		log.Warn("No open reception found", logger.Outcome(logger.OutcomeConflict), "pvz_id", pvzID)
 		return nil, errors.New("no open reception found for this pvz")
 	}
 
 	err = s.receptionRepo.CloseReception(ctx, openReception.ID)
+	if errors.Is(err, models.ErrReceptionClosed) {
+		// Приемку закрыли параллельно: закрытие, уведомления и событие уже выполнил другой запрос
+		log.Warn("Reception was closed concurrently", logger.Outcome(logger.OutcomeConflict), "reception_id", openReception.ID, "pvz_id", pvzID)
+		return nil, errors.New("no open reception found for this pvz")
+	}
 	if err != nil {
-		log.Error("Error closing reception", "error", err, "reception_id", openReception.ID)
+		log.Error("Error closing reception", logger.Outcome(logger.OutcomeError), "error", err, "reception_id", openReception.ID)
 		return nil, err
 	}
 
 	updatedReception, err := s.receptionRepo.GetReceptionByID(ctx, openReception.ID)
 	if err != nil {
-		log.Error("Error getting updated reception", "error", err, "reception_id", openReception.ID)
+		log.Error("Error getting updated reception", logger.Outcome(logger.OutcomeError), "error", err, "reception_id", openReception.ID)
+		return nil, err
+	}
+
+	log.Info("Reception closed successfully", logger.Outcome(logger.OutcomeSuccess), "reception_id", updatedReception.ID, "pvz_id", pvzID)
+
+	if s.closeNotifier != nil {
+		notified := *updatedReception
+		go s.notifyReceptionClosed(logger.WithLogger(context.Background(), log), &notified, time.Now())
+	}
+	publishEvent(s.events, models.EventReceptionClosed, updatedReception.PVZID, *updatedReception)
+
+	return updatedReception, nil
+}
+
+// TouchReception отмечает активность в открытой приемке ПВЗ, чтобы ее не закрыло
+// автозакрытие. Возвращает models.ErrReceptionNotFound, если открытой приемки нет
+func (s *ReceptionService) TouchReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error) {
+	log := logger.FromContext(ctx)
+	log.Debug("TouchReception called", "pvz_id", pvzID)
+
+	reception, err := s.receptionRepo.TouchOpenReception(ctx, pvzID)
+	if err != nil {
+		log.Error("Error touching open reception", logger.Outcome(logger.OutcomeError), "error", err, "pvz_id", pvzID)
+		return nil, err
+	}
+	if reception == nil {
+		log.Warn("No open reception to touch", logger.Outcome(logger.OutcomeNotFound), "pvz_id", pvzID)
+		return nil, models.ErrReceptionNotFound
+	}
+
+	log.Info("Reception touched", logger.Outcome(logger.OutcomeSuccess), "reception_id", reception.ID, "pvz_id", pvzID)
+	return reception, nil
+}
+
+// CloseReceptionByID закрывает приемку по ее идентификатору. Возвращает
+// models.ErrReceptionNotFound, если приемки нет, и models.ErrReceptionClosed,
+// если она уже закрыта
+func (s *ReceptionService) CloseReceptionByID(ctx context.Context, id uuid.UUID) (*models.Reception, error) {
+	log := logger.FromContext(ctx)
+	log.Debug("CloseReceptionByID called", "reception_id", id)
+
+	reception, err := s.receptionRepo.GetReceptionByID(ctx, id)
+	if err != nil {
+		log.Error("Error getting reception", logger.Outcome(logger.OutcomeError), "error", err, "reception_id", id)
+		return nil, err
+	}
+	if reception == nil {
+		log.Warn("Reception not found", logger.Outcome(logger.OutcomeNotFound), "reception_id", id)
+		return nil, models.ErrReceptionNotFound
+	}
+	if reception.Status != models.StatusInProgress {
+		log.Warn("Reception is already closed", logger.Outcome(logger.OutcomeConflict), "reception_id", id)
+		return nil, models.ErrReceptionClosed
+	}
+
+	err = s.receptionRepo.CloseReception(ctx, id)
+	if errors.Is(err, models.ErrReceptionClosed) {
+		// Приемку закрыли между чтением и обновлением: повторно не уведомляем
+		log.Warn("Reception was closed concurrently", logger.Outcome(logger.OutcomeConflict), "reception_id", id)
+		return nil, models.ErrReceptionClosed
+	}
+	if err != nil {
+		log.Error("Error closing reception", logger.Outcome(logger.OutcomeError), "error", err, "reception_id", id)
+		return nil, err
+	}
+
+	updatedReception, err := s.receptionRepo.GetReceptionByID(ctx, id)
+	if err != nil {
+		log.Error("Error getting updated reception", logger.Outcome(logger.OutcomeError), "error", err, "reception_id", id)
 		return nil, err
 	}
 
-	log.Info("Reception closed successfully", "reception_id", updatedReception.ID, "pvz_id", pvzID)
+	log.Info("Reception closed successfully", logger.Outcome(logger.OutcomeSuccess), "reception_id", id, "pvz_id", updatedReception.PVZID)
+
+	if s.closeNotifier != nil {
+		notified := *updatedReception
+		go s.notifyReceptionClosed(logger.WithLogger(context.Background(), log), &notified, time.Now())
+	}
+	publishEvent(s.events, models.EventReceptionClosed, updatedReception.PVZID, *updatedReception)
+
 	return updatedReception, nil
 }
 
+// publishEvent отправляет событие, если получатель задан. data передается по значению,
+// чтобы подписчики не видели последующих изменений объекта
+func publishEvent(events interfaces.EventPublisher, eventType models.EventType, pvzID uuid.UUID, data interface{}) {
+	if events == nil {
+		return
+	}
+	events.Publish(models.Event{
+		Type:     eventType,
+		PVZID:    pvzID,
+		DateTime: time.Now(),
+		Data:     data,
+	})
+}
+
+// notifyReceptionClosed дополняет закрытую приемку числом товаров и отправляет уведомление.
+// Вызывается в отдельной горутине: ошибки только логируются и не влияют на закрытие.
+// Если товары посчитать не удалось, уведомление уходит без числа товаров
+func (s *ReceptionService) notifyReceptionClosed(ctx context.Context, reception *models.Reception, closedAt time.Time) {
+	log := logger.FromContext(ctx)
+
+	notification := &models.ReceptionClosedNotification{
+		Reception: *reception,
+		ClosedAt:  closedAt,
+	}
+
+	productCount, err := s.productRepo.CountProductsByReceptionID(ctx, reception.ID)
+	if err != nil {
+		log.Error("Error counting products for close notification", "error", err, "reception_id", reception.ID)
+	} else {
+		notification.ProductCount = &productCount
+	}
+
+	if err := s.closeNotifier.NotifyReceptionClosed(ctx, notification); err != nil {
+		log.Error("Error notifying about closed reception", "error", err, "reception_id", reception.ID)
+	}
+}
+
 func (s *ReceptionService) GetReceptionByID(ctx context.Context, id uuid.UUID) (*models.Reception, error) {
 	log := logger.FromContext(ctx)
 	log.Debug("GetReceptionByID called", "reception_id", id)
 
 	reception, err := s.receptionRepo.GetReceptionByID(ctx, id)
 	if err != nil {
-		log.Error("Error getting reception", "error", err, "reception_id", id)
+		log.Error("Error getting reception", logger.Outcome(logger.OutcomeError), "error", err, "reception_id", id)
 		return nil, err
 	}
 	if reception == nil {
-		log.Warn("Reception not found", "reception_id", id)
-		return nil, errors.New("reception not found")
+		log.Warn("Reception not found", logger.Outcome(logger.OutcomeNotFound), "reception_id", id)
+		return nil, models.ErrReceptionNotFound
 	}
 
-	products, _, err := s.productRepo.GetProductsByReceptionID(ctx, id, 1, 1000)
+	products, _, err := s.productRepo.GetProductsByReceptionID(ctx, id, 1, 1000, "")
 	if err != nil {
-		log.Error("Error getting products for reception", "error", err, "reception_id", id)
+		log.Error("Error getting products for reception", logger.Outcome(logger.OutcomeError), "error", err, "reception_id", id)
 		return nil, err
 	}
 
-	reception.Products = products
-	log.Info("Reception retrieved successfully", "reception_id", id, "products_count", len(products))
+	reception.Products = emptyIfNil(products)
+	log.Info("Reception retrieved successfully", logger.Outcome(logger.OutcomeSuccess), "reception_id", id, "products_count", len(products))
 	return reception, nil
 }
+
+// GetReceptionSummary возвращает число товаров приемки всего и по каждому типу
+func (s *ReceptionService) GetReceptionSummary(ctx context.Context, id uuid.UUID) (*models.ReceptionSummary, error) {
+	log := logger.FromContext(ctx)
+	log.Debug("GetReceptionSummary called", "reception_id", id)
+
+	reception, err := s.receptionRepo.GetReceptionByID(ctx, id)
+	if err != nil {
+		log.Error("Error getting reception", logger.Outcome(logger.OutcomeError), "error", err, "reception_id", id)
+		return nil, err
+	}
+	if reception == nil {
+		log.Warn("Reception not found", logger.Outcome(logger.OutcomeNotFound), "reception_id", id)
+		return nil, models.ErrReceptionNotFound
+	}
+
+	counts, err := s.productRepo.CountProductsByTypeByReceptionID(ctx, id)
+	if err != nil {
+		log.Error("Error counting products by type", logger.Outcome(logger.OutcomeError), "error", err, "reception_id", id)
+		return nil, err
+	}
+
+	summary := &models.ReceptionSummary{
+		ByType: map[models.ProductType]int{
+			models.TypeElectronics: 0,
+			models.TypeClothes:     0,
+			models.TypeFootwear:    0,
+		},
+	}
+	for productType, count := range counts {
+		summary.ByType[productType] = count
+		summary.ItemsCount += count
+	}
+
+	log.Info("Reception summary retrieved successfully", logger.Outcome(logger.OutcomeSuccess), "reception_id", id, "items_count", summary.ItemsCount)
+	return summary, nil
+}
+
+// GetReceptionTypeCounts возвращает количество товаров приемки по типам.
+// Если приемка не найдена, возвращает models.ErrReceptionNotFound
+func (s *ReceptionService) GetReceptionTypeCounts(ctx context.Context, id uuid.UUID) (*models.ReceptionTypeCounts, error) {
+	log := logger.FromContext(ctx)
+	log.Debug("GetReceptionTypeCounts called", "reception_id", id)
+
+	reception, err := s.receptionRepo.GetReceptionByID(ctx, id)
+	if err != nil {
+		log.Error("Error getting reception", logger.Outcome(logger.OutcomeError), "error", err, "reception_id", id)
+		return nil, err
+	}
+	if reception == nil {
+		log.Warn("Reception not found", logger.Outcome(logger.OutcomeNotFound), "reception_id", id)
+		return nil, models.ErrReceptionNotFound
+	}
+
+	counts, err := s.productRepo.CountProductsByTypeByReceptionID(ctx, id)
+	if err != nil {
+		log.Error("Error counting products by type", logger.Outcome(logger.OutcomeError), "error", err, "reception_id", id)
+		return nil, err
+	}
+
+	result := &models.ReceptionTypeCounts{
+		Electronics: counts[models.TypeElectronics],
+		Clothes:     counts[models.TypeClothes],
+		Footwear:    counts[models.TypeFootwear],
+	}
+
+	log.Info("Reception type counts retrieved successfully", logger.Outcome(logger.OutcomeSuccess), "reception_id", id)
+	return result, nil
+}
+
+// GetReceptionSequenceGaps возвращает номера товаров, пропущенные в нумерации приемки.
+// Если приемка не найдена, возвращает models.ErrReceptionNotFound
+func (s *ReceptionService) GetReceptionSequenceGaps(ctx context.Context, id uuid.UUID) (*models.ReceptionSequenceGaps, error) {
+	log := logger.FromContext(ctx)
+	log.Debug("GetReceptionSequenceGaps called", "reception_id", id)
+
+	reception, err := s.receptionRepo.GetReceptionByID(ctx, id)
+	if err != nil {
+		log.Error("Error getting reception", logger.Outcome(logger.OutcomeError), "error", err, "reception_id", id)
+		return nil, err
+	}
+	if reception == nil {
+		log.Warn("Reception not found", logger.Outcome(logger.OutcomeNotFound), "reception_id", id)
+		return nil, models.ErrReceptionNotFound
+	}
+
+	missing, err := s.productRepo.GetSequenceGaps(ctx, id)
+	if err != nil {
+		log.Error("Error getting sequence gaps", logger.Outcome(logger.OutcomeError), "error", err, "reception_id", id)
+		return nil, err
+	}
+
+	log.Info("Reception sequence gaps retrieved successfully", logger.Outcome(logger.OutcomeSuccess), "reception_id", id, "missing", len(missing))
+	return &models.ReceptionSequenceGaps{ReceptionID: id, Missing: emptyIfNil(missing)}, nil
+}
+
+// ResequenceReception перенумеровывает товары открытой приемки подряд от 1 в текущем порядке.
+// Возвращает models.ErrReceptionNotFound или models.ErrReceptionClosed
+func (s *ReceptionService) ResequenceReception(ctx context.Context, id uuid.UUID) (*models.ReceptionResequence, error) {
+	log := logger.FromContext(ctx)
+	log.Debug("ResequenceReception called", "reception_id", id)
+
+	count, err := s.productRepo.ResequenceProducts(ctx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrReceptionNotFound):
+			log.Warn("Reception not found", logger.Outcome(logger.OutcomeNotFound), "reception_id", id)
+		case errors.Is(err, models.ErrReceptionClosed):
+			log.Warn("Reception is closed", logger.Outcome(logger.OutcomeConflict), "reception_id", id)
+		default:
+			log.Error("Error resequencing products", logger.Outcome(logger.OutcomeError), "error", err, "reception_id", id)
+		}
+		return nil, err
+	}
+
+	log.Info("Reception resequenced successfully", logger.Outcome(logger.OutcomeSuccess), "reception_id", id, "products", count)
+	return &models.ReceptionResequence{ReceptionID: id, Products: count}, nil
+}
+
+func (s *ReceptionService) ListReceptions(ctx context.Context, options models.ReceptionListOptions) ([]*models.Reception, int, error) {
+	log := logger.FromContext(ctx)
+	log.Debug("ListReceptions called",
+		"page", options.Page,
+		"limit", options.Limit,
+		"contains_type", options.ContainsType,
+	)
+
+	receptions, total, err := s.receptionRepo.ListReceptions(ctx, options)
+	if err != nil {
+		log.Error("Error listing receptions", logger.Outcome(logger.OutcomeError), "error", err)
+		return nil, 0, err
+	}
+
+	log.Debug("Receptions listed", "count", len(receptions), "total", total)
+	return emptyIfNil(receptions), total, nil
+}
+
+// GetReceptionStats возвращает количество открытых и закрытых приемок, по всем ПВЗ
+// или по одному, если pvzID не nil. Для несуществующего ПВЗ возвращает models.ErrPVZNotFound
+func (s *ReceptionService) GetReceptionStats(ctx context.Context, pvzID *uuid.UUID) (*models.ReceptionStats, error) {
+	log := logger.FromContext(ctx)
+	log.Debug("GetReceptionStats called", "pvz_id", pvzID)
+
+	if pvzID != nil {
+		pvz, err := s.pvzRepo.GetPVZByID(ctx, *pvzID)
+		if err != nil {
+			log.Error("Error getting PVZ", logger.Outcome(logger.OutcomeError), "error", err, "pvz_id", *pvzID)
+			return nil, err
+		}
+		if pvz == nil {
+			log.Warn("PVZ not found", logger.Outcome(logger.OutcomeNotFound), "pvz_id", *pvzID)
+			return nil, models.ErrPVZNotFound
+		}
+	}
+
+	counts, err := s.receptionRepo.CountReceptionsByStatus(ctx, pvzID)
+	if err != nil {
+		log.Error("Error counting receptions by status", logger.Outcome(logger.OutcomeError), "error", err)
+		return nil, err
+	}
+
+	stats := &models.ReceptionStats{
+		ByStatus: map[string]int{
+			string(models.StatusInProgress): 0,
+			string(models.StatusClosed):     0,
+		},
+	}
+	for status, count := range counts {
+		stats.ByStatus[status] = count
+		stats.Total += count
+	}
+
+	log.Info("Reception stats retrieved successfully", logger.Outcome(logger.OutcomeSuccess), "pvz_id", pvzID, "total", stats.Total)
+	return stats, nil
+}
+
+// PurgeReceptions удаляет закрытые приемки старше before вместе с товарами
+func (s *ReceptionService) PurgeReceptions(ctx context.Context, before time.Time) (int, error) {
+	log := logger.FromContext(ctx)
+	log.Debug("PurgeReceptions called", "before", before)
+
+	deleted, err := s.receptionRepo.PurgeReceptions(ctx, before)
+	if err != nil {
+		log.Error("Error purging receptions", logger.Outcome(logger.OutcomeError), "error", err, "before", before)
+		return 0, err
+	}
+
+	log.Info("Receptions purged successfully", logger.Outcome(logger.OutcomeSuccess), "before", before, "deleted", deleted)
+	return deleted, nil
+}
+
+// ExportReceptions вызывает fn для каждого ПВЗ, у которого есть приемки в [from, to), с этими
+// приемками (от новых к старым) и их товарами. ПВЗ без приемок в периоде отсекаются одним
+// запросом; приемки загружаются по одному ПВЗ, поэтому в памяти держатся приемки не больше
+// одного ПВЗ. Ошибка fn прерывает обход и возвращается как есть
+func (s *ReceptionService) ExportReceptions(ctx context.Context, from, to time.Time, fn func(pvz *models.PVZ, receptions []*models.Reception) error) error {
+	log := logger.FromContext(ctx)
+	log.Debug("ExportReceptions called", "from", from, "to", to)
+
+	exported := 0
+	err := func() error {
+		pvzs, err := s.pvzRepo.ListPVZWithReceptionsBetween(ctx, from, to)
+		if err != nil {
+			return err
+		}
+
+		for _, pvz := range pvzs {
+			ids, err := s.receptionRepo.ListReceptionIDsByPVZ(ctx, pvz.ID, from, to)
+			if err != nil {
+				return err
+			}
+			// Приемки могли удалить после выборки ПВЗ
+			if len(ids) == 0 {
+				continue
+			}
+
+			receptions, err := s.receptionRepo.GetReceptionsWithProducts(ctx, ids)
+			if err != nil {
+				return err
+			}
+
+			exported++
+			if err := fn(pvz, receptions); err != nil {
+				return err
+			}
+		}
+		return nil
+	}()
+	if err != nil {
+		log.Error("Error exporting receptions", logger.Outcome(logger.OutcomeError), "exported_pvz", exported, "error", err)
+		return err
+	}
+
+	log.Info("Receptions exported", logger.Outcome(logger.OutcomeSuccess), "from", from, "to", to, "exported_pvz", exported)
+	return nil
+}
+
+// ListReceptionsByPVZ возвращает приемки одного ПВЗ; PVZID в options заменяется на pvzID.
+// Если ПВЗ не существует, возвращает models.ErrPVZNotFound
+func (s *ReceptionService) ListReceptionsByPVZ(ctx context.Context, pvzID uuid.UUID, options models.ReceptionListOptions) ([]*models.Reception, int, error) {
+	log := logger.FromContext(ctx)
+	log.Debug("ListReceptionsByPVZ called", "pvz_id", pvzID, "page", options.Page, "limit", options.Limit, "status", options.Status)
+
+	pvz, err := s.pvzRepo.GetPVZByID(ctx, pvzID)
+	if err != nil {
+		log.Error("Error getting PVZ", logger.Outcome(logger.OutcomeError), "error", err, "pvz_id", pvzID)
+		return nil, 0, err
+	}
+	if pvz == nil {
+		log.Warn("PVZ not found", logger.Outcome(logger.OutcomeNotFound), "pvz_id", pvzID)
+		return nil, 0, models.ErrPVZNotFound
+	}
+
+	options.PVZID = pvzID
+	return s.ListReceptions(ctx, options)
+}
@@ -7,10 +7,13 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"pvz-service/internal/domain/models"
+	"pvz-service/internal/notifier"
 )
 
 var (
@@ -22,12 +25,12 @@ type PVZServiceTestMockRepository struct {
 	mock.Mock
 }
 
-func (m *PVZServiceTestMockRepository) CreatePVZ(ctx context.Context, city string) (*models.PVZ, error) {
-	args := m.Called(ctx, city)
+func (m *PVZServiceTestMockRepository) CreatePVZ(ctx context.Context, city, externalID string) (*models.PVZ, bool, error) {
+	args := m.Called(ctx, city, externalID)
 	if args.Get(0) == nil {
-		return nil, args.Error(1)
+		return nil, args.Bool(1), args.Error(2)
 	}
-	return args.Get(0).(*models.PVZ), args.Error(1)
+	return args.Get(0).(*models.PVZ), args.Bool(1), args.Error(2)
 }
 
 func (m *PVZServiceTestMockRepository) GetPVZByID(ctx context.Context, id uuid.UUID) (*models.PVZ, error) {
@@ -38,11 +41,55 @@ func (m *PVZServiceTestMockRepository) GetPVZByID(ctx context.Context, id uuid.U
 	return args.Get(0).(*models.PVZ), args.Error(1)
 }
 
+func (m *PVZServiceTestMockRepository) GetPVZsByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.PVZ, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.PVZ), args.Error(1)
+}
+
+func (m *PVZServiceTestMockRepository) CreatePVZBatch(ctx context.Context, cities []string) ([]*models.PVZ, error) {
+	args := m.Called(ctx, cities)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.PVZ), args.Error(1)
+}
+
 func (m *PVZServiceTestMockRepository) ListPVZ(ctx context.Context, options models.PVZListOptions) ([]*models.PVZWithReceptionsResponse, int, error) {
 	args := m.Called(ctx, options)
 	return args.Get(0).([]*models.PVZWithReceptionsResponse), args.Int(1), args.Error(2)
 }
 
+func (m *PVZServiceTestMockRepository) CountPVZByCity(ctx context.Context) ([]models.CityPVZCount, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.CityPVZCount), args.Error(1)
+}
+
+func (m *PVZServiceTestMockRepository) StreamPVZ(ctx context.Context, fn func(*models.PVZ) error) error {
+	args := m.Called(ctx)
+	if pvzs, ok := args.Get(0).([]*models.PVZ); ok {
+		for _, pvz := range pvzs {
+			if err := fn(pvz); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *PVZServiceTestMockRepository) ListPVZWithReceptionsBetween(ctx context.Context, from, to time.Time) ([]*models.PVZ, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.PVZ), args.Error(1)
+}
+
 func setupPVZServiceTest(t *testing.T) (*PVZServiceTestMockRepository, *PVZService, time.Time) {
 	mockRepo := new(PVZServiceTestMockRepository)
 	service := NewPVZService(mockRepo)
@@ -62,12 +109,12 @@ func TestPVZServiceCreate(t *testing.T) {
 			name: "Success - Moscow",
 			city: "Москва",
 			setupMock: func(repo *PVZServiceTestMockRepository, now time.Time) {
-				repo.On("CreatePVZ", mock.Anything, "Москва").
+				repo.On("CreatePVZ", mock.Anything, "Москва", "").
 					Return(&models.PVZ{
 						ID:               pvzServiceTestUUID1,
 						RegistrationDate: now,
 						City:             "Москва",
-					}, nil)
+					}, true, nil)
 			},
 			expectedError: false,
 			checkResult: func(t *testing.T, pvz *models.PVZ, err error) {
@@ -95,7 +142,7 @@ func TestPVZServiceCreate(t *testing.T) {
 			repo, service, now := setupPVZServiceTest(t)
 			tc.setupMock(repo, now)
 
-			pvz, err := service.CreatePVZ(context.Background(), tc.city)
+			pvz, _, err := service.CreatePVZ(context.Background(), tc.city, "")
 
 			tc.checkResult(t, pvz, err)
 			repo.AssertExpectations(t)
@@ -226,3 +273,444 @@ func TestPVZServiceList(t *testing.T) {
 		})
 	}
 }
+
+type ReceptionTestMockNotifier struct {
+	mock.Mock
+	done chan struct{}
+}
+
+func (m *ReceptionTestMockNotifier) NotifyReceptionClosed(ctx context.Context, notification *models.ReceptionClosedNotification) error {
+	defer close(m.done)
+	args := m.Called(ctx, notification)
+	return args.Error(0)
+}
+
+func TestReceptionService_CloseLastReception_Notifies(t *testing.T) {
+	testCases := []struct {
+		name        string
+		notifyError error
+	}{
+		{name: "Success - Webhook Delivered"},
+		{name: "Success - Webhook Error Does Not Fail Close", notifyError: errors.New("webhook unavailable")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockPVZRepo, mockReceptionRepo, mockProductRepo, now := setupProductTestMocks(t)
+
+			openReception := &models.Reception{
+				ID:       productTestReceptionUUID1,
+				DateTime: now,
+				PVZID:    productTestPvzUUID1,
+				Status:   models.StatusInProgress,
+			}
+			closedReception := &models.Reception{
+				ID:       productTestReceptionUUID1,
+				DateTime: now,
+				PVZID:    productTestPvzUUID1,
+				Status:   models.StatusClosed,
+			}
+
+			mockReceptionRepo.On("GetLastOpenReceptionByPVZID", mock.Anything, productTestPvzUUID1).Return(openReception, nil)
+			mockReceptionRepo.On("CloseReception", mock.Anything, productTestReceptionUUID1).Return(nil)
+			mockReceptionRepo.On("GetReceptionByID", mock.Anything, productTestReceptionUUID1).Return(closedReception, nil)
+
+			mockProductRepo.On("CountProductsByReceptionID", mock.Anything, productTestReceptionUUID1).Return(7, nil)
+
+			notifier := &ReceptionTestMockNotifier{done: make(chan struct{})}
+			notifier.On("NotifyReceptionClosed", mock.Anything, mock.MatchedBy(func(n *models.ReceptionClosedNotification) bool {
+				return n.ID == productTestReceptionUUID1 && n.Status == models.StatusClosed &&
+					n.ProductCount != nil && *n.ProductCount == 7 && !n.ClosedAt.IsZero()
+			})).Return(tc.notifyError)
+
+			service := NewReceptionService(mockReceptionRepo, mockPVZRepo, mockProductRepo).
+				WithCloseNotifier(notifier)
+
+			reception, err := service.CloseLastReception(context.Background(), productTestPvzUUID1)
+
+			assert.NoError(t, err)
+			assert.Equal(t, models.StatusClosed, reception.Status)
+
+			select {
+			case <-notifier.done:
+			case <-time.After(time.Second):
+				t.Fatal("уведомление о закрытии приемки не отправлено")
+			}
+
+			notifier.AssertExpectations(t)
+			mockReceptionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReceptionService_CloseLastReception_NotifiesWhenCountFails(t *testing.T) {
+	mockPVZRepo, mockReceptionRepo, mockProductRepo, now := setupProductTestMocks(t)
+
+	mockReceptionRepo.On("GetLastOpenReceptionByPVZID", mock.Anything, productTestPvzUUID1).Return(&models.Reception{
+		ID:       productTestReceptionUUID1,
+		DateTime: now,
+		PVZID:    productTestPvzUUID1,
+		Status:   models.StatusInProgress,
+	}, nil)
+	mockReceptionRepo.On("CloseReception", mock.Anything, productTestReceptionUUID1).Return(nil)
+	mockReceptionRepo.On("GetReceptionByID", mock.Anything, productTestReceptionUUID1).Return(&models.Reception{
+		ID:       productTestReceptionUUID1,
+		DateTime: now,
+		PVZID:    productTestPvzUUID1,
+		Status:   models.StatusClosed,
+	}, nil)
+	mockProductRepo.On("CountProductsByReceptionID", mock.Anything, productTestReceptionUUID1).Return(0, errors.New("database error"))
+
+	notifier := &ReceptionTestMockNotifier{done: make(chan struct{})}
+	notifier.On("NotifyReceptionClosed", mock.Anything, mock.MatchedBy(func(n *models.ReceptionClosedNotification) bool {
+		return n.ID == productTestReceptionUUID1 && n.ProductCount == nil
+	})).Return(nil)
+
+	service := NewReceptionService(mockReceptionRepo, mockPVZRepo, mockProductRepo).
+		WithCloseNotifier(notifier)
+
+	_, err := service.CloseLastReception(context.Background(), productTestPvzUUID1)
+	require.NoError(t, err)
+
+	select {
+	case <-notifier.done:
+	case <-time.After(time.Second):
+		t.Fatal("уведомление о закрытии приемки не отправлено")
+	}
+
+	notifier.AssertExpectations(t)
+}
+
+func TestReceptionService_GetReceptionSummary(t *testing.T) {
+	mockPVZRepo, mockReceptionRepo, mockProductRepo, now := setupProductTestMocks(t)
+
+	mockReceptionRepo.On("GetReceptionByID", mock.Anything, productTestReceptionUUID1).Return(&models.Reception{
+		ID:       productTestReceptionUUID1,
+		DateTime: now,
+		PVZID:    productTestPvzUUID1,
+		Status:   models.StatusInProgress,
+	}, nil)
+	mockProductRepo.On("CountProductsByTypeByReceptionID", mock.Anything, productTestReceptionUUID1).Return(map[models.ProductType]int{
+		models.TypeElectronics: 2,
+		models.TypeFootwear:    5,
+	}, nil)
+
+	service := NewReceptionService(mockReceptionRepo, mockPVZRepo, mockProductRepo)
+
+	summary, err := service.GetReceptionSummary(context.Background(), productTestReceptionUUID1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, summary.ItemsCount)
+	assert.Equal(t, map[models.ProductType]int{
+		models.TypeElectronics: 2,
+		models.TypeClothes:     0,
+		models.TypeFootwear:    5,
+	}, summary.ByType)
+
+	mockReceptionRepo.AssertExpectations(t)
+	mockProductRepo.AssertExpectations(t)
+}
+
+func TestReceptionService_GetReceptionSummary_NotFound(t *testing.T) {
+	mockPVZRepo, mockReceptionRepo, mockProductRepo, _ := setupProductTestMocks(t)
+
+	mockReceptionRepo.On("GetReceptionByID", mock.Anything, productTestReceptionUUID1).Return(nil, nil)
+
+	service := NewReceptionService(mockReceptionRepo, mockPVZRepo, mockProductRepo)
+
+	summary, err := service.GetReceptionSummary(context.Background(), productTestReceptionUUID1)
+
+	assert.ErrorIs(t, err, models.ErrReceptionNotFound)
+	assert.Nil(t, summary)
+	mockProductRepo.AssertNotCalled(t, "CountProductsByTypeByReceptionID", mock.Anything, mock.Anything)
+}
+
+func TestReceptionService_CloseReceptionByID(t *testing.T) {
+	mockPVZRepo, mockReceptionRepo, mockProductRepo, now := setupProductTestMocks(t)
+
+	mockReceptionRepo.On("GetReceptionByID", mock.Anything, productTestReceptionUUID1).Return(&models.Reception{
+		ID:       productTestReceptionUUID1,
+		DateTime: now,
+		PVZID:    productTestPvzUUID1,
+		Status:   models.StatusInProgress,
+	}, nil).Once()
+	mockReceptionRepo.On("CloseReception", mock.Anything, productTestReceptionUUID1).Return(nil)
+	mockReceptionRepo.On("GetReceptionByID", mock.Anything, productTestReceptionUUID1).Return(&models.Reception{
+		ID:       productTestReceptionUUID1,
+		DateTime: now,
+		PVZID:    productTestPvzUUID1,
+		Status:   models.StatusClosed,
+	}, nil).Once()
+
+	service := NewReceptionService(mockReceptionRepo, mockPVZRepo, mockProductRepo)
+
+	reception, err := service.CloseReceptionByID(context.Background(), productTestReceptionUUID1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusClosed, reception.Status)
+	mockReceptionRepo.AssertExpectations(t)
+}
+
+func TestReceptionService_CloseReceptionByID_AlreadyClosed(t *testing.T) {
+	mockPVZRepo, mockReceptionRepo, mockProductRepo, now := setupProductTestMocks(t)
+
+	mockReceptionRepo.On("GetReceptionByID", mock.Anything, productTestReceptionUUID1).Return(&models.Reception{
+		ID:       productTestReceptionUUID1,
+		DateTime: now,
+		PVZID:    productTestPvzUUID1,
+		Status:   models.StatusClosed,
+	}, nil)
+
+	service := NewReceptionService(mockReceptionRepo, mockPVZRepo, mockProductRepo)
+
+	reception, err := service.CloseReceptionByID(context.Background(), productTestReceptionUUID1)
+
+	assert.ErrorIs(t, err, models.ErrReceptionClosed)
+	assert.Nil(t, reception)
+	mockReceptionRepo.AssertNotCalled(t, "CloseReception", mock.Anything, mock.Anything)
+}
+
+func TestReceptionService_CloseReceptionByID_ClosedConcurrently(t *testing.T) {
+	mockPVZRepo, mockReceptionRepo, mockProductRepo, now := setupProductTestMocks(t)
+
+	mockReceptionRepo.On("GetReceptionByID", mock.Anything, productTestReceptionUUID1).Return(&models.Reception{
+		ID:       productTestReceptionUUID1,
+		DateTime: now,
+		PVZID:    productTestPvzUUID1,
+		Status:   models.StatusInProgress,
+	}, nil).Once()
+	mockReceptionRepo.On("CloseReception", mock.Anything, productTestReceptionUUID1).Return(models.ErrReceptionClosed)
+
+	closeNotifier := &ReceptionTestMockNotifier{done: make(chan struct{})}
+	bus := notifier.NewEventBus()
+	events, unsubscribe := bus.Subscribe(productTestPvzUUID1)
+	defer unsubscribe()
+
+	service := NewReceptionService(mockReceptionRepo, mockPVZRepo, mockProductRepo).
+		WithCloseNotifier(closeNotifier).
+		WithEventPublisher(bus)
+
+	reception, err := service.CloseReceptionByID(context.Background(), productTestReceptionUUID1)
+
+	assert.ErrorIs(t, err, models.ErrReceptionClosed)
+	assert.Nil(t, reception)
+	select {
+	case event := <-events:
+		t.Fatalf("неожиданное событие %s", event.Type)
+	default:
+	}
+	closeNotifier.AssertNotCalled(t, "NotifyReceptionClosed", mock.Anything, mock.Anything)
+	mockReceptionRepo.AssertExpectations(t)
+}
+
+func TestReceptionService_CloseReceptionByID_NotFound(t *testing.T) {
+	mockPVZRepo, mockReceptionRepo, mockProductRepo, _ := setupProductTestMocks(t)
+
+	mockReceptionRepo.On("GetReceptionByID", mock.Anything, productTestReceptionUUID1).Return(nil, nil)
+
+	service := NewReceptionService(mockReceptionRepo, mockPVZRepo, mockProductRepo)
+
+	reception, err := service.CloseReceptionByID(context.Background(), productTestReceptionUUID1)
+
+	assert.ErrorIs(t, err, models.ErrReceptionNotFound)
+	assert.Nil(t, reception)
+}
+
+// receptionsCreatedForCity читает receptions_created_total{city} из реестра по умолчанию
+func receptionsCreatedForCity(t *testing.T, city string) float64 {
+	t.Helper()
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != "receptions_created_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "city" && label.GetValue() == city {
+					return metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func TestReceptionService_CreateReception_CountsByCity(t *testing.T) {
+	mockPVZRepo, mockReceptionRepo, mockProductRepo, now := setupProductTestMocks(t)
+
+	mockPVZRepo.On("GetPVZByID", mock.Anything, productTestPvzUUID1).Return(&models.PVZ{
+		ID:               productTestPvzUUID1,
+		RegistrationDate: now,
+		City:             "Казань",
+	}, nil)
+	mockReceptionRepo.On("GetLastOpenReceptionByPVZID", mock.Anything, productTestPvzUUID1).Return(nil, nil)
+	mockReceptionRepo.On("CreateReception", mock.Anything, productTestPvzUUID1).Return(&models.Reception{
+		ID:       productTestReceptionUUID1,
+		DateTime: now,
+		PVZID:    productTestPvzUUID1,
+		Status:   models.StatusInProgress,
+	}, nil)
+
+	service := NewReceptionService(mockReceptionRepo, mockPVZRepo, mockProductRepo)
+
+	kazanBefore := receptionsCreatedForCity(t, "Казань")
+	moscowBefore := receptionsCreatedForCity(t, "Москва")
+
+	_, err := service.CreateReception(context.Background(), productTestPvzUUID1)
+	require.NoError(t, err)
+
+	assert.Equal(t, kazanBefore+1, receptionsCreatedForCity(t, "Казань"))
+	assert.Equal(t, moscowBefore, receptionsCreatedForCity(t, "Москва"))
+	mockReceptionRepo.AssertExpectations(t)
+}
+
+func TestReceptionService_CreateReception_OpenExists(t *testing.T) {
+	openReception := &models.Reception{
+		ID:     productTestReceptionUUID1,
+		PVZID:  productTestPvzUUID1,
+		Status: models.StatusInProgress,
+	}
+
+	t.Run("Strict", func(t *testing.T) {
+		mockPVZRepo, mockReceptionRepo, mockProductRepo, now := setupProductTestMocks(t)
+		mockPVZRepo.On("GetPVZByID", mock.Anything, productTestPvzUUID1).Return(&models.PVZ{ID: productTestPvzUUID1, RegistrationDate: now, City: "Москва"}, nil)
+		mockReceptionRepo.On("GetLastOpenReceptionByPVZID", mock.Anything, productTestPvzUUID1).Return(openReception, nil)
+
+		service := NewReceptionService(mockReceptionRepo, mockPVZRepo, mockProductRepo)
+		reception, err := service.CreateReception(context.Background(), productTestPvzUUID1)
+
+		assert.EqualError(t, err, "there is already an open reception for this pvz")
+		assert.Nil(t, reception)
+		mockReceptionRepo.AssertNotCalled(t, "CreateReception", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Reuse Open", func(t *testing.T) {
+		mockPVZRepo, mockReceptionRepo, mockProductRepo, now := setupProductTestMocks(t)
+		mockPVZRepo.On("GetPVZByID", mock.Anything, productTestPvzUUID1).Return(&models.PVZ{ID: productTestPvzUUID1, RegistrationDate: now, City: "Москва"}, nil)
+		mockReceptionRepo.On("GetLastOpenReceptionByPVZID", mock.Anything, productTestPvzUUID1).Return(openReception, nil)
+
+		service := NewReceptionService(mockReceptionRepo, mockPVZRepo, mockProductRepo)
+		reception, reused, err := service.CreateOrReuseReception(context.Background(), productTestPvzUUID1)
+
+		require.NoError(t, err)
+		assert.True(t, reused)
+		assert.Equal(t, openReception, reception)
+		mockReceptionRepo.AssertNotCalled(t, "CreateReception", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Reuse Open Creates When None", func(t *testing.T) {
+		mockPVZRepo, mockReceptionRepo, mockProductRepo, now := setupProductTestMocks(t)
+		mockPVZRepo.On("GetPVZByID", mock.Anything, productTestPvzUUID1).Return(&models.PVZ{ID: productTestPvzUUID1, RegistrationDate: now, City: "Москва"}, nil)
+		mockReceptionRepo.On("GetLastOpenReceptionByPVZID", mock.Anything, productTestPvzUUID1).Return(nil, nil)
+		mockReceptionRepo.On("CreateReception", mock.Anything, productTestPvzUUID1).Return(openReception, nil)
+
+		service := NewReceptionService(mockReceptionRepo, mockPVZRepo, mockProductRepo)
+		reception, reused, err := service.CreateOrReuseReception(context.Background(), productTestPvzUUID1)
+
+		require.NoError(t, err)
+		assert.False(t, reused)
+		assert.Equal(t, openReception, reception)
+		mockReceptionRepo.AssertExpectations(t)
+	})
+
+	t.Run("Strict Opened Concurrently", func(t *testing.T) {
+		mockPVZRepo, mockReceptionRepo, mockProductRepo, now := setupProductTestMocks(t)
+		mockPVZRepo.On("GetPVZByID", mock.Anything, productTestPvzUUID1).Return(&models.PVZ{ID: productTestPvzUUID1, RegistrationDate: now, City: "Москва"}, nil)
+		mockReceptionRepo.On("GetLastOpenReceptionByPVZID", mock.Anything, productTestPvzUUID1).Return(nil, nil).Once()
+		mockReceptionRepo.On("CreateReception", mock.Anything, productTestPvzUUID1).Return(nil, models.ErrReceptionAlreadyOpen)
+
+		service := NewReceptionService(mockReceptionRepo, mockPVZRepo, mockProductRepo)
+		reception, err := service.CreateReception(context.Background(), productTestPvzUUID1)
+
+		assert.ErrorIs(t, err, models.ErrReceptionAlreadyOpen)
+		assert.Nil(t, reception)
+		mockReceptionRepo.AssertExpectations(t)
+	})
+
+	t.Run("Reuse Open Opened Concurrently", func(t *testing.T) {
+		mockPVZRepo, mockReceptionRepo, mockProductRepo, now := setupProductTestMocks(t)
+		mockPVZRepo.On("GetPVZByID", mock.Anything, productTestPvzUUID1).Return(&models.PVZ{ID: productTestPvzUUID1, RegistrationDate: now, City: "Москва"}, nil)
+		mockReceptionRepo.On("GetLastOpenReceptionByPVZID", mock.Anything, productTestPvzUUID1).Return(nil, nil).Once()
+		mockReceptionRepo.On("CreateReception", mock.Anything, productTestPvzUUID1).Return(nil, models.ErrReceptionAlreadyOpen)
+		mockReceptionRepo.On("GetLastOpenReceptionByPVZID", mock.Anything, productTestPvzUUID1).Return(openReception, nil).Once()
+
+		service := NewReceptionService(mockReceptionRepo, mockPVZRepo, mockProductRepo)
+		reception, reused, err := service.CreateOrReuseReception(context.Background(), productTestPvzUUID1)
+
+		require.NoError(t, err)
+		assert.True(t, reused)
+		assert.Equal(t, openReception, reception)
+		mockReceptionRepo.AssertExpectations(t)
+	})
+}
+
+func TestReceptionService_ExportReceptions(t *testing.T) {
+	mockPVZRepo, mockReceptionRepo, mockProductRepo, now := setupProductTestMocks(t)
+
+	from := now.Add(-30 * 24 * time.Hour)
+	to := now
+	withReceptions := &models.PVZ{ID: productTestPvzUUID1, RegistrationDate: now, City: "Москва"}
+	reception := &models.Reception{
+		ID:       productTestReceptionUUID1,
+		DateTime: now.Add(-time.Hour),
+		PVZID:    productTestPvzUUID1,
+		Status:   models.StatusClosed,
+		Products: []*models.Product{{ID: uuid.New(), ReceptionID: productTestReceptionUUID1, SequenceNum: 1}},
+	}
+
+	mockPVZRepo.On("ListPVZWithReceptionsBetween", mock.Anything, from, to).Return([]*models.PVZ{withReceptions}, nil)
+	mockReceptionRepo.On("ListReceptionIDsByPVZ", mock.Anything, withReceptions.ID, from, to).Return([]uuid.UUID{reception.ID}, nil)
+	mockReceptionRepo.On("GetReceptionsWithProducts", mock.Anything, []uuid.UUID{reception.ID}).Return([]*models.Reception{reception}, nil)
+
+	service := NewReceptionService(mockReceptionRepo, mockPVZRepo, mockProductRepo)
+
+	exported := map[uuid.UUID][]*models.Reception{}
+	err := service.ExportReceptions(context.Background(), from, to, func(pvz *models.PVZ, receptions []*models.Reception) error {
+		exported[pvz.ID] = receptions
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[uuid.UUID][]*models.Reception{withReceptions.ID: {reception}}, exported)
+	mockReceptionRepo.AssertExpectations(t)
+	mockReceptionRepo.AssertNumberOfCalls(t, "GetReceptionsWithProducts", 1)
+	mockPVZRepo.AssertNotCalled(t, "StreamPVZ", mock.Anything)
+}
+
+func TestReceptionService_TouchReception(t *testing.T) {
+	mockPVZRepo, mockReceptionRepo, mockProductRepo, now := setupProductTestMocks(t)
+
+	touchedAt := now
+	mockReceptionRepo.On("TouchOpenReception", mock.Anything, productTestPvzUUID1).Return(&models.Reception{
+		ID:             productTestReceptionUUID1,
+		DateTime:       now.Add(-100 * time.Hour),
+		PVZID:          productTestPvzUUID1,
+		Status:         models.StatusInProgress,
+		LastActivityAt: &touchedAt,
+	}, nil)
+
+	service := NewReceptionService(mockReceptionRepo, mockPVZRepo, mockProductRepo)
+
+	reception, err := service.TouchReception(context.Background(), productTestPvzUUID1)
+
+	require.NoError(t, err)
+	assert.Equal(t, productTestReceptionUUID1, reception.ID)
+	assert.Equal(t, &touchedAt, reception.LastActivityAt)
+	mockReceptionRepo.AssertExpectations(t)
+}
+
+func TestReceptionService_TouchReception_NoOpenReception(t *testing.T) {
+	mockPVZRepo, mockReceptionRepo, mockProductRepo, _ := setupProductTestMocks(t)
+
+	mockReceptionRepo.On("TouchOpenReception", mock.Anything, productTestPvzUUID1).Return(nil, nil)
+
+	service := NewReceptionService(mockReceptionRepo, mockPVZRepo, mockProductRepo)
+
+	reception, err := service.TouchReception(context.Background(), productTestPvzUUID1)
+
+	assert.ErrorIs(t, err, models.ErrReceptionNotFound)
+	assert.Nil(t, reception)
+}
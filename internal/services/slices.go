@@ -0,0 +1,23 @@
+package services
+
+import "pvz-service/internal/domain/models"
+
+// emptyIfNil заменяет nil-срез пустым, чтобы пустая коллекция сериализовалась в JSON как [], а не null
+func emptyIfNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+// normalizePVZList приводит все вложенные коллекции списка ПВЗ к пустым срезам вместо nil
+func normalizePVZList(pvzs []*models.PVZWithReceptionsResponse) []*models.PVZWithReceptionsResponse {
+	pvzs = emptyIfNil(pvzs)
+	for _, pvz := range pvzs {
+		pvz.Receptions = emptyIfNil(pvz.Receptions)
+		for _, reception := range pvz.Receptions {
+			reception.Products = emptyIfNil(reception.Products)
+		}
+	}
+	return pvzs
+}
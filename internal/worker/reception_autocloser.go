@@ -0,0 +1,115 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"pvz-service/internal/domain/interfaces"
+	"pvz-service/internal/logger"
+	"pvz-service/internal/metrics"
+)
+
+// Clock абстрагирует текущее время, чтобы в тестах можно было подставить фиксированное значение
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+// ReceptionAutoCloser периодически закрывает приемки, которые остаются открытыми дольше maxAge
+type ReceptionAutoCloser struct {
+	receptionRepo interfaces.ReceptionRepository
+	maxAge        time.Duration
+	interval      time.Duration
+	clock         Clock
+	log           *slog.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewReceptionAutoCloser(receptionRepo interfaces.ReceptionRepository, maxAge, interval time.Duration, log *slog.Logger) *ReceptionAutoCloser {
+	return &ReceptionAutoCloser{
+		receptionRepo: receptionRepo,
+		maxAge:        maxAge,
+		interval:      interval,
+		clock:         realClock{},
+		log:           log,
+	}
+}
+
+// Start запускает фоновый цикл. Первая проверка выполняется сразу
+func (w *ReceptionAutoCloser) Start(ctx context.Context) {
+	ctx, w.cancel = context.WithCancel(ctx)
+	ctx = logger.WithLogger(ctx, w.log)
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		w.log.Info("запущено автозакрытие приемок", "max_age", w.maxAge.String(), "interval", w.interval.String())
+
+		for {
+			if _, err := w.RunOnce(ctx); err != nil && ctx.Err() == nil {
+				w.log.Error("ошибка автозакрытия приемок", "error", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop останавливает фоновый цикл и дожидается завершения текущего прохода
+func (w *ReceptionAutoCloser) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	w.wg.Wait()
+	w.log.Info("автозакрытие приемок остановлено")
+}
+
+// RunOnce закрывает все открытые приемки, неактивные дольше maxAge, и возвращает количество
+// действительно закрытых
+func (w *ReceptionAutoCloser) RunOnce(ctx context.Context) (int, error) {
+	threshold := w.clock.Now().Add(-w.maxAge)
+
+	receptions, err := w.receptionRepo.ListOpenReceptionsOlderThan(ctx, threshold)
+	if err != nil {
+		return 0, err
+	}
+
+	closed := 0
+	for _, reception := range receptions {
+		ok, err := w.receptionRepo.CloseStaleReception(ctx, reception.ID, threshold)
+		if err != nil {
+			w.log.Error("ошибка автозакрытия приемки", "reception_id", reception.ID, "error", err)
+			continue
+		}
+		if !ok {
+			// Между выборкой и закрытием приемку продлили или закрыли вручную
+			w.log.Debug("приемка больше не подлежит автозакрытию", "reception_id", reception.ID)
+			continue
+		}
+
+		closed++
+		metrics.IncrementReceptionAutoClosed()
+		w.log.Info("приемка закрыта автоматически по таймауту",
+			"reception_id", reception.ID,
+			"pvz_id", reception.PVZID,
+			"opened_at", reception.DateTime.Format(time.RFC3339),
+		)
+	}
+
+	return closed, nil
+}
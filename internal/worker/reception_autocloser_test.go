@@ -0,0 +1,204 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"pvz-service/internal/domain/models"
+	"pvz-service/internal/logger"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+type MockReceptionRepository struct {
+	mock.Mock
+}
+
+func (m *MockReceptionRepository) CreateReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error) {
+	args := m.Called(ctx, pvzID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Reception), args.Error(1)
+}
+
+func (m *MockReceptionRepository) GetReceptionByID(ctx context.Context, id uuid.UUID) (*models.Reception, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Reception), args.Error(1)
+}
+
+func (m *MockReceptionRepository) GetLastOpenReceptionByPVZID(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error) {
+	args := m.Called(ctx, pvzID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Reception), args.Error(1)
+}
+
+func (m *MockReceptionRepository) CloseStaleReception(ctx context.Context, id uuid.UUID, olderThan time.Time) (bool, error) {
+	args := m.Called(ctx, id, olderThan)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockReceptionRepository) CloseReception(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockReceptionRepository) CountReceptionsByStatus(ctx context.Context, pvzID *uuid.UUID) (map[string]int, error) {
+	args := m.Called(ctx, pvzID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int), args.Error(1)
+}
+
+func (m *MockReceptionRepository) PurgeReceptions(ctx context.Context, before time.Time) (int, error) {
+	args := m.Called(ctx, before)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockReceptionRepository) ListOpenReceptionsOlderThan(ctx context.Context, t time.Time) ([]*models.Reception, error) {
+	args := m.Called(ctx, t)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Reception), args.Error(1)
+}
+
+func (m *MockReceptionRepository) TouchOpenReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error) {
+	args := m.Called(ctx, pvzID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Reception), args.Error(1)
+}
+
+func (m *MockReceptionRepository) ListReceptions(ctx context.Context, options models.ReceptionListOptions) ([]*models.Reception, int, error) {
+	args := m.Called(ctx, options)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.Reception), args.Int(1), args.Error(2)
+}
+
+func (m *MockReceptionRepository) GetReceptionWithProducts(ctx context.Context, id uuid.UUID) (*models.Reception, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Reception), args.Error(1)
+}
+
+func (m *MockReceptionRepository) GetReceptionsWithProducts(ctx context.Context, ids []uuid.UUID) ([]*models.Reception, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Reception), args.Error(1)
+}
+
+func (m *MockReceptionRepository) ListReceptionIDsByPVZ(ctx context.Context, pvzID uuid.UUID, from, to time.Time) ([]uuid.UUID, error) {
+	args := m.Called(ctx, pvzID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func newTestAutoCloser(repo *MockReceptionRepository, now time.Time) *ReceptionAutoCloser {
+	log := logger.New(logger.Config{Output: io.Discard})
+	w := NewReceptionAutoCloser(repo, 72*time.Hour, time.Minute, log)
+	w.clock = &fakeClock{now: now}
+	return w
+}
+
+func TestReceptionAutoCloser_RunOnce(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	threshold := now.Add(-72 * time.Hour)
+	staleID := uuid.New()
+	failingID := uuid.New()
+	touchedID := uuid.New()
+
+	repo := new(MockReceptionRepository)
+	repo.On("ListOpenReceptionsOlderThan", mock.Anything, threshold).Return([]*models.Reception{
+		{ID: staleID, DateTime: now.Add(-100 * time.Hour), PVZID: uuid.New(), Status: models.StatusInProgress},
+		{ID: failingID, DateTime: now.Add(-80 * time.Hour), PVZID: uuid.New(), Status: models.StatusInProgress},
+		{ID: touchedID, DateTime: now.Add(-75 * time.Hour), PVZID: uuid.New(), Status: models.StatusInProgress},
+	}, nil)
+	repo.On("CloseStaleReception", mock.Anything, staleID, threshold).Return(true, nil)
+	repo.On("CloseStaleReception", mock.Anything, failingID, threshold).Return(false, errors.New("database error"))
+	// Приемку продлили после выборки: UPDATE не затронул строк, в счетчик она не попадает
+	repo.On("CloseStaleReception", mock.Anything, touchedID, threshold).Return(false, nil)
+
+	closed, err := newTestAutoCloser(repo, now).RunOnce(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, closed)
+	repo.AssertExpectations(t)
+}
+
+func TestReceptionAutoCloser_RunOnce_NothingToClose(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	repo := new(MockReceptionRepository)
+	repo.On("ListOpenReceptionsOlderThan", mock.Anything, now.Add(-72*time.Hour)).Return([]*models.Reception{}, nil)
+
+	closed, err := newTestAutoCloser(repo, now).RunOnce(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, closed)
+	repo.AssertNotCalled(t, "CloseStaleReception", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReceptionAutoCloser_RunOnce_ListError(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	repo := new(MockReceptionRepository)
+	repo.On("ListOpenReceptionsOlderThan", mock.Anything, now.Add(-72*time.Hour)).Return(nil, errors.New("database error"))
+
+	closed, err := newTestAutoCloser(repo, now).RunOnce(context.Background())
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, closed)
+}
+
+func TestReceptionAutoCloser_StartStop(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	called := make(chan struct{}, 1)
+	repo := new(MockReceptionRepository)
+	repo.On("ListOpenReceptionsOlderThan", mock.Anything, now.Add(-72*time.Hour)).
+		Run(func(mock.Arguments) {
+			select {
+			case called <- struct{}{}:
+			default:
+			}
+		}).
+		Return([]*models.Reception{}, nil)
+
+	w := newTestAutoCloser(repo, now)
+	w.Start(context.Background())
+
+	select {
+	case <-called:
+	case <-time.After(time.Second):
+		t.Fatal("фоновый проход не был выполнен")
+	}
+
+	w.Stop()
+}
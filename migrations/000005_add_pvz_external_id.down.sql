@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_pvz_external_id;
+
+ALTER TABLE pvz DROP COLUMN IF EXISTS external_id;
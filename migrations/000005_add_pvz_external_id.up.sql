@@ -0,0 +1,3 @@
+ALTER TABLE pvz ADD COLUMN IF NOT EXISTS external_id VARCHAR(255);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_pvz_external_id ON pvz(external_id);
@@ -0,0 +1 @@
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_reception_sequence ON products(reception_id, sequence_num);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS user_pvz (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    pvz_id UUID NOT NULL REFERENCES pvz(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (user_id, pvz_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_pvz_pvz_id ON user_pvz(pvz_id);
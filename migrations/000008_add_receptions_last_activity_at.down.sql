@@ -0,0 +1 @@
+ALTER TABLE receptions DROP COLUMN IF EXISTS last_activity_at;
@@ -0,0 +1 @@
+ALTER TABLE receptions ADD COLUMN IF NOT EXISTS last_activity_at TIMESTAMP WITH TIME ZONE;
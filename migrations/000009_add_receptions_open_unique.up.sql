@@ -0,0 +1 @@
+CREATE UNIQUE INDEX IF NOT EXISTS idx_receptions_pvz_open ON receptions(pvz_id) WHERE status = 'in_progress';
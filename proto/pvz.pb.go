@@ -161,6 +161,50 @@ func (x *ListPVZResponse) GetItems() []*PVZ {
 	return nil
 }
 
+type GetPVZRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPVZRequest) Reset() {
+	*x = GetPVZRequest{}
+	mi := &file_proto_pvz_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPVZRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPVZRequest) ProtoMessage() {}
+
+func (x *GetPVZRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_pvz_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPVZRequest.ProtoReflect.Descriptor instead.
+func (*GetPVZRequest) Descriptor() ([]byte, []int) {
+	return file_proto_pvz_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetPVZRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
 var File_proto_pvz_proto protoreflect.FileDescriptor
 
 const file_proto_pvz_proto_rawDesc = "" +
@@ -172,10 +216,13 @@ const file_proto_pvz_proto_rawDesc = "" +
 	"\x11registration_date\x18\x02 \x01(\tR\x10registrationDate\x12\x12\n" +
 	"\x04city\x18\x03 \x01(\tR\x04city\"1\n" +
 	"\x0fListPVZResponse\x12\x1e\n" +
-	"\x05items\x18\x01 \x03(\v2\b.pvz.PVZR\x05items2D\n" +
+	"\x05items\x18\x01 \x03(\v2\b.pvz.PVZR\x05items\"\x1f\n" +
+	"\rGetPVZRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id2n\n" +
 	"\n" +
 	"PVZService\x126\n" +
-	"\aListPVZ\x12\x13.pvz.ListPVZRequest\x1a\x14.pvz.ListPVZResponse\"\x00B\x13Z\x11pvz-service/protob\x06proto3"
+	"\aListPVZ\x12\x13.pvz.ListPVZRequest\x1a\x14.pvz.ListPVZResponse\"\x00\x12(\n" +
+	"\x06GetPVZ\x12\x12.pvz.GetPVZRequest\x1a\b.pvz.PVZ\"\x00B\x13Z\x11pvz-service/protob\x06proto3"
 
 var (
 	file_proto_pvz_proto_rawDescOnce sync.Once
@@ -189,18 +236,21 @@ func file_proto_pvz_proto_rawDescGZIP() []byte {
 	return file_proto_pvz_proto_rawDescData
 }
 
-var file_proto_pvz_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_proto_pvz_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_proto_pvz_proto_goTypes = []any{
 	(*ListPVZRequest)(nil),  // 0: pvz.ListPVZRequest
 	(*PVZ)(nil),             // 1: pvz.PVZ
 	(*ListPVZResponse)(nil), // 2: pvz.ListPVZResponse
+	(*GetPVZRequest)(nil),   // 3: pvz.GetPVZRequest
 }
 var file_proto_pvz_proto_depIdxs = []int32{
 	1, // 0: pvz.ListPVZResponse.items:type_name -> pvz.PVZ
 	0, // 1: pvz.PVZService.ListPVZ:input_type -> pvz.ListPVZRequest
-	2, // 2: pvz.PVZService.ListPVZ:output_type -> pvz.ListPVZResponse
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
+	3, // 2: pvz.PVZService.GetPVZ:input_type -> pvz.GetPVZRequest
+	2, // 3: pvz.PVZService.ListPVZ:output_type -> pvz.ListPVZResponse
+	1, // 4: pvz.PVZService.GetPVZ:output_type -> pvz.PVZ
+	3, // [3:5] is the sub-list for method output_type
+	1, // [1:3] is the sub-list for method input_type
 	1, // [1:1] is the sub-list for extension type_name
 	1, // [1:1] is the sub-list for extension extendee
 	0, // [0:1] is the sub-list for field type_name
@@ -217,7 +267,7 @@ func file_proto_pvz_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_pvz_proto_rawDesc), len(file_proto_pvz_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
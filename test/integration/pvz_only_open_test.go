@@ -0,0 +1,75 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/api"
+	"pvz-service/internal/domain/models"
+	"pvz-service/internal/repository/postgres"
+	"pvz-service/internal/services"
+)
+
+// TestListPVZ_OnlyOpen_ReturnsPVZWithOpenReception проходит GET /pvz?onlyOpen=true через
+// настоящие сервис и репозиторий; sqlmock требует, чтобы подзапрос EXISTS ссылался на pvz.id
+func TestListPVZ_OnlyOpen_ReturnsPVZWithOpenReception(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	pvzService := services.NewPVZService(postgres.NewPVZRepository(postgres.NewDB(db)))
+	router := api.NewRouter(createMockAuthService("test_secret_key_for_testing"), pvzService,
+		createMockReceptionService(), createMockProductService(), nil, nil, nil, 0)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	pvzID := uuid.New()
+	receptionID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id, registration_date, city FROM pvz WHERE EXISTS \\(SELECT 1 FROM receptions open_r WHERE open_r.pvz_id = pvz.id AND open_r.status = \\$1\\)").
+		WithArgs(models.StatusInProgress).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "registration_date", "city"}).
+			AddRow(pvzID, time.Now().AddDate(0, -1, 0), "Москва"))
+	mock.ExpectQuery("SELECT id, date_time, pvz_id, status FROM receptions").
+		WithArgs(pvzID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "pvz_id", "status"}).
+			AddRow(receptionID, time.Now(), pvzID, models.StatusInProgress))
+	mock.ExpectQuery("SELECT id, date_time, type, reception_id, sequence_num FROM products").
+		WithArgs(receptionID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "date_time", "type", "reception_id", "sequence_num"}))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM pvz WHERE EXISTS (.+) open_r.pvz_id = pvz.id AND open_r.status = \\$1\\)").
+		WithArgs(models.StatusInProgress).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectCommit()
+
+	token := getToken(t, server, "employee")
+	req, err := http.NewRequest("GET", server.URL+"/pvz?onlyOpen=true", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Data []*models.PVZWithReceptionsResponse `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Len(t, body.Data, 1)
+	assert.Equal(t, pvzID, body.Data[0].PVZ.ID)
+	require.Len(t, body.Data[0].Receptions, 1)
+	assert.Equal(t, models.StatusInProgress, body.Data[0].Receptions[0].Reception.Status)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -25,7 +25,7 @@ func setupTestServer(t *testing.T) *httptest.Server {
 	receptionService := createMockReceptionService()
 	productService := createMockProductService()
 
-	router := api.NewRouter(authService, pvzService, receptionService, productService)
+	router := api.NewRouter(authService, pvzService, receptionService, productService, nil, nil, nil, 0)
 
 	return httptest.NewServer(router)
 }
@@ -93,7 +93,7 @@ func (m *MockAuthService) Login(ctx context.Context, email, password string) (st
 	return "mock_auth_token_for_testing", nil
 }
 
-func (m *MockAuthService) GenerateDummyToken(role models.UserRole) (string, error) {
+func (m *MockAuthService) GenerateDummyToken(role models.UserRole, ttl time.Duration) (string, error) {
 	return "test_token_for_" + string(role), nil
 }
 
@@ -113,9 +113,29 @@ func (m *MockAuthService) ValidateToken(token string) (*models.User, error) {
 	}, nil
 }
 
-func (m *MockPVZService) CreatePVZ(ctx context.Context, city string) (*models.PVZ, error) {
+func (m *MockAuthService) ValidateAPIKey(key string) (*models.User, error) {
+	return nil, fmt.Errorf("api keys are not configured")
+}
+
+func (m *MockAuthService) IntrospectToken(token string) (*models.TokenIntrospection, error) {
+	user, err := m.ValidateToken(token)
+	if err != nil {
+		return nil, err
+	}
+	return &models.TokenIntrospection{Active: true, UserID: &user.ID, Role: user.Role}, nil
+}
+
+func (m *MockPVZService) CreatePVZ(ctx context.Context, city, externalID string) (*models.PVZ, bool, error) {
 	if !models.AllowedCities[city] {
-		return nil, fmt.Errorf("city must be one of: Москва, Санкт-Петербург, Казань")
+		return nil, false, fmt.Errorf("city must be one of: Москва, Санкт-Петербург, Казань")
+	}
+
+	if externalID != "" {
+		for _, existing := range m.pvzs {
+			if existing.ExternalID != nil && *existing.ExternalID == externalID {
+				return existing, false, nil
+			}
+		}
 	}
 
 	pvz := &models.PVZ{
@@ -123,9 +143,28 @@ func (m *MockPVZService) CreatePVZ(ctx context.Context, city string) (*models.PV
 		RegistrationDate: time.Now(),
 		City:             city,
 	}
+	if externalID != "" {
+		pvz.ExternalID = &externalID
+	}
 
 	m.pvzs[pvz.ID] = pvz
-	return pvz, nil
+	return pvz, true, nil
+}
+
+func (m *MockPVZService) CreatePVZBatch(ctx context.Context, cities []string) (*models.PVZBatchCreateResponse, error) {
+	result := &models.PVZBatchCreateResponse{
+		Created: []*models.PVZ{},
+		Errors:  []models.PVZBatchItemError{},
+	}
+	for i, city := range cities {
+		pvz, _, err := m.CreatePVZ(ctx, city, "")
+		if err != nil {
+			result.Errors = append(result.Errors, models.PVZBatchItemError{Index: i, City: city, Error: err.Error()})
+			continue
+		}
+		result.Created = append(result.Created, pvz)
+	}
+	return result, nil
 }
 
 func (m *MockPVZService) GetPVZByID(ctx context.Context, id uuid.UUID) (*models.PVZ, error) {
@@ -141,6 +180,16 @@ func (m *MockPVZService) GetPVZByID(ctx context.Context, id uuid.UUID) (*models.
 	return pvz, nil
 }
 
+func (m *MockPVZService) GetPVZsByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.PVZ, error) {
+	pvzs := make([]*models.PVZ, 0, len(ids))
+	for _, id := range ids {
+		if pvz, exists := m.pvzs[id]; exists {
+			pvzs = append(pvzs, pvz)
+		}
+	}
+	return pvzs, nil
+}
+
 func (m *MockPVZService) ListPVZ(ctx context.Context, options models.PVZListOptions) ([]*models.PVZWithReceptionsResponse, int, error) {
 	var results []*models.PVZWithReceptionsResponse
 
@@ -170,6 +219,36 @@ func (m *MockPVZService) ListPVZ(ctx context.Context, options models.PVZListOpti
 	return results, len(results), nil
 }
 
+func (m *MockPVZService) CountPVZByCity(ctx context.Context) ([]models.CityPVZCount, error) {
+	counts := make(map[string]int)
+	for _, pvz := range m.pvzs {
+		counts[pvz.City]++
+	}
+
+	cities := make([]models.CityPVZCount, 0, len(counts))
+	for city, count := range counts {
+		cities = append(cities, models.CityPVZCount{City: city, Count: count})
+	}
+	return cities, nil
+}
+
+func (m *MockPVZService) ExportPVZ(ctx context.Context, fn func(*models.PVZ) error) error {
+	for _, pvz := range m.pvzs {
+		if err := fn(pvz); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MockReceptionService) CreateOrReuseReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, bool, error) {
+	if id, exists := m.openReceptionsByPVZ[pvzID]; exists {
+		return m.receptions[id], true, nil
+	}
+	reception, err := m.CreateReception(ctx, pvzID)
+	return reception, false, err
+}
+
 func (m *MockReceptionService) CreateReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error) {
 	if _, exists := m.openReceptionsByPVZ[pvzID]; exists {
 		return nil, fmt.Errorf("there is already an open reception for this pvz")
@@ -212,6 +291,21 @@ func (m *MockReceptionService) CloseLastReception(ctx context.Context, pvzID uui
 	return reception, nil
 }
 
+func (m *MockReceptionService) CloseReceptionByID(ctx context.Context, id uuid.UUID) (*models.Reception, error) {
+	reception, exists := m.receptions[id]
+	if !exists {
+		return nil, models.ErrReceptionNotFound
+	}
+	if reception.Status != models.StatusInProgress {
+		return nil, models.ErrReceptionClosed
+	}
+
+	reception.Status = models.StatusClosed
+	delete(m.openReceptionsByPVZ, reception.PVZID)
+
+	return reception, nil
+}
+
 func (m *MockReceptionService) GetReceptionByID(ctx context.Context, id uuid.UUID) (*models.Reception, error) {
 	reception, exists := m.receptions[id]
 	if !exists {
@@ -227,6 +321,111 @@ func (m *MockReceptionService) GetReceptionByID(ctx context.Context, id uuid.UUI
 	return reception, nil
 }
 
+func (m *MockReceptionService) ListReceptions(ctx context.Context, options models.ReceptionListOptions) ([]*models.Reception, int, error) {
+	receptions := make([]*models.Reception, 0, len(m.receptions))
+	for _, reception := range m.receptions {
+		receptions = append(receptions, reception)
+	}
+	return receptions, len(receptions), nil
+}
+
+func (m *MockReceptionService) ListReceptionsByPVZ(ctx context.Context, pvzID uuid.UUID, options models.ReceptionListOptions) ([]*models.Reception, int, error) {
+	receptions := make([]*models.Reception, 0, len(m.receptions))
+	for _, reception := range m.receptions {
+		if reception.PVZID == pvzID {
+			receptions = append(receptions, reception)
+		}
+	}
+	return receptions, len(receptions), nil
+}
+
+func (m *MockReceptionService) GetReceptionStats(ctx context.Context, pvzID *uuid.UUID) (*models.ReceptionStats, error) {
+	stats := &models.ReceptionStats{ByStatus: map[string]int{}}
+	for _, reception := range m.receptions {
+		if pvzID != nil && reception.PVZID != *pvzID {
+			continue
+		}
+		stats.ByStatus[string(reception.Status)]++
+		stats.Total++
+	}
+	return stats, nil
+}
+
+func (m *MockReceptionService) ExportReceptions(ctx context.Context, from, to time.Time, fn func(pvz *models.PVZ, receptions []*models.Reception) error) error {
+	byPVZ := make(map[uuid.UUID][]*models.Reception)
+	for _, reception := range m.receptions {
+		if !reception.DateTime.Before(from) && reception.DateTime.Before(to) {
+			byPVZ[reception.PVZID] = append(byPVZ[reception.PVZID], reception)
+		}
+	}
+	for pvzID, receptions := range byPVZ {
+		if err := fn(&models.PVZ{ID: pvzID}, receptions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MockReceptionService) PurgeReceptions(ctx context.Context, before time.Time) (int, error) {
+	deleted := 0
+	for id, reception := range m.receptions {
+		if reception.Status == models.StatusClosed && reception.DateTime.Before(before) {
+			delete(m.receptions, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (m *MockReceptionService) TouchReception(ctx context.Context, pvzID uuid.UUID) (*models.Reception, error) {
+	for _, reception := range m.receptions {
+		if reception.PVZID == pvzID && reception.Status == models.StatusInProgress {
+			now := time.Now()
+			reception.LastActivityAt = &now
+			return reception, nil
+		}
+	}
+	return nil, models.ErrReceptionNotFound
+}
+
+func (m *MockReceptionService) GetReceptionTypeCounts(ctx context.Context, id uuid.UUID) (*models.ReceptionTypeCounts, error) {
+	if _, exists := m.receptions[id]; !exists {
+		return nil, models.ErrReceptionNotFound
+	}
+	return &models.ReceptionTypeCounts{}, nil
+}
+
+func (m *MockReceptionService) GetReceptionSequenceGaps(ctx context.Context, id uuid.UUID) (*models.ReceptionSequenceGaps, error) {
+	if _, exists := m.receptions[id]; !exists {
+		return nil, models.ErrReceptionNotFound
+	}
+	return &models.ReceptionSequenceGaps{ReceptionID: id, Missing: []int{}}, nil
+}
+
+func (m *MockReceptionService) ResequenceReception(ctx context.Context, id uuid.UUID) (*models.ReceptionResequence, error) {
+	reception, exists := m.receptions[id]
+	if !exists {
+		return nil, models.ErrReceptionNotFound
+	}
+	if reception.Status != models.StatusInProgress {
+		return nil, models.ErrReceptionClosed
+	}
+	return &models.ReceptionResequence{ReceptionID: id}, nil
+}
+
+func (m *MockReceptionService) GetReceptionSummary(ctx context.Context, id uuid.UUID) (*models.ReceptionSummary, error) {
+	if _, exists := m.receptions[id]; !exists {
+		return nil, models.ErrReceptionNotFound
+	}
+	return &models.ReceptionSummary{
+		ByType: map[models.ProductType]int{
+			models.TypeElectronics: 0,
+			models.TypeClothes:     0,
+			models.TypeFootwear:    0,
+		},
+	}, nil
+}
+
 func (m *MockProductService) AddProduct(ctx context.Context, pvzID uuid.UUID, productType models.ProductType) (*models.Product, error) {
 	if productType != models.TypeElectronics &&
 		productType != models.TypeClothes &&
@@ -267,6 +466,58 @@ func (m *MockProductService) DeleteLastProduct(ctx context.Context, pvzID uuid.U
 	return nil
 }
 
+func (m *MockProductService) GetProductByID(ctx context.Context, productID uuid.UUID) (*models.Product, error) {
+	product, exists := m.products[productID]
+	if !exists {
+		return nil, models.ErrProductNotFound
+	}
+	return product, nil
+}
+
+func (m *MockProductService) UpdateProductType(ctx context.Context, productID uuid.UUID, productType models.ProductType) error {
+	product, exists := m.products[productID]
+	if !exists {
+		return models.ErrProductNotFound
+	}
+	product.Type = productType
+	return nil
+}
+
+func (m *MockProductService) MoveProduct(ctx context.Context, productID, targetPVZID uuid.UUID) error {
+	if _, exists := m.products[productID]; !exists {
+		return models.ErrProductNotFound
+	}
+	return nil
+}
+
+func (m *MockProductService) GetRecentProducts(ctx context.Context, pvzID uuid.UUID, n int) ([]*models.Product, error) {
+	return []*models.Product{}, nil
+}
+
+func (m *MockProductService) ClearReception(ctx context.Context, pvzID uuid.UUID) (int, error) {
+	// Для теста считаем, что приемка уже пуста
+	return 0, nil
+}
+
+func (m *MockProductService) ListPVZProducts(ctx context.Context, pvzID uuid.UUID, productType models.ProductType, page, limit int) ([]*models.Product, int, error) {
+	return []*models.Product{}, 0, nil
+}
+
+func (m *MockProductService) GetProductsByReceptionID(ctx context.Context, receptionID uuid.UUID, page, limit int, sort string) ([]*models.Product, int, error) {
+	products := m.productsByReception[receptionID]
+	total := len(products)
+
+	start := (page - 1) * limit
+	if start >= total {
+		return []*models.Product{}, total, nil
+	}
+	end := start + limit
+	if end > total {
+		end = total
+	}
+	return products[start:end], total, nil
+}
+
 func TestPVZWorkflow(t *testing.T) {
 	server := setupTestServer(t)
 	defer server.Close()